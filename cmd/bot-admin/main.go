@@ -0,0 +1,186 @@
+// Command bot-admin runs admin operations against the database without connecting to Discord:
+//
+//	go run ./cmd/bot-admin stats <userID>
+//	go run ./cmd/bot-admin purge-user <userID>
+//	go run ./cmd/bot-admin list-problems <userID> > problems.csv
+//	go run ./cmd/bot-admin db-vacuum
+//	go run ./cmd/bot-admin orphan-tags
+//
+// Every subcommand takes --config, before any arguments, to read a config file other than
+// ./config/config.yaml, and exits with status 1 if it fails.
+package main
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+
+	"github.com/yugonline/grind_review_bot/config"
+	"github.com/yugonline/grind_review_bot/internal/database"
+)
+
+// subcommand is one bot-admin operation. Args are the positional arguments after the flags.
+type subcommand struct {
+	args []string // Names of the positional arguments it requires
+	run  func(ctx context.Context, repo *database.Repository, args []string) error
+}
+
+// subcommands lists the available operations by name
+var subcommands = map[string]subcommand{
+	"stats":         {args: []string{"<userID>"}, run: runStats},
+	"purge-user":    {args: []string{"<userID>"}, run: runPurgeUser},
+	"list-problems": {args: []string{"<userID>"}, run: runListProblems},
+	"db-vacuum":     {run: runVacuum},
+	"orphan-tags":   {run: runOrphanTags},
+}
+
+// subcommandNames lists the subcommands in the order usage shows them
+var subcommandNames = []string{"stats", "purge-user", "list-problems", "db-vacuum", "orphan-tags"}
+
+func main() {
+	// Logs go to stderr so command output can be piped
+	log.Logger = log.Output(zerolog.ConsoleWriter{Out: os.Stderr, TimeFormat: time.RFC3339})
+	zerolog.DefaultContextLogger = &log.Logger
+
+	if err := run(context.Background(), os.Args[1:]); err != nil {
+		fmt.Fprintln(os.Stderr, "bot-admin:", err)
+		os.Exit(1)
+	}
+}
+
+// run parses the subcommand and its flags, connects to the configured database and runs it
+func run(ctx context.Context, args []string) error {
+	if len(args) == 0 {
+		return errors.New(usage())
+	}
+	cmd, ok := subcommands[args[0]]
+	if !ok {
+		return fmt.Errorf("unknown subcommand %q\n%s", args[0], usage())
+	}
+
+	flags := flag.NewFlagSet(args[0], flag.ContinueOnError)
+	configPath := flags.String("config", config.DefaultPath, "Path to the YAML config file")
+	if err := flags.Parse(args[1:]); err != nil {
+		return err
+	}
+	if flags.NArg() != len(cmd.args) {
+		return fmt.Errorf("usage: %s", subcommandUsage(args[0]))
+	}
+
+	cfg, err := config.LoadFile(*configPath)
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+
+	// Only warnings and errors, so GORM's query logging doesn't drown out the output
+	zerolog.SetGlobalLevel(zerolog.WarnLevel)
+
+	repo, err := database.New(ctx, cfg.Database)
+	if err != nil {
+		return fmt.Errorf("failed to connect to database: %w", err)
+	}
+
+	return cmd.run(ctx, repo, flags.Args())
+}
+
+// usage lists the subcommands
+func usage() string {
+	lines := []string{"usage: bot-admin <subcommand> [--config path] [args]", "subcommands:"}
+	for _, name := range subcommandNames {
+		lines = append(lines, "  "+subcommandUsage(name))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// subcommandUsage shows how to invoke one subcommand
+func subcommandUsage(name string) string {
+	return strings.Join(append([]string{"bot-admin", name, "[--config path]"}, subcommands[name].args...), " ")
+}
+
+func runStats(ctx context.Context, repo *database.Repository, args []string) error {
+	stats, err := repo.GetUserStats(ctx, args[0])
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("User:           %s\n", stats.UserID)
+	fmt.Printf("Total problems: %d\n", stats.TotalProblems)
+	fmt.Printf("By difficulty:  %d easy, %d medium, %d hard\n", stats.Easy, stats.Medium, stats.Hard)
+	fmt.Printf("By status:      %d solved, %d needed a hint, %d stuck\n", stats.Solved, stats.NeededHint, stats.Stuck)
+	return nil
+}
+
+func runPurgeUser(ctx context.Context, repo *database.Repository, args []string) error {
+	removed, err := repo.TruncateUserData(ctx, args[0])
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Purged all data for %s, including %d problems.\n", args[0], removed)
+	return nil
+}
+
+// problemCSVHeader names the columns list-problems writes
+var problemCSVHeader = []string{"id", "problem_name", "difficulty", "category", "status", "solved_at", "review_count", "last_reviewed_at", "archived", "tags", "link", "notes"}
+
+func runListProblems(ctx context.Context, repo *database.Repository, args []string) error {
+	problems, err := repo.ListProblems(ctx, args[0], "", "", "", nil, true, 0, 0)
+	if err != nil {
+		return err
+	}
+
+	w := csv.NewWriter(os.Stdout)
+	if err := w.Write(problemCSVHeader); err != nil {
+		return err
+	}
+	for _, p := range problems {
+		lastReviewed := ""
+		if p.LastReviewedAt != nil {
+			lastReviewed = p.LastReviewedAt.UTC().Format(time.RFC3339)
+		}
+		record := []string{
+			strconv.FormatUint(uint64(p.ID), 10),
+			p.ProblemName,
+			p.Difficulty,
+			p.Category,
+			p.Status,
+			p.SolvedAt.UTC().Format(time.RFC3339),
+			strconv.Itoa(p.ReviewCount),
+			lastReviewed,
+			strconv.FormatBool(p.Archived),
+			strings.Join(p.Tags, ";"),
+			p.Link,
+			p.Notes,
+		}
+		if err := w.Write(record); err != nil {
+			return err
+		}
+	}
+	w.Flush()
+	return w.Error()
+}
+
+func runVacuum(ctx context.Context, repo *database.Repository, args []string) error {
+	if err := repo.Compact(ctx); err != nil {
+		return err
+	}
+	fmt.Println("Database compacted.")
+	return nil
+}
+
+func runOrphanTags(ctx context.Context, repo *database.Repository, args []string) error {
+	removed, err := repo.CleanupOrphanedTags(ctx)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Removed %d orphaned tags.\n", removed)
+	return nil
+}
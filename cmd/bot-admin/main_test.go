@@ -0,0 +1,130 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/yugonline/grind_review_bot/config"
+	"github.com/yugonline/grind_review_bot/internal/database"
+)
+
+// buildBotAdmin compiles the command into a temporary directory and returns the binary's path
+func buildBotAdmin(t *testing.T) string {
+	t.Helper()
+	binary := filepath.Join(t.TempDir(), "bot-admin")
+	if out, err := exec.Command("go", "build", "-o", binary, ".").CombinedOutput(); err != nil {
+		t.Fatalf("go build: %v\n%s", err, out)
+	}
+	return binary
+}
+
+// newTestDatabase creates a migrated SQLite database holding problems for userID and returns
+// the path of a config file pointing at it
+func newTestDatabase(t *testing.T, userID string, problems ...*database.ProblemEntry) string {
+	t.Helper()
+	ctx := context.Background()
+	dir := t.TempDir()
+	cfg := config.DatabaseConfig{Driver: "sqlite3", DSN: filepath.Join(dir, "grind.db"), MaxOpenConns: 1, MaxIdleConns: 1, MaxTagsPerProblem: 10}
+
+	repo, err := database.New(ctx, cfg)
+	if err != nil {
+		t.Fatalf("failed to open test database: %v", err)
+	}
+	// Migrations are found relative to the working directory, so run them from the module root
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("Getwd: %v", err)
+	}
+	if err := os.Chdir(filepath.Join("..", "..")); err != nil {
+		t.Fatalf("Chdir: %v", err)
+	}
+	err = database.Migrate(ctx, repo)
+	if chdirErr := os.Chdir(wd); chdirErr != nil {
+		t.Fatalf("Chdir: %v", chdirErr)
+	}
+	if err != nil {
+		t.Fatalf("failed to migrate test database: %v", err)
+	}
+
+	for _, p := range problems {
+		p.UserID = userID
+		if err := repo.CreateProblem(ctx, p); err != nil {
+			t.Fatalf("CreateProblem(%s): %v", p.ProblemName, err)
+		}
+	}
+
+	configPath := filepath.Join(dir, "config.yaml")
+	content := "discord:\n  token: t\ndatabase:\n  driver: sqlite3\n  dsn: " + cfg.DSN + "\n"
+	if err := os.WriteFile(configPath, []byte(content), 0o600); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+	return configPath
+}
+
+// runBotAdmin runs the binary and returns its stdout and exit code
+func runBotAdmin(t *testing.T, binary string, args ...string) (string, int) {
+	t.Helper()
+	cmd := exec.Command(binary, args...)
+	// Keep the environment from overriding the test config
+	for _, kv := range os.Environ() {
+		if !strings.HasPrefix(kv, "GRIND_REVIEW_") && !strings.HasPrefix(kv, "DISCORD_BOT_TOKEN=") {
+			cmd.Env = append(cmd.Env, kv)
+		}
+	}
+	var stdout, stderr strings.Builder
+	cmd.Stdout, cmd.Stderr = &stdout, &stderr
+
+	err := cmd.Run()
+	var exitErr *exec.ExitError
+	switch {
+	case err == nil:
+		return stdout.String(), 0
+	case errors.As(err, &exitErr):
+		t.Logf("bot-admin %s: %s", strings.Join(args, " "), stderr.String())
+		return stdout.String(), exitErr.ExitCode()
+	default:
+		t.Fatalf("running bot-admin: %v", err)
+		return "", 0
+	}
+}
+
+func TestStatsCommand(t *testing.T) {
+	binary := buildBotAdmin(t)
+	solvedAt := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	configPath := newTestDatabase(t, "user-1",
+		&database.ProblemEntry{ProblemName: "Two Sum", Difficulty: database.DifficultyEasy, Category: "Array", Status: database.StatusSolved, SolvedAt: solvedAt},
+		&database.ProblemEntry{ProblemName: "Word Ladder", Difficulty: database.DifficultyHard, Category: "Graph", Status: database.StatusStuck, SolvedAt: solvedAt},
+	)
+
+	out, code := runBotAdmin(t, binary, "stats", "--config", configPath, "user-1")
+	if code != 0 {
+		t.Fatalf("stats exited %d, want 0", code)
+	}
+	for _, want := range []string{"User:           user-1", "Total problems: 2", "1 easy, 0 medium, 1 hard", "1 solved, 0 needed a hint, 1 stuck"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("stats output = %q, want it to contain %q", out, want)
+		}
+	}
+
+	tests := []struct {
+		name string
+		args []string
+	}{
+		{name: "missing user", args: []string{"stats", "--config", configPath}},
+		{name: "unknown subcommand", args: []string{"restore", "--config", configPath}},
+		{name: "missing config", args: []string{"stats", "--config", filepath.Join(t.TempDir(), "missing.yaml"), "user-1"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, code := runBotAdmin(t, binary, tt.args...); code != 1 {
+				t.Errorf("exit code = %d, want 1", code)
+			}
+		})
+	}
+}
@@ -0,0 +1,14 @@
+// Command gen-config prints an example config.yaml with every field set to its default:
+//
+//	go run ./cmd/gen-config > config/config.yaml
+package main
+
+import (
+	"fmt"
+
+	"github.com/yugonline/grind_review_bot/config"
+)
+
+func main() {
+	fmt.Print(config.ExampleConfig())
+}
@@ -11,15 +11,20 @@ import (
 	"github.com/rs/zerolog/log"
 
 	"github.com/yugonline/grind_review_bot/config"
+	"github.com/yugonline/grind_review_bot/internal/api"
 	"github.com/yugonline/grind_review_bot/internal/bot"
 	"github.com/yugonline/grind_review_bot/internal/database"
 	"github.com/yugonline/grind_review_bot/internal/metrics"
+	"github.com/yugonline/grind_review_bot/internal/webhook"
+	"github.com/yugonline/grind_review_bot/pkg/cache"
 )
 
 func main() {
 	// Initialize structured logging
 	zerolog.TimeFieldFormat = zerolog.TimeFormatUnix
 	log.Logger = log.Output(zerolog.ConsoleWriter{Out: os.Stdout, TimeFormat: time.RFC3339})
+	// Loggers pulled from a context without an interaction logger fall back to the global one
+	zerolog.DefaultContextLogger = &log.Logger
 
 	// Load configuration
 	cfg, err := config.Load()
@@ -42,12 +47,14 @@ func main() {
 	// Initialize metrics (if enabled)
 	if cfg.Metrics.Enabled {
 		metricsServer := metrics.New(cfg.Metrics)
-		go func() {
-			if err := metricsServer.Start(); err != nil {
-				log.Error().Err(err).Msg("Metrics server failed")
+		if err := metricsServer.Start(); err != nil {
+			if cfg.Metrics.FailOnError {
+				log.Fatal().Err(err).Msg("Failed to start metrics server")
 			}
-		}()
-		defer metricsServer.Stop(ctx)
+			log.Error().Err(err).Msg("Failed to start metrics server, continuing without metrics")
+		} else {
+			defer metricsServer.Stop(ctx)
+		}
 	}
 
 	// Initialize database repository
@@ -61,12 +68,43 @@ func main() {
 		log.Fatal().Err(err).Msg("Failed to run database migrations")
 	}
 
+	// Repair review counters that drifted from the review history (if enabled)
+	if cfg.Database.RecalculateStatsOnStartup {
+		corrected, err := repo.RecalculateAllUserStats(ctx)
+		if err != nil {
+			log.Error().Err(err).Msg("Failed to recalculate user stats")
+		} else {
+			log.Info().Int("corrected", corrected).Msg("Recalculated user stats")
+		}
+	}
+
+	// Start refreshing aggregate gauges (if metrics are enabled)
+	if cfg.Metrics.Enabled {
+		collector := metrics.NewCollector(repo, cfg.Metrics.RefreshInterval)
+		collector.Start(ctx)
+		defer collector.Stop()
+	}
+
+	// Serve the read-only HTTP API (if enabled)
+	if cfg.API.Enabled {
+		apiServer := api.New(cfg.API, repo)
+		go func() {
+			if err := apiServer.Start(); err != nil {
+				log.Error().Err(err).Msg("API server failed")
+			}
+		}()
+		defer apiServer.Stop(ctx)
+	}
+
 	// Create and set up Discord bot
-	discordBot, err := bot.New(ctx, cfg.Discord, repo)
+	webhooks := webhook.New(cfg.Webhooks)
+	discordBot, err := bot.New(ctx, cfg.Discord, repo, webhooks)
 	if err != nil {
 		log.Fatal().Err(err).Msg("Failed to create Discord bot")
 	}
 
+	discordBot.SetBackupConfig(cfg.Backup)
+
 	// Start the bot
 	if err := discordBot.Start(ctx); err != nil {
 		log.Fatal().Err(err).Msg("Failed to start bot")
@@ -74,7 +112,7 @@ func main() {
 	log.Info().Msg("LeetCode Grind Review Bot is running! 🚀")
 
 	// Start scheduler for daily reviews
-	scheduler := bot.StartScheduler(ctx, discordBot, cfg.Scheduler)
+	scheduler := bot.StartScheduler(ctx, discordBot, cfg.Scheduler, cfg.Backup)
 	defer scheduler.Stop()
 
 	// Wait for termination signal
@@ -91,4 +129,6 @@ func main() {
 	if err := discordBot.Shutdown(shutdownCtx); err != nil {
 		log.Error().Err(err).Msg("Error during bot shutdown")
 	}
+	webhooks.Wait(shutdownCtx)
+	cache.DefaultCache.Close()
 }
\ No newline at end of file
@@ -0,0 +1,108 @@
+// Command migrate applies, rolls back or reports database migrations without starting the bot:
+//
+//	go run ./cmd/migrate --direction up
+//	go run ./cmd/migrate --direction status --config /etc/grind/config.yaml
+//	go run ./cmd/migrate --direction down
+//
+// It exits with status 1 if anything fails.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+
+	"github.com/yugonline/grind_review_bot/config"
+	"github.com/yugonline/grind_review_bot/internal/database"
+)
+
+func main() {
+	direction := flag.String("direction", "up", "What to do: up, down (revert the latest migration) or status")
+	configPath := flag.String("config", config.DefaultPath, "Path to the YAML config file")
+	flag.Parse()
+
+	// Logs go to stderr so status output can be piped
+	log.Logger = log.Output(zerolog.ConsoleWriter{Out: os.Stderr, TimeFormat: time.RFC3339})
+
+	if err := run(context.Background(), *direction, *configPath); err != nil {
+		log.Error().Err(err).Str("direction", *direction).Msg("Migration failed")
+		os.Exit(1)
+	}
+}
+
+// run connects to the configured database and carries out direction
+func run(ctx context.Context, direction, configPath string) error {
+	if direction != "up" && direction != "down" && direction != "status" {
+		return fmt.Errorf("unknown direction %q, expected up, down or status", direction)
+	}
+
+	cfg, err := config.LoadFile(configPath)
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+
+	repo, err := database.New(ctx, cfg.Database)
+	if err != nil {
+		return fmt.Errorf("failed to connect to database: %w", err)
+	}
+
+	switch direction {
+	case "up":
+		return database.Migrate(ctx, repo)
+	case "down":
+		return rollbackLatest(ctx, repo)
+	default:
+		return printStatus(repo)
+	}
+}
+
+// rollbackLatest reverts the most recently applied migration
+func rollbackLatest(ctx context.Context, repo *database.Repository) error {
+	status, err := database.GetSchemaStatus(repo)
+	if err != nil {
+		return err
+	}
+	if status.Version == 0 {
+		return fmt.Errorf("no migrations have been applied")
+	}
+
+	// Step back to the newest applied migration older than the current one
+	var target uint
+	for _, migration := range status.Migrations {
+		if migration.Version < status.Version {
+			target = migration.Version
+		}
+	}
+	return database.MigrateDown(ctx, repo, target)
+}
+
+// printStatus writes the schema version and each migration's state to stdout
+func printStatus(repo *database.Repository) error {
+	status, err := database.GetSchemaStatus(repo)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Schema version: %d", status.Version)
+	if status.Dirty {
+		fmt.Print(" (dirty)")
+	}
+	fmt.Println()
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "VERSION\tNAME\tSTATE")
+	for _, migration := range status.Migrations {
+		state := "pending"
+		if migration.Applied {
+			state = "applied"
+		}
+		fmt.Fprintf(w, "%06d\t%s\t%s\n", migration.Version, migration.Name, state)
+	}
+	return w.Flush()
+}
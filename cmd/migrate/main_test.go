@@ -0,0 +1,99 @@
+package main
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// buildMigrate compiles the command into a temporary directory and returns the binary's path
+func buildMigrate(t *testing.T) string {
+	t.Helper()
+	binary := filepath.Join(t.TempDir(), "migrate")
+	if out, err := exec.Command("go", "build", "-o", binary, ".").CombinedOutput(); err != nil {
+		t.Fatalf("go build: %v\n%s", err, out)
+	}
+	return binary
+}
+
+// runMigrate runs the binary from the module root, where the migrations are found, and
+// returns its stdout and exit code
+func runMigrate(t *testing.T, binary string, args ...string) (string, int) {
+	t.Helper()
+	root, err := filepath.Abs(filepath.Join("..", ".."))
+	if err != nil {
+		t.Fatalf("Abs: %v", err)
+	}
+	cmd := exec.Command(binary, args...)
+	cmd.Dir = root
+	// Keep the environment from overriding the test config
+	for _, kv := range os.Environ() {
+		if !strings.HasPrefix(kv, "GRIND_REVIEW_") && !strings.HasPrefix(kv, "DISCORD_BOT_TOKEN=") {
+			cmd.Env = append(cmd.Env, kv)
+		}
+	}
+	var stdout, stderr strings.Builder
+	cmd.Stdout, cmd.Stderr = &stdout, &stderr
+
+	err = cmd.Run()
+	var exitErr *exec.ExitError
+	switch {
+	case err == nil:
+		return stdout.String(), 0
+	case errors.As(err, &exitErr):
+		t.Logf("migrate %s: %s", strings.Join(args, " "), stderr.String())
+		return stdout.String(), exitErr.ExitCode()
+	default:
+		t.Fatalf("running migrate: %v", err)
+		return "", 0
+	}
+}
+
+func TestMigrateCommand(t *testing.T) {
+	binary := buildMigrate(t)
+	dir := t.TempDir()
+	configPath := filepath.Join(dir, "config.yaml")
+	content := "discord:\n  token: t\ndatabase:\n  driver: sqlite3\n  dsn: " + filepath.Join(dir, "grind.db") + "\n"
+	if err := os.WriteFile(configPath, []byte(content), 0o600); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+
+	out, code := runMigrate(t, binary, "--direction", "status", "--config", configPath)
+	if code != 0 || !strings.Contains(out, "Schema version: 0") || strings.Contains(out, "applied") {
+		t.Fatalf("status exited %d with %q, want every migration pending", code, out)
+	}
+
+	if _, code := runMigrate(t, binary, "--direction", "up", "--config", configPath); code != 0 {
+		t.Fatalf("up exited %d, want 0", code)
+	}
+	out, code = runMigrate(t, binary, "--direction", "status", "--config", configPath)
+	if code != 0 || strings.Contains(out, "pending") || strings.Contains(out, "Schema version: 0") {
+		t.Fatalf("status exited %d with %q, want every migration applied", code, out)
+	}
+
+	if _, code := runMigrate(t, binary, "--direction", "down", "--config", configPath); code != 0 {
+		t.Fatalf("down exited %d, want 0", code)
+	}
+	out, _ = runMigrate(t, binary, "--direction", "status", "--config", configPath)
+	if strings.Count(out, "pending") != 1 {
+		t.Errorf("status after down = %q, want only the latest migration pending", out)
+	}
+
+	tests := []struct {
+		name string
+		args []string
+	}{
+		{name: "unknown direction", args: []string{"--direction", "sideways", "--config", configPath}},
+		{name: "missing config", args: []string{"--direction", "up", "--config", filepath.Join(dir, "missing.yaml")}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, code := runMigrate(t, binary, tt.args...); code != 1 {
+				t.Errorf("exit code = %d, want 1", code)
+			}
+		})
+	}
+}
@@ -2,8 +2,15 @@ package config
 
 import (
 	"bytes"
+	"errors"
 	"fmt"
+	"net"
+	"net/url"
 	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"text/template"
 	"time"
 
 	"github.com/spf13/viper"
@@ -15,6 +22,9 @@ type Config struct {
 	Database  DatabaseConfig  `mapstructure:"database"`
 	Scheduler SchedulerConfig `mapstructure:"scheduler"`
 	Metrics   MetricsConfig   `mapstructure:"metrics"`
+	API       APIConfig       `mapstructure:"api"`
+	Webhooks  WebhooksConfig  `mapstructure:"webhooks"`
+	Backup    BackupConfig    `mapstructure:"backup"`
 	LogLevel  string          `mapstructure:"log_level"`
 }
 
@@ -23,8 +33,67 @@ type DiscordConfig struct {
 	Token             string        `mapstructure:"token"`
 	GuildID           string        `mapstructure:"guild_id"`
 	ReviewChannelID   string        `mapstructure:"review_channel_id"` // Channel ID where commands are allowed
+	AdminRoleID       string        `mapstructure:"admin_role_id"`     // Role granted access to admin commands
 	CommandsTimeout   time.Duration `mapstructure:"commands_timeout"`
 	InteractionExpiry time.Duration `mapstructure:"interaction_expiry"`
+	// CommandCooldowns maps a command name to how long a user must wait between invocations
+	CommandCooldowns map[string]time.Duration `mapstructure:"command_cooldowns"`
+	// CommandPrefix is prepended to every registered command name so several bot instances can share a guild
+	CommandPrefix string `mapstructure:"command_prefix"`
+	// EmbedColor is the "#RRGGBB" accent color of informational embeds; empty keeps the default colors
+	EmbedColor string `mapstructure:"embed_color"`
+	// EmbedFooter is footer text added to informational embeds
+	EmbedFooter string `mapstructure:"embed_footer"`
+	// MessageTemplates customises the wording of the daily review reminder
+	MessageTemplates MessageTemplates `mapstructure:"message_templates"`
+	// Cache sizes the bot's in-memory caches
+	Cache CacheConfig `mapstructure:"cache"`
+}
+
+// CacheConfig holds settings for the bot's in-memory caches
+type CacheConfig struct {
+	// MaxSize caps the number of leaderboard pages kept, evicting the least recently used; 0 keeps
+	// every page until it expires
+	MaxSize int `mapstructure:"max_size"`
+}
+
+// MessageTemplates holds the text/template strings a daily review reminder is built from. Each is
+// executed with the reminded user's mention as .UserMention and the number of problems listed as
+// .ProblemCount; ReminderProblemLine also gets the problem as .Problem. .FormatDate writes a
+// date the way the guild's locale does.
+type MessageTemplates struct {
+	ReminderIntro       string `mapstructure:"reminder_intro"`        // First line of the reminder
+	ReminderProblemLine string `mapstructure:"reminder_problem_line"` // One line per problem
+	ReminderOutro       string `mapstructure:"reminder_outro"`        // Closing paragraph
+}
+
+// Default reminder message templates
+const (
+	DefaultReminderIntro       = `Hey {{.UserMention}}! Here are some problems you might want to review today:`
+	DefaultReminderProblemLine = `- {{.Problem.ProblemName}} (Solved: {{.FormatDate .Problem.SolvedAt}}){{if .Problem.Link}} - <{{.Problem.Link}}>{{end}}`
+	DefaultReminderOutro       = `Remember, consistent review helps reinforce your understanding! Press a problem's button once you've reviewed it.`
+)
+
+// AccentColor returns the configured embed accent color and whether one is set
+func (c DiscordConfig) AccentColor() (int, bool) {
+	if c.EmbedColor == "" {
+		return 0, false
+	}
+	color, err := ParseHexColor(c.EmbedColor)
+	return color, err == nil
+}
+
+// ParseHexColor parses a color written as "#RRGGBB" or "RRGGBB"
+func ParseHexColor(s string) (int, error) {
+	hex := strings.TrimPrefix(s, "#")
+	if len(hex) != 6 {
+		return 0, fmt.Errorf("color %q must be six hex digits like #0099FF", s)
+	}
+	color, err := strconv.ParseUint(hex, 16, 32)
+	if err != nil {
+		return 0, fmt.Errorf("color %q must be six hex digits like #0099FF", s)
+	}
+	return int(color), nil
 }
 
 // DatabaseConfig holds database configuration
@@ -36,6 +105,12 @@ type DatabaseConfig struct {
 	ConnMaxLife    time.Duration `mapstructure:"conn_max_life"`
 	QueryTimeout   time.Duration `mapstructure:"query_timeout"`
 	MigrationsPath string        `mapstructure:"migrations_path"`
+	// MaxTagsPerProblem caps how many distinct tags a single problem may carry
+	MaxTagsPerProblem int `mapstructure:"max_tags_per_problem"`
+	// StreakGraceDays is how many skipped days a profile streak survives; 0 keeps streaks strict
+	StreakGraceDays int `mapstructure:"streak_grace_days"`
+	// RecalculateStatsOnStartup reconciles every user's review counters with their review history at startup
+	RecalculateStatsOnStartup bool `mapstructure:"recalculate_stats_on_startup"`
 }
 
 // SchedulerConfig holds configuration for the scheduler
@@ -45,32 +120,160 @@ type SchedulerConfig struct {
 	RetryAttempts  int           `mapstructure:"retry_attempts"`
 	RetryDelay     time.Duration `mapstructure:"retry_delay"`
 	LookbackPeriod time.Duration `mapstructure:"lookback_period"`
+	// MaxProblemsPerReminder caps how many problems are listed per user in a single reminder
+	MaxProblemsPerReminder int `mapstructure:"max_problems_per_reminder"`
+	// PrioritizeByDifficulty lists harder and less confidently solved problems first
+	PrioritizeByDifficulty bool `mapstructure:"prioritize_by_difficulty"`
+	// ReviewChannels maps a lowercase category name to the channel its reminders are posted in
+	ReviewChannels map[string]string `mapstructure:"review_channels"`
+	// DefaultReviewChannel receives reminders for categories missing from ReviewChannels; empty uses ReviewChannel
+	DefaultReviewChannel string `mapstructure:"default_review_channel"`
+}
+
+// ReviewChannelFor returns the channel reminders for problems in category are posted in, or "" if none is configured
+func (c SchedulerConfig) ReviewChannelFor(category string) string {
+	if channel := c.ReviewChannels[strings.ToLower(category)]; channel != "" {
+		return channel
+	}
+	if c.DefaultReviewChannel != "" {
+		return c.DefaultReviewChannel
+	}
+	return c.ReviewChannel
+}
+
+// HasReviewChannel reports whether reminders have anywhere to be posted
+func (c SchedulerConfig) HasReviewChannel() bool {
+	return c.ReviewChannel != "" || c.DefaultReviewChannel != "" || len(c.ReviewChannels) > 0
 }
 
 // MetricsConfig holds configuration for metrics collection
 type MetricsConfig struct {
+	Enabled         bool          `mapstructure:"enabled"`
+	Address         string        `mapstructure:"address"`
+	RefreshInterval time.Duration `mapstructure:"refresh_interval"` // How often aggregate gauges are recomputed
+	FailOnError     bool          `mapstructure:"fail_on_error"`    // Exit if the metrics server can't start instead of running without it
+}
+
+// APIConfig holds configuration for the read-only HTTP API
+type APIConfig struct {
 	Enabled bool   `mapstructure:"enabled"`
 	Address string `mapstructure:"address"`
 }
 
+// WebhooksConfig holds configuration for outgoing webhook notifications
+type WebhooksConfig struct {
+	URLs          []string      `mapstructure:"urls"`           // Endpoints notified when a problem is logged
+	Secret        string        `mapstructure:"secret"`         // HMAC key used to sign payloads
+	Timeout       time.Duration `mapstructure:"timeout"`        // Per-request timeout
+	RetryAttempts int           `mapstructure:"retry_attempts"` // Total attempts per delivery
+	RetryDelay    time.Duration `mapstructure:"retry_delay"`    // Delay before the first retry, doubled each time
+}
+
+// BackupConfig holds configuration for scheduled database backups
+type BackupConfig struct {
+	Enabled         bool   `mapstructure:"enabled"`          // Run backups on Schedule
+	DestinationPath string `mapstructure:"destination_path"` // Backup file; {date} is replaced with the UTC timestamp
+	Schedule        string `mapstructure:"schedule"`         // Cron expression, in the server's local time
+}
+
+// Destination returns the backup file path for a backup taken at t
+func (c BackupConfig) Destination(t time.Time) string {
+	return strings.ReplaceAll(c.DestinationPath, "{date}", t.UTC().Format("20060102-150405"))
+}
+
+// exampleConfig is a complete config.yaml with every field set to its default
+const exampleConfig = `discord:
+  token: "" # Bot token; GRIND_REVIEW_DISCORD_TOKEN or DISCORD_BOT_TOKEN override it
+  guild_id: "" # Server the commands are registered in; empty registers them globally
+  review_channel_id: "" # Channel commands are allowed in; empty allows every channel
+  admin_role_id: "" # Role granted admin commands; server administrators always have access
+  commands_timeout: 2.5s # How long a command may run before the user is told it timed out; must be under 3s
+  interaction_expiry: 15m # How long buttons and modals stay usable
+  command_cooldowns: {} # Per-command cooldowns, e.g. { add: 10s }; /stats defaults to 60s and /random to 30s
+  command_prefix: "" # Prepended to command names, e.g. "grind-" registers /grind-add
+  embed_color: "" # Accent color of informational embeds, e.g. "#5865F2"
+  embed_footer: "" # Footer text shown on informational embeds
+  message_templates: # Go text/template strings for the daily review reminder
+    reminder_intro: 'Hey {{.UserMention}}! Here are some problems you might want to review today:'
+    reminder_problem_line: '- {{.Problem.ProblemName}} (Solved: {{.FormatDate .Problem.SolvedAt}}){{if .Problem.Link}} - <{{.Problem.Link}}>{{end}}'
+    reminder_outro: "Remember, consistent review helps reinforce your understanding! Press a problem's button once you've reviewed it."
+  cache:
+    max_size: 0 # Most leaderboard pages kept in memory, evicting the least recently used; 0 for no limit
+
+database:
+  driver: sqlite3 # Only sqlite3 is supported
+  dsn: grind_review.db # Overridden by GRIND_REVIEW_DATABASE_DSN
+  max_open_conns: 10 # Maximum open connections; must be at least max_idle_conns
+  max_idle_conns: 5 # Maximum idle connections kept in the pool
+  conn_max_life: 1h # How long a connection is reused before being closed
+  query_timeout: 30s # Timeout applied to each query
+  migrations_path: ./internal/database/migrations # Directory holding the SQL migrations
+  max_tags_per_problem: 10 # Most distinct tags a single problem may carry
+  streak_grace_days: 0 # Skipped days a streak survives; 0 means any missed day resets it
+  recalculate_stats_on_startup: false # Reconcile review counts with the review history at startup, like /admin-recalc
+
+scheduler:
+  review_time: "08:00" # Daily reminder time, HH:MM in the server's local time
+  review_channel: "" # Channel the daily reminders are posted in
+  retry_attempts: 3 # Attempts per reminder message
+  retry_delay: 2s # Delay between reminder attempts
+  lookback_period: 24h # How long ago a problem must have been solved to come up for review
+  max_problems_per_reminder: 5 # Problems listed per user in a single reminder
+  prioritize_by_difficulty: false # List harder and less confidently solved problems first
+  review_channels: {} # Per-category reminder channels, e.g. { "dynamic programming": "123", trees: "456" }
+  default_review_channel: "" # Channel for categories not in review_channels; empty uses review_channel
+
+metrics:
+  enabled: false # Serve Prometheus metrics
+  address: ":9090" # host:port the metrics server listens on
+  refresh_interval: 1m # How often aggregate gauges are recomputed
+  fail_on_error: false # Exit at startup if the metrics address can't be bound
+
+api:
+  enabled: false # Serve the read-only HTTP API
+  address: ":8081" # host:port the API listens on; must differ from metrics.address
+
+webhooks:
+  urls: [] # Endpoints POSTed the problem JSON whenever a problem is logged
+  secret: "" # Signs payloads in the X-Grind-Signature-256 header; required when urls is set
+  timeout: 5s # Per-request timeout
+  retry_attempts: 3 # Total attempts per delivery
+  retry_delay: 1s # Delay before the first retry, doubled each time
+
+backup:
+  enabled: false # Back up the database on schedule
+  destination_path: ./backups/grind_review-{date}.db # {date} becomes the UTC timestamp; also used by /admin-backup
+  schedule: "0 4 * * *" # Cron expression in the server's local time, daily at 04:00
+
+log_level: info # debug, info, warn or error
+`
+
+// ExampleConfig returns a commented config.yaml with every field set to its default value
+func ExampleConfig() string {
+	return exampleConfig
+}
+
+// DefaultPath is where Load reads the config file from
+const DefaultPath = "./config/config.yaml"
+
 // Load reads in config file and ENV variables if set
 func Load() (*Config, error) {
+	return LoadFile(DefaultPath)
+}
+
+// LoadFile reads the config file at path, applying defaults and ENV variables like Load
+func LoadFile(path string) (*Config, error) {
 	// Set defaults first
 	setDefaults()
 
-	fmt.Print("DISCORD_BOT_TOKEN = ", os.Getenv("DISCORD_BOT_TOKEN"))
-
-	// Read and expand config.yaml from ./config/
-	raw, err := os.ReadFile("./config/config.yaml")
+	raw, err := os.ReadFile(path)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read config.yaml: %w", err)
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
 	}
 
 	// Expand environment variables like ${DISCORD_BOT_TOKEN}
 	expanded := os.ExpandEnv(string(raw))
 
-	fmt.Println("Expanded YAML:\n", expanded)
-
 	// Load the expanded content into Viper
 	viper.SetConfigType("yaml")
 	if err := viper.ReadConfig(bytes.NewBufferString(expanded)); err != nil {
@@ -82,21 +285,151 @@ func Load() (*Config, error) {
 	if err := viper.Unmarshal(&config); err != nil {
 		return nil, fmt.Errorf("unable to decode config: %w", err)
 	}
-	fmt.Println(config)
+
+	// Viper lowercases YAML map keys, but normalise anyway so lookups by category are case-insensitive
+	// whatever the source
+	reviewChannels := make(map[string]string, len(config.Scheduler.ReviewChannels))
+	for category, channel := range config.Scheduler.ReviewChannels {
+		reviewChannels[strings.ToLower(category)] = channel
+	}
+	config.Scheduler.ReviewChannels = reviewChannels
+
+	// Environment overrides take precedence over the config file
+	applyEnvOverrides(&config)
 
 	// Validate
-	if config.Discord.Token == "" {
-		return nil, fmt.Errorf("Discord bot token is required")
+	if err := config.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid configuration: %w", err)
 	}
 
 	return &config, nil
 }
 
+// applyEnvOverrides replaces config values with explicitly set environment variables.
+// Prefixed GRIND_REVIEW_* variables win over the legacy unprefixed names.
+func applyEnvOverrides(config *Config) {
+	if token := firstEnv("GRIND_REVIEW_DISCORD_TOKEN", "DISCORD_BOT_TOKEN"); token != "" {
+		config.Discord.Token = token
+	}
+	if dsn := firstEnv("GRIND_REVIEW_DATABASE_DSN"); dsn != "" {
+		config.Database.DSN = dsn
+	}
+}
+
+// firstEnv returns the value of the first non-empty environment variable in keys
+func firstEnv(keys ...string) string {
+	for _, key := range keys {
+		if value := os.Getenv(key); value != "" {
+			return value
+		}
+	}
+	return ""
+}
+
+// supportedDrivers lists the database drivers the repository can open
+var supportedDrivers = map[string]bool{
+	"sqlite3": true,
+}
+
+// discordResponseWindow is how long Discord waits for an interaction to be answered
+const discordResponseWindow = 3 * time.Second
+
+// commandPrefixPattern matches the characters Discord allows in slash command names
+var commandPrefixPattern = regexp.MustCompile(`^[-_a-z0-9]*$`)
+
+// Validate checks required fields and the relationships between them, reporting every problem found
+func (c *Config) Validate() error {
+	var errs []error
+
+	if c.Discord.Token == "" {
+		errs = append(errs, errors.New("Discord bot token is required"))
+	}
+
+	// Discord fails interactions that aren't answered within 3 seconds, so the timeout reply must beat that
+	if c.Discord.CommandsTimeout >= discordResponseWindow {
+		errs = append(errs, fmt.Errorf("discord.commands_timeout must be under %s, got %s", discordResponseWindow, c.Discord.CommandsTimeout))
+	}
+	if !commandPrefixPattern.MatchString(c.Discord.CommandPrefix) {
+		errs = append(errs, fmt.Errorf("discord.command_prefix %q may only contain lowercase letters, digits, '-' and '_'", c.Discord.CommandPrefix))
+	}
+	if c.Discord.EmbedColor != "" {
+		if _, err := ParseHexColor(c.Discord.EmbedColor); err != nil {
+			errs = append(errs, fmt.Errorf("discord.embed_color: %w", err))
+		}
+	}
+
+	if c.Discord.Cache.MaxSize < 0 {
+		errs = append(errs, fmt.Errorf("discord.cache.max_size must not be negative, got %d", c.Discord.Cache.MaxSize))
+	}
+
+	for _, tmpl := range []struct{ name, text string }{
+		{"reminder_intro", c.Discord.MessageTemplates.ReminderIntro},
+		{"reminder_problem_line", c.Discord.MessageTemplates.ReminderProblemLine},
+		{"reminder_outro", c.Discord.MessageTemplates.ReminderOutro},
+	} {
+		if _, err := template.New(tmpl.name).Parse(tmpl.text); err != nil {
+			errs = append(errs, fmt.Errorf("discord.message_templates.%s: %w", tmpl.name, err))
+		}
+	}
+
+	if _, err := time.Parse("15:04", c.Scheduler.ReviewTime); err != nil {
+		errs = append(errs, fmt.Errorf("scheduler.review_time %q must be in HH:MM format", c.Scheduler.ReviewTime))
+	}
+	if c.Scheduler.LookbackPeriod <= 0 {
+		errs = append(errs, fmt.Errorf("scheduler.lookback_period must be positive, got %s", c.Scheduler.LookbackPeriod))
+	}
+
+	if !supportedDrivers[c.Database.Driver] {
+		errs = append(errs, fmt.Errorf("database.driver %q is not supported", c.Database.Driver))
+	}
+	if c.Database.MaxOpenConns < c.Database.MaxIdleConns {
+		errs = append(errs, fmt.Errorf("database.max_open_conns (%d) must be at least database.max_idle_conns (%d)", c.Database.MaxOpenConns, c.Database.MaxIdleConns))
+	}
+	if c.Database.MaxTagsPerProblem <= 0 {
+		errs = append(errs, fmt.Errorf("database.max_tags_per_problem must be positive, got %d", c.Database.MaxTagsPerProblem))
+	}
+	if c.Database.StreakGraceDays < 0 {
+		errs = append(errs, fmt.Errorf("database.streak_grace_days must not be negative, got %d", c.Database.StreakGraceDays))
+	}
+	if c.Backup.DestinationPath == "" {
+		errs = append(errs, fmt.Errorf("backup.destination_path is required"))
+	}
+	if c.Backup.Enabled && c.Backup.Schedule == "" {
+		errs = append(errs, fmt.Errorf("backup.schedule is required when backups are enabled"))
+	}
+
+	if _, _, err := net.SplitHostPort(c.Metrics.Address); err != nil {
+		errs = append(errs, fmt.Errorf("metrics.address %q must be a valid host:port: %w", c.Metrics.Address, err))
+	}
+	for _, webhookURL := range c.Webhooks.URLs {
+		if u, err := url.Parse(webhookURL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			errs = append(errs, fmt.Errorf("webhooks.urls entry %q must be an absolute http(s) URL", webhookURL))
+		}
+	}
+	if len(c.Webhooks.URLs) > 0 && c.Webhooks.Secret == "" {
+		errs = append(errs, errors.New("webhooks.secret is required when webhooks.urls is set"))
+	}
+
+	if c.API.Enabled {
+		if _, _, err := net.SplitHostPort(c.API.Address); err != nil {
+			errs = append(errs, fmt.Errorf("api.address %q must be a valid host:port: %w", c.API.Address, err))
+		} else if c.Metrics.Enabled && c.API.Address == c.Metrics.Address {
+			errs = append(errs, fmt.Errorf("api.address %q must differ from metrics.address", c.API.Address))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
 // setDefaults sets default values for configuration
 func setDefaults() {
 	// Discord defaults
-	viper.SetDefault("discord.commands_timeout", 5*time.Second)
+	viper.SetDefault("discord.commands_timeout", 2500*time.Millisecond)
 	viper.SetDefault("discord.interaction_expiry", 15*time.Minute)
+	viper.SetDefault("discord.message_templates.reminder_intro", DefaultReminderIntro)
+	viper.SetDefault("discord.message_templates.reminder_problem_line", DefaultReminderProblemLine)
+	viper.SetDefault("discord.message_templates.reminder_outro", DefaultReminderOutro)
+	viper.SetDefault("discord.cache.max_size", 0)
 
 	// Database defaults
 	viper.SetDefault("database.driver", "sqlite3")
@@ -106,16 +439,39 @@ func setDefaults() {
 	viper.SetDefault("database.conn_max_life", 1*time.Hour)
 	viper.SetDefault("database.query_timeout", 30*time.Second)
 	viper.SetDefault("database.migrations_path", "./internal/database/migrations")
+	viper.SetDefault("database.max_tags_per_problem", 10)
+	viper.SetDefault("database.streak_grace_days", 0)
+	viper.SetDefault("database.recalculate_stats_on_startup", false)
 
 	// Scheduler defaults
 	viper.SetDefault("scheduler.review_time", "08:00")
 	viper.SetDefault("scheduler.retry_attempts", 3)
 	viper.SetDefault("scheduler.retry_delay", 2*time.Second)
 	viper.SetDefault("scheduler.lookback_period", 24*time.Hour)
+	viper.SetDefault("scheduler.max_problems_per_reminder", 5)
+	viper.SetDefault("scheduler.prioritize_by_difficulty", false)
+	viper.SetDefault("scheduler.review_channels", map[string]string{})
+	viper.SetDefault("scheduler.default_review_channel", "")
 
 	// Metrics defaults
 	viper.SetDefault("metrics.enabled", false)
 	viper.SetDefault("metrics.address", ":9090")
+	viper.SetDefault("metrics.refresh_interval", 1*time.Minute)
+	viper.SetDefault("metrics.fail_on_error", false)
+
+	// API defaults
+	viper.SetDefault("api.enabled", false)
+	viper.SetDefault("api.address", ":8081")
+
+	// Webhook defaults
+	viper.SetDefault("webhooks.timeout", 5*time.Second)
+	viper.SetDefault("webhooks.retry_attempts", 3)
+	viper.SetDefault("webhooks.retry_delay", 1*time.Second)
+
+	// Backup defaults
+	viper.SetDefault("backup.enabled", false)
+	viper.SetDefault("backup.destination_path", "./backups/grind_review-{date}.db")
+	viper.SetDefault("backup.schedule", "0 4 * * *")
 
 	// Logging defaults
 	viper.SetDefault("log_level", "info")
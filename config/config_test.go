@@ -0,0 +1,313 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+// writeConfig writes content to a config file in a temporary directory and returns its path
+func writeConfig(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+	return path
+}
+
+// clearTokenEnv unsets the environment overrides for the rest of the test
+func clearTokenEnv(t *testing.T) {
+	t.Helper()
+	t.Setenv("GRIND_REVIEW_DISCORD_TOKEN", "")
+	t.Setenv("DISCORD_BOT_TOKEN", "")
+	t.Setenv("GRIND_REVIEW_DATABASE_DSN", "")
+}
+
+// chdirModuleRoot runs the rest of the test from the module root, where Load finds config.yaml
+func chdirModuleRoot(t *testing.T) {
+	t.Helper()
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("Getwd: %v", err)
+	}
+	if err := os.Chdir(".."); err != nil {
+		t.Fatalf("Chdir: %v", err)
+	}
+	t.Cleanup(func() { os.Chdir(wd) })
+}
+
+func TestLoad(t *testing.T) {
+	chdirModuleRoot(t)
+	clearTokenEnv(t)
+	t.Setenv("DISCORD_BOT_TOKEN", "env-token")
+	t.Setenv("DISCORD_CHANNEL_ID", "123")
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if cfg.Discord.Token != "env-token" {
+		t.Errorf("token = %q, want env-token", cfg.Discord.Token)
+	}
+	// ${DISCORD_CHANNEL_ID} is expanded wherever the file uses it
+	if cfg.Discord.ReviewChannelID != "123" || cfg.Scheduler.ReviewChannel != "123" {
+		t.Errorf("review channels = %q and %q, want 123", cfg.Discord.ReviewChannelID, cfg.Scheduler.ReviewChannel)
+	}
+}
+
+func TestLoadRequiresToken(t *testing.T) {
+	chdirModuleRoot(t)
+	clearTokenEnv(t)
+
+	_, err := Load()
+	if err == nil || !strings.Contains(err.Error(), "token is required") {
+		t.Errorf("Load = %v, want a missing token error", err)
+	}
+}
+
+func TestLoadFileEnvOverrides(t *testing.T) {
+	clearTokenEnv(t)
+	path := writeConfig(t, "discord:\n  token: file-token\ndatabase:\n  dsn: file.db\n")
+
+	cfg, err := LoadFile(path)
+	if err != nil {
+		t.Fatalf("LoadFile: %v", err)
+	}
+	if cfg.Discord.Token != "file-token" || cfg.Database.DSN != "file.db" {
+		t.Errorf("token %q and dsn %q, want the file's values", cfg.Discord.Token, cfg.Database.DSN)
+	}
+
+	t.Setenv("DISCORD_BOT_TOKEN", "legacy-token")
+	t.Setenv("GRIND_REVIEW_DISCORD_TOKEN", "prefixed-token")
+	t.Setenv("GRIND_REVIEW_DATABASE_DSN", "env.db")
+	cfg, err = LoadFile(path)
+	if err != nil {
+		t.Fatalf("LoadFile: %v", err)
+	}
+	if cfg.Discord.Token != "prefixed-token" {
+		t.Errorf("token = %q, want the GRIND_REVIEW_ variable to win", cfg.Discord.Token)
+	}
+	if cfg.Database.DSN != "env.db" {
+		t.Errorf("dsn = %q, want env.db", cfg.Database.DSN)
+	}
+}
+
+func TestLoadFile(t *testing.T) {
+	clearTokenEnv(t)
+	path := writeConfig(t, `
+discord:
+  token: file-token
+scheduler:
+  review_time: "09:30"
+  lookback_period: 72h
+  review_channels:
+    Graph: "456"
+log_level: debug
+`)
+
+	cfg, err := LoadFile(path)
+	if err != nil {
+		t.Fatalf("LoadFile: %v", err)
+	}
+	if cfg.LogLevel != "debug" {
+		t.Errorf("LogLevel = %q, want debug", cfg.LogLevel)
+	}
+	if cfg.Scheduler.ReviewTime != "09:30" || cfg.Scheduler.LookbackPeriod != 72*time.Hour {
+		t.Errorf("scheduler = %q every %s, want 09:30 every 72h", cfg.Scheduler.ReviewTime, cfg.Scheduler.LookbackPeriod)
+	}
+	if got := cfg.Scheduler.ReviewChannelFor("GRAPH"); got != "456" {
+		t.Errorf("ReviewChannelFor(GRAPH) = %q, want 456", got)
+	}
+
+	// Unset fields keep their defaults
+	if cfg.Database.Driver != "sqlite3" || cfg.Database.MaxOpenConns != 10 || cfg.Scheduler.RetryAttempts != 3 {
+		t.Errorf("defaults not applied: driver %q, %d connections, %d retries", cfg.Database.Driver, cfg.Database.MaxOpenConns, cfg.Scheduler.RetryAttempts)
+	}
+}
+
+func TestReviewChannelFor(t *testing.T) {
+	routed := SchedulerConfig{ReviewChannel: "review", DefaultReviewChannel: "default", ReviewChannels: map[string]string{"trees": "trees-practice"}}
+	tests := []struct {
+		name     string
+		config   SchedulerConfig
+		category string
+		want     string
+	}{
+		{name: "mapped category", config: routed, category: "Trees", want: "trees-practice"},
+		{name: "unmapped category", config: routed, category: "Graph", want: "default"},
+		{name: "no default channel", config: SchedulerConfig{ReviewChannel: "review", ReviewChannels: routed.ReviewChannels}, category: "Graph", want: "review"},
+		{name: "nothing configured", category: "Graph", want: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.config.ReviewChannelFor(tt.category); got != tt.want {
+				t.Errorf("ReviewChannelFor(%q) = %q, want %q", tt.category, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadFileInvalid(t *testing.T) {
+	clearTokenEnv(t)
+	tests := []struct {
+		name    string
+		content string
+		want    string
+	}{
+		{name: "malformed yaml", content: "discord:\n  token: [unclosed\n", want: "failed to parse"},
+		{name: "unsupported driver", content: "discord:\n  token: t\ndatabase:\n  driver: postgres\n", want: `database.driver "postgres" is not supported`},
+		{name: "bad review time", content: "discord:\n  token: t\nscheduler:\n  review_time: 9.30\n", want: "scheduler.review_time"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := LoadFile(writeConfig(t, tt.content))
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("LoadFile = %v, want an error containing %q", err, tt.want)
+			}
+		})
+	}
+
+	if _, err := LoadFile(filepath.Join(t.TempDir(), "missing.yaml")); err == nil {
+		t.Error("LoadFile succeeded without a file")
+	}
+}
+
+func TestExampleConfigLoads(t *testing.T) {
+	clearTokenEnv(t)
+	t.Setenv("DISCORD_BOT_TOKEN", "env-token")
+
+	if _, err := LoadFile(writeConfig(t, ExampleConfig())); err != nil {
+		t.Errorf("the example config doesn't load: %v", err)
+	}
+}
+
+// configKeys returns the dotted mapstructure key of every leaf field of t, a struct type
+func configKeys(t reflect.Type, prefix string) []string {
+	var keys []string
+	for idx := range t.NumField() {
+		field := t.Field(idx)
+		key := prefix + field.Tag.Get("mapstructure")
+		if field.Type.Kind() == reflect.Struct {
+			keys = append(keys, configKeys(field.Type, key+".")...)
+			continue
+		}
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+func TestExampleConfigMatchesConfig(t *testing.T) {
+	// A fresh instance without defaults, so only keys the example sets are present
+	v := viper.New()
+	v.SetConfigType("yaml")
+	if err := v.ReadConfig(strings.NewReader(ExampleConfig())); err != nil {
+		t.Fatalf("ReadConfig: %v", err)
+	}
+
+	var cfg Config
+	if err := v.UnmarshalExact(&cfg); err != nil {
+		t.Errorf("the example config has keys Config doesn't: %v", err)
+	}
+	for _, key := range configKeys(reflect.TypeOf(Config{}), "") {
+		if !v.IsSet(key) {
+			t.Errorf("the example config is missing %s", key)
+		}
+	}
+}
+
+// validConfig loads the example config, which passes Validate, for a test to break
+func validConfig(t *testing.T) *Config {
+	t.Helper()
+	clearTokenEnv(t)
+	t.Setenv("DISCORD_BOT_TOKEN", "env-token")
+	cfg, err := LoadFile(writeConfig(t, ExampleConfig()))
+	if err != nil {
+		t.Fatalf("LoadFile: %v", err)
+	}
+	return cfg
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(*Config)
+		want   string
+	}{
+		{name: "missing token", modify: func(c *Config) { c.Discord.Token = "" }, want: "token is required"},
+		{name: "review time without minutes", modify: func(c *Config) { c.Scheduler.ReviewTime = "9" }, want: "scheduler.review_time"},
+		{name: "review time out of range", modify: func(c *Config) { c.Scheduler.ReviewTime = "25:00" }, want: "scheduler.review_time"},
+		{name: "unsupported driver", modify: func(c *Config) { c.Database.Driver = "postgres" }, want: "database.driver"},
+		{name: "more idle than open connections", modify: func(c *Config) { c.Database.MaxOpenConns, c.Database.MaxIdleConns = 2, 5 }, want: "database.max_open_conns (2)"},
+		{name: "metrics address without port", modify: func(c *Config) { c.Metrics.Address = "localhost" }, want: "metrics.address"},
+		{name: "zero lookback", modify: func(c *Config) { c.Scheduler.LookbackPeriod = 0 }, want: "scheduler.lookback_period"},
+		{name: "negative lookback", modify: func(c *Config) { c.Scheduler.LookbackPeriod = -time.Hour }, want: "scheduler.lookback_period"},
+		{name: "invalid embed color", modify: func(c *Config) { c.Discord.EmbedColor = "#12345G" }, want: "discord.embed_color"},
+		{name: "timeout past the response window", modify: func(c *Config) { c.Discord.CommandsTimeout = 5 * time.Second }, want: "discord.commands_timeout"},
+		{name: "unparseable reminder template", modify: func(c *Config) { c.Discord.MessageTemplates.ReminderProblemLine = "{{.Problem.ProblemName" }, want: "discord.message_templates.reminder_problem_line"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := validConfig(t)
+			tt.modify(cfg)
+			err := cfg.Validate()
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Validate = %v, want an error containing %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateReportsEveryProblem(t *testing.T) {
+	cfg := validConfig(t)
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("the example config is invalid: %v", err)
+	}
+
+	cfg.Discord.Token = ""
+	cfg.Database.Driver = "postgres"
+	cfg.Metrics.Address = "nowhere"
+	err := cfg.Validate()
+	for _, want := range []string{"token is required", "database.driver", "metrics.address"} {
+		if err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("Validate = %v, want it to include %q", err, want)
+		}
+	}
+}
+
+func TestParseHexColor(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    int
+		wantErr bool
+	}{
+		{input: "#5865F2", want: 0x5865F2},
+		{input: "5865f2", want: 0x5865F2},
+		{input: "#000000", want: 0},
+		{input: "#FFFFFF", want: 0xFFFFFF},
+		{input: "", wantErr: true},
+		{input: "#FFF", wantErr: true},
+		{input: "#12345G", wantErr: true},
+		{input: "##5865F2", wantErr: true},
+		{input: "0x5865F2", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := ParseHexColor(tt.input)
+			if tt.wantErr {
+				if err == nil || !strings.Contains(err.Error(), "six hex digits") {
+					t.Errorf("ParseHexColor(%q) = %#06x, %v, want an invalid color error", tt.input, got, err)
+				}
+				return
+			}
+			if err != nil || got != tt.want {
+				t.Errorf("ParseHexColor(%q) = %#06x, %v, want %#06x", tt.input, got, err, tt.want)
+			}
+		})
+	}
+}
@@ -16,6 +16,7 @@ require (
 
 require (
 	github.com/golang-migrate/migrate/v4 v4.18.2
+	golang.org/x/sync v0.12.0
 	gorm.io/driver/sqlite v1.5.7
 	gorm.io/gorm v1.25.12
 )
@@ -0,0 +1,59 @@
+package api
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/rs/zerolog/log"
+)
+
+// tokenBytes is the amount of randomness in a generated API token
+const tokenBytes = 32
+
+// NewToken generates a random API token and the hash to store for it
+func NewToken() (token, hash string, err error) {
+	buf := make([]byte, tokenBytes)
+	if _, err := rand.Read(buf); err != nil {
+		return "", "", fmt.Errorf("failed to generate API token: %w", err)
+	}
+	token = hex.EncodeToString(buf)
+	return token, HashToken(token), nil
+}
+
+// HashToken returns the hex-encoded SHA-256 hash under which a token is stored
+func HashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// authenticate only lets a request through when it carries the bearer token of the user in its path
+func (s *Server) authenticate(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok || token == "" {
+			writeError(w, http.StatusUnauthorized, "missing bearer token")
+			return
+		}
+
+		userID := r.PathValue("id")
+		settings, err := s.store.GetUserSettings(r.Context(), userID)
+		if err != nil {
+			log.Error().Err(err).Str("user_id", userID).Msg("Failed to load API token")
+			writeError(w, http.StatusInternalServerError, "internal error")
+			return
+		}
+
+		// Users without a token can't be accessed; compare in constant time to avoid leaking the hash
+		if settings.APITokenHash == "" || subtle.ConstantTimeCompare([]byte(settings.APITokenHash), []byte(HashToken(token))) != 1 {
+			writeError(w, http.StatusUnauthorized, "invalid token")
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,85 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/rs/zerolog/log"
+)
+
+// Pagination bounds for the problems endpoint
+const (
+	defaultProblemLimit = 50
+	maxProblemLimit     = 200
+)
+
+// handleListProblems serves GET /users/{id}/problems, filtered by the optional status,
+// difficulty, category and tag query parameters and paged with limit and offset.
+// Archived problems are only included when include_archived=true.
+func (s *Server) handleListProblems(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	limit, err := intParam(query.Get("limit"), defaultProblemLimit)
+	if err != nil || limit < 1 || limit > maxProblemLimit {
+		writeError(w, http.StatusBadRequest, "limit must be between 1 and "+strconv.Itoa(maxProblemLimit))
+		return
+	}
+	offset, err := intParam(query.Get("offset"), 0)
+	if err != nil || offset < 0 {
+		writeError(w, http.StatusBadRequest, "offset must be a non-negative integer")
+		return
+	}
+
+	includeArchived := false
+	if value := query.Get("include_archived"); value != "" {
+		if includeArchived, err = strconv.ParseBool(value); err != nil {
+			writeError(w, http.StatusBadRequest, "include_archived must be a boolean")
+			return
+		}
+	}
+
+	problems, err := s.store.ListProblems(r.Context(), r.PathValue("id"),
+		query.Get("status"), query.Get("difficulty"), query.Get("category"), query["tag"], includeArchived, limit, offset)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to list problems for API")
+		writeError(w, http.StatusInternalServerError, "internal error")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, problems)
+}
+
+// handleStats serves GET /users/{id}/stats
+func (s *Server) handleStats(w http.ResponseWriter, r *http.Request) {
+	stats, err := s.store.GetUserStats(r.Context(), r.PathValue("id"))
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to get stats for API")
+		writeError(w, http.StatusInternalServerError, "internal error")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, stats)
+}
+
+// intParam parses an optional integer query parameter
+func intParam(value string, fallback int) (int, error) {
+	if value == "" {
+		return fallback, nil
+	}
+	return strconv.Atoi(value)
+}
+
+// writeJSON writes v as a JSON response body
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		log.Error().Err(err).Msg("Failed to write API response")
+	}
+}
+
+// writeError writes a JSON error body
+func writeError(w http.ResponseWriter, status int, message string) {
+	writeJSON(w, status, map[string]string{"error": message})
+}
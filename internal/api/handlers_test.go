@@ -0,0 +1,152 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+
+	"github.com/yugonline/grind_review_bot/config"
+	"github.com/yugonline/grind_review_bot/internal/database"
+)
+
+// stubStore serves fixed data and records the filters ListProblems was called with
+type stubStore struct {
+	tokenHashes map[string]string
+	problems    []*database.ProblemEntry
+	stats       *database.UserStats
+	err         error
+
+	listed struct {
+		userID, status, difficulty, category string
+		tags                                 []string
+		includeArchived                      bool
+		limit, offset                        int
+	}
+}
+
+func (s *stubStore) ListProblems(ctx context.Context, userID, status, difficulty, category string, tagNames []string, includeArchived bool, limit, offset int, scopes ...database.ProblemScope) ([]*database.ProblemEntry, error) {
+	s.listed.userID, s.listed.status, s.listed.difficulty, s.listed.category = userID, status, difficulty, category
+	s.listed.tags, s.listed.includeArchived, s.listed.limit, s.listed.offset = tagNames, includeArchived, limit, offset
+	return s.problems, s.err
+}
+
+func (s *stubStore) GetUserStats(ctx context.Context, userID string) (*database.UserStats, error) {
+	return s.stats, s.err
+}
+
+func (s *stubStore) GetUserSettings(ctx context.Context, userID string) (*database.UserSettings, error) {
+	return &database.UserSettings{UserID: userID, APITokenHash: s.tokenHashes[userID]}, nil
+}
+
+// newStubServer returns an API server over a store where user-1's token is "secret"
+func newStubServer() (*Server, *stubStore) {
+	store := &stubStore{
+		tokenHashes: map[string]string{"user-1": HashToken("secret")},
+		problems:    []*database.ProblemEntry{{ID: 1, UserID: "user-1", ProblemName: "Two Sum", Difficulty: database.DifficultyEasy}},
+		stats:       &database.UserStats{UserID: "user-1", TotalProblems: 1, Easy: 1},
+	}
+	return New(config.APIConfig{Address: "127.0.0.1:0"}, store), store
+}
+
+// get performs a GET request against the server's routes, with a bearer token if one is given
+func get(server *Server, path, token string) *httptest.ResponseRecorder {
+	return request(server, http.MethodGet, path, token)
+}
+
+// request performs a request against the server's routes, with a bearer token if one is given
+func request(server *Server, method, path, token string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, nil)
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	rec := httptest.NewRecorder()
+	server.routes().ServeHTTP(rec, req)
+	return rec
+}
+
+func TestAuthentication(t *testing.T) {
+	server, _ := newStubServer()
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		token  string
+		status int
+	}{
+		{name: "own token", method: http.MethodGet, path: "/users/user-1/problems", token: "secret", status: http.StatusOK},
+		{name: "missing token", method: http.MethodGet, path: "/users/user-1/problems", status: http.StatusUnauthorized},
+		{name: "wrong token", method: http.MethodGet, path: "/users/user-1/stats", token: "guess", status: http.StatusUnauthorized},
+		{name: "another user's data", method: http.MethodGet, path: "/users/user-2/problems", token: "secret", status: http.StatusUnauthorized},
+		{name: "writes not allowed", method: http.MethodPost, path: "/users/user-1/problems", token: "secret", status: http.StatusMethodNotAllowed},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if rec := request(server, tt.method, tt.path, tt.token); rec.Code != tt.status {
+				t.Errorf("status = %d, want %d", rec.Code, tt.status)
+			}
+		})
+	}
+}
+
+func TestHandleListProblems(t *testing.T) {
+	server, store := newStubServer()
+
+	rec := get(server, "/users/user-1/problems?status=Stuck&difficulty=Hard&category=Graph&tag=bfs&tag=dfs&include_archived=true&limit=10&offset=20", "secret")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200: %s", rec.Code, rec.Body)
+	}
+	if ct := rec.Header().Get("Content-Type"); ct != "application/json" {
+		t.Errorf("Content-Type = %q, want application/json", ct)
+	}
+	var problems []database.ProblemEntry
+	if err := json.NewDecoder(rec.Body).Decode(&problems); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if len(problems) != 1 || problems[0].ProblemName != "Two Sum" {
+		t.Errorf("problems = %+v, want Two Sum", problems)
+	}
+
+	listed := store.listed
+	if listed.userID != "user-1" || listed.status != "Stuck" || listed.difficulty != "Hard" || listed.category != "Graph" ||
+		!slices.Equal(listed.tags, []string{"bfs", "dfs"}) || !listed.includeArchived || listed.limit != 10 || listed.offset != 20 {
+		t.Errorf("ListProblems called with %+v", listed)
+	}
+
+	get(server, "/users/user-1/problems", "secret")
+	if store.listed.limit != defaultProblemLimit || store.listed.offset != 0 || store.listed.includeArchived {
+		t.Errorf("defaults = %+v, want limit %d, offset 0, no archived", store.listed, defaultProblemLimit)
+	}
+}
+
+func TestHandleListProblemsBadParameters(t *testing.T) {
+	server, _ := newStubServer()
+	for _, query := range []string{"limit=0", "limit=201", "limit=ten", "offset=-1", "include_archived=maybe"} {
+		if rec := get(server, "/users/user-1/problems?"+query, "secret"); rec.Code != http.StatusBadRequest {
+			t.Errorf("%s: status = %d, want 400", query, rec.Code)
+		}
+	}
+}
+
+func TestHandleStats(t *testing.T) {
+	server, store := newStubServer()
+
+	rec := get(server, "/users/user-1/stats", "secret")
+	var stats database.UserStats
+	if err := json.NewDecoder(rec.Body).Decode(&stats); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if rec.Code != http.StatusOK || stats.TotalProblems != 1 || stats.Easy != 1 {
+		t.Errorf("GET stats = %d %+v, want 200 with the user's stats", rec.Code, stats)
+	}
+
+	// Store failures aren't leaked to the client
+	store.err = errors.New("database is locked")
+	rec = get(server, "/users/user-1/stats", "secret")
+	if rec.Code != http.StatusInternalServerError || rec.Body.String() != "{\"error\":\"internal error\"}\n" {
+		t.Errorf("GET stats = %d %q, want a generic 500", rec.Code, rec.Body)
+	}
+}
@@ -0,0 +1,69 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"github.com/yugonline/grind_review_bot/config"
+	"github.com/yugonline/grind_review_bot/internal/database"
+)
+
+// Store provides the read-only data served by the API
+type Store interface {
+	ListProblems(ctx context.Context, userID, status, difficulty, category string, tagNames []string, includeArchived bool, limit, offset int, scopes ...database.ProblemScope) ([]*database.ProblemEntry, error)
+	GetUserStats(ctx context.Context, userID string) (*database.UserStats, error)
+	GetUserSettings(ctx context.Context, userID string) (*database.UserSettings, error)
+}
+
+// Server represents the HTTP API server
+type Server struct {
+	httpServer *http.Server
+	store      Store
+	config     config.APIConfig
+}
+
+// New creates a new API server
+func New(cfg config.APIConfig, store Store) *Server {
+	s := &Server{
+		store:  store,
+		config: cfg,
+	}
+
+	s.httpServer = &http.Server{
+		Addr:              cfg.Address,
+		Handler:           s.routes(),
+		ReadHeaderTimeout: 5 * time.Second,
+	}
+	return s
+}
+
+// routes registers the API endpoints; every endpoint requires the user's own token
+func (s *Server) routes() http.Handler {
+	mux := http.NewServeMux()
+	mux.Handle("GET /users/{id}/problems", s.authenticate(http.HandlerFunc(s.handleListProblems)))
+	mux.Handle("GET /users/{id}/stats", s.authenticate(http.HandlerFunc(s.handleStats)))
+	return mux
+}
+
+// Start starts the API server
+func (s *Server) Start() error {
+	log.Info().Str("address", s.config.Address).Msg("Starting API server")
+	if err := s.httpServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+		return fmt.Errorf("API server failed: %w", err)
+	}
+	return nil
+}
+
+// Stop stops the API server gracefully
+func (s *Server) Stop(ctx context.Context) error {
+	log.Info().Msg("Stopping API server")
+	shutdownCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+	if err := s.httpServer.Shutdown(shutdownCtx); err != nil {
+		return fmt.Errorf("API server shutdown failed: %w", err)
+	}
+	return nil
+}
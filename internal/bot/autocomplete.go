@@ -0,0 +1,79 @@
+package bot
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/rs/zerolog"
+)
+
+// maxAutocompleteChoices is the most choices Discord accepts in an autocomplete response
+const maxAutocompleteChoices = 25
+
+// autocompleteHandler returns the choices for a focused option given what the user has typed so far
+type autocompleteHandler func(ctx context.Context, i *discordgo.InteractionCreate, partial string) ([]*discordgo.ApplicationCommandOptionChoice, error)
+
+// autocompleteHandlers maps option names to the handler that completes them on every command
+func (b *Bot) autocompleteHandlers() map[string]autocompleteHandler {
+	return map[string]autocompleteHandler{
+		"id":         b.problemIDChoices,
+		"problem_id": b.problemIDChoices,
+	}
+}
+
+// handleAutocomplete answers an autocomplete request for the focused option. Failures are
+// logged and answered with no choices, since autocomplete can't show an error message.
+func (b *Bot) handleAutocomplete(s DiscordSession, i *discordgo.InteractionCreate) {
+	cmdName := b.commandKey(i)
+	ctx := withInteractionLogger(context.Background(), i, cmdName)
+	logger := zerolog.Ctx(ctx)
+
+	choices := []*discordgo.ApplicationCommandOptionChoice{}
+	if focused := focusedOption(i); focused != nil && !isDirectMessage(i) {
+		if handler, ok := b.autocompleteHandlers()[focused.Name]; ok {
+			found, err := handler(ctx, i, fmt.Sprint(focused.Value))
+			if err != nil {
+				logger.Error().Err(err).Str("option", focused.Name).Msg("Failed to build autocomplete choices")
+			} else {
+				choices = found
+			}
+		}
+	}
+
+	err := s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionApplicationCommandAutocompleteResult,
+		Data: &discordgo.InteractionResponseData{Choices: choices},
+	})
+	if err != nil {
+		logger.Error().Err(err).Msg("Failed to respond to autocomplete")
+	}
+}
+
+// problemIDChoices suggests the user's most recent problems matching the partial name or ID
+func (b *Bot) problemIDChoices(ctx context.Context, i *discordgo.InteractionCreate, partial string) ([]*discordgo.ApplicationCommandOptionChoice, error) {
+	problems, err := b.repo.SearchProblemsByPartialName(ctx, interactionUserID(i), partial, maxAutocompleteChoices)
+	if err != nil {
+		return nil, err
+	}
+
+	choices := make([]*discordgo.ApplicationCommandOptionChoice, len(problems))
+	for idx, p := range problems {
+		choices[idx] = &discordgo.ApplicationCommandOptionChoice{
+			Name:  truncateString(fmt.Sprintf("%d: %s (%s)", p.ID, p.ProblemName, p.Difficulty), 100),
+			Value: p.ID,
+		}
+	}
+	return choices, nil
+}
+
+// focusedOption returns the option the user is currently typing in, searching subcommands too
+func focusedOption(i *discordgo.InteractionCreate) *discordgo.ApplicationCommandInteractionDataOption {
+	_, options := getSubcommand(i)
+	for _, opt := range options {
+		if opt.Focused {
+			return opt
+		}
+	}
+	return nil
+}
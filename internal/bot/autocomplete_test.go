@@ -0,0 +1,82 @@
+package bot
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/yugonline/grind_review_bot/config"
+	"github.com/yugonline/grind_review_bot/internal/database"
+)
+
+// autocompleteInteraction builds an autocomplete request from testUserID with option focused
+func autocompleteInteraction(command string, option *discordgo.ApplicationCommandInteractionDataOption) *discordgo.InteractionCreate {
+	option.Focused = true
+	i := commandInteraction(command, option)
+	i.Type = discordgo.InteractionApplicationCommandAutocomplete
+	return i
+}
+
+// choiceNames returns the labels of an autocomplete response's choices
+func choiceNames(resp *discordgo.InteractionResponse) []string {
+	names := make([]string, len(resp.Data.Choices))
+	for idx, choice := range resp.Data.Choices {
+		names[idx] = choice.Name
+	}
+	return names
+}
+
+func TestProblemIDAutocomplete(t *testing.T) {
+	bot, session := newTestBot(t, config.DiscordConfig{})
+	var entries []*database.ProblemEntry
+	for idx, name := range []string{"Two Sum", "Coin Change", "Two Sum II"} {
+		entry := &database.ProblemEntry{UserID: testUserID, ProblemName: name, Difficulty: database.DifficultyEasy, Category: "Array", Status: database.StatusSolved, SolvedAt: time.Date(2024, time.January, 1+idx, 12, 0, 0, 0, time.UTC)}
+		if err := bot.repo.CreateProblem(context.Background(), entry); err != nil {
+			t.Fatalf("CreateProblem: %v", err)
+		}
+		entries = append(entries, entry)
+	}
+	addOtherUserProblem(t, bot, "user-2", "Two Sum III")
+
+	resp := dispatch(t, bot, session, autocompleteInteraction("get", stringOption("id", "two")))
+	if resp.Type != discordgo.InteractionApplicationCommandAutocompleteResult {
+		t.Fatalf("response type = %v, want an autocomplete result", resp.Type)
+	}
+	// Most recently solved first, and only the user's own problems
+	want := []string{
+		"3: Two Sum II (Easy)",
+		"1: Two Sum (Easy)",
+	}
+	if got := choiceNames(resp); !slices.Equal(got, want) {
+		t.Errorf("choices = %v, want %v", got, want)
+	}
+	if value := resp.Data.Choices[0].Value; value != entries[2].ID {
+		t.Errorf("first choice value = %v, want %d", value, entries[2].ID)
+	}
+
+	// Every problem-ID option completes, whatever the command
+	resp = dispatch(t, bot, session, autocompleteInteraction("mark-reviewed", stringOption("id", "")))
+	if got := choiceNames(resp); len(got) != 3 || got[0] != "3: Two Sum II (Easy)" {
+		t.Errorf("choices = %v, want all three problems, newest first", got)
+	}
+}
+
+func TestAutocompleteCapsChoices(t *testing.T) {
+	bot, session := newTestBot(t, config.DiscordConfig{})
+	for range maxAutocompleteChoices + 5 {
+		addTestProblem(t, bot, "Two Sum", database.DifficultyEasy)
+	}
+
+	resp := dispatch(t, bot, session, autocompleteInteraction("get", stringOption("id", "sum")))
+	if len(resp.Data.Choices) != maxAutocompleteChoices {
+		t.Errorf("got %d choices, want %d", len(resp.Data.Choices), maxAutocompleteChoices)
+	}
+
+	// Options without a completer get an empty list rather than an error
+	resp = dispatch(t, bot, session, autocompleteInteraction("list", stringOption("category", "arr")))
+	if resp.Data.Choices == nil || len(resp.Data.Choices) != 0 {
+		t.Errorf("choices = %v, want an empty list", resp.Data.Choices)
+	}
+}
@@ -2,25 +2,63 @@ package bot
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/bwmarrin/discordgo"
+	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/log"
 	"github.com/yugonline/grind_review_bot/config"
 	"github.com/yugonline/grind_review_bot/internal/database"
+	"github.com/yugonline/grind_review_bot/internal/metrics"
+	"github.com/yugonline/grind_review_bot/internal/webhook"
+	"github.com/yugonline/grind_review_bot/pkg/retry"
+)
+
+// Retry policy for connecting to the Discord gateway
+const (
+	sessionOpenRetries = 5
+	sessionOpenDelay   = 2 * time.Second
+	reconnectRetries   = 10
+	reconnectMaxDelay  = 30 * time.Second
 )
 
 // Bot represents the Discord bot
 type Bot struct {
-	session         *discordgo.Session
-	repo            *database.Repository
+	session         DiscordSession
+	applicationID   string // The bot user's ID, known once the session is open
+	repo            database.RepositoryInterface
 	cfg             config.DiscordConfig
-	reviewChannelID string // ID of the channel where commands are allowed
-	commandHandlers map[string]func(s *discordgo.Session, i *discordgo.InteractionCreate) (*discordgo.InteractionResponse, error)
+	reviewChannelID string           // ID of the channel where commands are allowed
+	cooldowns       *CooldownManager // Tracks when each user may next invoke a rate-limited command
+	permissions     PermissionChecker
+	state           *InteractionState         // Carries data from a command to the modal or button that follows it
+	scheduler       atomic.Pointer[Scheduler] // Set once the review scheduler starts, while handlers may be running
+	backup          config.BackupConfig
+	webhooks        *webhook.Notifier
+	leaderboard     *LeaderboardCache
+	commands        []*discordgo.ApplicationCommand
+	commandHandlers map[string]CommandHandler
+	syncMu          sync.Mutex      // Serialises command syncs
+	ownedCommands   map[string]bool // Names of the commands this bot has registered, guarded by syncMu
+	seededGuilds    sync.Map        // Guild IDs whose built-in problem sets exist
+
+	ctx          context.Context    // Lives until Shutdown, bounds reconnection attempts
+	stop         context.CancelFunc // Cancels ctx so closing the session doesn't trigger a reconnect
+	reconnecting atomic.Bool        // Set while a reconnection loop is running
+	started      atomic.Bool        // Set once Start has registered the commands
 }
 
 // New creates a new Discord bot instance
-func New(ctx context.Context, cfg config.DiscordConfig, repo *database.Repository) (*Bot, error) {
+func New(ctx context.Context, cfg config.DiscordConfig, repo *database.Repository, webhooks *webhook.Notifier) (*Bot, error) {
+	if strings.TrimSpace(cfg.Token) == "" {
+		return nil, errors.New("discord bot token is empty")
+	}
+
 	// Create Discord session
 	session, err := discordgo.New("Bot " + cfg.Token)
 	if err != nil {
@@ -33,52 +71,205 @@ func New(ctx context.Context, cfg config.DiscordConfig, repo *database.Repositor
 		repo:            repo,
 		cfg:             cfg,
 		reviewChannelID: cfg.ReviewChannelID,
+		cooldowns:       NewCooldownManager(),
+		state:           NewInteractionState(),
+		permissions:     DiscordPermissionChecker{AdminRoleID: cfg.AdminRoleID},
+		webhooks:        webhooks,
+		leaderboard:     NewLeaderboardCache(cfg.Cache.MaxSize),
 	}
+	bot.ctx, bot.stop = context.WithCancel(ctx)
+	repo.SetCacheInvalidator(bot.leaderboard)
 
 	// Register command handlers
 	bot.registerCommandHandlers()
 
 	// Add handlers for Discord events
-	session.AddHandler(bot.interactionCreate)
-	session.AddHandler(func(s *discordgo.Session, r *discordgo.Ready) {
-		log.Info().Str("username", s.State.User.Username).Str("id", s.State.User.ID).Msg("Bot is ready")
+	session.AddHandler(func(s *discordgo.Session, i *discordgo.InteractionCreate) {
+		bot.interactionCreate(s, i)
 	})
+	session.AddHandler(bot.ready)
+	session.AddHandler(bot.disconnected)
+	session.AddHandler(bot.resumed)
 
 	// Identify with intents
 	session.Identify.Intents = discordgo.IntentsGuildMessages | discordgo.IntentsGuilds | discordgo.IntentsGuildMembers
 
+	// disconnected reconnects with backoff; discordgo's own reconnect would race it
+	session.ShouldReconnectOnError = false
+
 	return bot, nil
 }
 
+// SetBackupConfig sets where /admin-backup writes database backups
+func (b *Bot) SetBackupConfig(cfg config.BackupConfig) {
+	b.backup = cfg
+}
+
 // Start starts the Discord bot
 func (b *Bot) Start(ctx context.Context) error {
-	// Connect to Discord
-	if err := b.session.Open(); err != nil {
+	// Connect to Discord, riding out brief gateway outages
+	err := retry.WithBackoff(ctx, sessionOpenRetries, sessionOpenDelay, func() error {
+		err := b.session.Open()
+		if err != nil {
+			log.Warn().Err(err).Msg("Failed to connect to Discord, retrying")
+		}
+		return err
+	})
+	if err != nil {
 		return fmt.Errorf("failed to connect to Discord: %w", err)
 	}
 
-	// Register slash commands
-	if err := b.registerCommands(); err != nil {
+	// Commands are registered under the bot user's ID
+	me, err := b.session.User("@me")
+	if err != nil {
+		b.session.Close()
+		return fmt.Errorf("failed to get bot user: %w", err)
+	}
+	b.applicationID = me.ID
+
+	// Catch misconfigured IDs now rather than on the first command
+	if err := b.validateConfig(); err != nil {
+		b.session.Close()
+		return err
+	}
+
+	// Register slash commands, changing only what differs from the last run
+	if err := b.syncCommands(); err != nil {
 		return fmt.Errorf("failed to register commands: %w", err)
 	}
+	b.started.Store(true)
+
+	return nil
+}
+
+// disconnected reopens the gateway connection after Discord drops it
+func (b *Bot) disconnected(_ *discordgo.Session, _ *discordgo.Disconnect) {
+	if b.ctx.Err() != nil {
+		return
+	}
+	log.Error().Msg("Disconnected from Discord")
+
+	// discordgo fires one event per dropped connection, so only one loop should be running
+	if !b.reconnecting.CompareAndSwap(false, true) {
+		return
+	}
+	go func() {
+		defer b.reconnecting.Store(false)
+
+		err := retry.WithCappedBackoff(b.ctx, reconnectRetries, sessionOpenDelay, reconnectMaxDelay, func() error {
+			err := b.session.Open()
+			if errors.Is(err, discordgo.ErrWSAlreadyOpen) {
+				return nil
+			}
+			if err != nil {
+				log.Warn().Err(err).Msg("Failed to reconnect to Discord, retrying")
+			}
+			return err
+		})
+		if err != nil && b.ctx.Err() == nil {
+			log.Error().Err(err).Msg("Giving up reconnecting to Discord")
+			return
+		}
+		if err == nil {
+			log.Info().Msg("Reconnected to Discord")
+		}
+	}()
+}
+
+// ready re-checks the bot's commands when Discord starts a new session, which happens when a
+// dropped session couldn't be resumed. Start handles the first one.
+func (b *Bot) ready(s *discordgo.Session, r *discordgo.Ready) {
+	log.Info().Str("username", r.User.Username).Str("id", r.User.ID).Msg("Bot is ready")
+	if b.started.Load() {
+		b.reconcileCommands()
+	}
+}
+
+// resumed logs that discordgo resumed a dropped gateway session. Nothing was missed, so the
+// commands aren't re-checked.
+func (b *Bot) resumed(_ *discordgo.Session, _ *discordgo.Resumed) {
+	log.Info().Msg("Resumed Discord session")
+}
+
+// reconcileCommands re-registers any of the bot's commands that Discord no longer has or has
+// out of date, for example because they were changed while the bot was disconnected
+func (b *Bot) reconcileCommands() {
+	if err := b.syncCommands(); err != nil {
+		log.Error().Err(err).Msg("Failed to restore commands after reconnecting")
+	}
+}
+
+// validateConfig checks that the configured guild and review channel exist and are usable.
+// It needs an open session, so it runs from Start rather than New.
+func (b *Bot) validateConfig() error {
+	if b.cfg.GuildID != "" {
+		guild, err := b.session.Guild(b.cfg.GuildID)
+		if err != nil {
+			return fmt.Errorf("guild %q not found or not accessible: %w", b.cfg.GuildID, err)
+		}
+		log.Info().Str("guild_id", guild.ID).Str("guild_name", guild.Name).Msg("Using configured guild")
+	}
+
+	if b.reviewChannelID != "" {
+		channel, err := b.session.Channel(b.reviewChannelID)
+		if err != nil {
+			return fmt.Errorf("review channel %q not found or not accessible (it must be a channel ID, not a name): %w", b.reviewChannelID, err)
+		}
+		if channel.Type != discordgo.ChannelTypeGuildText {
+			return fmt.Errorf("review channel %q (#%s) is not a guild text channel", b.reviewChannelID, channel.Name)
+		}
+		if b.cfg.GuildID != "" && channel.GuildID != b.cfg.GuildID {
+			return fmt.Errorf("review channel %q (#%s) does not belong to guild %q", b.reviewChannelID, channel.Name, b.cfg.GuildID)
+		}
+		log.Info().Str("channel_id", channel.ID).Str("channel_name", channel.Name).Msg("Using configured review channel")
+	}
 
 	return nil
 }
 
 // Shutdown gracefully shuts down the bot
 func (b *Bot) Shutdown(ctx context.Context) error {
-	// Unregister commands if needed and close session
-	if err := b.unregisterCommands(); err != nil {
-		log.Warn().Err(err).Msg("Failed to unregister commands during shutdown")
-	}
+	// Closing the session fires a Disconnect event, which must not reconnect
+	b.stop()
 
+	// Stop the caches' cleanup goroutines; nothing reads them once the session is closed
+	defer b.closeCaches()
+
+	// Commands stay registered so the next start only has to sync what changed
 	return b.session.Close()
 }
 
-// interactionCreate handles Discord interactions (slash commands)
-func (b *Bot) interactionCreate(s *discordgo.Session, i *discordgo.InteractionCreate) {
+// closeCaches stops the cleanup goroutines of the bot's in-memory caches
+func (b *Bot) closeCaches() {
+	b.cooldowns.Close()
+	b.state.Close()
+	b.leaderboard.Close()
+}
+
+// interactionCreate handles Discord interactions (slash commands, context menus, modal submissions and button clicks)
+func (b *Bot) interactionCreate(s DiscordSession, i *discordgo.InteractionCreate) {
+	// Autocomplete must be answered with choices, never with a message
+	if i.Type == discordgo.InteractionApplicationCommandAutocomplete {
+		b.handleAutocomplete(s, i)
+		return
+	}
+
 	// Validate interaction type
-	if i.Type != discordgo.InteractionApplicationCommand {
+	switch i.Type {
+	case discordgo.InteractionApplicationCommand, discordgo.InteractionModalSubmit, discordgo.InteractionMessageComponent:
+	default:
+		return
+	}
+
+	// Commands operate on server data, so they are not available in DMs
+	if isDirectMessage(i) {
+		s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+			Type: discordgo.InteractionResponseChannelMessageWithSource,
+			Data: &discordgo.InteractionResponseData{
+				Content: "Commands can only be used in the server, not in direct messages.",
+				Flags:   discordgo.MessageFlagsEphemeral,
+			},
+		})
 		return
 	}
 
@@ -96,7 +287,7 @@ func (b *Bot) interactionCreate(s *discordgo.Session, i *discordgo.InteractionCr
 	}
 	
 	// Verify user is a member of this server
-	if !b.isServerMember(i.Member.User.ID) {
+	if !b.isServerMember(interactionUserID(i)) {
 		s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
 			Type: discordgo.InteractionResponseChannelMessageWithSource,
 			Data: &discordgo.InteractionResponseData{
@@ -107,11 +298,26 @@ func (b *Bot) interactionCreate(s *discordgo.Session, i *discordgo.InteractionCr
 		return
 	}
 
-	// Get command name and find handler
-	cmdName := i.ApplicationCommandData().Name
+	// Get command name and attach a correlated logger for this interaction
+	cmdName := b.commandKey(i)
+	ctx := withInteractionLogger(context.Background(), i, cmdName)
+	logger := zerolog.Ctx(ctx)
+
+	b.ensureBuiltinProblemSets(ctx, i.GuildID)
+
+	// Aliases are registered as commands of their own; run them as the command they stand for
+	if _, ok := b.commandHandlers[cmdName]; !ok && i.Type == discordgo.InteractionApplicationCommand {
+		if err := b.applyAlias(ctx, i); err != nil {
+			logHandlerError(ctx, err)
+			s.InteractionRespond(i.Interaction, handlerErrorResponse(err))
+			return
+		}
+		cmdName = b.commandKey(i)
+	}
+
 	handler, ok := b.commandHandlers[cmdName]
 	if !ok {
-		log.Error().Str("command", cmdName).Msg("No handler for command")
+		logger.Error().Msg("No handler for command")
 		s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
 			Type: discordgo.InteractionResponseChannelMessageWithSource,
 			Data: &discordgo.InteractionResponseData{
@@ -122,13 +328,48 @@ func (b *Bot) interactionCreate(s *discordgo.Session, i *discordgo.InteractionCr
 		return
 	}
 
+	// Follow-ups that carry state may only be used once, by the user who started them
+	userID := interactionUserID(i)
+	if token, ok := stateToken(interactionCustomID(i)); ok {
+		pending, ok := b.state.Consume(token, userID)
+		if !ok {
+			s.InteractionRespond(i.Interaction, ephemeralResponse("This has expired or isn't yours. Please run the command again."))
+			return
+		}
+		ctx = context.WithValue(ctx, pendingInteractionKey{}, pending)
+	}
+
+	// Enforce per-command cooldowns before doing any work
+	if remaining, ok := b.cooldowns.Check(userID, cmdName); !ok {
+		s.InteractionRespond(i.Interaction, cooldownResponse(remaining))
+		return
+	}
+	b.cooldowns.Set(userID, cmdName, b.cooldowns.Duration(cmdName))
+
 	// Get start time for metrics
-	log.Debug().Str("command", cmdName).Str("user", i.Member.User.Username).Msg("Command received")
+	logger.Debug().Str("user", interactionUser(i).Username).Msg("Command received")
+
+	// Bound the handler's work so a degraded database can't stall the interaction
+	if b.cfg.CommandsTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, b.cfg.CommandsTimeout)
+		defer cancel()
+	}
+
+	// Execute handler, replying at the deadline if it hasn't answered by then
+	responder := &onceResponder{DiscordSession: s}
+	response, err := runHandler(ctx, handler, responder, i)
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		metrics.RecordCommandTimeout(cmdName)
+		logger.Warn().Err(err).Dur("timeout", b.cfg.CommandsTimeout).Msg("Command timed out")
 
-	// Execute handler
-	response, err := handler(s, i)
+		// Deferred handlers have already reported the failure by editing their message
+		if !responder.acknowledged() {
+			response, err = ephemeralResponse(commandTimeoutMessage), nil
+		}
+	}
 	if err != nil {
-		log.Error().Err(err).Str("command", cmdName).Msg("Error handling command")
+		logger.Error().Err(err).Msg("Error handling command")
 		
 		// Send error response if one wasn't already provided
 		if response == nil {
@@ -142,305 +383,13 @@ func (b *Bot) interactionCreate(s *discordgo.Session, i *discordgo.InteractionCr
 		}
 	}
 
-	// Respond to the interaction
-	if err := s.InteractionRespond(i.Interaction, response); err != nil {
-		log.Error().Err(err).Str("command", cmdName).Msg("Failed to respond to interaction")
-	}
-}
-
-// registerCommands registers slash commands with Discord
-func (b *Bot) registerCommands() error {
-	commands := []*discordgo.ApplicationCommand{
-		{
-			Name:        "add",
-			Description: "Add a solved problem to your review list",
-			Options: []*discordgo.ApplicationCommandOption{
-				{
-					Type:        discordgo.ApplicationCommandOptionString,
-					Name:        "name",
-					Description: "Problem name",
-					Required:    true,
-				},
-				{
-					Type:        discordgo.ApplicationCommandOptionString,
-					Name:        "difficulty",
-					Description: "Problem difficulty",
-					Required:    true,
-					Choices: []*discordgo.ApplicationCommandOptionChoice{
-						{
-							Name:  "Easy",
-							Value: "Easy",
-						},
-						{
-							Name:  "Medium",
-							Value: "Medium",
-						},
-						{
-							Name:  "Hard",
-							Value: "Hard",
-						},
-					},
-				},
-				{
-					Type:        discordgo.ApplicationCommandOptionString,
-					Name:        "category",
-					Description: "Problem category/topic",
-					Required:    true,
-				},
-				{
-					Type:        discordgo.ApplicationCommandOptionString,
-					Name:        "status",
-					Description: "How did you solve it?",
-					Required:    true,
-					Choices: []*discordgo.ApplicationCommandOptionChoice{
-						{
-							Name:  "Solved",
-							Value: "Solved",
-						},
-						{
-							Name:  "Needed Hint",
-							Value: "Needed Hint",
-						},
-						{
-							Name:  "Stuck",
-							Value: "Stuck",
-						},
-					},
-				},
-				{
-					Type:        discordgo.ApplicationCommandOptionString,
-					Name:        "solved_at",
-					Description: "Date you solved it (YYYY-MM-DD)",
-					Required:    true,
-				},
-				{
-					Type:        discordgo.ApplicationCommandOptionString,
-					Name:        "link",
-					Description: "Link to the problem",
-					Required:    false,
-				},
-				{
-					Type:        discordgo.ApplicationCommandOptionString,
-					Name:        "tags",
-					Description: "Tags, comma separated (e.g. 'dp,recursion,trees')",
-					Required:    false,
-				},
-				{
-					Type:        discordgo.ApplicationCommandOptionString,
-					Name:        "notes",
-					Description: "Your notes about the problem",
-					Required:    false,
-				},
-			},
-		},
-		{
-			Name:        "list",
-			Description: "List your solved problems",
-			Options: []*discordgo.ApplicationCommandOption{
-				{
-					Type:        discordgo.ApplicationCommandOptionString,
-					Name:        "status",
-					Description: "Filter by status",
-					Required:    false,
-					Choices: []*discordgo.ApplicationCommandOptionChoice{
-						{
-							Name:  "Solved",
-							Value: "Solved",
-						},
-						{
-							Name:  "Needed Hint",
-							Value: "Needed Hint",
-						},
-						{
-							Name:  "Stuck",
-							Value: "Stuck",
-						},
-					},
-				},
-				{
-					Type:        discordgo.ApplicationCommandOptionString,
-					Name:        "difficulty",
-					Description: "Filter by difficulty",
-					Required:    false,
-					Choices: []*discordgo.ApplicationCommandOptionChoice{
-						{
-							Name:  "Easy",
-							Value: "Easy",
-						},
-						{
-							Name:  "Medium",
-							Value: "Medium",
-						},
-						{
-							Name:  "Hard",
-							Value: "Hard",
-						},
-					},
-				},
-				{
-					Type:        discordgo.ApplicationCommandOptionString,
-					Name:        "category",
-					Description: "Filter by category/topic",
-					Required:    false,
-				},
-				{
-					Type:        discordgo.ApplicationCommandOptionString,
-					Name:        "tags",
-					Description: "Filter by tags, comma separated (e.g. 'dp,recursion')",
-					Required:    false,
-				},
-				{
-					Type:        discordgo.ApplicationCommandOptionInteger,
-					Name:        "limit",
-					Description: "Maximum number of problems to show",
-					Required:    false,
-					MinValue:    &[]float64{1}[0],
-					MaxValue:    50,
-				},
-			},
-		},
-		{
-			Name:        "get",
-			Description: "Get details of a specific problem",
-			Options: []*discordgo.ApplicationCommandOption{
-				{
-					Type:        discordgo.ApplicationCommandOptionInteger,
-					Name:        "id",
-					Description: "Problem ID",
-					Required:    true,
-					MinValue:    &[]float64{1}[0],
-				},
-			},
-		},
-		{
-			Name:        "edit",
-			Description: "Edit a problem entry",
-			Options: []*discordgo.ApplicationCommandOption{
-				{
-					Type:        discordgo.ApplicationCommandOptionInteger,
-					Name:        "id",
-					Description: "Problem ID",
-					Required:    true,
-					MinValue:    &[]float64{1}[0],
-				},
-				{
-					Type:        discordgo.ApplicationCommandOptionString,
-					Name:        "name",
-					Description: "Problem name",
-					Required:    false,
-				},
-				{
-					Type:        discordgo.ApplicationCommandOptionString,
-					Name:        "difficulty",
-					Description: "Problem difficulty",
-					Required:    false,
-					Choices: []*discordgo.ApplicationCommandOptionChoice{
-						{
-							Name:  "Easy",
-							Value: "Easy",
-						},
-						{
-							Name:  "Medium",
-							Value: "Medium",
-						},
-						{
-							Name:  "Hard",
-							Value: "Hard",
-						},
-					},
-				},
-				{
-					Type:        discordgo.ApplicationCommandOptionString,
-					Name:        "category",
-					Description: "Problem category/topic",
-					Required:    false,
-				},
-				{
-					Type:        discordgo.ApplicationCommandOptionString,
-					Name:        "status",
-					Description: "How did you solve it?",
-					Required:    false,
-					Choices: []*discordgo.ApplicationCommandOptionChoice{
-						{
-							Name:  "Solved",
-							Value: "Solved",
-						},
-						{
-							Name:  "Needed Hint",
-							Value: "Needed Hint",
-						},
-						{
-							Name:  "Stuck",
-							Value: "Stuck",
-						},
-					},
-				},
-				{
-					Type:        discordgo.ApplicationCommandOptionString,
-					Name:        "solved_at",
-					Description: "Date you solved it (YYYY-MM-DD)",
-					Required:    false,
-				},
-				{
-					Type:        discordgo.ApplicationCommandOptionString,
-					Name:        "link",
-					Description: "Link to the problem",
-					Required:    false,
-				},
-				{
-					Type:        discordgo.ApplicationCommandOptionString,
-					Name:        "tags",
-					Description: "Tags, comma separated (e.g. 'dp,recursion,trees')",
-					Required:    false,
-				},
-				{
-					Type:        discordgo.ApplicationCommandOptionString,
-					Name:        "notes",
-					Description: "Your notes about the problem",
-					Required:    false,
-				},
-			},
-		},
-		{
-			Name:        "delete",
-			Description: "Delete a problem entry",
-			Options: []*discordgo.ApplicationCommandOption{
-				{
-					Type:        discordgo.ApplicationCommandOptionInteger,
-					Name:        "id",
-					Description: "Problem ID",
-					Required:    true,
-					MinValue:    &[]float64{1}[0],
-				},
-			},
-		},
+	// Deferred handlers have already responded by editing their deferred message
+	if response == nil {
+		return
 	}
 
-	for _, command := range commands {
-		_, err := b.session.ApplicationCommandCreate(b.session.State.User.ID, b.cfg.GuildID, command)
-		if err != nil {
-			return fmt.Errorf("failed to create command %s: %w", command.Name, err)
-		}
+	// Respond to the interaction
+	if err := responder.InteractionRespond(i.Interaction, response); err != nil {
+		logger.Error().Err(err).Msg("Failed to respond to interaction")
 	}
-
-	return nil
 }
-
-// unregisterCommands removes all slash commands from Discord
-func (b *Bot) unregisterCommands() error {
-	// Get all commands
-	commands, err := b.session.ApplicationCommands(b.session.State.User.ID, b.cfg.GuildID)
-	if err != nil {
-		return fmt.Errorf("failed to get commands: %w", err)
-	}
-
-	// Delete each command
-	for _, cmd := range commands {
-		err := b.session.ApplicationCommandDelete(b.session.State.User.ID, b.cfg.GuildID, cmd.ID)
-		if err != nil {
-			return fmt.Errorf("failed to delete command %s: %w", cmd.Name, err)
-		}
-	}
-
-	return nil
-}
\ No newline at end of file
@@ -0,0 +1,293 @@
+package bot
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/yugonline/grind_review_bot/config"
+	"github.com/yugonline/grind_review_bot/internal/database"
+)
+
+func TestMain(m *testing.M) {
+	// Migrations are looked up from the working directory, which for tests is the package's
+	if err := os.Chdir("../.."); err != nil {
+		panic(err)
+	}
+	os.Exit(m.Run())
+}
+
+// newTestRepository opens a migrated SQLite database in a temporary directory
+func newTestRepository(t testing.TB) *database.Repository {
+	t.Helper()
+	ctx := context.Background()
+
+	repo, err := database.New(ctx, config.DatabaseConfig{
+		Driver:            "sqlite3",
+		DSN:               filepath.Join(t.TempDir(), "test.db"),
+		MaxOpenConns:      1,
+		MaxIdleConns:      1,
+		MaxTagsPerProblem: 10,
+	})
+	if err != nil {
+		t.Fatalf("failed to open test database: %v", err)
+	}
+	if err := database.Migrate(ctx, repo); err != nil {
+		t.Fatalf("failed to migrate test database: %v", err)
+	}
+	return repo
+}
+
+// newTestBot creates a bot backed by a fresh database whose session is a MockSession
+func newTestBot(t testing.TB, cfg config.DiscordConfig) (*Bot, *MockSession) {
+	t.Helper()
+	if cfg.Token == "" {
+		cfg.Token = "test-token"
+	}
+
+	bot, err := New(context.Background(), cfg, newTestRepository(t), nil)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	t.Cleanup(bot.closeCaches)
+
+	session := NewMockSession()
+	bot.session = session
+	return bot, session
+}
+
+func TestNew(t *testing.T) {
+	bot, err := New(context.Background(), config.DiscordConfig{Token: "test-token", ReviewChannelID: "review-channel"}, newTestRepository(t), nil)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	defer bot.closeCaches()
+
+	if bot.reviewChannelID != "review-channel" {
+		t.Errorf("reviewChannelID = %q, want review-channel", bot.reviewChannelID)
+	}
+
+	// New only configures the session; connecting is left to Start
+	session, ok := bot.session.(*discordgo.Session)
+	if !ok {
+		t.Fatalf("session is a %T, want *discordgo.Session", bot.session)
+	}
+	if session.DataReady {
+		t.Error("New opened the session")
+	}
+	if session.ShouldReconnectOnError {
+		t.Error("discordgo's reconnect is enabled alongside the bot's own")
+	}
+
+	// Commands with subcommands are handled per subcommand, as "name/subcommand"
+	for _, command := range bot.commands {
+		handled := bot.commandHandlers[command.Name] != nil
+		for name := range bot.commandHandlers {
+			handled = handled || strings.HasPrefix(name, command.Name+"/")
+		}
+		if !handled {
+			t.Errorf("no handler for /%s", command.Name)
+		}
+	}
+	for _, name := range []string{"add", "list", "get", "edit", "delete"} {
+		if bot.commandHandlers[name] == nil {
+			t.Errorf("no handler for /%s", name)
+		}
+	}
+}
+
+func TestNewRequiresToken(t *testing.T) {
+	if _, err := New(context.Background(), config.DiscordConfig{Token: "  "}, newTestRepository(t), nil); err == nil {
+		t.Error("New accepted an empty token")
+	}
+}
+
+func TestStartRegistersCommands(t *testing.T) {
+	bot, session := newTestBot(t, config.DiscordConfig{GuildID: "guild-1"})
+	session.Users.Store("@me", &discordgo.User{ID: "app-1"})
+
+	if err := bot.Start(context.Background()); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+
+	if calls := session.Calls("Open"); len(calls) != 1 {
+		t.Errorf("Open called %d times, want 1", len(calls))
+	}
+	if bot.applicationID != "app-1" {
+		t.Errorf("applicationID = %q, want app-1", bot.applicationID)
+	}
+	created := session.Calls("ApplicationCommandCreate")
+	if len(created) != len(bot.commands) {
+		t.Errorf("created %d commands, want %d", len(created), len(bot.commands))
+	}
+	for _, call := range created {
+		if appID, guildID := call.Args[0], call.Args[1]; appID != "app-1" || guildID != "guild-1" {
+			t.Errorf("command created for app %v in guild %v, want app-1 in guild-1", appID, guildID)
+		}
+	}
+	if !bot.started.Load() {
+		t.Error("started is not set after Start")
+	}
+}
+
+func TestCommandPrefix(t *testing.T) {
+	bot, session := newTestBot(t, config.DiscordConfig{GuildID: "guild-1", CommandPrefix: "grind-"})
+	session.Users.Store("@me", &discordgo.User{ID: "app-1"})
+
+	if err := bot.Start(context.Background()); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	created := session.Calls("ApplicationCommandCreate")
+	if len(created) != len(bot.commands) {
+		t.Errorf("created %d commands, want %d", len(created), len(bot.commands))
+	}
+	for _, call := range created {
+		if name := call.Args[2].(*discordgo.ApplicationCommand).Name; !strings.HasPrefix(name, "grind-") {
+			t.Errorf("registered /%s, want the grind- prefix", name)
+		}
+	}
+
+	// Interactions arrive with the prefixed name and reach the unprefixed handler
+	addTestProblem(t, bot, "Two Sum", database.DifficultyEasy)
+	resp := dispatch(t, bot, session, commandInteraction("grind-list"))
+	if !strings.Contains(resp.Data.Content, "Two Sum") {
+		t.Errorf("/grind-list = %q, want the problem list", resp.Data.Content)
+	}
+	if key := bot.commandKey(commandInteraction("grind-list")); key != "list" {
+		t.Errorf("commandKey(grind-list) = %q, want list", key)
+	}
+}
+
+func TestShutdownClosesSession(t *testing.T) {
+	bot, session := newTestBot(t, config.DiscordConfig{})
+
+	if err := bot.Shutdown(context.Background()); err != nil {
+		t.Fatalf("Shutdown: %v", err)
+	}
+
+	if calls := session.Calls("Close"); len(calls) != 1 {
+		t.Errorf("Close called %d times, want 1", len(calls))
+	}
+	if bot.ctx.Err() == nil {
+		t.Error("Shutdown left the reconnect context running")
+	}
+}
+
+// unknownIDSession is a MockSession on which the listed guild and channel IDs don't exist
+type unknownIDSession struct {
+	*MockSession
+	unknown map[string]bool
+}
+
+// Guild implements DiscordSession
+func (s *unknownIDSession) Guild(guildID string, options ...discordgo.RequestOption) (*discordgo.Guild, error) {
+	if s.unknown[guildID] {
+		return nil, errors.New("HTTP 404 Not Found, {\"message\": \"Unknown Guild\", \"code\": 10004}")
+	}
+	return s.MockSession.Guild(guildID, options...)
+}
+
+// Channel implements DiscordSession
+func (s *unknownIDSession) Channel(channelID string, options ...discordgo.RequestOption) (*discordgo.Channel, error) {
+	if s.unknown[channelID] {
+		return nil, errors.New("HTTP 404 Not Found, {\"message\": \"Unknown Channel\", \"code\": 10003}")
+	}
+	return s.MockSession.Channel(channelID, options...)
+}
+
+func TestStartValidatesConfiguredIDs(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     config.DiscordConfig
+		wantErr string
+	}{
+		{name: "valid", cfg: config.DiscordConfig{GuildID: "guild-1", ReviewChannelID: "channel-1"}},
+		{name: "nothing configured", cfg: config.DiscordConfig{}},
+		{name: "unknown guild", cfg: config.DiscordConfig{GuildID: "missing"}, wantErr: `guild "missing" not found`},
+		{name: "channel name instead of ID", cfg: config.DiscordConfig{ReviewChannelID: "leetcode-reviews"}, wantErr: "must be a channel ID, not a name"},
+		{name: "voice channel", cfg: config.DiscordConfig{ReviewChannelID: "voice-1"}, wantErr: "is not a guild text channel"},
+		{name: "channel in another guild", cfg: config.DiscordConfig{GuildID: "guild-1", ReviewChannelID: "elsewhere-1"}, wantErr: `does not belong to guild "guild-1"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bot, mock := newTestBot(t, tt.cfg)
+			mock.Users.Store("@me", &discordgo.User{ID: "app-1"})
+			mock.Channels.Store("channel-1", &discordgo.Channel{ID: "channel-1", GuildID: "guild-1", Name: "reviews", Type: discordgo.ChannelTypeGuildText})
+			mock.Channels.Store("voice-1", &discordgo.Channel{ID: "voice-1", GuildID: "guild-1", Name: "study-hall", Type: discordgo.ChannelTypeGuildVoice})
+			mock.Channels.Store("elsewhere-1", &discordgo.Channel{ID: "elsewhere-1", GuildID: "guild-2", Name: "general", Type: discordgo.ChannelTypeGuildText})
+			bot.session = &unknownIDSession{MockSession: mock, unknown: map[string]bool{"missing": true, "leetcode-reviews": true}}
+
+			err := bot.Start(context.Background())
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("Start: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("Start = %v, want an error containing %q", err, tt.wantErr)
+			}
+			if calls := mock.Calls("Close"); len(calls) != 1 {
+				t.Errorf("Close called %d times after a failed Start, want 1", len(calls))
+			}
+			if calls := mock.Calls("ApplicationCommandCreate"); len(calls) != 0 {
+				t.Errorf("registered %d commands despite the bad config", len(calls))
+			}
+		})
+	}
+}
+
+// flakySession is a MockSession whose gateway connection fails the first failures times
+type flakySession struct {
+	*MockSession
+	failures int
+	opens    int
+}
+
+// Open implements DiscordSession
+func (s *flakySession) Open() error {
+	s.opens++
+	if s.opens <= s.failures {
+		return errors.New("websocket: bad handshake")
+	}
+	return s.MockSession.Open()
+}
+
+func TestStartRetriesOpen(t *testing.T) {
+	bot, mock := newTestBot(t, config.DiscordConfig{})
+	mock.Users.Store("@me", &discordgo.User{ID: "app-1"})
+	session := &flakySession{MockSession: mock, failures: 1}
+	bot.session = session
+
+	// A transient failure is retried rather than ending Start
+	if err := bot.Start(context.Background()); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	if session.opens != 2 {
+		t.Errorf("Open called %d times, want a failure then a success", session.opens)
+	}
+	if !bot.started.Load() || len(mock.Calls("ApplicationCommandCreate")) != len(bot.commands) {
+		t.Error("Start didn't finish registering commands after reconnecting")
+	}
+}
+
+func TestStartStopsRetryingOnCancel(t *testing.T) {
+	bot, mock := newTestBot(t, config.DiscordConfig{})
+	session := &flakySession{MockSession: mock, failures: sessionOpenRetries + 1}
+	bot.session = session
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	err := bot.Start(ctx)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Start = %v, want it to give up when the context ends", err)
+	}
+	if session.opens != 1 || bot.started.Load() {
+		t.Errorf("Open called %d times (started %v), want one attempt and no start", session.opens, bot.started.Load())
+	}
+}
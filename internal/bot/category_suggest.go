@@ -0,0 +1,55 @@
+package bot
+
+import "strings"
+
+// categoryKeywords maps keywords commonly found in LeetCode problem names to a topic.
+// Longer, more specific phrases are listed first so they win over generic ones.
+var categoryKeywords = []struct {
+	keyword    string
+	category   string
+	confidence float64
+}{
+	{"binary search tree", "Binary Search Tree", 0.9},
+	{"binary tree", "Binary Tree", 0.9},
+	{"linked list", "Linked List", 0.9},
+	{"sliding window", "Sliding Window", 0.9},
+	{"two sum", "Array", 0.8},
+	{"parenthes", "Stack", 0.8},
+	{"substring", "Sliding Window", 0.6},
+	{"subsequence", "Dynamic Programming", 0.7},
+	{"palindrom", "String", 0.6},
+	{"anagram", "Hash Table", 0.7},
+	{"interval", "Intervals", 0.8},
+	{"island", "Graph", 0.8},
+	{"graph", "Graph", 0.9},
+	{"course schedule", "Graph", 0.9},
+	{"trie", "Trie", 0.9},
+	{"word search", "Backtracking", 0.8},
+	{"permutation", "Backtracking", 0.7},
+	{"combination", "Backtracking", 0.7},
+	{"subsets", "Backtracking", 0.8},
+	{"kth", "Heap", 0.6},
+	{"median", "Heap", 0.6},
+	{"stairs", "Dynamic Programming", 0.8},
+	{"coin", "Dynamic Programming", 0.8},
+	{"robber", "Dynamic Programming", 0.8},
+	{"matrix", "Matrix", 0.6},
+	{"tree", "Tree", 0.7},
+	{"stack", "Stack", 0.9},
+	{"queue", "Queue", 0.8},
+	{"heap", "Heap", 0.9},
+	{"string", "String", 0.6},
+	{"array", "Array", 0.6},
+}
+
+// classifyCategory guesses a category from a problem name, returning the guess and a
+// confidence between 0 and 1. An empty category with zero confidence means no guess.
+func classifyCategory(name string) (string, float64) {
+	lower := strings.ToLower(name)
+	for _, kw := range categoryKeywords {
+		if strings.Contains(lower, kw.keyword) {
+			return kw.category, kw.confidence
+		}
+	}
+	return "", 0
+}
@@ -0,0 +1,125 @@
+package bot
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/yugonline/grind_review_bot/config"
+	"github.com/yugonline/grind_review_bot/internal/database"
+)
+
+func TestClassifyCategory(t *testing.T) {
+	tests := []struct {
+		name         string
+		wantCategory string
+	}{
+		{name: "Validate Binary Search Tree", wantCategory: "Binary Search Tree"},
+		{name: "Binary Tree Level Order Traversal", wantCategory: "Binary Tree"},
+		{name: "Reverse Linked List", wantCategory: "Linked List"},
+		{name: "Valid Parentheses", wantCategory: "Stack"},
+		{name: "NUMBER OF ISLANDS", wantCategory: "Graph"},
+		{name: "Merge Intervals", wantCategory: "Intervals"},
+		{name: "Implement Trie (Prefix Tree)", wantCategory: "Trie"},
+		{name: "Longest Increasing Subsequence", wantCategory: "Dynamic Programming"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			category, confidence := classifyCategory(tt.name)
+			if category != tt.wantCategory {
+				t.Errorf("classifyCategory(%q) = %q, want %q", tt.name, category, tt.wantCategory)
+			}
+			if confidence <= 0 || confidence > 1 {
+				t.Errorf("confidence = %v, want it in (0, 1]", confidence)
+			}
+		})
+	}
+}
+
+func TestClassifyCategoryUnknown(t *testing.T) {
+	if category, confidence := classifyCategory("Zigzag Conversion"); category != "" || confidence != 0 {
+		t.Errorf("classifyCategory = %q, %v; want no guess", category, confidence)
+	}
+}
+
+func TestAddCommandSuggestsCategory(t *testing.T) {
+	bot, session := newTestBot(t, config.DiscordConfig{})
+	add := func(name string) *discordgo.InteractionResponse {
+		return dispatch(t, bot, session, commandInteraction("add",
+			stringOption("name", name),
+			stringOption("difficulty", "Medium"),
+			stringOption("status", database.StatusSolved),
+			stringOption("solved_at", "2024-03-05"),
+		))
+	}
+
+	if text := responseText(add("Reverse Linked List")); !strings.Contains(text, "Category set to **Linked List**") {
+		t.Errorf("response = %q, want the suggested category", text)
+	}
+	if text := responseText(add("Zigzag Conversion")); !strings.Contains(text, "provide one with the category option") {
+		t.Errorf("response = %q, want a request for a category", text)
+	}
+
+	// A given category is never overridden
+	dispatch(t, bot, session, commandInteraction("add",
+		stringOption("name", "Binary Tree Paths"),
+		stringOption("difficulty", "Easy"),
+		stringOption("status", database.StatusSolved),
+		stringOption("solved_at", "2024-03-05"),
+		stringOption("category", "Backtracking"),
+	))
+	problems, err := bot.repo.ListProblems(context.Background(), testUserID, "", "", "", nil, true, 0, 0)
+	if err != nil {
+		t.Fatalf("ListProblems: %v", err)
+	}
+	categories := make(map[string]string)
+	for _, p := range problems {
+		categories[p.ProblemName] = p.Category
+	}
+	if categories["Reverse Linked List"] != "Linked List" || categories["Binary Tree Paths"] != "Backtracking" {
+		t.Errorf("stored categories = %v", categories)
+	}
+	if _, ok := categories["Zigzag Conversion"]; ok {
+		t.Error("a problem without a category was stored")
+	}
+}
+
+func TestAddCommandCanonicalCategory(t *testing.T) {
+	tests := []struct {
+		input      string
+		want       string
+		suggestion string
+	}{
+		{input: "Dynamic Programing", want: "Dynamic Programming"},
+		{input: "trees", want: "trees", suggestion: "Did you mean the category **Tree**?"},
+		{input: "Astrophysics", want: "Astrophysics"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			bot, session := newTestBot(t, config.DiscordConfig{})
+			resp := dispatch(t, bot, session, commandInteraction("add",
+				stringOption("name", "Coin Change"),
+				stringOption("difficulty", "Medium"),
+				stringOption("status", database.StatusSolved),
+				stringOption("solved_at", "2024-03-05"),
+				stringOption("category", tt.input),
+			))
+
+			text := responseText(resp)
+			if tt.suggestion != "" && !strings.Contains(text, tt.suggestion) {
+				t.Errorf("response = %q, want the suggestion %q", text, tt.suggestion)
+			}
+			if tt.suggestion == "" && strings.Contains(text, "Did you mean") {
+				t.Errorf("response = %q, want no suggestion", text)
+			}
+			problems, err := bot.repo.ListProblems(context.Background(), testUserID, "", "", "", nil, true, 0, 0)
+			if err != nil {
+				t.Fatalf("ListProblems: %v", err)
+			}
+			if len(problems) != 1 || problems[0].Category != tt.want {
+				t.Errorf("stored %+v, want category %q", problems, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,327 @@
+package bot
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"regexp"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/rs/zerolog/log"
+	"github.com/yugonline/grind_review_bot/internal/database"
+)
+
+// maxAliasesPerUser caps how many aliases one user may create
+const maxAliasesPerUser = 5
+
+// maxGuildCommands is the number of commands Discord allows an application to register in a guild
+const maxGuildCommands = 100
+
+// aliasNamePattern matches the names Discord allows for slash commands
+var aliasNamePattern = regexp.MustCompile(`^[-_a-z0-9]{1,32}$`)
+
+// aliasTarget returns the definition of the command an alias may stand for. Only top-level slash
+// commands qualify; grouped commands and context menu entries can't be aliased.
+func (b *Bot) aliasTarget(command string) (*discordgo.ApplicationCommand, bool) {
+	for _, def := range b.commands {
+		if def.Name != command || (def.Type != 0 && def.Type != discordgo.ChatApplicationCommand) {
+			continue
+		}
+		for _, opt := range def.Options {
+			if opt.Type == discordgo.ApplicationCommandOptionSubCommand || opt.Type == discordgo.ApplicationCommandOptionSubCommandGroup {
+				return nil, false
+			}
+		}
+		return def, true
+	}
+	return nil, false
+}
+
+// parseAliasDefaults decodes an alias's default options, checking each names an option of def and
+// holds a value of that option's type
+func parseAliasDefaults(def *discordgo.ApplicationCommand, raw string) (map[string]interface{}, error) {
+	defaults := make(map[string]interface{})
+	if strings.TrimSpace(raw) == "" {
+		return defaults, nil
+	}
+	if err := json.Unmarshal([]byte(raw), &defaults); err != nil {
+		return nil, errors.New(`default options must be a JSON object, e.g. {"difficulty":"Easy"}`)
+	}
+
+	options := make(map[string]*discordgo.ApplicationCommandOption, len(def.Options))
+	for _, opt := range def.Options {
+		options[opt.Name] = opt
+	}
+
+	for name, value := range defaults {
+		opt, ok := options[name]
+		if !ok {
+			return nil, fmt.Errorf("/%s has no option named %q", def.Name, name)
+		}
+
+		valid := false
+		switch opt.Type {
+		case discordgo.ApplicationCommandOptionString, discordgo.ApplicationCommandOptionUser,
+			discordgo.ApplicationCommandOptionChannel, discordgo.ApplicationCommandOptionRole,
+			discordgo.ApplicationCommandOptionMentionable:
+			_, valid = value.(string)
+		case discordgo.ApplicationCommandOptionInteger:
+			number, ok := value.(float64)
+			valid = ok && number == math.Trunc(number)
+		case discordgo.ApplicationCommandOptionNumber:
+			_, valid = value.(float64)
+		case discordgo.ApplicationCommandOptionBoolean:
+			_, valid = value.(bool)
+		}
+		if !valid {
+			return nil, fmt.Errorf("option %q has a value of the wrong type", name)
+		}
+
+		if len(opt.Choices) > 0 && !hasChoice(opt.Choices, value) {
+			return nil, fmt.Errorf("option %q must be one of the values /%s offers", name, def.Name)
+		}
+	}
+	return defaults, nil
+}
+
+// hasChoice reports whether value is one of choices
+func hasChoice(choices []*discordgo.ApplicationCommandOptionChoice, value interface{}) bool {
+	for _, choice := range choices {
+		if fmt.Sprint(choice.Value) == fmt.Sprint(value) {
+			return true
+		}
+	}
+	return false
+}
+
+// aliasCommand builds the command registered for an alias: the target's definition under the
+// alias's name, with the options the alias fills in made optional
+func aliasCommand(alias *database.CommandAlias, def *discordgo.ApplicationCommand, defaults map[string]interface{}) *discordgo.ApplicationCommand {
+	command := *def
+	command.Name = alias.Alias
+	command.Description = truncateString(fmt.Sprintf("Alias for /%s: %s", def.Name, def.Description), 100)
+
+	// Discord requires required options to come before optional ones
+	var required, optional []*discordgo.ApplicationCommandOption
+	for _, opt := range def.Options {
+		copied := *opt
+		if _, ok := defaults[opt.Name]; ok {
+			copied.Required = false
+		}
+		if copied.Required {
+			required = append(required, &copied)
+		} else {
+			optional = append(optional, &copied)
+		}
+	}
+	command.Options = append(required, optional...)
+	return &command
+}
+
+// aliasCommands returns the commands registered for every user's aliases. Aliases whose command
+// no longer exists are skipped.
+func (b *Bot) aliasCommands(ctx context.Context) ([]*discordgo.ApplicationCommand, error) {
+	aliases, err := b.repo.ListAliases(ctx, "")
+	if err != nil {
+		return nil, err
+	}
+
+	commands := make([]*discordgo.ApplicationCommand, 0, len(aliases))
+	for _, alias := range aliases {
+		def, ok := b.aliasTarget(alias.Command)
+		if !ok {
+			log.Warn().Str("alias", alias.Alias).Str("command", alias.Command).Msg("Aliased command no longer exists, skipping")
+			continue
+		}
+		defaults, err := parseAliasDefaults(def, alias.DefaultOptions)
+		if err != nil {
+			log.Warn().Err(err).Str("alias", alias.Alias).Msg("Alias default options no longer match the command, skipping")
+			continue
+		}
+		commands = append(commands, aliasCommand(alias, def, defaults))
+	}
+	return commands, nil
+}
+
+// ResolveAlias returns the command cmdName stands for when it's one of the user's aliases, together
+// with the option values the alias fills in. Other names resolve to themselves.
+func (b *Bot) ResolveAlias(ctx context.Context, userID, cmdName string) (string, map[string]interface{}, error) {
+	alias, err := b.repo.GetAlias(ctx, cmdName)
+	if errors.Is(err, database.ErrAliasNotFound) {
+		return cmdName, nil, nil
+	}
+	if err != nil {
+		return "", nil, databaseError("Failed to look up the alias.", err)
+	}
+	if alias.UserID != userID {
+		return "", nil, permissionError(fmt.Sprintf("/%s%s is another member's alias for /%s%s.", b.cfg.CommandPrefix, cmdName, b.cfg.CommandPrefix, alias.Command))
+	}
+
+	def, ok := b.aliasTarget(alias.Command)
+	if !ok {
+		return "", nil, notFoundError(fmt.Sprintf("The command /%s%s aliases no longer exists. Delete the alias with /%salias delete.", b.cfg.CommandPrefix, alias.Command, b.cfg.CommandPrefix))
+	}
+	defaults, err := parseAliasDefaults(def, alias.DefaultOptions)
+	if err != nil {
+		return "", nil, validationError(fmt.Sprintf("The alias's default options no longer fit /%s: %v.", alias.Command, err))
+	}
+	return alias.Command, defaults, nil
+}
+
+// applyAlias rewrites an alias invocation into the command it stands for, filling in the alias's
+// default options wherever the user didn't give a value
+func (b *Bot) applyAlias(ctx context.Context, i *discordgo.InteractionCreate) error {
+	data := i.ApplicationCommandData()
+	name := strings.TrimPrefix(data.Name, b.cfg.CommandPrefix)
+
+	command, defaults, err := b.ResolveAlias(ctx, interactionUserID(i), name)
+	if err != nil || command == name {
+		return err
+	}
+
+	def, _ := b.aliasTarget(command)
+	data.Name = b.cfg.CommandPrefix + command
+	data.Options = mergeAliasOptions(def, defaults, data.Options)
+	i.Data = data
+	return nil
+}
+
+// mergeAliasOptions adds an option for each default the user didn't supply; given options win
+func mergeAliasOptions(def *discordgo.ApplicationCommand, defaults map[string]interface{}, given []*discordgo.ApplicationCommandInteractionDataOption) []*discordgo.ApplicationCommandInteractionDataOption {
+	merged := append([]*discordgo.ApplicationCommandInteractionDataOption(nil), given...)
+	present := make(map[string]bool, len(given))
+	for _, opt := range given {
+		present[opt.Name] = true
+	}
+
+	// Walk the definition rather than the map so the merged options keep a stable order
+	for _, opt := range def.Options {
+		value, ok := defaults[opt.Name]
+		if !ok || present[opt.Name] {
+			continue
+		}
+		merged = append(merged, &discordgo.ApplicationCommandInteractionDataOption{
+			Name:  opt.Name,
+			Type:  opt.Type,
+			Value: value,
+		})
+	}
+	return merged
+}
+
+func (b *Bot) handleAliasCreateCommand(ctx context.Context, s DiscordSession, i *discordgo.InteractionCreate) (*discordgo.InteractionResponse, error) {
+	_, options := getSubcommand(i)
+	optionMap := make(map[string]*discordgo.ApplicationCommandInteractionDataOption, len(options))
+	for _, opt := range options {
+		optionMap[opt.Name] = opt
+	}
+
+	userID := interactionUserID(i)
+	name := strings.ToLower(strings.TrimSpace(optionMap["alias_name"].StringValue()))
+	command := strings.TrimPrefix(strings.ToLower(strings.TrimSpace(optionMap["command_name"].StringValue())), "/")
+
+	if !aliasNamePattern.MatchString(name) || len(b.cfg.CommandPrefix+name) > 32 {
+		return nil, validationError("Alias names may only contain lowercase letters, digits, '-' and '_', and must fit Discord's 32 character limit.")
+	}
+	for _, def := range b.commands {
+		if def.Name == name {
+			return nil, validationError(fmt.Sprintf("/%s is already a command.", name))
+		}
+	}
+	def, ok := b.aliasTarget(command)
+	if !ok {
+		return nil, validationError(fmt.Sprintf("/%s isn't a command that can be aliased. Commands with subcommands can't be.", command))
+	}
+
+	defaultOptions := ""
+	if defaultsOpt, ok := optionMap["default_options"]; ok {
+		defaultOptions = defaultsOpt.StringValue()
+	}
+	if _, err := parseAliasDefaults(def, defaultOptions); err != nil {
+		return nil, validationError(fmt.Sprintf("Invalid default options: %v.", err))
+	}
+
+	all, err := b.repo.ListAliases(ctx, "")
+	if err != nil {
+		return nil, databaseError("Failed to retrieve aliases from the database.", err)
+	}
+	owned := 0
+	for _, alias := range all {
+		if alias.UserID == userID {
+			owned++
+		}
+	}
+	if owned >= maxAliasesPerUser {
+		return nil, validationError(fmt.Sprintf("You already have %d aliases, the most allowed. Delete one with /alias delete first.", maxAliasesPerUser))
+	}
+	if len(b.commands)+len(all) >= maxGuildCommands {
+		return nil, validationError("This server has reached Discord's limit on commands, so no more aliases can be added.")
+	}
+
+	alias := &database.CommandAlias{Alias: name, UserID: userID, Command: command, DefaultOptions: defaultOptions}
+	if err := b.repo.CreateAlias(ctx, alias); err != nil {
+		if errors.Is(err, database.ErrAliasTaken) {
+			return nil, validationError(fmt.Sprintf("/%s is already taken by another alias.", name))
+		}
+		return nil, databaseError("Failed to save the alias.", err)
+	}
+
+	// The alias is only usable once Discord knows about it
+	if err := b.syncCommands(); err != nil {
+		if deleteErr := b.repo.DeleteAlias(ctx, userID, name); deleteErr != nil {
+			log.Error().Err(deleteErr).Str("alias", name).Msg("Failed to remove alias after registration failed")
+		}
+		return nil, &UserError{Kind: ErrUnavailable, Message: "Couldn't register the alias with Discord. Please try again later.", Err: err}
+	}
+
+	return ephemeralResponse(fmt.Sprintf("Created /%s%s as an alias for /%s%s.", b.cfg.CommandPrefix, name, b.cfg.CommandPrefix, command)), nil
+}
+
+func (b *Bot) handleAliasListCommand(ctx context.Context, s DiscordSession, i *discordgo.InteractionCreate) (*discordgo.InteractionResponse, error) {
+	aliases, err := b.repo.ListAliases(ctx, interactionUserID(i))
+	if err != nil {
+		return nil, databaseError("Failed to retrieve your aliases from the database.", err)
+	}
+	if len(aliases) == 0 {
+		return ephemeralResponse("You don't have any aliases. Create one with /alias create."), nil
+	}
+
+	var sb strings.Builder
+	sb.WriteString("# Your Aliases\n")
+	for _, alias := range aliases {
+		sb.WriteString(fmt.Sprintf("- **/%s%s** → /%s%s", b.cfg.CommandPrefix, alias.Alias, b.cfg.CommandPrefix, alias.Command))
+		if alias.DefaultOptions != "" && alias.DefaultOptions != "{}" {
+			sb.WriteString(fmt.Sprintf(" `%s`", alias.DefaultOptions))
+		}
+		sb.WriteString("\n")
+	}
+	return ephemeralResponse(sb.String()), nil
+}
+
+func (b *Bot) handleAliasDeleteCommand(ctx context.Context, s DiscordSession, i *discordgo.InteractionCreate) (*discordgo.InteractionResponse, error) {
+	_, options := getSubcommand(i)
+	optionMap := make(map[string]*discordgo.ApplicationCommandInteractionDataOption, len(options))
+	for _, opt := range options {
+		optionMap[opt.Name] = opt
+	}
+
+	name := strings.TrimPrefix(strings.ToLower(strings.TrimSpace(optionMap["alias_name"].StringValue())), "/")
+	name = strings.TrimPrefix(name, b.cfg.CommandPrefix)
+
+	if err := b.repo.DeleteAlias(ctx, interactionUserID(i), name); err != nil {
+		if errors.Is(err, database.ErrAliasNotFound) {
+			return nil, notFoundError(fmt.Sprintf("You don't have an alias named /%s.", name))
+		}
+		return nil, databaseError("Failed to delete the alias.", err)
+	}
+
+	// A failed sync leaves the command registered, but it no longer resolves; the next sync while
+	// the bot runs removes it
+	if err := b.syncCommands(); err != nil {
+		log.Error().Err(err).Str("alias", name).Msg("Failed to unregister deleted alias")
+	}
+	return ephemeralResponse(fmt.Sprintf("Deleted the alias /%s%s.", b.cfg.CommandPrefix, name)), nil
+}
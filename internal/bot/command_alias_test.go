@@ -0,0 +1,173 @@
+package bot
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/yugonline/grind_review_bot/config"
+	"github.com/yugonline/grind_review_bot/internal/database"
+)
+
+// aliasInteraction builds an /alias subcommand invocation
+func aliasInteraction(subcommand string, options ...*discordgo.ApplicationCommandInteractionDataOption) *discordgo.InteractionCreate {
+	return commandInteraction("alias", &discordgo.ApplicationCommandInteractionDataOption{
+		Name:    subcommand,
+		Type:    discordgo.ApplicationCommandOptionSubCommand,
+		Options: options,
+	})
+}
+
+// createAlias stores an alias for userID directly, without registering it with Discord
+func createAlias(t *testing.T, bot *Bot, userID, name, command, defaults string) {
+	t.Helper()
+	alias := &database.CommandAlias{Alias: name, UserID: userID, Command: command, DefaultOptions: defaults}
+	if err := bot.repo.CreateAlias(context.Background(), alias); err != nil {
+		t.Fatalf("CreateAlias: %v", err)
+	}
+}
+
+func TestResolveAlias(t *testing.T) {
+	ctx := context.Background()
+	bot, _ := newTestBot(t, config.DiscordConfig{})
+	createAlias(t, bot, testUserID, "a", "add", `{"difficulty":"Easy","status":"Solved"}`)
+	createAlias(t, bot, testUserID, "gone", "removed-command", "")
+	createAlias(t, bot, "user-2", "l", "list", "")
+
+	command, defaults, err := bot.ResolveAlias(ctx, testUserID, "a")
+	if err != nil || command != "add" {
+		t.Fatalf("ResolveAlias(a) = %q, %v, want add", command, err)
+	}
+	if len(defaults) != 2 || defaults["difficulty"] != "Easy" || defaults["status"] != "Solved" {
+		t.Errorf("defaults = %v, want difficulty Easy and status Solved", defaults)
+	}
+
+	// Names that aren't aliases resolve to themselves
+	if command, defaults, err := bot.ResolveAlias(ctx, testUserID, "list"); err != nil || command != "list" || defaults != nil {
+		t.Errorf("ResolveAlias(list) = %q, %v, %v, want list unchanged", command, defaults, err)
+	}
+
+	tests := []struct {
+		name  string
+		alias string
+		kind  error
+	}{
+		{name: "another member's alias", alias: "l", kind: ErrPermissionDenied},
+		{name: "aliased command removed", alias: "gone", kind: ErrNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, _, err := bot.ResolveAlias(ctx, testUserID, tt.alias); !errors.Is(err, tt.kind) {
+				t.Errorf("ResolveAlias(%s) = %v, want a %v error", tt.alias, err, tt.kind)
+			}
+		})
+	}
+}
+
+func TestMergeAliasOptions(t *testing.T) {
+	bot, _ := newTestBot(t, config.DiscordConfig{})
+	def, ok := bot.aliasTarget("add")
+	if !ok {
+		t.Fatal("/add can't be aliased")
+	}
+	defaults := map[string]interface{}{"difficulty": "Easy", "status": "Solved"}
+
+	merged := mergeAliasOptions(def, defaults, []*discordgo.ApplicationCommandInteractionDataOption{
+		stringOption("name", "Two Sum"),
+		stringOption("difficulty", "Hard"),
+	})
+	values := make(map[string]interface{}, len(merged))
+	for _, opt := range merged {
+		if _, ok := values[opt.Name]; ok {
+			t.Errorf("option %s given twice", opt.Name)
+		}
+		values[opt.Name] = opt.Value
+	}
+	// The user's difficulty wins; the status the user left out comes from the alias
+	if len(values) != 3 || values["name"] != "Two Sum" || values["difficulty"] != "Hard" || values["status"] != "Solved" {
+		t.Errorf("merged options = %v, want name Two Sum, difficulty Hard and status Solved", values)
+	}
+}
+
+func TestAliasInvocation(t *testing.T) {
+	ctx := context.Background()
+	bot, session := newTestBot(t, config.DiscordConfig{})
+	createAlias(t, bot, testUserID, "a", "add", `{"difficulty":"Easy","status":"Solved","category":"Array"}`)
+
+	resp := dispatch(t, bot, session, commandInteraction("a", stringOption("name", "Two Sum"), stringOption("solved_at", "2024-03-05")))
+	if !strings.Contains(resp.Data.Content, "Successfully added problem 'Two Sum'") {
+		t.Fatalf("response = %q, want the alias to run /add", responseText(resp))
+	}
+	dispatch(t, bot, session, commandInteraction("a", stringOption("name", "Word Ladder"), stringOption("difficulty", "Hard"), stringOption("solved_at", "2024-03-05")))
+
+	problems, err := bot.repo.ListProblems(ctx, testUserID, "", "", "", nil, true, 0, 0)
+	if err != nil {
+		t.Fatalf("ListProblems: %v", err)
+	}
+	difficulties := make(map[string]string, len(problems))
+	for _, p := range problems {
+		difficulties[p.ProblemName] = p.Difficulty
+		if p.Status != database.StatusSolved || p.Category != "Array" {
+			t.Errorf("%s stored as %s in %s, want the alias's Solved in Array", p.ProblemName, p.Status, p.Category)
+		}
+	}
+	if difficulties["Two Sum"] != database.DifficultyEasy || difficulties["Word Ladder"] != database.DifficultyHard {
+		t.Errorf("difficulties = %v, want the alias's Easy unless overridden with Hard", difficulties)
+	}
+
+	// Other members can't run someone else's alias
+	other := commandInteraction("a", stringOption("name", "Jump Game"))
+	other.Member.User.ID = "user-2"
+	if text := responseText(dispatch(t, bot, session, other)); !strings.Contains(text, "another member's alias") {
+		t.Errorf("response = %q, want a permission error", text)
+	}
+}
+
+func TestAliasCommands(t *testing.T) {
+	bot, session := newTestBot(t, config.DiscordConfig{})
+
+	resp := deferredResult(t, bot, session, aliasInteraction("create", stringOption("alias_name", "A"), stringOption("command_name", "/add"), stringOption("default_options", `{"difficulty":"Easy"}`)))
+	if resp.Data.Content != "Created /a as an alias for /add." {
+		t.Fatalf("response = %q, want the alias created", responseText(resp))
+	}
+	if _, ok := session.Commands.Load("a"); !ok {
+		t.Error("the alias wasn't registered with Discord")
+	}
+
+	tests := []struct {
+		name    string
+		options []*discordgo.ApplicationCommandInteractionDataOption
+		want    string
+	}{
+		{name: "taken", options: []*discordgo.ApplicationCommandInteractionDataOption{stringOption("alias_name", "a"), stringOption("command_name", "list")}, want: "/a is already taken"},
+		{name: "shadows a command", options: []*discordgo.ApplicationCommandInteractionDataOption{stringOption("alias_name", "list"), stringOption("command_name", "add")}, want: "/list is already a command"},
+		{name: "subcommands", options: []*discordgo.ApplicationCommandInteractionDataOption{stringOption("alias_name", "al"), stringOption("command_name", "alias")}, want: "isn't a command that can be aliased"},
+		{name: "unknown option", options: []*discordgo.ApplicationCommandInteractionDataOption{stringOption("alias_name", "b"), stringOption("command_name", "add"), stringOption("default_options", `{"speed":"fast"}`)}, want: `/add has no option named "speed"`},
+		{name: "invalid choice", options: []*discordgo.ApplicationCommandInteractionDataOption{stringOption("alias_name", "b"), stringOption("command_name", "add"), stringOption("default_options", `{"status":"Skipped"}`)}, want: `option "status" must be one of the values /add offers`},
+		{name: "not JSON", options: []*discordgo.ApplicationCommandInteractionDataOption{stringOption("alias_name", "b"), stringOption("command_name", "add"), stringOption("default_options", "difficulty=Easy")}, want: "must be a JSON object"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if text := responseText(deferredResult(t, bot, session, aliasInteraction("create", tt.options...))); !strings.Contains(text, tt.want) {
+				t.Errorf("response = %q, want it to contain %q", text, tt.want)
+			}
+		})
+	}
+
+	if text := responseText(dispatch(t, bot, session, aliasInteraction("list"))); !strings.Contains(text, "**/a** → /add `{\"difficulty\":\"Easy\"}`") {
+		t.Errorf("/alias list = %q, want the alias and its defaults", text)
+	}
+
+	resp = deferredResult(t, bot, session, aliasInteraction("delete", stringOption("alias_name", "/a")))
+	if resp.Data.Content != "Deleted the alias /a." {
+		t.Errorf("response = %q, want the alias deleted", responseText(resp))
+	}
+	if _, ok := session.Commands.Load("a"); ok {
+		t.Error("the deleted alias is still registered with Discord")
+	}
+	if text := responseText(dispatch(t, bot, session, aliasInteraction("list"))); !strings.Contains(text, "You don't have any aliases.") {
+		t.Errorf("/alias list = %q, want no aliases", text)
+	}
+}
@@ -0,0 +1,76 @@
+package bot
+
+import (
+	"context"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// CommandHandler handles a slash command interaction and returns the response to send
+type CommandHandler func(ctx context.Context, s DiscordSession, i *discordgo.InteractionCreate) (*discordgo.InteractionResponse, error)
+
+// CommandRegistry collects command definitions together with their handlers so each
+// command is declared in exactly one place
+type CommandRegistry struct {
+	commands []*discordgo.ApplicationCommand
+	handlers map[string]CommandHandler
+}
+
+// NewCommandRegistry creates an empty command registry
+func NewCommandRegistry() *CommandRegistry {
+	return &CommandRegistry{
+		handlers: make(map[string]CommandHandler),
+	}
+}
+
+// Register adds a command definition and its handler, returning the registry for chaining
+func (r *CommandRegistry) Register(def *discordgo.ApplicationCommand, handler CommandHandler) *CommandRegistry {
+	r.commands = append(r.commands, def)
+	r.handlers[def.Name] = handler
+	return r
+}
+
+// RegisterGroup adds a command whose options are subcommands, routing each subcommand
+// to its handler under the key "command/subcommand"
+func (r *CommandRegistry) RegisterGroup(def *discordgo.ApplicationCommand, subcommands map[string]CommandHandler) *CommandRegistry {
+	r.commands = append(r.commands, def)
+	for name, handler := range subcommands {
+		r.handlers[def.Name+"/"+name] = handler
+	}
+	return r
+}
+
+// RegisterModal routes submissions of the modal with the given custom ID to handler
+func (r *CommandRegistry) RegisterModal(customID string, handler CommandHandler) *CommandRegistry {
+	r.handlers[modalKey(customID)] = handler
+	return r
+}
+
+// RegisterComponent routes clicks on message components whose custom ID is prefix, or starts
+// with prefix followed by ":", to handler. The rest of the custom ID carries the component's arguments.
+func (r *CommandRegistry) RegisterComponent(prefix string, handler CommandHandler) *CommandRegistry {
+	r.handlers[componentKey(prefix)] = handler
+	return r
+}
+
+// modalKey returns the handler lookup key for a modal's custom ID
+func modalKey(customID string) string {
+	return "modal:" + customID
+}
+
+// componentKey returns the handler lookup key for a message component's custom ID prefix
+func componentKey(prefix string) string {
+	return "component:" + prefix
+}
+
+// Build returns the command definitions to register with Discord and the handler lookup by name
+func (r *CommandRegistry) Build() ([]*discordgo.ApplicationCommand, map[string]CommandHandler) {
+	commands := make([]*discordgo.ApplicationCommand, len(r.commands))
+	copy(commands, r.commands)
+
+	handlers := make(map[string]CommandHandler, len(r.handlers))
+	for name, handler := range r.handlers {
+		handlers[name] = handler
+	}
+	return commands, handlers
+}
@@ -0,0 +1,173 @@
+package bot
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/rs/zerolog/log"
+)
+
+// commandDiff lists the changes that bring the commands registered with Discord in line with the bot's
+type commandDiff struct {
+	Create []*discordgo.ApplicationCommand
+	Update []*discordgo.ApplicationCommand // Desired definitions carrying the ID of the command they replace
+	Delete []*discordgo.ApplicationCommand
+}
+
+// empty reports whether the registered commands already match
+func (d commandDiff) empty() bool {
+	return len(d.Create) == 0 && len(d.Update) == 0 && len(d.Delete) == 0
+}
+
+// diffCommands matches current and desired commands by name. Desired commands missing from current
+// are created and ones whose definition differs are updated. Current commands no longer desired are
+// deleted if they carry prefix, or if owned, the names this bot has registered, lists them. Without
+// a prefix every name would match, so only owned commands are deleted and those of instances running
+// under other prefixes are left alone.
+func diffCommands(current, desired []*discordgo.ApplicationCommand, owned map[string]bool, prefix string) commandDiff {
+	existing := make(map[string]*discordgo.ApplicationCommand, len(current))
+	for _, command := range current {
+		existing[command.Name] = command
+	}
+
+	var diff commandDiff
+	for _, command := range desired {
+		registered, ok := existing[command.Name]
+		delete(existing, command.Name)
+		switch {
+		case !ok:
+			diff.Create = append(diff.Create, command)
+		case commandFingerprint(registered) != commandFingerprint(command):
+			update := *command
+			update.ID = registered.ID
+			diff.Update = append(diff.Update, &update)
+		}
+	}
+
+	// Walk current again rather than the map so deletions keep Discord's order
+	for _, command := range current {
+		if _, ok := existing[command.Name]; ok && (owned[command.Name] || prefix != "" && strings.HasPrefix(command.Name, prefix)) {
+			diff.Delete = append(diff.Delete, command)
+		}
+	}
+	return diff
+}
+
+// commandFingerprint hashes the parts of a command definition the bot controls, ignoring the IDs and
+// version Discord assigns. Fields Discord fills with defaults are normalized first so a command
+// read back from Discord matches the definition it was registered from.
+func commandFingerprint(command *discordgo.ApplicationCommand) string {
+	commandType := command.Type
+	if commandType == 0 {
+		commandType = discordgo.ChatApplicationCommand
+	}
+	// Commands are usable in DMs unless they say otherwise
+	dmPermission := command.DMPermission == nil || *command.DMPermission
+	// Unset permissions come back as null; an explicit 0 is a real restriction to admins
+	var memberPermissions int64 = -1
+	if command.DefaultMemberPermissions != nil {
+		memberPermissions = *command.DefaultMemberPermissions
+	}
+
+	data, err := json.Marshal(struct {
+		Type                     discordgo.ApplicationCommandType
+		Name                     string
+		Description              string
+		Options                  []*discordgo.ApplicationCommandOption
+		DefaultMemberPermissions int64
+		DMPermission             bool
+	}{commandType, command.Name, command.Description, command.Options, memberPermissions, dmPermission})
+	if err != nil {
+		// Every field is plain data, so this can't happen; an unmatched fingerprint only forces an update
+		return ""
+	}
+
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// desiredCommands returns the bot's command definitions and users' aliases, named with the
+// configured command prefix
+func (b *Bot) desiredCommands(ctx context.Context) ([]*discordgo.ApplicationCommand, error) {
+	aliases, err := b.aliasCommands(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load command aliases: %w", err)
+	}
+
+	commands := make([]*discordgo.ApplicationCommand, 0, len(b.commands)+len(aliases))
+	for _, def := range append(b.commands[:len(b.commands):len(b.commands)], aliases...) {
+		command := *def
+		command.Name = b.cfg.CommandPrefix + def.Name
+		commands = append(commands, &command)
+	}
+	return commands, nil
+}
+
+// syncCommands brings the commands registered with Discord in line with the bot's, touching only the
+// ones that changed so restarts don't recreate every command. Commands carrying the bot's prefix that
+// it no longer defines, such as deleted aliases or renamed commands, are removed, along with any
+// this bot registered while running.
+func (b *Bot) syncCommands() error {
+	// Alias changes sync too, so keep concurrent runs from diffing against the same stale list
+	b.syncMu.Lock()
+	defer b.syncMu.Unlock()
+
+	desired, err := b.desiredCommands(b.ctx)
+	if err != nil {
+		return err
+	}
+
+	current, err := b.session.ApplicationCommands(b.applicationID, b.cfg.GuildID)
+	if err != nil {
+		return fmt.Errorf("failed to list registered commands: %w", err)
+	}
+
+	// Names stay owned until a sync that no longer wants them succeeds, so failed deletions are retried
+	owned := make(map[string]bool, len(b.ownedCommands)+len(desired))
+	for name := range b.ownedCommands {
+		owned[name] = true
+	}
+	for _, command := range desired {
+		owned[command.Name] = true
+	}
+	b.ownedCommands = owned
+
+	diff := diffCommands(current, desired, owned, b.cfg.CommandPrefix)
+	if diff.empty() {
+		log.Info().Int("commands", len(desired)).Msg("Registered commands are up to date")
+		return nil
+	}
+
+	for _, command := range diff.Create {
+		if _, err := b.session.ApplicationCommandCreate(b.applicationID, b.cfg.GuildID, command); err != nil {
+			return fmt.Errorf("failed to create command %s: %w", command.Name, err)
+		}
+	}
+	for _, command := range diff.Update {
+		if _, err := b.session.ApplicationCommandEdit(b.applicationID, b.cfg.GuildID, command.ID, command); err != nil {
+			return fmt.Errorf("failed to update command %s: %w", command.Name, err)
+		}
+	}
+	for _, command := range diff.Delete {
+		if err := b.session.ApplicationCommandDelete(b.applicationID, b.cfg.GuildID, command.ID); err != nil {
+			return fmt.Errorf("failed to delete command %s: %w", command.Name, err)
+		}
+	}
+
+	b.ownedCommands = make(map[string]bool, len(desired))
+	for _, command := range desired {
+		b.ownedCommands[command.Name] = true
+	}
+
+	log.Info().
+		Int("created", len(diff.Create)).
+		Int("updated", len(diff.Update)).
+		Int("deleted", len(diff.Delete)).
+		Msg("Synchronised registered commands")
+	return nil
+}
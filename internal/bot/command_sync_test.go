@@ -0,0 +1,146 @@
+package bot
+
+import (
+	"testing"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/yugonline/grind_review_bot/config"
+)
+
+func TestDiffCommands(t *testing.T) {
+	dmAllowed := true
+	desired := []*discordgo.ApplicationCommand{
+		{Name: "add", Description: "Add a problem"},
+		{Name: "list", Description: "List your problems"},
+		{Name: "stats", Description: "Show your stats"},
+	}
+	current := []*discordgo.ApplicationCommand{
+		// Unchanged, but read back with the IDs and defaults Discord fills in
+		{ID: "1", Version: "7", Type: discordgo.ChatApplicationCommand, Name: "add", Description: "Add a problem", DMPermission: &dmAllowed},
+		{ID: "2", Name: "list", Description: "List problems"},
+		{ID: "3", Name: "old-alias", Description: "A deleted alias"},
+		{ID: "4", Name: "grind-add", Description: "Another instance's command"},
+	}
+	owned := map[string]bool{"add": true, "list": true, "stats": true, "old-alias": true}
+
+	diff := diffCommands(current, desired, owned, "")
+
+	if names := commandNames(diff.Create); len(names) != 1 || names[0] != "stats" {
+		t.Errorf("Create = %v, want [stats]", names)
+	}
+	if len(diff.Update) != 1 || diff.Update[0].Name != "list" || diff.Update[0].ID != "2" {
+		t.Errorf("Update = %v, want [list] carrying ID 2", commandNames(diff.Update))
+	}
+	if names := commandNames(diff.Delete); len(names) != 1 || names[0] != "old-alias" {
+		t.Errorf("Delete = %v, want [old-alias]", names)
+	}
+}
+
+func TestDiffCommandsUpToDate(t *testing.T) {
+	dmAllowed := true
+	desired := []*discordgo.ApplicationCommand{{Name: "add", Description: "Add a problem"}}
+	current := []*discordgo.ApplicationCommand{{ID: "1", Type: discordgo.ChatApplicationCommand, Name: "add", Description: "Add a problem", DMPermission: &dmAllowed}}
+
+	if diff := diffCommands(current, desired, map[string]bool{"add": true}, ""); !diff.empty() {
+		t.Errorf("diff = %+v, want no changes", diff)
+	}
+}
+
+func TestDiffCommandsKeepsUnownedCommands(t *testing.T) {
+	// With no prefix every name matches, so ownership alone must protect sibling instances' commands
+	current := []*discordgo.ApplicationCommand{
+		{ID: "1", Name: "staging-add", Description: "Add a problem"},
+		{ID: "2", Name: "staging-list", Description: "List your problems"},
+	}
+
+	if diff := diffCommands(current, nil, map[string]bool{}, ""); len(diff.Delete) != 0 {
+		t.Errorf("Delete = %v, want none", commandNames(diff.Delete))
+	}
+}
+
+func TestDiffCommandsDeletesStalePrefixedCommands(t *testing.T) {
+	// After a restart nothing is owned yet, so the prefix alone marks the stale commands as the bot's
+	desired := []*discordgo.ApplicationCommand{{Name: "grind-review", Description: "Review a problem"}}
+	current := []*discordgo.ApplicationCommand{
+		{ID: "1", Name: "grind-review", Description: "Review a problem"},
+		{ID: "2", Name: "grind-mark-reviewed", Description: "A renamed command"},
+		{ID: "3", Name: "staging-add", Description: "Another instance's command"},
+	}
+
+	diff := diffCommands(current, desired, map[string]bool{}, "grind-")
+	if names := commandNames(diff.Delete); len(names) != 1 || names[0] != "grind-mark-reviewed" {
+		t.Errorf("Delete = %v, want [grind-mark-reviewed]", names)
+	}
+}
+
+func TestSyncCommandsDeletesStaleCommandsAfterRestart(t *testing.T) {
+	bot, session := newTestBot(t, config.DiscordConfig{GuildID: "guild-1", CommandPrefix: "grind-"})
+	bot.applicationID = "app-1"
+	session.Commands.Store("stale", &discordgo.ApplicationCommand{ID: "stale", Name: "grind-mark-reviewed", Description: "A renamed command"})
+	session.Commands.Store("sibling", &discordgo.ApplicationCommand{ID: "sibling", Name: "staging-add", Description: "Another instance's command"})
+
+	if len(bot.ownedCommands) != 0 {
+		t.Fatalf("ownedCommands = %v before the first sync, want none", bot.ownedCommands)
+	}
+	if err := bot.syncCommands(); err != nil {
+		t.Fatalf("syncCommands: %v", err)
+	}
+
+	deleted := session.Calls("ApplicationCommandDelete")
+	if len(deleted) != 1 || deleted[0].Args[2] != "stale" {
+		t.Errorf("deleted %v, want only the stale grind-mark-reviewed", deleted)
+	}
+	if _, ok := session.Commands.Load("sibling"); !ok {
+		t.Error("deleted another instance's command")
+	}
+}
+
+func TestCommandFingerprint(t *testing.T) {
+	dmAllowed, dmDenied := true, false
+	var adminOnly int64
+
+	tests := []struct {
+		name       string
+		registered *discordgo.ApplicationCommand
+		want       *discordgo.ApplicationCommand
+		same       bool
+	}{
+		{
+			name:       "default type and DM permission",
+			registered: &discordgo.ApplicationCommand{ID: "1", Version: "2", Type: discordgo.ChatApplicationCommand, Name: "add", DMPermission: &dmAllowed},
+			want:       &discordgo.ApplicationCommand{Name: "add"},
+			same:       true,
+		},
+		{
+			name:       "DM permission revoked",
+			registered: &discordgo.ApplicationCommand{Name: "add", DMPermission: &dmDenied},
+			want:       &discordgo.ApplicationCommand{Name: "add"},
+		},
+		{
+			name:       "restricted to admins",
+			registered: &discordgo.ApplicationCommand{Name: "add"},
+			want:       &discordgo.ApplicationCommand{Name: "add", DefaultMemberPermissions: &adminOnly},
+		},
+		{
+			name:       "option changed",
+			registered: &discordgo.ApplicationCommand{Name: "add", Options: []*discordgo.ApplicationCommandOption{{Name: "name", Required: true}}},
+			want:       &discordgo.ApplicationCommand{Name: "add", Options: []*discordgo.ApplicationCommandOption{{Name: "name"}}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if same := commandFingerprint(tt.registered) == commandFingerprint(tt.want); same != tt.same {
+				t.Errorf("fingerprints match = %v, want %v", same, tt.same)
+			}
+		})
+	}
+}
+
+// commandNames lists the names of commands, in order
+func commandNames(commands []*discordgo.ApplicationCommand) []string {
+	names := make([]string, len(commands))
+	for i, command := range commands {
+		names[i] = command.Name
+	}
+	return names
+}
@@ -1,8 +1,968 @@
 package bot
 
+import (
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/yugonline/grind_review_bot/internal/database"
+)
+
+// commandRegistry defines every slash command alongside the handler that serves it
+func (b *Bot) commandRegistry() *CommandRegistry {
+	return NewCommandRegistry().
+		Register(&discordgo.ApplicationCommand{
+			Name:        "add",
+			Description: "Add a solved problem to your review list",
+			Options: []*discordgo.ApplicationCommandOption{
+				{
+					Type:        discordgo.ApplicationCommandOptionString,
+					Name:        "name",
+					Description: "Problem name",
+					Required:    true,
+				},
+				{
+					Type:        discordgo.ApplicationCommandOptionString,
+					Name:        "difficulty",
+					Description: "Problem difficulty",
+					Required:    true,
+					Choices: []*discordgo.ApplicationCommandOptionChoice{
+						{
+							Name:  "Easy",
+							Value: "Easy",
+						},
+						{
+							Name:  "Medium",
+							Value: "Medium",
+						},
+						{
+							Name:  "Hard",
+							Value: "Hard",
+						},
+					},
+				},
+				{
+					Type:        discordgo.ApplicationCommandOptionString,
+					Name:        "status",
+					Description: "How did you solve it?",
+					Required:    true,
+					Choices: []*discordgo.ApplicationCommandOptionChoice{
+						{
+							Name:  "Solved",
+							Value: "Solved",
+						},
+						{
+							Name:  "Needed Hint",
+							Value: "Needed Hint",
+						},
+						{
+							Name:  "Stuck",
+							Value: "Stuck",
+						},
+					},
+				},
+				{
+					Type:        discordgo.ApplicationCommandOptionString,
+					Name:        "solved_at",
+					Description: "Date you solved it (YYYY-MM-DD, today or yesterday)",
+					Required:    true,
+				},
+				{
+					Type:        discordgo.ApplicationCommandOptionString,
+					Name:        "perceived_difficulty",
+					Description: "How hard it felt to you, if different from the official difficulty",
+					Required:    false,
+					Choices: []*discordgo.ApplicationCommandOptionChoice{
+						{
+							Name:  "Easy",
+							Value: "Easy",
+						},
+						{
+							Name:  "Medium",
+							Value: "Medium",
+						},
+						{
+							Name:  "Hard",
+							Value: "Hard",
+						},
+					},
+				},
+				{
+					Type:        discordgo.ApplicationCommandOptionString,
+					Name:        "category",
+					Description: "Problem category/topic (guessed from the name if omitted)",
+					Required:    false,
+				},
+				{
+					Type:        discordgo.ApplicationCommandOptionString,
+					Name:        "link",
+					Description: "Link to the problem",
+					Required:    false,
+				},
+				{
+					Type:        discordgo.ApplicationCommandOptionString,
+					Name:        "tags",
+					Description: "Tags, comma separated (e.g. 'dp,recursion,trees')",
+					Required:    false,
+				},
+				{
+					Type:        discordgo.ApplicationCommandOptionString,
+					Name:        "notes",
+					Description: "Your notes about the problem",
+					Required:    false,
+				},
+			},
+		}, b.handleAddCommand).
+		Register(&discordgo.ApplicationCommand{
+			Name:        "list",
+			Description: "List your solved problems",
+			Options: []*discordgo.ApplicationCommandOption{
+				{
+					Type:        discordgo.ApplicationCommandOptionString,
+					Name:        "status",
+					Description: "Filter by status",
+					Required:    false,
+					Choices: []*discordgo.ApplicationCommandOptionChoice{
+						{
+							Name:  "Solved",
+							Value: "Solved",
+						},
+						{
+							Name:  "Needed Hint",
+							Value: "Needed Hint",
+						},
+						{
+							Name:  "Stuck",
+							Value: "Stuck",
+						},
+					},
+				},
+				{
+					Type:        discordgo.ApplicationCommandOptionString,
+					Name:        "difficulty",
+					Description: "Filter by difficulty",
+					Required:    false,
+					Choices: []*discordgo.ApplicationCommandOptionChoice{
+						{
+							Name:  "Easy",
+							Value: "Easy",
+						},
+						{
+							Name:  "Medium",
+							Value: "Medium",
+						},
+						{
+							Name:  "Hard",
+							Value: "Hard",
+						},
+					},
+				},
+				{
+					Type:        discordgo.ApplicationCommandOptionString,
+					Name:        "category",
+					Description: "Filter by category/topic",
+					Required:    false,
+				},
+				{
+					Type:        discordgo.ApplicationCommandOptionString,
+					Name:        "tags",
+					Description: "Filter by tags, comma separated (e.g. 'dp,recursion')",
+					Required:    false,
+				},
+				{
+					Type:        discordgo.ApplicationCommandOptionString,
+					Name:        "tag_match",
+					Description: "Whether problems need any or all of the tags (default any)",
+					Required:    false,
+					Choices: []*discordgo.ApplicationCommandOptionChoice{
+						{
+							Name:  "Any tag",
+							Value: tagMatchAny,
+						},
+						{
+							Name:  "All tags",
+							Value: tagMatchAll,
+						},
+					},
+				},
+				{
+					Type:        discordgo.ApplicationCommandOptionString,
+					Name:        "date_from",
+					Description: "Only problems solved on or after this date (YYYY-MM-DD, today, yesterday)",
+					Required:    false,
+				},
+				{
+					Type:        discordgo.ApplicationCommandOptionString,
+					Name:        "date_to",
+					Description: "Only problems solved on or before this date (YYYY-MM-DD, today, yesterday)",
+					Required:    false,
+				},
+				{
+					Type:        discordgo.ApplicationCommandOptionInteger,
+					Name:        "limit",
+					Description: "Maximum number of problems to show",
+					Required:    false,
+					MinValue:    &[]float64{1}[0],
+					MaxValue:    50,
+				},
+				{
+					Type:        discordgo.ApplicationCommandOptionBoolean,
+					Name:        "include_archived",
+					Description: "Also show archived problems",
+					Required:    false,
+				},
+				{
+					Type:        discordgo.ApplicationCommandOptionBoolean,
+					Name:        "json",
+					Description: "Return the problems as JSON for other tools",
+					Required:    false,
+				},
+				ephemeralOption,
+			},
+		}, b.withVisibility(false, b.handleListCommand)).
+		Register(&discordgo.ApplicationCommand{
+			Name:        "get",
+			Description: "Get details of a specific problem",
+			Options: []*discordgo.ApplicationCommandOption{
+				{
+					Type:         discordgo.ApplicationCommandOptionInteger,
+					Name:         "id",
+					Description:  "Problem ID",
+					Autocomplete: true,
+					Required:     false,
+					MinValue:     &[]float64{1}[0],
+				},
+				{
+					Type:        discordgo.ApplicationCommandOptionString,
+					Name:        "name",
+					Description: "Problem name (used when no ID is given)",
+					Required:    false,
+				},
+				{
+					Type:        discordgo.ApplicationCommandOptionBoolean,
+					Name:        "json",
+					Description: "Return the problem as JSON for other tools",
+					Required:    false,
+				},
+				ephemeralOption,
+			},
+		}, b.requireOwnedProblem("view", b.withVisibility(false, b.handleGetCommand))).
+		Register(&discordgo.ApplicationCommand{
+			Name:        "search",
+			Description: "Search your problem names and notes",
+			Options: []*discordgo.ApplicationCommandOption{
+				{
+					Type:        discordgo.ApplicationCommandOptionString,
+					Name:        "query",
+					Description: "Words to look for",
+					Required:    true,
+				},
+				{
+					Type:        discordgo.ApplicationCommandOptionInteger,
+					Name:        "limit",
+					Description: "Maximum number of results (default 10)",
+					Required:    false,
+					MinValue:    &[]float64{1}[0],
+					MaxValue:    25,
+				},
+			},
+		}, b.handleSearchCommand).
+		Register(&discordgo.ApplicationCommand{
+			Name:        "edit",
+			Description: "Edit a problem entry",
+			Options: []*discordgo.ApplicationCommandOption{
+				{
+					Type:         discordgo.ApplicationCommandOptionInteger,
+					Name:         "id",
+					Description:  "Problem ID",
+					Autocomplete: true,
+					Required:     true,
+					MinValue:     &[]float64{1}[0],
+				},
+				{
+					Type:        discordgo.ApplicationCommandOptionString,
+					Name:        "name",
+					Description: "Problem name",
+					Required:    false,
+				},
+				{
+					Type:        discordgo.ApplicationCommandOptionString,
+					Name:        "difficulty",
+					Description: "Problem difficulty",
+					Required:    false,
+					Choices: []*discordgo.ApplicationCommandOptionChoice{
+						{
+							Name:  "Easy",
+							Value: "Easy",
+						},
+						{
+							Name:  "Medium",
+							Value: "Medium",
+						},
+						{
+							Name:  "Hard",
+							Value: "Hard",
+						},
+					},
+				},
+				{
+					Type:        discordgo.ApplicationCommandOptionString,
+					Name:        "perceived_difficulty",
+					Description: "How hard it felt to you, if different from the official difficulty",
+					Required:    false,
+					Choices: []*discordgo.ApplicationCommandOptionChoice{
+						{
+							Name:  "Easy",
+							Value: "Easy",
+						},
+						{
+							Name:  "Medium",
+							Value: "Medium",
+						},
+						{
+							Name:  "Hard",
+							Value: "Hard",
+						},
+						{
+							Name:  "Same as official",
+							Value: perceivedSameAsOfficial,
+						},
+					},
+				},
+				{
+					Type:        discordgo.ApplicationCommandOptionString,
+					Name:        "category",
+					Description: "Problem category/topic",
+					Required:    false,
+				},
+				{
+					Type:        discordgo.ApplicationCommandOptionString,
+					Name:        "status",
+					Description: "How did you solve it?",
+					Required:    false,
+					Choices: []*discordgo.ApplicationCommandOptionChoice{
+						{
+							Name:  "Solved",
+							Value: "Solved",
+						},
+						{
+							Name:  "Needed Hint",
+							Value: "Needed Hint",
+						},
+						{
+							Name:  "Stuck",
+							Value: "Stuck",
+						},
+					},
+				},
+				{
+					Type:        discordgo.ApplicationCommandOptionString,
+					Name:        "solved_at",
+					Description: "Date you solved it (YYYY-MM-DD, today or yesterday)",
+					Required:    false,
+				},
+				{
+					Type:        discordgo.ApplicationCommandOptionString,
+					Name:        "link",
+					Description: "Link to the problem",
+					Required:    false,
+				},
+				{
+					Type:        discordgo.ApplicationCommandOptionString,
+					Name:        "tags",
+					Description: "Tags, comma separated (e.g. 'dp,recursion,trees')",
+					Required:    false,
+				},
+				{
+					Type:        discordgo.ApplicationCommandOptionString,
+					Name:        "notes",
+					Description: "Your notes about the problem",
+					Required:    false,
+				},
+			},
+		}, b.requireOwnedProblem("edit", b.handleEditCommand)).
+		Register(&discordgo.ApplicationCommand{
+			Name:        "delete",
+			Description: "Delete a problem entry",
+			Options: []*discordgo.ApplicationCommandOption{
+				{
+					Type:         discordgo.ApplicationCommandOptionInteger,
+					Name:         "id",
+					Description:  "Problem ID",
+					Autocomplete: true,
+					Required:     true,
+					MinValue:     &[]float64{1}[0],
+				},
+			},
+		}, b.requireOwnedProblem("delete", b.handleDeleteCommand)).
+		Register(&discordgo.ApplicationCommand{
+			Name:        "archive",
+			Description: "Take a problem out of review rotation without deleting it",
+			Options: []*discordgo.ApplicationCommandOption{
+				{
+					Type:         discordgo.ApplicationCommandOptionInteger,
+					Name:         "id",
+					Description:  "Problem ID",
+					Autocomplete: true,
+					Required:     true,
+					MinValue:     &[]float64{1}[0],
+				},
+			},
+		}, b.requireOwnedProblem("archive", b.handleArchiveCommand(true))).
+		Register(&discordgo.ApplicationCommand{
+			Name:        "unarchive",
+			Description: "Put an archived problem back into review rotation",
+			Options: []*discordgo.ApplicationCommandOption{
+				{
+					Type:         discordgo.ApplicationCommandOptionInteger,
+					Name:         "id",
+					Description:  "Problem ID",
+					Autocomplete: true,
+					Required:     true,
+					MinValue:     &[]float64{1}[0],
+				},
+			},
+		}, b.requireOwnedProblem("unarchive", b.handleArchiveCommand(false))).
+		Register(&discordgo.ApplicationCommand{
+			Name:        "stats",
+			Description: "View your LeetCode problem solving statistics",
+		}, b.deferred(false, b.handleStatsCommand)).
+		Register(&discordgo.ApplicationCommand{
+			Name:        "streak",
+			Description: "Show your current and longest streaks with a calendar of recent solves",
+		}, b.deferred(false, b.handleStreakCommand)).
+		Register(&discordgo.ApplicationCommand{
+			Name:        "heatmap",
+			Description: "Show a calendar of your solves over the last year",
+			Options: []*discordgo.ApplicationCommandOption{
+				{
+					Type:        discordgo.ApplicationCommandOptionString,
+					Name:        "timezone",
+					Description: "Time zone to count days in, e.g. America/New_York (defaults to UTC)",
+					Required:    false,
+				},
+			},
+		}, b.deferred(false, b.handleHeatmapCommand)).
+		Register(&discordgo.ApplicationCommand{
+			Name:        "profile",
+			Description: "Show an activity overview for you or another member",
+			Options: []*discordgo.ApplicationCommandOption{
+				{
+					Type:        discordgo.ApplicationCommandOptionUser,
+					Name:        "user",
+					Description: "Member whose profile to show (must have a public profile)",
+					Required:    false,
+				},
+			},
+		}, b.deferred(false, b.handleProfileCommand)).
+		Register(&discordgo.ApplicationCommand{
+			Name:        "compare",
+			Description: "Compare your progress with another member or the server average",
+			Options: []*discordgo.ApplicationCommandOption{
+				{
+					Type:        discordgo.ApplicationCommandOptionUser,
+					Name:        "user",
+					Description: "Member to compare with (must have a public profile)",
+					Required:    false,
+				},
+			},
+		}, b.deferred(false, b.handleCompareCommand)).
+		Register(&discordgo.ApplicationCommand{
+			Name:        "leaderboard",
+			Description: "Rank the members with public profiles",
+			Options: []*discordgo.ApplicationCommandOption{
+				{
+					Type:        discordgo.ApplicationCommandOptionString,
+					Name:        "metric",
+					Description: "What to rank by (default problems solved)",
+					Required:    false,
+					Choices: []*discordgo.ApplicationCommandOptionChoice{
+						{
+							Name:  "Problems solved",
+							Value: database.LeaderboardSolved,
+						},
+						{
+							Name:  "Reviews",
+							Value: database.LeaderboardReviews,
+						},
+					},
+				},
+				{
+					Type:        discordgo.ApplicationCommandOptionInteger,
+					Name:        "page",
+					Description: "Page to show",
+					Required:    false,
+					MinValue:    &[]float64{1}[0],
+				},
+			},
+		}, b.handleLeaderboardCommand).
+		Register(&discordgo.ApplicationCommand{
+			Name:        "privacy",
+			Description: "View or change whether other members can see your progress",
+			Options: []*discordgo.ApplicationCommandOption{
+				{
+					Type:        discordgo.ApplicationCommandOptionBoolean,
+					Name:        "public",
+					Description: "Make your profile visible to other members",
+					Required:    false,
+				},
+				{
+					Type:        discordgo.ApplicationCommandOptionString,
+					Name:        "responses",
+					Description: "Who sees the replies to /list and /get",
+					Required:    false,
+					Choices: []*discordgo.ApplicationCommandOptionChoice{
+						{
+							Name:  "Default",
+							Value: "default",
+						},
+						{
+							Name:  "Everyone",
+							Value: database.ResponseVisibilityPublic,
+						},
+						{
+							Name:  "Only me",
+							Value: database.ResponseVisibilityPrivate,
+						},
+					},
+				},
+			},
+		}, b.handlePrivacyCommand).
+		Register(&discordgo.ApplicationCommand{
+			Name:        "import-history",
+			Description: "Log problems from your \"Solved: <name> [difficulty] [category]\" messages in this channel",
+			Options: []*discordgo.ApplicationCommandOption{
+				{
+					Type:        discordgo.ApplicationCommandOptionInteger,
+					Name:        "count",
+					Description: "How many recent messages to read (default 50)",
+					Required:    false,
+					MinValue:    &[]float64{1}[0],
+					MaxValue:    maxImportMessageCount,
+				},
+			},
+		}, b.deferred(true, b.handleImportHistoryCommand)).
+		Register(&discordgo.ApplicationCommand{
+			Name:        "reminders",
+			Description: "View or change which problems your daily review reminders cover",
+			Options: []*discordgo.ApplicationCommandOption{
+				{
+					Type:        discordgo.ApplicationCommandOptionString,
+					Name:        "difficulty",
+					Description: "Only remind you about problems of this difficulty",
+					Required:    false,
+					Choices: []*discordgo.ApplicationCommandOptionChoice{
+						{
+							Name:  "Any",
+							Value: reminderFilterAny,
+						},
+						{
+							Name:  "Easy",
+							Value: database.DifficultyEasy,
+						},
+						{
+							Name:  "Medium",
+							Value: database.DifficultyMedium,
+						},
+						{
+							Name:  "Hard",
+							Value: database.DifficultyHard,
+						},
+					},
+				},
+				{
+					Type:        discordgo.ApplicationCommandOptionString,
+					Name:        "category",
+					Description: "Only remind you about problems in this category ('any' for all)",
+					Required:    false,
+				},
+			},
+		}, b.handleRemindersCommand).
+		Register(&discordgo.ApplicationCommand{
+			Name:        "api-token",
+			Description: "Create a token for the read-only HTTP API, replacing any existing one",
+		}, b.handleAPITokenCommand).
+		Register(&discordgo.ApplicationCommand{
+			Name:        "due",
+			Description: "List your problems due for review",
+			Options: []*discordgo.ApplicationCommandOption{
+				{
+					Type:        discordgo.ApplicationCommandOptionInteger,
+					Name:        "days_ahead",
+					Description: "Also list problems becoming due within this many days",
+					Required:    false,
+					MinValue:    &[]float64{0}[0],
+					MaxValue:    maxDueDaysAhead,
+				},
+				{
+					Type:        discordgo.ApplicationCommandOptionString,
+					Name:        "tags",
+					Description: "Filter by tags, comma separated (e.g. 'dp,recursion')",
+					Required:    false,
+				},
+				{
+					Type:        discordgo.ApplicationCommandOptionString,
+					Name:        "tag_match",
+					Description: "Whether problems need any or all of the tags (default any)",
+					Required:    false,
+					Choices: []*discordgo.ApplicationCommandOptionChoice{
+						{
+							Name:  "Any tag",
+							Value: tagMatchAny,
+						},
+						{
+							Name:  "All tags",
+							Value: tagMatchAll,
+						},
+					},
+				},
+			},
+		}, b.handleDueCommand).
+		RegisterGroup(&discordgo.ApplicationCommand{
+			Name:        "review",
+			Description: "Track and trigger problem reviews",
+			Options: []*discordgo.ApplicationCommandOption{
+				{
+					Type:        discordgo.ApplicationCommandOptionSubCommand,
+					Name:        "mark",
+					Description: "Mark a problem as reviewed",
+					Options: []*discordgo.ApplicationCommandOption{
+						{
+							Type:         discordgo.ApplicationCommandOptionInteger,
+							Name:         "id",
+							Description:  "Problem ID",
+							Autocomplete: true,
+							Required:     true,
+							MinValue:     &[]float64{1}[0],
+						},
+					},
+				},
+				{
+					Type:        discordgo.ApplicationCommandOptionSubCommand,
+					Name:        "history",
+					Description: "Show when a problem was reviewed",
+					Options: []*discordgo.ApplicationCommandOption{
+						{
+							Type:         discordgo.ApplicationCommandOptionInteger,
+							Name:         "id",
+							Description:  "Problem ID",
+							Autocomplete: true,
+							Required:     true,
+							MinValue:     &[]float64{1}[0],
+						},
+					},
+				},
+				{
+					Type:        discordgo.ApplicationCommandOptionSubCommand,
+					Name:        "trigger",
+					Description: "Send the daily review reminders now (admin only)",
+				},
+			},
+		}, map[string]CommandHandler{
+			"mark":    b.requireOwnedProblem("review", b.handleReviewMarkCommand),
+			"history": b.requireOwnedProblem("view", b.handleReviewHistoryCommand),
+			"trigger": b.requirePermission(PermissionAdmin, b.handleTriggerReviewCommand),
+		}).
+		RegisterGroup(&discordgo.ApplicationCommand{
+			Name:        "tag",
+			Description: "Manage your tags",
+			Options: []*discordgo.ApplicationCommandOption{
+				{
+					Type:        discordgo.ApplicationCommandOptionSubCommand,
+					Name:        "delete",
+					Description: "Remove a tag from all of your problems",
+					Options: []*discordgo.ApplicationCommandOption{
+						{
+							Type:        discordgo.ApplicationCommandOptionString,
+							Name:        "name",
+							Description: "Tag to remove",
+							Required:    true,
+						},
+						{
+							Type:        discordgo.ApplicationCommandOptionString,
+							Name:        "reassign_to",
+							Description: "Tag to apply to the affected problems instead",
+							Required:    false,
+						},
+					},
+				},
+			},
+		}, map[string]CommandHandler{
+			"delete": b.handleTagDeleteCommand,
+		}).
+		RegisterGroup(&discordgo.ApplicationCommand{
+			Name:        "alias",
+			Description: "Manage your command shortcuts",
+			Options: []*discordgo.ApplicationCommandOption{
+				{
+					Type:        discordgo.ApplicationCommandOptionSubCommand,
+					Name:        "create",
+					Description: "Create a shortcut for a command",
+					Options: []*discordgo.ApplicationCommandOption{
+						{
+							Type:        discordgo.ApplicationCommandOptionString,
+							Name:        "alias_name",
+							Description: "Name of the shortcut, e.g. 'a'",
+							Required:    true,
+						},
+						{
+							Type:        discordgo.ApplicationCommandOptionString,
+							Name:        "command_name",
+							Description: "Command it runs, e.g. 'add'",
+							Required:    true,
+						},
+						{
+							Type:        discordgo.ApplicationCommandOptionString,
+							Name:        "default_options",
+							Description: "JSON option values to fill in, e.g. {\"difficulty\":\"Easy\",\"status\":\"Solved\"}",
+							Required:    false,
+						},
+					},
+				},
+				{
+					Type:        discordgo.ApplicationCommandOptionSubCommand,
+					Name:        "list",
+					Description: "List your shortcuts",
+				},
+				{
+					Type:        discordgo.ApplicationCommandOptionSubCommand,
+					Name:        "delete",
+					Description: "Delete one of your shortcuts",
+					Options: []*discordgo.ApplicationCommandOption{
+						{
+							Type:        discordgo.ApplicationCommandOptionString,
+							Name:        "alias_name",
+							Description: "Shortcut to delete",
+							Required:    true,
+						},
+					},
+				},
+			},
+		}, map[string]CommandHandler{
+			"create": b.deferred(true, b.handleAliasCreateCommand),
+			"list":   b.handleAliasListCommand,
+			"delete": b.deferred(true, b.handleAliasDeleteCommand),
+		}).
+		RegisterGroup(&discordgo.ApplicationCommand{
+			Name:        "set",
+			Description: "Group problems into named sets such as Blind 75",
+			Options: []*discordgo.ApplicationCommandOption{
+				{
+					Type:        discordgo.ApplicationCommandOptionSubCommand,
+					Name:        "create",
+					Description: "Create a problem set",
+					Options: []*discordgo.ApplicationCommandOption{
+						{
+							Type:        discordgo.ApplicationCommandOptionString,
+							Name:        "name",
+							Description: "Name of the set",
+							Required:    true,
+						},
+						{
+							Type:        discordgo.ApplicationCommandOptionString,
+							Name:        "description",
+							Description: "What the set is for",
+							Required:    false,
+						},
+					},
+				},
+				{
+					Type:        discordgo.ApplicationCommandOptionSubCommand,
+					Name:        "add",
+					Description: "Add one of your problems to a set",
+					Options: []*discordgo.ApplicationCommandOption{
+						{
+							Type:        discordgo.ApplicationCommandOptionInteger,
+							Name:        "set_id",
+							Description: "ID of the set",
+							Required:    true,
+							MinValue:    &[]float64{1}[0],
+						},
+						{
+							Type:         discordgo.ApplicationCommandOptionInteger,
+							Name:         "problem_id",
+							Description:  "ID of the problem to add",
+							Required:     true,
+							Autocomplete: true,
+							MinValue:     &[]float64{1}[0],
+						},
+					},
+				},
+				{
+					Type:        discordgo.ApplicationCommandOptionSubCommand,
+					Name:        "remove",
+					Description: "Remove a problem from a set",
+					Options: []*discordgo.ApplicationCommandOption{
+						{
+							Type:        discordgo.ApplicationCommandOptionInteger,
+							Name:        "set_id",
+							Description: "ID of the set",
+							Required:    true,
+							MinValue:    &[]float64{1}[0],
+						},
+						{
+							Type:         discordgo.ApplicationCommandOptionInteger,
+							Name:         "problem_id",
+							Description:  "ID of the problem to remove",
+							Required:     true,
+							Autocomplete: true,
+							MinValue:     &[]float64{1}[0],
+						},
+					},
+				},
+				{
+					Type:        discordgo.ApplicationCommandOptionSubCommand,
+					Name:        "list",
+					Description: "List the built-in sets and your own",
+				},
+				{
+					Type:        discordgo.ApplicationCommandOptionSubCommand,
+					Name:        "review",
+					Description: "Start a review session over a set's problems",
+					Options: []*discordgo.ApplicationCommandOption{
+						{
+							Type:        discordgo.ApplicationCommandOptionInteger,
+							Name:        "set_id",
+							Description: "ID of the set to review",
+							Required:    true,
+							MinValue:    &[]float64{1}[0],
+						},
+					},
+				},
+				{
+					Type:        discordgo.ApplicationCommandOptionSubCommand,
+					Name:        "delete",
+					Description: "Delete one of your sets, keeping its problems",
+					Options: []*discordgo.ApplicationCommandOption{
+						{
+							Type:        discordgo.ApplicationCommandOptionInteger,
+							Name:        "set_id",
+							Description: "ID of the set to delete",
+							Required:    true,
+							MinValue:    &[]float64{1}[0],
+						},
+					},
+				},
+			},
+		}, map[string]CommandHandler{
+			"create": b.handleSetCreateCommand,
+			"add":    b.handleSetAddCommand,
+			"remove": b.handleSetRemoveCommand,
+			"list":   b.handleSetListCommand,
+			"review": b.handleSetReviewCommand,
+			"delete": b.handleSetDeleteCommand,
+		}).
+		Register(&discordgo.ApplicationCommand{
+			Name:        "dedupe",
+			Description: "Merge your problems that were logged more than once under the same name",
+			Options: []*discordgo.ApplicationCommandOption{
+				{
+					Type:        discordgo.ApplicationCommandOptionBoolean,
+					Name:        "dry_run",
+					Description: "Only list the duplicates without merging them",
+					Required:    false,
+				},
+			},
+		}, b.handleDedupeCommand).
+		Register(&discordgo.ApplicationCommand{
+			Name:        "admin-vacuum",
+			Description: "Compact the database and refresh query statistics (admin only)",
+		}, b.requirePermission(PermissionAdmin, b.deferred(true, b.handleAdminVacuumCommand))).
+		Register(&discordgo.ApplicationCommand{
+			Name:        "admin-backup",
+			Description: "Write a snapshot of the database to the backup destination (admin only)",
+		}, b.requirePermission(PermissionAdmin, b.deferred(true, b.handleAdminBackupCommand))).
+		Register(&discordgo.ApplicationCommand{
+			Name:        "admin-recalc",
+			Description: "Reconcile review counts with the review history (admin only)",
+			Options: []*discordgo.ApplicationCommandOption{
+				{
+					Type:        discordgo.ApplicationCommandOptionUser,
+					Name:        "user",
+					Description: "Only recalculate this member's stats (defaults to everyone)",
+					Required:    false,
+				},
+			},
+		}, b.requirePermission(PermissionAdmin, b.deferred(true, b.handleAdminRecalcCommand))).
+		Register(&discordgo.ApplicationCommand{
+			Name:        "admin-usage",
+			Description: "Show the members who run the most commands (admin only)",
+		}, b.requirePermission(PermissionAdmin, b.deferred(true, b.handleAdminUsageCommand))).
+		Register(&discordgo.ApplicationCommand{
+			Name: addToReviewListCommand,
+			Type: discordgo.MessageApplicationCommand,
+		}, b.handleContextMenuAdd).
+		RegisterModal(addProblemModalID, b.handleAddProblemModal).
+		RegisterComponent(reviewDoneButtonPrefix, b.handleReviewDoneButton).
+		RegisterComponent(reviewAllButtonPrefix, b.handleReviewAllButton).
+		RegisterComponent(addAnywayButtonPrefix, b.handleAddAnywayButton)
+}
+
 // isServerMember checks if a given user ID belongs to a server member
 func (b *Bot) isServerMember(userID string) bool {
 	// This is a placeholder. In production, you would check the guild members
 	// through the Discord API or maintain a cache of members.
 	return true // Simplified for now
-}
\ No newline at end of file
+}
+
+// interactionUser returns the user who triggered an interaction. Guild interactions carry
+// the user on i.Member, while DM interactions carry it on i.User.
+func interactionUser(i *discordgo.InteractionCreate) *discordgo.User {
+	if i.Member != nil && i.Member.User != nil {
+		return i.Member.User
+	}
+	if i.User != nil {
+		return i.User
+	}
+	return &discordgo.User{}
+}
+
+// interactionUserID returns the ID of the user who triggered an interaction
+func interactionUserID(i *discordgo.InteractionCreate) string {
+	return interactionUser(i).ID
+}
+
+// optionUser returns the full user picked in a user option. Discord sends the user along with
+// the interaction, so no API call is needed; only the ID is known if it is missing.
+func optionUser(i *discordgo.InteractionCreate, opt *discordgo.ApplicationCommandInteractionDataOption) *discordgo.User {
+	user := opt.UserValue(nil)
+	if resolved := i.ApplicationCommandData().Resolved; resolved != nil {
+		if full, ok := resolved.Users[user.ID]; ok {
+			return full
+		}
+	}
+	return user
+}
+
+// isDirectMessage reports whether an interaction came from a DM rather than a guild
+func isDirectMessage(i *discordgo.InteractionCreate) bool {
+	return i.Member == nil
+}
+
+// getSubcommand returns the invoked subcommand name and its options, or an empty name
+// and the top-level options if the command has no subcommands
+func getSubcommand(i *discordgo.InteractionCreate) (string, []*discordgo.ApplicationCommandInteractionDataOption) {
+	options := i.ApplicationCommandData().Options
+	if len(options) > 0 && options[0].Type == discordgo.ApplicationCommandOptionSubCommand {
+		return options[0].Name, options[0].Options
+	}
+	return "", options
+}
+
+// commandKey returns the handler lookup key for an interaction, including the subcommand if any.
+// Modal submissions are keyed by the modal's custom ID and component clicks by their custom ID prefix.
+// The configured command prefix is stripped, so keys match the names commands were registered with.
+func (b *Bot) commandKey(i *discordgo.InteractionCreate) string {
+	switch i.Type {
+	case discordgo.InteractionModalSubmit:
+		return modalKey(i.ModalSubmitData().CustomID)
+	case discordgo.InteractionMessageComponent:
+		prefix, _, _ := strings.Cut(i.MessageComponentData().CustomID, ":")
+		return componentKey(prefix)
+	}
+
+	name := strings.TrimPrefix(i.ApplicationCommandData().Name, b.cfg.CommandPrefix)
+	if sub, _ := getSubcommand(i); sub != "" {
+		return name + "/" + sub
+	}
+	return name
+}
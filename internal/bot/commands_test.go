@@ -0,0 +1,55 @@
+package bot
+
+import (
+	"testing"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/yugonline/grind_review_bot/config"
+)
+
+// directMessageInteraction builds a slash command sent in a DM, where Discord sets User
+// instead of Member
+func directMessageInteraction(name string) *discordgo.InteractionCreate {
+	return &discordgo.InteractionCreate{Interaction: &discordgo.Interaction{
+		Type:      discordgo.InteractionApplicationCommand,
+		ChannelID: "dm-channel",
+		User:      &discordgo.User{ID: testUserID, Username: "tester"},
+		Data:      discordgo.ApplicationCommandInteractionData{Name: name},
+	}}
+}
+
+func TestInteractionUser(t *testing.T) {
+	guild := commandInteraction("list")
+	dm := directMessageInteraction("list")
+	empty := &discordgo.InteractionCreate{Interaction: &discordgo.Interaction{}}
+
+	tests := []struct {
+		name   string
+		i      *discordgo.InteractionCreate
+		wantID string
+		wantDM bool
+	}{
+		{name: "guild", i: guild, wantID: testUserID},
+		{name: "direct message", i: dm, wantID: testUserID, wantDM: true},
+		{name: "no user", i: empty, wantID: "", wantDM: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := interactionUserID(tt.i); got != tt.wantID {
+				t.Errorf("interactionUserID = %q, want %q", got, tt.wantID)
+			}
+			if got := isDirectMessage(tt.i); got != tt.wantDM {
+				t.Errorf("isDirectMessage = %v, want %v", got, tt.wantDM)
+			}
+		})
+	}
+}
+
+func TestDirectMessageInteractionRejected(t *testing.T) {
+	bot, session := newTestBot(t, config.DiscordConfig{})
+
+	resp := dispatch(t, bot, session, directMessageInteraction("list"))
+	if resp.Data.Flags&discordgo.MessageFlagsEphemeral == 0 || resp.Data.Content != "Commands can only be used in the server, not in direct messages." {
+		t.Errorf("response = %+v, want the ephemeral DM refusal", resp.Data)
+	}
+}
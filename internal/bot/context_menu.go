@@ -0,0 +1,138 @@
+package bot
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/yugonline/grind_review_bot/internal/database"
+	"github.com/yugonline/grind_review_bot/pkg/leetcode"
+)
+
+// addToReviewListCommand is the name of the message context menu command
+const addToReviewListCommand = "Add to Review List"
+
+// addProblemModalID identifies the modal opened from the context menu
+const addProblemModalID = "add_problem"
+
+// urlPattern finds links in message content
+var urlPattern = regexp.MustCompile(`https?://\S+`)
+
+// handleContextMenuAdd opens a modal for logging a problem, pre-filled with the first link in the selected message
+func (b *Bot) handleContextMenuAdd(ctx context.Context, s DiscordSession, i *discordgo.InteractionCreate) (*discordgo.InteractionResponse, error) {
+	data := i.ApplicationCommandData()
+
+	link := ""
+	if data.Resolved != nil {
+		if message, ok := data.Resolved.Messages[data.TargetID]; ok {
+			link = urlPattern.FindString(message.Content)
+		}
+	}
+
+	return &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseModal,
+		Data: &discordgo.InteractionResponseData{
+			CustomID: addProblemModalID,
+			Title:    "Add to Review List",
+			Components: []discordgo.MessageComponent{
+				modalTextInput("name", "Problem name", "", "", true),
+				modalTextInput("link", "Link", link, "", false),
+				modalTextInput("difficulty", "Difficulty", "", "Easy, Medium or Hard", true),
+				modalTextInput("category", "Category", "", "Leave blank to guess from the name", false),
+				modalTextInput("status", "Status", database.StatusSolved, "Solved, Needed Hint or Stuck", true),
+			},
+		},
+	}, nil
+}
+
+// handleAddProblemModal logs the problem submitted through the context menu modal
+func (b *Bot) handleAddProblemModal(ctx context.Context, s DiscordSession, i *discordgo.InteractionCreate) (*discordgo.InteractionResponse, error) {
+	values := modalValues(i.ModalSubmitData())
+
+	difficulty, err := leetcode.NormalizeDifficulty(values["difficulty"])
+	if err != nil {
+		return nil, validationError(err.Error())
+	}
+	status, err := parseStatus(values["status"])
+	if err != nil {
+		return nil, validationError(err.Error())
+	}
+
+	problem := &database.ProblemEntry{
+		UserID:      interactionUserID(i),
+		ProblemName: values["name"],
+		Link:        values["link"],
+		Difficulty:  difficulty,
+		Status:      status,
+		SolvedAt:    time.Now(),
+	}
+
+	if category := values["category"]; category != "" {
+		problem.Category = category
+		if canonical, confidence := leetcode.NormalizeCategory(category); confidence > canonicalCategoryConfidence {
+			problem.Category = canonical
+		}
+	} else {
+		category, confidence := classifyCategory(problem.ProblemName)
+		if confidence < minCategoryConfidence {
+			return nil, validationError("Couldn't guess a category from the problem name. Please fill in the category.")
+		}
+		problem.Category = category
+	}
+
+	message := fmt.Sprintf("Successfully added problem '%s' (%s, %s)!", problem.ProblemName, problem.Difficulty, problem.Category)
+	if confirm, err := b.confirmDuplicate(ctx, i, problem, message); confirm != nil || err != nil {
+		return confirm, err
+	}
+	if err := b.createProblem(ctx, problem); err != nil {
+		return nil, err
+	}
+	return messageResponse(message), nil
+}
+
+// modalTextInput builds a single-line text input wrapped in its own row, as modals require
+func modalTextInput(customID, label, value, placeholder string, required bool) discordgo.ActionsRow {
+	return discordgo.ActionsRow{
+		Components: []discordgo.MessageComponent{
+			discordgo.TextInput{
+				CustomID:    customID,
+				Label:       label,
+				Style:       discordgo.TextInputShort,
+				Value:       value,
+				Placeholder: placeholder,
+				Required:    required,
+				MaxLength:   200,
+			},
+		},
+	}
+}
+
+// modalValues collects the trimmed text input values of a submitted modal by custom ID
+func modalValues(data discordgo.ModalSubmitInteractionData) map[string]string {
+	values := make(map[string]string)
+	for _, component := range data.Components {
+		row, ok := component.(*discordgo.ActionsRow)
+		if !ok {
+			continue
+		}
+		for _, inner := range row.Components {
+			if input, ok := inner.(*discordgo.TextInput); ok {
+				values[input.CustomID] = strings.TrimSpace(input.Value)
+			}
+		}
+	}
+	return values
+}
+
+// parseStatus matches a case-insensitive status name to its canonical form
+func parseStatus(s string) (string, error) {
+	for _, status := range []string{database.StatusSolved, database.StatusNeededHint, database.StatusStuck} {
+		if strings.EqualFold(strings.TrimSpace(s), status) {
+			return status, nil
+		}
+	}
+	return "", fmt.Errorf("unknown status %q, expected one of %s, %s or %s", s, database.StatusSolved, database.StatusNeededHint, database.StatusStuck)
+}
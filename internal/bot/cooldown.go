@@ -0,0 +1,69 @@
+package bot
+
+import (
+	"time"
+
+	"github.com/yugonline/grind_review_bot/pkg/cache"
+)
+
+// defaultCooldowns rate-limits commands that run expensive queries; configured
+// discord.command_cooldowns entries take precedence
+var defaultCooldowns = map[string]time.Duration{
+	"random": 30 * time.Second,
+	"stats":  60 * time.Second,
+}
+
+// CooldownManager tracks per-user, per-command cooldowns
+type CooldownManager struct {
+	expiries  *cache.TypedCache[time.Time]
+	durations map[string]time.Duration
+}
+
+// NewCooldownManager creates a cooldown manager with no commands rate-limited
+func NewCooldownManager() *CooldownManager {
+	return &CooldownManager{
+		expiries:  cache.NewTyped[time.Time](time.Minute, time.Minute),
+		durations: make(map[string]time.Duration),
+	}
+}
+
+// Configure sets how long users must wait between invocations of command; zero disables it
+func (m *CooldownManager) Configure(command string, duration time.Duration) {
+	m.durations[command] = duration
+}
+
+// Duration returns the cooldown configured for command
+func (m *CooldownManager) Duration(command string) time.Duration {
+	return m.durations[command]
+}
+
+// Check reports whether the user may run the command now. When the command is still
+// cooling down it returns the remaining wait time.
+func (m *CooldownManager) Check(userID, command string) (time.Duration, bool) {
+	expiry, found := m.expiries.Get(cooldownKey(userID, command))
+	if !found {
+		return 0, true
+	}
+	if remaining := time.Until(expiry); remaining > 0 {
+		return remaining, false
+	}
+	return 0, true
+}
+
+// Set starts a cooldown of duration for the user's next invocation of command
+func (m *CooldownManager) Set(userID, command string, duration time.Duration) {
+	if duration <= 0 {
+		return
+	}
+	m.expiries.SetWithExpiration(cooldownKey(userID, command), time.Now().Add(duration), duration)
+}
+
+// Close stops the background sweep of expired cooldowns
+func (m *CooldownManager) Close() {
+	m.expiries.Close()
+}
+
+// cooldownKey identifies a user's cooldown for a command
+func cooldownKey(userID, command string) string {
+	return "cooldown:" + userID + ":" + command
+}
@@ -0,0 +1,88 @@
+package bot
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/yugonline/grind_review_bot/config"
+)
+
+func TestCooldownManager(t *testing.T) {
+	m := NewCooldownManager()
+	defer m.Close()
+
+	if _, ok := m.Check("user-1", "stats"); !ok {
+		t.Fatal("a fresh command is cooling down")
+	}
+
+	m.Set("user-1", "stats", time.Minute)
+	remaining, ok := m.Check("user-1", "stats")
+	if ok || remaining <= 0 || remaining > time.Minute {
+		t.Errorf("Check = %v, %v; want about a minute left", remaining, ok)
+	}
+	if _, ok := m.Check("user-2", "stats"); !ok {
+		t.Error("the cooldown applies to another user")
+	}
+	if _, ok := m.Check("user-1", "streak"); !ok {
+		t.Error("the cooldown applies to another command")
+	}
+
+	m.Set("user-1", "streak", 0)
+	if _, ok := m.Check("user-1", "streak"); !ok {
+		t.Error("a zero cooldown blocks the command")
+	}
+}
+
+func TestDefaultCooldowns(t *testing.T) {
+	for command, want := range map[string]time.Duration{"random": 30 * time.Second, "stats": time.Minute} {
+		if got := defaultCooldowns[command]; got != want {
+			t.Errorf("default cooldown for %s = %v, want %v", command, got, want)
+		}
+	}
+}
+
+// countingHandler counts its invocations and answers with a plain message
+func countingHandler(calls *int) CommandHandler {
+	return func(ctx context.Context, s DiscordSession, i *discordgo.InteractionCreate) (*discordgo.InteractionResponse, error) {
+		*calls++
+		return messageResponse("Done."), nil
+	}
+}
+
+func TestCommandCooldownExpiry(t *testing.T) {
+	bot, session := newTestBot(t, config.DiscordConfig{CommandCooldowns: map[string]time.Duration{
+		"export": 50 * time.Millisecond,
+		"cheap":  0,
+	}})
+	var exports, cheap int
+	bot.commandHandlers["export"] = chain(countingHandler(&exports), bot.commandMiddleware()...)
+	bot.commandHandlers["cheap"] = chain(countingHandler(&cheap), bot.commandMiddleware()...)
+
+	dispatch(t, bot, session, commandInteraction("export"))
+	resp := dispatch(t, bot, session, commandInteraction("export"))
+	if resp.Data.Flags&discordgo.MessageFlagsEphemeral == 0 || !strings.Contains(responseText(resp), "on cooldown") {
+		t.Errorf("second export = %q, want an ephemeral cooldown message", responseText(resp))
+	}
+	if exports != 1 {
+		t.Errorf("export ran %d times during its cooldown, want 1", exports)
+	}
+
+	// Commands without a cooldown are never blocked
+	for range 3 {
+		dispatch(t, bot, session, commandInteraction("cheap"))
+	}
+	if cheap != 3 {
+		t.Errorf("cheap ran %d times, want 3", cheap)
+	}
+
+	time.Sleep(60 * time.Millisecond)
+	if resp := dispatch(t, bot, session, commandInteraction("export")); resp.Data.Content != "Done." {
+		t.Errorf("export after its cooldown = %q, want Done.", responseText(resp))
+	}
+	if exports != 2 {
+		t.Errorf("export ran %d times, want 2 once the cooldown expired", exports)
+	}
+}
@@ -0,0 +1,87 @@
+package bot
+
+import (
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// dateLayouts are the absolute date formats accepted wherever a command takes a date
+var dateLayouts = []string{"2006-01-02", "2006/01/02", "2006.01.02", "Jan 2 2006", "Jan 2, 2006", "2 Jan 2006"}
+
+// isoDateLayout is how dates are shown when the reader's locale has no layout of its own
+const isoDateLayout = "2006-01-02"
+
+// localeDateLayouts is how dates are conventionally written in each Discord locale. Locales
+// that write dates the ISO way, such as Swedish and Lithuanian, are left out.
+var localeDateLayouts = map[discordgo.Locale]string{
+	discordgo.EnglishUS:    "01/02/2006",
+	discordgo.EnglishGB:    "02/01/2006",
+	discordgo.French:       "02/01/2006",
+	discordgo.SpanishES:    "02/01/2006",
+	discordgo.Italian:      "02/01/2006",
+	discordgo.PortugueseBR: "02/01/2006",
+	discordgo.Greek:        "02/01/2006",
+	discordgo.Hindi:        "02/01/2006",
+	discordgo.Thai:         "02/01/2006",
+	discordgo.Vietnamese:   "02/01/2006",
+	discordgo.German:       "02.01.2006",
+	discordgo.Bulgarian:    "02.01.2006",
+	discordgo.Croatian:     "02.01.2006.",
+	discordgo.Czech:        "02.01.2006",
+	discordgo.Danish:       "02.01.2006",
+	discordgo.Finnish:      "02.01.2006",
+	discordgo.Norwegian:    "02.01.2006",
+	discordgo.Polish:       "02.01.2006",
+	discordgo.Romanian:     "02.01.2006",
+	discordgo.Russian:      "02.01.2006",
+	discordgo.Turkish:      "02.01.2006",
+	discordgo.Ukrainian:    "02.01.2006",
+	discordgo.Dutch:        "02-01-2006",
+	discordgo.Hungarian:    "2006.01.02.",
+	discordgo.Japanese:     "2006/01/02",
+	discordgo.ChineseCN:    "2006/01/02",
+	discordgo.ChineseTW:    "2006/01/02",
+	discordgo.Korean:       "2006. 01. 02.",
+}
+
+// dateLayoutFor returns the date layout for locale, falling back to ISO for unknown locales
+func dateLayoutFor(locale discordgo.Locale) string {
+	if layout, ok := localeDateLayouts[locale]; ok {
+		return layout
+	}
+	return isoDateLayout
+}
+
+// formatDate writes t as a date the way readers in locale expect
+func formatDate(t time.Time, locale discordgo.Locale) string {
+	return t.Format(dateLayoutFor(locale))
+}
+
+// parseDate reads a calendar date as midnight UTC, accepting the layouts above as well as
+// "today" and "yesterday"
+func parseDate(s string, now time.Time) (time.Time, error) {
+	s = strings.TrimSpace(s)
+	today := now.UTC()
+	today = time.Date(today.Year(), today.Month(), today.Day(), 0, 0, 0, 0, time.UTC)
+
+	switch strings.ToLower(s) {
+	case "today":
+		return today, nil
+	case "yesterday":
+		return today.AddDate(0, 0, -1), nil
+	}
+
+	for _, layout := range dateLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, ErrInvalidDateFormat
+}
+
+// endOfDay returns the last instant of the day starting at midnight t
+func endOfDay(t time.Time) time.Time {
+	return t.AddDate(0, 0, 1).Add(-time.Nanosecond)
+}
@@ -0,0 +1,65 @@
+package bot
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/yugonline/grind_review_bot/config"
+	"github.com/yugonline/grind_review_bot/internal/database"
+)
+
+func TestFormatDate(t *testing.T) {
+	date := time.Date(2024, time.March, 5, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		locale discordgo.Locale
+		want   string
+	}{
+		{locale: discordgo.EnglishUS, want: "03/05/2024"},
+		{locale: discordgo.EnglishGB, want: "05/03/2024"},
+		{locale: discordgo.German, want: "05.03.2024"},
+		{locale: discordgo.Dutch, want: "05-03-2024"},
+		{locale: discordgo.Japanese, want: "2024/03/05"},
+		{locale: discordgo.Korean, want: "2024. 03. 05."},
+		// Locales that write ISO dates, unknown locales and interactions without one fall back to ISO
+		{locale: discordgo.Swedish, want: "2024-03-05"},
+		{locale: discordgo.Locale("xx-XX"), want: "2024-03-05"},
+		{locale: discordgo.Unknown, want: "2024-03-05"},
+	}
+	for _, tt := range tests {
+		if got := formatDate(date, tt.locale); got != tt.want {
+			t.Errorf("formatDate(%q) = %q, want %q", tt.locale, got, tt.want)
+		}
+	}
+}
+
+func TestParseDate(t *testing.T) {
+	now := time.Date(2024, time.March, 5, 23, 30, 0, 0, time.UTC)
+	want := time.Date(2024, time.March, 5, 0, 0, 0, 0, time.UTC)
+	for _, input := range []string{"2024-03-05", "2024/03/05", "2024.03.05", "Mar 5 2024", "Mar 5, 2024", "5 Mar 2024", " today "} {
+		if got, err := parseDate(input, now); err != nil || !got.Equal(want) {
+			t.Errorf("parseDate(%q) = %v, %v, want %v", input, got, err, want)
+		}
+	}
+	if got, err := parseDate("Yesterday", now); err != nil || !got.Equal(want.AddDate(0, 0, -1)) {
+		t.Errorf("parseDate(Yesterday) = %v, %v, want %v", got, err, want.AddDate(0, 0, -1))
+	}
+	// Locale layouts are ambiguous, so they're only used for output
+	if _, err := parseDate("03/05/2024", now); !errors.Is(err, ErrInvalidDateFormat) {
+		t.Errorf("parseDate(03/05/2024) error = %v, want ErrInvalidDateFormat", err)
+	}
+}
+
+func TestRenderReminderUsesLocale(t *testing.T) {
+	problems := []*database.ProblemEntry{{ProblemName: "Two Sum", SolvedAt: time.Date(2024, time.March, 5, 12, 0, 0, 0, time.UTC)}}
+
+	content, err := renderReminder(config.MessageTemplates{}, discordgo.German, "<@1>", problems, 0)
+	if err != nil {
+		t.Fatalf("renderReminder: %v", err)
+	}
+	if !strings.Contains(content, "Two Sum (Solved: 05.03.2024)") {
+		t.Errorf("reminder %q doesn't write the date the German way", content)
+	}
+}
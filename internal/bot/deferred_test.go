@@ -0,0 +1,55 @@
+package bot
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/yugonline/grind_review_bot/config"
+	"github.com/yugonline/grind_review_bot/internal/database"
+)
+
+func TestSlowCommandsAreDeferred(t *testing.T) {
+	bot, session := newTestBot(t, config.DiscordConfig{})
+	addTestProblem(t, bot, "Two Sum", database.DifficultyEasy, "array")
+	addTestProblem(t, bot, "Coin Change", database.DifficultyMedium, "dp")
+
+	for _, name := range []string{"stats", "streak", "heatmap", "profile"} {
+		t.Run(name, func(t *testing.T) {
+			start := time.Now()
+			resp := deferredResult(t, bot, session, commandInteraction(name))
+			if elapsed := time.Since(start); elapsed > 10*time.Second {
+				t.Errorf("/%s took %s to deliver its result, want under 10s", name, elapsed)
+			}
+			if len(resp.Data.Embeds) == 0 && resp.Data.Content == "" {
+				t.Errorf("/%s edited its deferral with an empty message", name)
+			}
+		})
+	}
+}
+
+func TestDeferredReportsErrors(t *testing.T) {
+	bot, session := newTestBot(t, config.DiscordConfig{})
+	bot.commandHandlers["failing"] = chain(bot.deferred(true, func(ctx context.Context, s DiscordSession, i *discordgo.InteractionCreate) (*discordgo.InteractionResponse, error) {
+		return nil, databaseError("Failed to load your problems.", errors.New("disk I/O error"))
+	}), bot.commandMiddleware()...)
+
+	before := session.Calls("InteractionRespond")
+	resp := deferredResult(t, bot, session, commandInteraction("failing"))
+	if !strings.Contains(responseText(resp), "Failed to load your problems.") {
+		t.Errorf("edit = %q, want the handler's error", responseText(resp))
+	}
+	if strings.Contains(responseText(resp), "disk I/O error") {
+		t.Error("the edit leaked the underlying error")
+	}
+
+	// The error is delivered by the edit alone, not by a second response
+	if calls := session.Calls("InteractionRespond"); len(calls) != len(before)+1 {
+		t.Errorf("sent %d responses, want only the deferral", len(calls)-len(before))
+	} else if data := calls[len(calls)-1].Args[1].(*discordgo.InteractionResponse).Data; data == nil || data.Flags&discordgo.MessageFlagsEphemeral == 0 {
+		t.Error("an ephemeral command's deferral was public")
+	}
+}
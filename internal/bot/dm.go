@@ -0,0 +1,39 @@
+package bot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/rs/zerolog"
+)
+
+// ErrDMDisabled is returned when a user doesn't accept direct messages from the bot
+var ErrDMDisabled = errors.New("user has direct messages disabled")
+
+// SendDM sends an embed to a user in a direct message. Users who have DMs from server members
+// turned off are expected, so that case is logged at debug level and reported as ErrDMDisabled
+// for the caller to skip the user quietly.
+func SendDM(ctx context.Context, session DiscordSession, userID string, embed *discordgo.MessageEmbed) error {
+	channel, err := session.UserChannelCreate(userID)
+	if err != nil {
+		return fmt.Errorf("failed to open DM channel: %w", err)
+	}
+
+	if _, err := session.ChannelMessageSendEmbed(channel.ID, embed); err != nil {
+		if isDMDisabled(err) {
+			zerolog.Ctx(ctx).Debug().Str("user_id", userID).Msg("User has DMs disabled, skipping")
+			return ErrDMDisabled
+		}
+		return fmt.Errorf("failed to send DM: %w", err)
+	}
+	return nil
+}
+
+// isDMDisabled reports whether Discord refused a message because the recipient doesn't accept DMs
+func isDMDisabled(err error) bool {
+	var restErr *discordgo.RESTError
+	return errors.As(err, &restErr) && restErr.Message != nil &&
+		restErr.Message.Code == discordgo.ErrCodeCannotSendMessagesToThisUser
+}
@@ -0,0 +1,70 @@
+package bot
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// failingDMSession is a MockSession whose embed sends fail with err
+type failingDMSession struct {
+	*MockSession
+	err error
+}
+
+// ChannelMessageSendEmbed implements DiscordSession
+func (s *failingDMSession) ChannelMessageSendEmbed(channelID string, embed *discordgo.MessageEmbed, _ ...discordgo.RequestOption) (*discordgo.Message, error) {
+	return nil, s.err
+}
+
+func TestSendDM(t *testing.T) {
+	session := NewMockSession()
+	embed := &discordgo.MessageEmbed{Title: "Weekly digest"}
+
+	if err := SendDM(context.Background(), session, "user-1", embed); err != nil {
+		t.Fatalf("SendDM: %v", err)
+	}
+
+	sent := session.Calls("ChannelMessageSendEmbed")
+	if len(sent) != 1 {
+		t.Fatalf("sent %d embeds, want 1", len(sent))
+	}
+	if channelID := sent[0].Args[0].(string); channelID != "dm-user-1" {
+		t.Errorf("embed sent to %q, want the DM channel dm-user-1", channelID)
+	}
+	if sent[0].Args[1] != embed {
+		t.Error("SendDM didn't send the given embed")
+	}
+}
+
+func TestSendDMErrors(t *testing.T) {
+	dmDisabled := &discordgo.RESTError{Message: &discordgo.APIErrorMessage{Code: discordgo.ErrCodeCannotSendMessagesToThisUser}}
+	otherErr := &discordgo.RESTError{Message: &discordgo.APIErrorMessage{Code: discordgo.ErrCodeMissingAccess}}
+
+	tests := []struct {
+		name    string
+		sendErr error
+		want    error
+	}{
+		{name: "dms disabled", sendErr: dmDisabled, want: ErrDMDisabled},
+		{name: "other error", sendErr: otherErr, want: otherErr},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			session := &failingDMSession{MockSession: NewMockSession(), err: tt.sendErr}
+
+			err := SendDM(context.Background(), session, "user-1", &discordgo.MessageEmbed{})
+			if !errors.Is(err, tt.want) {
+				t.Errorf("SendDM = %v, want %v", err, tt.want)
+			}
+		})
+	}
+
+	// Only a refused DM is reported as ErrDMDisabled
+	session := &failingDMSession{MockSession: NewMockSession(), err: otherErr}
+	if err := SendDM(context.Background(), session, "user-1", &discordgo.MessageEmbed{}); errors.Is(err, ErrDMDisabled) {
+		t.Errorf("SendDM = %v for error %d, want it wrapped as is", err, discordgo.ErrCodeMissingAccess)
+	}
+}
@@ -0,0 +1,95 @@
+package bot
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/yugonline/grind_review_bot/internal/database"
+)
+
+// maxDueDaysAhead is the furthest ahead /due can look
+const maxDueDaysAhead = 30
+
+// dueListLimit caps how many problems /due lists so the reply fits in one message
+const dueListLimit = 25
+
+func (b *Bot) handleDueCommand(ctx context.Context, s DiscordSession, i *discordgo.InteractionCreate) (*discordgo.InteractionResponse, error) {
+	scheduler := b.scheduler.Load()
+	if scheduler == nil {
+		return nil, unavailableError("The scheduler is not running, so the review lookback isn't known.")
+	}
+
+	options := i.ApplicationCommandData().Options
+	optionMap := make(map[string]*discordgo.ApplicationCommandInteractionDataOption, len(options))
+	for _, opt := range options {
+		optionMap[opt.Name] = opt
+	}
+
+	daysAhead := 0
+	if daysOpt, ok := optionMap["days_ahead"]; ok {
+		daysAhead = int(daysOpt.IntValue())
+	}
+
+	// Shifting the cutoff forward pulls in problems that become due within the window
+	lookback := scheduler.config.LookbackPeriod
+	userID := interactionUserID(i)
+	problems, err := b.repo.ListProblemsForReview(ctx, userID, lookback-time.Duration(daysAhead)*24*time.Hour, tagFilterScope(userID, optionMap))
+	if err != nil {
+		return nil, databaseError("Failed to retrieve your due problems from the database.", err)
+	}
+
+	return messageResponse(dueMessage(problems, lookback, daysAhead, time.Now())), nil
+}
+
+// dueMessage lists problems due now ahead of those becoming due within daysAhead days
+func dueMessage(problems []*database.ProblemEntry, lookback time.Duration, daysAhead int, now time.Time) string {
+	var dueNow, upcoming []*database.ProblemEntry
+	for _, p := range problems {
+		if database.ReviewDueAt(p, lookback).After(now) {
+			upcoming = append(upcoming, p)
+		} else {
+			dueNow = append(dueNow, p)
+		}
+	}
+
+	if len(dueNow) == 0 && len(upcoming) == 0 {
+		if daysAhead > 0 {
+			return fmt.Sprintf("Nothing is due for review in the next %d days.", daysAhead)
+		}
+		return "Nothing is due for review right now."
+	}
+
+	var sb strings.Builder
+	listed := 0
+	writeSection := func(title string, section []*database.ProblemEntry, label func(p *database.ProblemEntry) string) {
+		if len(section) == 0 {
+			return
+		}
+		sb.WriteString(title + "\n")
+		for _, p := range section {
+			if listed == dueListLimit {
+				break
+			}
+			sb.WriteString(fmt.Sprintf("- [%d] %s (%s)%s\n", p.ID, p.ProblemName, p.Difficulty, label(p)))
+			listed++
+		}
+	}
+
+	writeSection(fmt.Sprintf("**Due now** (%d)", len(dueNow)), dueNow, func(p *database.ProblemEntry) string {
+		return ""
+	})
+	if len(dueNow) > 0 && len(upcoming) > 0 {
+		sb.WriteString("\n")
+	}
+	writeSection(fmt.Sprintf("**Due in the next %d days** (%d)", daysAhead, len(upcoming)), upcoming, func(p *database.ProblemEntry) string {
+		return fmt.Sprintf(" - due %s", database.ReviewDueAt(p, lookback).Format("Jan 2"))
+	})
+
+	if remaining := len(problems) - listed; remaining > 0 {
+		sb.WriteString(fmt.Sprintf("…and %d more\n", remaining))
+	}
+	return sb.String()
+}
@@ -0,0 +1,81 @@
+package bot
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/yugonline/grind_review_bot/config"
+	"github.com/yugonline/grind_review_bot/internal/database"
+)
+
+func TestDueCommand(t *testing.T) {
+	bot, session := newTestBot(t, config.DiscordConfig{})
+	bot.scheduler.Store(&Scheduler{bot: bot, config: config.SchedulerConfig{LookbackPeriod: 7 * 24 * time.Hour}})
+
+	// Two Sum is long overdue; Coin Change becomes due in two days and Word Ladder in four
+	addTestProblem(t, bot, "Two Sum", database.DifficultyEasy)
+	for _, p := range []struct {
+		name    string
+		daysAgo int
+	}{{"Coin Change", 5}, {"Word Ladder", 3}} {
+		entry := &database.ProblemEntry{UserID: testUserID, ProblemName: p.name, Difficulty: database.DifficultyMedium, Category: "Array", Status: database.StatusSolved, SolvedAt: time.Now().AddDate(0, 0, -p.daysAgo)}
+		if err := bot.repo.CreateProblem(context.Background(), entry); err != nil {
+			t.Fatalf("CreateProblem: %v", err)
+		}
+	}
+
+	tests := []struct {
+		name      string
+		daysAhead int
+		want      []string
+		exclude   []string
+	}{
+		{name: "due now", want: []string{"**Due now** (1)", "Two Sum"}, exclude: []string{"Coin Change", "Word Ladder", "Due in the next"}},
+		{name: "three days ahead", daysAhead: 3, want: []string{"**Due now** (1)", "**Due in the next 3 days** (1)", "Coin Change (Medium) - due "}, exclude: []string{"Word Ladder"}},
+		{name: "thirty days ahead", daysAhead: 30, want: []string{"**Due in the next 30 days** (2)", "Coin Change", "Word Ladder"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var options []*discordgo.ApplicationCommandInteractionDataOption
+			if tt.daysAhead > 0 {
+				options = append(options, intOption("days_ahead", tt.daysAhead))
+			}
+			content := dispatch(t, bot, session, commandInteraction("due", options...)).Data.Content
+			for _, want := range tt.want {
+				if !strings.Contains(content, want) {
+					t.Errorf("response = %q, want it to contain %q", content, want)
+				}
+			}
+			for _, exclude := range tt.exclude {
+				if strings.Contains(content, exclude) {
+					t.Errorf("response = %q, want it without %q", content, exclude)
+				}
+			}
+		})
+	}
+
+	// The overdue problem is listed ahead of the upcoming ones
+	content := dispatch(t, bot, session, commandInteraction("due", intOption("days_ahead", 30))).Data.Content
+	if strings.Index(content, "Two Sum") > strings.Index(content, "Coin Change") {
+		t.Errorf("response = %q, want problems due now listed first", content)
+	}
+}
+
+func TestDueCommandNothingDue(t *testing.T) {
+	bot, session := newTestBot(t, config.DiscordConfig{})
+
+	if resp := dispatch(t, bot, session, commandInteraction("due")); !strings.Contains(responseText(resp), "scheduler is not running") {
+		t.Errorf("response = %q, want an error without a scheduler", responseText(resp))
+	}
+
+	bot.scheduler.Store(&Scheduler{bot: bot, config: config.SchedulerConfig{LookbackPeriod: 7 * 24 * time.Hour}})
+	if resp := dispatch(t, bot, session, commandInteraction("due")); resp.Data.Content != "Nothing is due for review right now." {
+		t.Errorf("content = %q, want nothing due", resp.Data.Content)
+	}
+	if resp := dispatch(t, bot, session, commandInteraction("due", intOption("days_ahead", 5))); resp.Data.Content != "Nothing is due for review in the next 5 days." {
+		t.Errorf("content = %q, want nothing due in 5 days", resp.Data.Content)
+	}
+}
@@ -0,0 +1,83 @@
+package bot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/yugonline/grind_review_bot/internal/database"
+)
+
+// addAnywayButtonPrefix is the custom ID prefix of the button that confirms adding a problem
+// the user has already logged. The problem waits in the interaction state until it's clicked.
+const addAnywayButtonPrefix = "add_anyway"
+
+// pendingProblem is the interaction state behind an "Add anyway" button
+type pendingProblem struct {
+	problem *database.ProblemEntry
+	message string // Success message to send once the problem is added
+}
+
+// confirmDuplicate checks whether the user already logged a problem with the same name. If so,
+// it stores the problem and returns a response asking them to confirm; otherwise it returns nil.
+func (b *Bot) confirmDuplicate(ctx context.Context, i *discordgo.InteractionCreate, problem *database.ProblemEntry, message string) (*discordgo.InteractionResponse, error) {
+	_, err := b.repo.GetProblemByName(ctx, problem.UserID, problem.ProblemName)
+	var ambiguous *database.AmbiguousProblemError
+	switch {
+	case errors.Is(err, database.ErrProblemNotFound):
+		return nil, nil
+	case err != nil && !errors.As(err, &ambiguous):
+		return nil, databaseError("Failed to check your existing problems.", err)
+	}
+
+	token := b.state.Store(&PendingInteraction{
+		UserID:  problem.UserID,
+		Command: b.commandKey(i),
+		Payload: &pendingProblem{problem: problem, message: message},
+	})
+	response := ephemeralResponse(fmt.Sprintf("You've already logged '%s'. Add it again anyway?", problem.ProblemName))
+	response.Data.Components = []discordgo.MessageComponent{
+		discordgo.ActionsRow{
+			Components: []discordgo.MessageComponent{
+				discordgo.Button{
+					Label:    "Add anyway",
+					Style:    discordgo.PrimaryButton,
+					CustomID: stateCustomID(addAnywayButtonPrefix, token),
+				},
+			},
+		},
+	}
+	return response, nil
+}
+
+// handleAddAnywayButton adds the problem stored by confirmDuplicate
+func (b *Bot) handleAddAnywayButton(ctx context.Context, s DiscordSession, i *discordgo.InteractionCreate) (*discordgo.InteractionResponse, error) {
+	pending, ok := pendingInteraction(ctx)
+	if !ok {
+		return nil, validationError("This has expired. Please run the command again.")
+	}
+	payload, ok := pending.Payload.(*pendingProblem)
+	if !ok {
+		return nil, fmt.Errorf("unexpected state for %s button: %T", addAnywayButtonPrefix, pending.Payload)
+	}
+
+	if err := b.createProblem(ctx, payload.problem); err != nil {
+		return nil, err
+	}
+	return messageResponse(payload.message), nil
+}
+
+// createProblem saves a new problem and announces it, turning failures into user errors
+func (b *Bot) createProblem(ctx context.Context, problem *database.ProblemEntry) error {
+	err := b.repo.CreateProblem(ctx, problem)
+	var invalid *database.ValidationError
+	if errors.As(err, &invalid) {
+		return validationError("Couldn't add problem: " + problemValidationMessage(invalid))
+	}
+	if err != nil {
+		return databaseError("Failed to add problem to the database.", err)
+	}
+	b.webhooks.ProblemCreated(problem)
+	return nil
+}
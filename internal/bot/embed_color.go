@@ -0,0 +1,51 @@
+package bot
+
+import (
+	"math"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/yugonline/grind_review_bot/internal/database"
+)
+
+// Embed colors. Status colors say how an outcome went; difficulty colors match LeetCode's.
+const (
+	ColorSuccess int = 0x00FF00
+	ColorWarning int = 0xFFAA00
+	ColorError   int = 0xFF0000
+	ColorInfo    int = 0x0099FF
+	ColorEasy    int = 0x00B8A9
+	ColorMedium  int = 0xFFB800
+	ColorHard    int = 0xFF375E
+)
+
+// DifficultyColor returns the color of a problem difficulty, or ColorInfo for an unknown one
+func DifficultyColor(d string) int {
+	switch d {
+	case database.DifficultyEasy:
+		return ColorEasy
+	case database.DifficultyMedium:
+		return ColorMedium
+	case database.DifficultyHard:
+		return ColorHard
+	}
+	return ColorInfo
+}
+
+// ProgressColor blends from red at ratio 0 to green at ratio 1, clamping ratios outside that range
+func ProgressColor(ratio float64) int {
+	ratio = math.Max(0, math.Min(1, ratio))
+	red := int(math.Round(255 * (1 - ratio)))
+	green := int(math.Round(255 * ratio))
+	return red<<16 | green<<8
+}
+
+// branded applies the server's configured accent color and footer to an informational embed
+func (b *Bot) branded(embed *discordgo.MessageEmbed) *discordgo.MessageEmbed {
+	if accent, ok := b.cfg.AccentColor(); ok {
+		embed.Color = accent
+	}
+	if b.cfg.EmbedFooter != "" {
+		embed.Footer = &discordgo.MessageEmbedFooter{Text: b.cfg.EmbedFooter}
+	}
+	return embed
+}
@@ -0,0 +1,68 @@
+package bot
+
+import (
+	"testing"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/yugonline/grind_review_bot/config"
+	"github.com/yugonline/grind_review_bot/internal/database"
+)
+
+func TestProgressColor(t *testing.T) {
+	tests := []struct {
+		ratio float64
+		want  int
+	}{
+		{ratio: 0, want: 0xFF0000},
+		{ratio: 0.5, want: 0x808000},
+		{ratio: 1, want: 0x00FF00},
+		{ratio: -1, want: 0xFF0000},
+		{ratio: 2, want: 0x00FF00},
+	}
+	for _, tt := range tests {
+		if got := ProgressColor(tt.ratio); got != tt.want {
+			t.Errorf("ProgressColor(%v) = %#06x, want %#06x", tt.ratio, got, tt.want)
+		}
+	}
+}
+
+func TestDifficultyColor(t *testing.T) {
+	tests := map[string]int{
+		database.DifficultyEasy:   ColorEasy,
+		database.DifficultyMedium: ColorMedium,
+		database.DifficultyHard:   ColorHard,
+		"Unknown":                 ColorInfo,
+	}
+	for difficulty, want := range tests {
+		if got := DifficultyColor(difficulty); got != want {
+			t.Errorf("DifficultyColor(%q) = %#06x, want %#06x", difficulty, got, want)
+		}
+	}
+}
+
+func TestBrandedEmbeds(t *testing.T) {
+	bot, session := newTestBot(t, config.DiscordConfig{EmbedColor: "#5865F2", EmbedFooter: "Grind Club"})
+	addTestProblem(t, bot, "Two Sum", database.DifficultyEasy)
+
+	for _, name := range []string{"stats", "streak"} {
+		resp := deferredResult(t, bot, session, commandInteraction(name))
+		if len(resp.Data.Embeds) == 0 {
+			t.Fatalf("/%s response = %q, want an embed", name, responseText(resp))
+		}
+		embed := resp.Data.Embeds[0]
+		if embed.Color != 0x5865F2 {
+			t.Errorf("/%s color = %#06x, want the accent color", name, embed.Color)
+		}
+		if embed.Footer == nil || embed.Footer.Text != "Grind Club" {
+			t.Errorf("/%s footer = %+v, want Grind Club", name, embed.Footer)
+		}
+	}
+}
+
+func TestBrandedDefaults(t *testing.T) {
+	bot, _ := newTestBot(t, config.DiscordConfig{})
+	embed := bot.branded(&discordgo.MessageEmbed{Color: ColorInfo})
+	if embed.Color != ColorInfo || embed.Footer != nil {
+		t.Errorf("embed = %+v, want it unchanged without branding", embed)
+	}
+}
@@ -0,0 +1,136 @@
+package bot
+
+import (
+	"context"
+	"errors"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/rs/zerolog"
+)
+
+// Kinds of handler errors, in the spirit of HTTP status codes. Handlers report failures as a
+// UserError of one of these kinds so middleware can pick the response and how loudly to log.
+var (
+	ErrValidation       = errors.New("invalid input")       // 400: the user can fix the request
+	ErrPermissionDenied = errors.New("permission denied")   // 403: the user may not do this
+	ErrNotFound         = errors.New("not found")           // 404: the target doesn't exist
+	ErrRateLimit        = errors.New("rate limited")        // 429: the user must wait
+	ErrDatabaseFailure  = errors.New("database failure")    // 500: storage failed
+	ErrUnavailable      = errors.New("service unavailable") // 503: a dependency isn't running
+)
+
+// UserError is a handler error whose Message is safe to show the user. Kind is one of the error
+// kinds above; Err is the underlying cause, if any, which is logged but never shown.
+type UserError struct {
+	Kind    error
+	Message string
+	Err     error
+}
+
+// Error implements error
+func (e *UserError) Error() string {
+	if e.Err != nil {
+		return e.Message + ": " + e.Err.Error()
+	}
+	return e.Message
+}
+
+// Unwrap lets errors.Is match both the kind and the cause
+func (e *UserError) Unwrap() []error {
+	if e.Err != nil {
+		return []error{e.Kind, e.Err}
+	}
+	return []error{e.Kind}
+}
+
+// validationError reports input the user can correct
+func validationError(message string) error {
+	return &UserError{Kind: ErrValidation, Message: message}
+}
+
+// permissionError reports an action the user isn't allowed to take
+func permissionError(message string) error {
+	return &UserError{Kind: ErrPermissionDenied, Message: message}
+}
+
+// notFoundError reports a problem or other record that doesn't exist
+func notFoundError(message string) error {
+	return &UserError{Kind: ErrNotFound, Message: message}
+}
+
+// rateLimitError reports that the user must wait before trying again
+func rateLimitError(message string) error {
+	return &UserError{Kind: ErrRateLimit, Message: message}
+}
+
+// databaseError reports a failed repository call; cause is logged, message is shown
+func databaseError(message string, cause error) error {
+	return &UserError{Kind: ErrDatabaseFailure, Message: message, Err: cause}
+}
+
+// unavailableError reports that a feature the command needs isn't running or supported
+func unavailableError(message string) error {
+	return &UserError{Kind: ErrUnavailable, Message: message}
+}
+
+// userErrorTitles and userErrorColors style the embed shown for each kind of error
+var (
+	userErrorTitles = map[error]string{
+		ErrValidation:       "Invalid input",
+		ErrPermissionDenied: "Permission denied",
+		ErrNotFound:         "Not found",
+		ErrRateLimit:        "Slow down",
+		ErrDatabaseFailure:  "Something went wrong",
+		ErrUnavailable:      "Unavailable",
+	}
+	userErrorColors = map[error]int{
+		ErrValidation:       ColorWarning,
+		ErrPermissionDenied: ColorError,
+		ErrNotFound:         ColorWarning,
+		ErrRateLimit:        ColorWarning,
+		ErrDatabaseFailure:  ColorError,
+		ErrUnavailable:      ColorError,
+	}
+)
+
+// userErrorEmbed describes a UserError to the user
+func userErrorEmbed(err *UserError) *discordgo.MessageEmbed {
+	title, ok := userErrorTitles[err.Kind]
+	if !ok {
+		title = "Error"
+	}
+	return &discordgo.MessageEmbed{
+		Title:       title,
+		Description: err.Message,
+		Color:       userErrorColors[err.Kind],
+	}
+}
+
+// userErrorResponse creates an ephemeral response describing a UserError
+func userErrorResponse(err *UserError) *discordgo.InteractionResponse {
+	return &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Embeds: []*discordgo.MessageEmbed{userErrorEmbed(err)},
+			Flags:  discordgo.MessageFlagsEphemeral,
+		},
+	}
+}
+
+// logHandlerError logs a handler error. Failures of the bot itself are errors; mistakes and
+// refusals the user caused are only logged at debug level.
+func logHandlerError(ctx context.Context, err error) {
+	logger := zerolog.Ctx(ctx)
+
+	var userErr *UserError
+	if !errors.As(err, &userErr) {
+		logger.Error().Err(err).Msg("Error during command execution")
+		return
+	}
+	switch userErr.Kind {
+	case ErrDatabaseFailure, ErrUnavailable:
+		logger.Error().Err(userErr.Err).Str("kind", userErr.Kind.Error()).Msg(userErr.Message)
+	default:
+		logger.Debug().Str("kind", userErr.Kind.Error()).Msg(userErr.Message)
+	}
+}
@@ -0,0 +1,67 @@
+package bot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/yugonline/grind_review_bot/config"
+)
+
+func TestUserErrorResponses(t *testing.T) {
+	bot, session := newTestBot(t, config.DiscordConfig{})
+	cause := errors.New("database is locked")
+
+	tests := []struct {
+		name      string
+		err       error
+		wantTitle string
+		wantColor int
+	}{
+		{name: "validation", err: validationError("Difficulty must be Easy, Medium or Hard."), wantTitle: "Invalid input", wantColor: ColorWarning},
+		{name: "permission", err: permissionError("Only admins can do that."), wantTitle: "Permission denied", wantColor: ColorError},
+		{name: "not found", err: notFoundError("Problem with ID 7 not found."), wantTitle: "Not found", wantColor: ColorWarning},
+		{name: "rate limit", err: rateLimitError("Try again in 3s."), wantTitle: "Slow down", wantColor: ColorWarning},
+		{name: "database", err: databaseError("Failed to save the problem.", cause), wantTitle: "Something went wrong", wantColor: ColorError},
+		{name: "unavailable", err: unavailableError("Search isn't available."), wantTitle: "Unavailable", wantColor: ColorError},
+		{name: "wrapped", err: fmt.Errorf("add: %w", validationError("Name is required.")), wantTitle: "Invalid input", wantColor: ColorWarning},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bot.commandHandlers["failing"] = chain(func(ctx context.Context, s DiscordSession, i *discordgo.InteractionCreate) (*discordgo.InteractionResponse, error) {
+				return nil, tt.err
+			}, bot.commandMiddleware()...)
+
+			resp := dispatch(t, bot, session, commandInteraction("failing"))
+			if resp.Data.Flags&discordgo.MessageFlagsEphemeral == 0 || len(resp.Data.Embeds) != 1 {
+				t.Fatalf("response = %+v, want one ephemeral embed", resp.Data)
+			}
+			var userErr *UserError
+			errors.As(tt.err, &userErr)
+			embed := resp.Data.Embeds[0]
+			if embed.Title != tt.wantTitle || embed.Color != tt.wantColor || embed.Description != userErr.Message {
+				t.Errorf("embed = %q (%#06x): %q, want %q (%#06x): %q", embed.Title, embed.Color, embed.Description, tt.wantTitle, tt.wantColor, userErr.Message)
+			}
+		})
+	}
+}
+
+func TestUserErrorUnwrap(t *testing.T) {
+	cause := errors.New("database is locked")
+	err := fmt.Errorf("stats: %w", databaseError("Failed to load your stats.", cause))
+
+	if !errors.Is(err, ErrDatabaseFailure) || !errors.Is(err, cause) {
+		t.Errorf("errors.Is doesn't match both the kind and the cause of %v", err)
+	}
+	if errors.Is(err, ErrValidation) {
+		t.Error("a database error matched ErrValidation")
+	}
+	if got, want := databaseError("Failed.", cause).Error(), "Failed.: database is locked"; got != want {
+		t.Errorf("Error() = %q, want %q", got, want)
+	}
+	if got := notFoundError("Gone.").Error(); got != "Gone." {
+		t.Errorf("Error() = %q, want the message alone", got)
+	}
+}
@@ -1,32 +1,70 @@
 package bot
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"os"
+	"sort"
 	"strings"
 	"time"
 
 	"github.com/bwmarrin/discordgo"
-	"github.com/rs/zerolog/log"
+	"github.com/rs/zerolog"
+	"github.com/yugonline/grind_review_bot/internal/api"
 	"github.com/yugonline/grind_review_bot/internal/database"
+	"github.com/yugonline/grind_review_bot/pkg/discord"
+	"github.com/yugonline/grind_review_bot/pkg/leetcode"
 )
 
+// reviewHistoryLimit is the maximum number of past reviews shown by /review history
+const reviewHistoryLimit = 10
+
+// statsActivityDays is how many days of solve activity /stats summarises
+const statsActivityDays = 7
+
+// statsTopCount is how many categories and tags /stats lists
+const statsTopCount = 5
+
+// minCategoryConfidence is the lowest confidence at which a guessed category is applied automatically
+const minCategoryConfidence = 0.6
+
+// canonicalCategoryConfidence is the confidence above which a typed category is replaced by its canonical form
+const canonicalCategoryConfidence = 0.8
+
+// suggestCategoryConfidence is the lowest confidence at which a canonical category is suggested instead
+const suggestCategoryConfidence = 0.5
+
+// perceivedSameAsOfficial is the /edit choice that clears a perceived difficulty
+const perceivedSameAsOfficial = "official"
+
+// commandTimeoutMessage is shown when a command runs past the configured timeout
+const commandTimeoutMessage = "Command processing timed out. Please try again."
+
 // Error constants
 var (
-	ErrInvalidDateFormat = fmt.Errorf("invalid date format, please use YYYY-MM-DD")
+	ErrInvalidDateFormat = fmt.Errorf("invalid date format, please use YYYY-MM-DD, \"today\" or \"yesterday\"")
 )
 
+// registerCommandHandlers builds the command definitions and handler lookup from the registry
+// and configures per-command cooldowns
 func (b *Bot) registerCommandHandlers() {
-	b.commandHandlers = map[string]func(s *discordgo.Session, i *discordgo.InteractionCreate) (*discordgo.InteractionResponse, error){
-		"add":    b.handleAddCommand,
-		"list":   b.handleListCommand,
-		"get":    b.handleGetCommand,
-		"edit":   b.handleEditCommand,
-		"delete": b.handleDeleteCommand,
+	b.commands, b.commandHandlers = b.commandRegistry().Build()
+	for name, handler := range b.commandHandlers {
+		b.commandHandlers[name] = chain(handler, b.commandMiddleware()...)
+	}
+
+	for command, duration := range defaultCooldowns {
+		b.cooldowns.Configure(command, duration)
+	}
+	for command, duration := range b.cfg.CommandCooldowns {
+		b.cooldowns.Configure(command, duration)
 	}
 }
 
-func (b *Bot) handleAddCommand(s *discordgo.Session, i *discordgo.InteractionCreate) (*discordgo.InteractionResponse, error) {
+func (b *Bot) handleAddCommand(ctx context.Context, s DiscordSession, i *discordgo.InteractionCreate) (*discordgo.InteractionResponse, error) {
 	options := i.ApplicationCommandData().Options
 	optionMap := make(map[string]*discordgo.ApplicationCommandInteractionDataOption, len(options))
 	for _, opt := range options {
@@ -35,19 +73,23 @@ func (b *Bot) handleAddCommand(s *discordgo.Session, i *discordgo.InteractionCre
 
 	solvedAtStr, ok := optionMap["solved_at"]
 	if !ok || solvedAtStr.StringValue() == "" {
-		return errorResponse("Missing or invalid solved_at date."), nil
+		return nil, validationError("Missing or invalid solved_at date.")
 	}
-	solvedAt, err := time.Parse("2006-01-02", solvedAtStr.StringValue())
+	solvedAt, err := parseDate(solvedAtStr.StringValue(), time.Now())
 	if err != nil {
-		return errorResponse(ErrInvalidDateFormat.Error()), nil
+		return nil, validationError(ErrInvalidDateFormat.Error())
+	}
+
+	difficulty, err := leetcode.NormalizeDifficulty(optionMap["difficulty"].StringValue())
+	if err != nil {
+		return nil, validationError(err.Error())
 	}
 
 	// Initialize problem with required fields
 	problem := &database.ProblemEntry{
-		UserID:      i.Member.User.ID,
+		UserID:      interactionUserID(i),
 		ProblemName: optionMap["name"].StringValue(),
-		Difficulty:  optionMap["difficulty"].StringValue(),
-		Category:    optionMap["category"].StringValue(),
+		Difficulty:  difficulty,
 		Status:      optionMap["status"].StringValue(),
 		SolvedAt:    solvedAt,
 		Link:        "", // Default empty string for optional fields
@@ -55,6 +97,39 @@ func (b *Bot) handleAddCommand(s *discordgo.Session, i *discordgo.InteractionCre
 		Tags:        make([]string, 0),
 	}
 
+	if perceivedOpt, ok := optionMap["perceived_difficulty"]; ok {
+		perceived, err := perceivedDifficulty(perceivedOpt.StringValue(), difficulty)
+		if err != nil {
+			return nil, validationError(err.Error())
+		}
+		problem.PerceivedDifficulty = perceived
+	}
+
+	// Use the given category, or suggest one from the problem name
+	suggestedCategory := false
+	closeCategory := ""
+	if categoryOpt, ok := optionMap["category"]; ok && categoryOpt.StringValue() != "" {
+		// Snap near-identical spellings to the canonical topic, and only suggest looser matches
+		input := strings.TrimSpace(categoryOpt.StringValue())
+		canonical, confidence := leetcode.NormalizeCategory(input)
+		switch {
+		case confidence > canonicalCategoryConfidence:
+			problem.Category = canonical
+		case confidence >= suggestCategoryConfidence:
+			problem.Category = input
+			closeCategory = canonical
+		default:
+			problem.Category = input
+		}
+	} else {
+		category, confidence := classifyCategory(problem.ProblemName)
+		if confidence < minCategoryConfidence {
+			return nil, validationError("Couldn't guess a category from the problem name. Please provide one with the category option.")
+		}
+		problem.Category = category
+		suggestedCategory = true
+	}
+
 	// Add optional fields if they exist
 	if linkOpt, ok := optionMap["link"]; ok {
 		problem.Link = linkOpt.StringValue()
@@ -72,16 +147,25 @@ func (b *Bot) handleAddCommand(s *discordgo.Session, i *discordgo.InteractionCre
 		problem.Tags = tagStrings
 	}
 
-	err = b.repo.CreateProblem(context.Background(), problem)
-	if err != nil {
-		log.Error().Err(err).Msg("Failed to create problem")
-		return errorResponse("Failed to add problem to the database."), nil
+	message := fmt.Sprintf("Successfully added problem '%s'!", problem.ProblemName)
+	if suggestedCategory {
+		message += fmt.Sprintf(" Category set to **%s** based on the name; use /edit to change it.", problem.Category)
+	}
+	if closeCategory != "" {
+		message += fmt.Sprintf(" Did you mean the category **%s**? Use /edit to change it.", closeCategory)
 	}
 
-	return messageResponse(fmt.Sprintf("Successfully added problem '%s'!", problem.ProblemName)), nil
+	// Ask before logging the same problem twice
+	if confirm, err := b.confirmDuplicate(ctx, i, problem, message); confirm != nil || err != nil {
+		return confirm, err
+	}
+	if err := b.createProblem(ctx, problem); err != nil {
+		return nil, err
+	}
+	return messageResponse(message), nil
 }
 
-func (b *Bot) handleListCommand(s *discordgo.Session, i *discordgo.InteractionCreate) (*discordgo.InteractionResponse, error) {
+func (b *Bot) handleListCommand(ctx context.Context, s DiscordSession, i *discordgo.InteractionCreate) (*discordgo.InteractionResponse, error) {
 	options := i.ApplicationCommandData().Options
 	optionMap := make(map[string]*discordgo.ApplicationCommandInteractionDataOption, len(options))
 	for _, opt := range options {
@@ -106,38 +190,80 @@ func (b *Bot) handleListCommand(s *discordgo.Session, i *discordgo.InteractionCr
 		limit = int(limitOpt.IntValue())
 	}
 
-	var tags []string
-	if tagsOpt, ok := optionMap["tags"]; ok && tagsOpt.StringValue() != "" {
-		tagStrings := strings.Split(tagsOpt.StringValue(), ",")
-		for i := range tagStrings {
-			tagStrings[i] = strings.TrimSpace(tagStrings[i])
+	includeArchived := false
+	if archivedOpt, ok := optionMap["include_archived"]; ok {
+		includeArchived = archivedOpt.BoolValue()
+	}
+
+	var from, to time.Time
+	if fromOpt, ok := optionMap["date_from"]; ok {
+		parsed, err := parseDate(fromOpt.StringValue(), time.Now())
+		if err != nil {
+			return nil, validationError(fmt.Sprintf("Invalid date_from: %v.", err))
+		}
+		from = parsed
+	}
+	if toOpt, ok := optionMap["date_to"]; ok {
+		parsed, err := parseDate(toOpt.StringValue(), time.Now())
+		if err != nil {
+			return nil, validationError(fmt.Sprintf("Invalid date_to: %v.", err))
 		}
-		tags = tagStrings
+		to = endOfDay(parsed)
+	}
+	if !from.IsZero() && !to.IsZero() && from.After(to) {
+		return nil, validationError("date_from must be on or before date_to.")
 	}
 
 	// Get problems
+	userID := interactionUserID(i)
 	problems, err := b.repo.ListProblems(
-		context.Background(),
-		i.Member.User.ID,
+		ctx,
+		userID,
 		status,
 		difficulty,
 		category,
-		tags,
+		nil, // Tags are filtered by tagFilterScope so tag_match applies
+		includeArchived,
 		limit,
 		0, // No offset for simple listing
+		database.SolvedBetween(from, to),
+		tagFilterScope(userID, optionMap),
 	)
 	if err != nil {
-		log.Error().Err(err).Msg("Failed to list problems")
-		return errorResponse("Failed to retrieve problems from the database."), nil
+		return nil, databaseError("Failed to retrieve problems from the database.", err)
+	}
+
+	if jsonOpt, ok := optionMap["json"]; ok && jsonOpt.BoolValue() {
+		return jsonResponse("problems.json", problems)
 	}
 
 	if len(problems) == 0 {
 		return messageResponse("No problems found matching your criteria."), nil
 	}
 
-	// Format problems as a table
+	// Inline the table when it fits, otherwise attach it as a text file
+	table := problemTable(problems, i.Locale)
+	content := "Your Problems:\n```\n" + table + "```"
+	if len(content) <= discord.MaxMessageLength {
+		return messageResponse(content), nil
+	}
+
+	return &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Content: fmt.Sprintf("Your %d problems are too many to show inline, so they're attached.", len(problems)),
+			Files: []*discordgo.File{{
+				Name:        "problems.txt",
+				ContentType: "text/plain",
+				Reader:      strings.NewReader(table),
+			}},
+		},
+	}, nil
+}
+
+// problemTable formats problems as a fixed-width text table, writing dates for locale
+func problemTable(problems []*database.ProblemEntry, locale discordgo.Locale) string {
 	var sb strings.Builder
-	sb.WriteString("Your Problems:\n```\n")
 	sb.WriteString(fmt.Sprintf("%-5s | %-30s | %-8s | %-15s | %-10s | %-20s\n", "ID", "Name", "Status", "Category", "Difficulty", "Solved At"))
 	sb.WriteString(strings.Repeat("-", 100) + "\n")
 
@@ -148,40 +274,56 @@ func (b *Bot) handleListCommand(s *discordgo.Session, i *discordgo.InteractionCr
 			truncateString(p.Status, 8),
 			truncateString(p.Category, 15),
 			truncateString(p.Difficulty, 10),
-			p.SolvedAt.Format("2006-01-02"),
+			formatDate(p.SolvedAt, locale),
 		))
 	}
-	sb.WriteString("```")
-
-	return messageResponse(sb.String()), nil
+	return sb.String()
 }
 
-func (b *Bot) handleGetCommand(s *discordgo.Session, i *discordgo.InteractionCreate) (*discordgo.InteractionResponse, error) {
+func (b *Bot) handleGetCommand(ctx context.Context, s DiscordSession, i *discordgo.InteractionCreate) (*discordgo.InteractionResponse, error) {
 	options := i.ApplicationCommandData().Options
 	optionMap := make(map[string]*discordgo.ApplicationCommandInteractionDataOption, len(options))
 	for _, opt := range options {
 		optionMap[opt.Name] = opt
 	}
 
-	problemID := uint(optionMap["id"].IntValue())
-	problem, err := b.repo.GetProblem(context.Background(), problemID)
-	if err != nil {
-		log.Error().Err(err).Uint("id", problemID).Msg("Failed to get problem")
-		return errorResponse(fmt.Sprintf("Problem with ID %d not found or you don't have permission to view it.", problemID)), nil
+	// Lookups by ID were resolved and ownership-checked by requireOwnedProblem;
+	// lookups by name only search the user's own problems
+	problem, ok := ownedProblem(ctx)
+	switch {
+	case ok:
+	case optionMap["name"] != nil:
+		name := optionMap["name"].StringValue()
+		found, err := b.repo.GetProblemByName(ctx, interactionUserID(i), name)
+		var ambiguous *database.AmbiguousProblemError
+		if errors.As(err, &ambiguous) {
+			return ephemeralResponse(ambiguousProblemMessage(ambiguous, i.Locale)), nil
+		}
+		if err != nil {
+			zerolog.Ctx(ctx).Error().Err(err).Str("name", name).Msg("Failed to get problem by name")
+			return nil, notFoundError(fmt.Sprintf("You haven't logged a problem named '%s'.", name))
+		}
+		problem = found
+	default:
+		return nil, validationError("Provide either a problem ID or a name.")
 	}
 
-	// Check if the user is the owner of the problem
-	if problem.UserID != i.Member.User.ID {
-		return errorResponse("You don't have permission to view this problem."), nil
+	if jsonOpt, ok := optionMap["json"]; ok && jsonOpt.BoolValue() {
+		return jsonResponse("problem.json", problem)
 	}
 
 	// Format problem details
 	var sb strings.Builder
-	sb.WriteString(fmt.Sprintf("# Problem: %s\n", problem.ProblemName))
 	sb.WriteString(fmt.Sprintf("**Difficulty:** %s\n", problem.Difficulty))
+	if problem.PerceivedDifficulty != "" {
+		sb.WriteString(fmt.Sprintf("**Felt Like:** %s\n", problem.PerceivedDifficulty))
+	}
 	sb.WriteString(fmt.Sprintf("**Category:** %s\n", problem.Category))
 	sb.WriteString(fmt.Sprintf("**Status:** %s\n", problem.Status))
-	sb.WriteString(fmt.Sprintf("**Solved On:** %s\n", problem.SolvedAt.Format("2006-01-02")))
+	sb.WriteString(fmt.Sprintf("**Solved On:** %s\n", formatDate(problem.SolvedAt, i.Locale)))
+	if problem.Archived {
+		sb.WriteString("**Archived:** Yes, excluded from reviews\n")
+	}
 
 	if problem.Link != "" {
 		sb.WriteString(fmt.Sprintf("**Link:** %s\n", problem.Link))
@@ -192,7 +334,7 @@ func (b *Bot) handleGetCommand(s *discordgo.Session, i *discordgo.InteractionCre
 	}
 
 	if problem.LastReviewedAt != nil {
-		sb.WriteString(fmt.Sprintf("**Last Reviewed:** %s\n", problem.LastReviewedAt.Format("2006-01-02")))
+		sb.WriteString(fmt.Sprintf("**Last Reviewed:** %s\n", formatDate(*problem.LastReviewedAt, i.Locale)))
 		sb.WriteString(fmt.Sprintf("**Review Count:** %d\n", problem.ReviewCount))
 	} else {
 		sb.WriteString("**Last Reviewed:** Never\n")
@@ -204,36 +346,43 @@ func (b *Bot) handleGetCommand(s *discordgo.Session, i *discordgo.InteractionCre
 		sb.WriteString(problem.Notes)
 	}
 
-	return messageResponse(sb.String()), nil
+	return embedResponse(&discordgo.MessageEmbed{
+		Title:       fmt.Sprintf("Problem: %s", problem.ProblemName),
+		Color:       DifficultyColor(problem.Difficulty),
+		Description: sb.String(),
+	}), nil
 }
 
-func (b *Bot) handleEditCommand(s *discordgo.Session, i *discordgo.InteractionCreate) (*discordgo.InteractionResponse, error) {
+func (b *Bot) handleEditCommand(ctx context.Context, s DiscordSession, i *discordgo.InteractionCreate) (*discordgo.InteractionResponse, error) {
 	options := i.ApplicationCommandData().Options
 	optionMap := make(map[string]*discordgo.ApplicationCommandInteractionDataOption, len(options))
 	for _, opt := range options {
 		optionMap[opt.Name] = opt
 	}
 
-	problemID := uint(optionMap["id"].IntValue())
-
-	// Get the existing problem
-	existing, err := b.repo.GetProblem(context.Background(), problemID)
-	if err != nil {
-		log.Error().Err(err).Uint("id", problemID).Msg("Failed to get problem for editing")
-		return errorResponse(fmt.Sprintf("Problem with ID %d not found or you don't have permission to edit it.", problemID)), nil
-	}
-
-	// Check if the user is the owner of the problem
-	if existing.UserID != i.Member.User.ID {
-		return errorResponse("You don't have permission to edit this problem."), nil
-	}
+	existing, _ := ownedProblem(ctx)
 
 	// Update fields that are specified
 	if nameOpt, ok := optionMap["name"]; ok {
 		existing.ProblemName = nameOpt.StringValue()
 	}
 	if difficultyOpt, ok := optionMap["difficulty"]; ok {
-		existing.Difficulty = difficultyOpt.StringValue()
+		difficulty, err := leetcode.NormalizeDifficulty(difficultyOpt.StringValue())
+		if err != nil {
+			return nil, validationError(err.Error())
+		}
+		existing.Difficulty = difficulty
+	}
+	if perceivedOpt, ok := optionMap["perceived_difficulty"]; ok {
+		perceived, err := perceivedDifficulty(perceivedOpt.StringValue(), existing.Difficulty)
+		if err != nil {
+			return nil, validationError(err.Error())
+		}
+		existing.PerceivedDifficulty = perceived
+	}
+	if existing.PerceivedDifficulty == existing.Difficulty {
+		// The official difficulty was changed to match how it felt
+		existing.PerceivedDifficulty = ""
 	}
 	if categoryOpt, ok := optionMap["category"]; ok {
 		existing.Category = categoryOpt.StringValue()
@@ -258,54 +407,606 @@ func (b *Bot) handleEditCommand(s *discordgo.Session, i *discordgo.InteractionCr
 		}
 	}
 	if solvedAtOpt, ok := optionMap["solved_at"]; ok {
-		solvedAt, err := time.Parse("2006-01-02", solvedAtOpt.StringValue())
+		solvedAt, err := parseDate(solvedAtOpt.StringValue(), time.Now())
 		if err != nil {
-			return errorResponse(ErrInvalidDateFormat.Error()), nil
+			return nil, validationError(ErrInvalidDateFormat.Error())
 		}
 		existing.SolvedAt = solvedAt
 	}
 
 	// Update the problem
-	if err := b.repo.UpdateProblem(context.Background(), existing); err != nil {
-		log.Error().Err(err).Uint("id", problemID).Msg("Failed to update problem")
-		return errorResponse("Failed to update problem in the database."), nil
+	if err := b.repo.UpdateProblem(ctx, existing); err != nil {
+		var invalid *database.ValidationError
+		if errors.As(err, &invalid) {
+			return nil, validationError("Couldn't update problem: " + problemValidationMessage(invalid))
+		}
+		return nil, databaseError("Failed to update problem in the database.", err)
 	}
 
 	return messageResponse(fmt.Sprintf("Successfully updated problem '%s'!", existing.ProblemName)), nil
 }
 
-func (b *Bot) handleDeleteCommand(s *discordgo.Session, i *discordgo.InteractionCreate) (*discordgo.InteractionResponse, error) {
+func (b *Bot) handleDeleteCommand(ctx context.Context, s DiscordSession, i *discordgo.InteractionCreate) (*discordgo.InteractionResponse, error) {
+	problem, _ := ownedProblem(ctx)
+
+	// Delete the problem
+	if err := b.repo.DeleteProblem(ctx, problem.ID); err != nil {
+		return nil, databaseError("Failed to delete problem from the database.", err)
+	}
+
+	return messageResponse(fmt.Sprintf("Successfully deleted problem '%s'!", problem.ProblemName)), nil
+}
+
+// handleArchiveCommand returns the handler for /archive when archived is set and /unarchive otherwise
+func (b *Bot) handleArchiveCommand(archived bool) CommandHandler {
+	verb := "archive"
+	if !archived {
+		verb = "unarchive"
+	}
+
+	return func(ctx context.Context, s DiscordSession, i *discordgo.InteractionCreate) (*discordgo.InteractionResponse, error) {
+		problem, _ := ownedProblem(ctx)
+		if problem.Archived == archived {
+			return messageResponse(fmt.Sprintf("Problem '%s' is already %sd.", problem.ProblemName, verb)), nil
+		}
+
+		if err := b.repo.SetArchived(ctx, problem.ID, archived); err != nil {
+			return nil, databaseError(fmt.Sprintf("Failed to %s the problem.", verb), err)
+		}
+
+		if archived {
+			return messageResponse(fmt.Sprintf("Archived problem '%s'. It won't come up for review until you unarchive it.", problem.ProblemName)), nil
+		}
+		return messageResponse(fmt.Sprintf("Unarchived problem '%s'. It's back in review rotation.", problem.ProblemName)), nil
+	}
+}
+
+func (b *Bot) handleCompareCommand(ctx context.Context, s DiscordSession, i *discordgo.InteractionCreate) (*discordgo.InteractionResponse, error) {
 	options := i.ApplicationCommandData().Options
 	optionMap := make(map[string]*discordgo.ApplicationCommandInteractionDataOption, len(options))
 	for _, opt := range options {
 		optionMap[opt.Name] = opt
 	}
 
-	problemID := uint(optionMap["id"].IntValue())
+	userID := interactionUserID(i)
+	mine, err := b.repo.GetUserStats(ctx, userID)
+	if err != nil {
+		return nil, databaseError("Failed to retrieve your stats from the database.", err)
+	}
+
+	// Compare against another member only if they have opted into a public profile
+	if userOpt, ok := optionMap["user"]; ok && userOpt.UserValue(nil).ID != userID {
+		other := optionUser(i, userOpt)
+
+		settings, err := b.repo.GetUserSettings(ctx, other.ID)
+		if err != nil {
+			return nil, databaseError("Failed to retrieve the other member's settings.", err)
+		}
+		if !settings.ProfilePublic {
+			return nil, permissionError("That member hasn't made their profile public.")
+		}
 
-	// Get the problem to verify ownership
-	problem, err := b.repo.GetProblem(context.Background(), problemID)
+		theirs, err := b.repo.GetUserStats(ctx, other.ID)
+		if err != nil {
+			return nil, databaseError("Failed to retrieve the other member's stats.", err)
+		}
+
+		return embedResponse(b.branded(compareEmbed("You", other.Username, statsColumn(mine), statsColumn(theirs)))), nil
+	}
+
+	// Otherwise compare against the server average, which never exposes individual members
+	all, err := b.repo.GetAllUserStats(ctx)
 	if err != nil {
-		log.Error().Err(err).Uint("id", problemID).Msg("Failed to get problem for deletion")
-		return errorResponse(fmt.Sprintf("Problem with ID %d not found or you don't have permission to delete it.", problemID)), nil
+		return nil, databaseError("Failed to retrieve server stats from the database.", err)
 	}
 
-	// Check if the user is the owner of the problem
-	if problem.UserID != i.Member.User.ID {
-		return errorResponse("You don't have permission to delete this problem."), nil
+	return embedResponse(b.branded(compareEmbed("You", "Server Average", statsColumn(mine), averageStatsColumn(all)))), nil
+}
+
+func (b *Bot) handleStatsCommand(ctx context.Context, s DiscordSession, i *discordgo.InteractionCreate) (*discordgo.InteractionResponse, error) {
+	userID := interactionUserID(i)
+
+	stats, err := b.repo.GetUserStats(ctx, userID)
+	if err != nil {
+		return nil, databaseError("Failed to retrieve your stats from the database.", err)
+	}
+	categories, err := b.repo.GetCategoryDistribution(ctx, userID)
+	if err != nil {
+		return nil, databaseError("Failed to retrieve your stats from the database.", err)
+	}
+	tags, err := b.repo.GetTagDistribution(ctx, userID)
+	if err != nil {
+		return nil, databaseError("Failed to retrieve your stats from the database.", err)
+	}
+	solves, err := b.repo.GetSolveCountByDate(ctx, userID, statsActivityDays)
+	if err != nil {
+		return nil, databaseError("Failed to retrieve your stats from the database.", err)
+	}
+	effectiveness, err := b.repo.GetReviewEffectiveness(ctx, userID)
+	if err != nil {
+		return nil, databaseError("Failed to retrieve your stats from the database.", err)
 	}
 
-	// Delete the problem
-	if err := b.repo.DeleteProblem(context.Background(), problemID); err != nil {
-		log.Error().Err(err).Uint("id", problemID).Msg("Failed to delete problem")
-		return errorResponse("Failed to delete problem from the database."), nil
+	return embedResponse(b.branded(statsEmbed(stats, categories, tags, solves, effectiveness))), nil
+}
+
+func (b *Bot) handlePrivacyCommand(ctx context.Context, s DiscordSession, i *discordgo.InteractionCreate) (*discordgo.InteractionResponse, error) {
+	options := i.ApplicationCommandData().Options
+	optionMap := make(map[string]*discordgo.ApplicationCommandInteractionDataOption, len(options))
+	for _, opt := range options {
+		optionMap[opt.Name] = opt
 	}
 
-	return messageResponse(fmt.Sprintf("Successfully deleted problem '%s'!", problem.ProblemName)), nil
+	userID := interactionUserID(i)
+
+	if publicOpt, ok := optionMap["public"]; ok {
+		if err := b.repo.SetProfilePublic(ctx, userID, publicOpt.BoolValue()); err != nil {
+			return nil, databaseError("Failed to update your privacy setting.", err)
+		}
+	}
+	if responsesOpt, ok := optionMap["responses"]; ok {
+		visibility := responsesOpt.StringValue()
+		if visibility == "default" {
+			visibility = database.ResponseVisibilityDefault
+		}
+		if err := b.repo.SetResponseVisibility(ctx, userID, visibility); err != nil {
+			return nil, databaseError("Failed to update your response visibility setting.", err)
+		}
+	}
+
+	// Report the settings as they now stand, whether or not anything changed
+	settings, err := b.repo.GetUserSettings(ctx, userID)
+	if err != nil {
+		return nil, databaseError("Failed to retrieve your settings from the database.", err)
+	}
+	return ephemeralResponse(privacyMessage(settings)), nil
+}
+
+func (b *Bot) handleRemindersCommand(ctx context.Context, s DiscordSession, i *discordgo.InteractionCreate) (*discordgo.InteractionResponse, error) {
+	options := i.ApplicationCommandData().Options
+	optionMap := make(map[string]*discordgo.ApplicationCommandInteractionDataOption, len(options))
+	for _, opt := range options {
+		optionMap[opt.Name] = opt
+	}
+
+	userID := interactionUserID(i)
+
+	settings, err := b.repo.GetUserSettings(ctx, userID)
+	if err != nil {
+		return nil, databaseError("Failed to retrieve your settings from the database.", err)
+	}
+
+	if len(optionMap) > 0 {
+		difficulty, category := settings.ReminderDifficulty, settings.ReminderCategory
+		if difficultyOpt, ok := optionMap["difficulty"]; ok {
+			difficulty = reminderFilterValue(difficultyOpt.StringValue())
+		}
+		if categoryOpt, ok := optionMap["category"]; ok {
+			category = reminderFilterValue(strings.TrimSpace(categoryOpt.StringValue()))
+		}
+
+		if err := b.repo.SetReminderFilters(ctx, userID, difficulty, category); err != nil {
+			return nil, databaseError("Failed to update your reminder filters.", err)
+		}
+		settings.ReminderDifficulty, settings.ReminderCategory = difficulty, category
+	}
+
+	return ephemeralResponse(remindersMessage(settings)), nil
+}
+
+func (b *Bot) handleAPITokenCommand(ctx context.Context, s DiscordSession, i *discordgo.InteractionCreate) (*discordgo.InteractionResponse, error) {
+	userID := interactionUserID(i)
+
+	token, hash, err := api.NewToken()
+	if err != nil {
+		return nil, err
+	}
+	if err := b.repo.SetAPITokenHash(ctx, userID, hash); err != nil {
+		return nil, databaseError("Failed to create your API token.", err)
+	}
+
+	return ephemeralResponse(fmt.Sprintf("Your new API token (any previous token no longer works):\n```\n%s\n```\n"+
+		"Send it as `Authorization: Bearer <token>` to `/users/%s/problems` or `/users/%s/stats`. It won't be shown again.",
+		token, userID, userID)), nil
+}
+
+func (b *Bot) handleReviewMarkCommand(ctx context.Context, s DiscordSession, i *discordgo.InteractionCreate) (*discordgo.InteractionResponse, error) {
+	problem, _ := ownedProblem(ctx)
+
+	if err := b.repo.IncrementReviewCount(ctx, problem.ID); err != nil {
+		return nil, databaseError("Failed to record the review in the database.", err)
+	}
+
+	return messageResponse(fmt.Sprintf("Marked '%s' as reviewed (%d reviews total).", problem.ProblemName, problem.ReviewCount+1)), nil
+}
+
+func (b *Bot) handleReviewHistoryCommand(ctx context.Context, s DiscordSession, i *discordgo.InteractionCreate) (*discordgo.InteractionResponse, error) {
+	problem, _ := ownedProblem(ctx)
+
+	history, err := b.repo.ListReviewHistory(ctx, problem.ID, reviewHistoryLimit)
+	if err != nil {
+		return nil, databaseError("Failed to retrieve review history from the database.", err)
+	}
+
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("# Review History: %s\n", problem.ProblemName))
+	sb.WriteString(fmt.Sprintf("**Solved On:** %s\n", problem.SolvedAt.Format("2006-01-02")))
+	sb.WriteString(fmt.Sprintf("**Review Count:** %d\n", problem.ReviewCount))
+	if len(history) == 0 {
+		sb.WriteString("\nNo reviews recorded yet.")
+	} else {
+		sb.WriteString("\n**Recent Reviews:**\n")
+		for _, reviewedAt := range history {
+			sb.WriteString(fmt.Sprintf("- %s\n", reviewedAt.Format("2006-01-02 15:04")))
+		}
+	}
+
+	return messageResponse(sb.String()), nil
+}
+
+func (b *Bot) handleTagDeleteCommand(ctx context.Context, s DiscordSession, i *discordgo.InteractionCreate) (*discordgo.InteractionResponse, error) {
+	_, options := getSubcommand(i)
+	optionMap := make(map[string]*discordgo.ApplicationCommandInteractionDataOption, len(options))
+	for _, opt := range options {
+		optionMap[opt.Name] = opt
+	}
+
+	name := optionMap["name"].StringValue()
+	reassignTo := ""
+	if reassignOpt, ok := optionMap["reassign_to"]; ok {
+		reassignTo = reassignOpt.StringValue()
+	}
+
+	updated, err := b.repo.DeleteTag(ctx, interactionUserID(i), name, reassignTo)
+	if err != nil {
+		zerolog.Ctx(ctx).Error().Err(err).Str("tag", name).Msg("Failed to delete tag")
+		return nil, validationError(fmt.Sprintf("Failed to delete tag '%s': %s", name, err.Error()))
+	}
+
+	if reassignTo != "" {
+		return messageResponse(fmt.Sprintf("Moved %d problem(s) from tag '%s' to '%s'.", updated, name, reassignTo)), nil
+	}
+	return messageResponse(fmt.Sprintf("Removed tag '%s' from %d problem(s).", name, updated)), nil
+}
+
+func (b *Bot) handleTriggerReviewCommand(ctx context.Context, s DiscordSession, i *discordgo.InteractionCreate) (*discordgo.InteractionResponse, error) {
+	scheduler := b.scheduler.Load()
+	if scheduler == nil {
+		return nil, unavailableError("The review scheduler is not running.")
+	}
+
+	// Reminders can take a while to send, so run them outside the interaction
+	go scheduler.sendDailyReviewReminder(context.Background())
+
+	zerolog.Ctx(ctx).Info().Msg("Daily review reminders triggered manually")
+	return ephemeralResponse("Daily review reminders are being sent."), nil
+}
+
+func (b *Bot) handleAdminVacuumCommand(ctx context.Context, s DiscordSession, i *discordgo.InteractionCreate) (*discordgo.InteractionResponse, error) {
+	if err := b.repo.Compact(ctx); err != nil {
+		return nil, databaseError("Failed to compact the database.", err)
+	}
+	return ephemeralResponse("Database compacted successfully."), nil
+}
+
+func (b *Bot) handleAdminRecalcCommand(ctx context.Context, s DiscordSession, i *discordgo.InteractionCreate) (*discordgo.InteractionResponse, error) {
+	options := i.ApplicationCommandData().Options
+	optionMap := make(map[string]*discordgo.ApplicationCommandInteractionDataOption, len(options))
+	for _, opt := range options {
+		optionMap[opt.Name] = opt
+	}
+
+	if userOpt, ok := optionMap["user"]; ok {
+		user := userOpt.UserValue(nil)
+		corrected, err := b.repo.RecalculateUserStats(ctx, user.ID)
+		if err != nil {
+			return nil, databaseError("Failed to recalculate stats.", err)
+		}
+		return ephemeralResponse(fmt.Sprintf("Recalculated stats for %s: %d problems corrected.", user.Mention(), corrected)), nil
+	}
+
+	corrected, err := b.repo.RecalculateAllUserStats(ctx)
+	if err != nil {
+		return nil, databaseError("Failed to recalculate stats.", err)
+	}
+	return ephemeralResponse(fmt.Sprintf("Recalculated stats for all users: %d problems corrected.", corrected)), nil
+}
+
+func (b *Bot) handleAdminBackupCommand(ctx context.Context, s DiscordSession, i *discordgo.InteractionCreate) (*discordgo.InteractionResponse, error) {
+	if b.backup.DestinationPath == "" {
+		return nil, unavailableError("No backup destination is configured.")
+	}
+
+	path := b.backup.Destination(time.Now())
+	err := b.repo.BackupDatabase(ctx, path)
+	if errors.Is(err, database.ErrBackupUnsupported) {
+		return nil, unavailableError("Backups aren't supported for this database driver through this command.")
+	}
+	if err != nil {
+		return nil, databaseError("Failed to back up the database.", err)
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to stat backup file: %w", err)
+	}
+	return ephemeralResponse(fmt.Sprintf("Database backed up to `%s` (%.1f KB).", path, float64(info.Size())/1024)), nil
+}
+
+func (b *Bot) handleDedupeCommand(ctx context.Context, s DiscordSession, i *discordgo.InteractionCreate) (*discordgo.InteractionResponse, error) {
+	dryRun := false
+	for _, opt := range i.ApplicationCommandData().Options {
+		if opt.Name == "dry_run" {
+			dryRun = opt.BoolValue()
+		}
+	}
+
+	groups, err := b.repo.MergeDuplicateProblems(ctx, interactionUserID(i), dryRun)
+	if err != nil {
+		return nil, databaseError("Failed to merge duplicate problems.", err)
+	}
+	if len(groups) == 0 {
+		return ephemeralResponse("You don't have any duplicate problems."), nil
+	}
+
+	duplicates := 0
+	var lines strings.Builder
+	for _, group := range groups {
+		ids := make([]string, len(group.Duplicates))
+		for idx, dup := range group.Duplicates {
+			ids[idx] = fmt.Sprintf("%d", dup.ID)
+		}
+		duplicates += len(group.Duplicates)
+		lines.WriteString(fmt.Sprintf("- **%s**: keeping ID %d, merging ID %s\n", group.Keep.ProblemName, group.Keep.ID, strings.Join(ids, ", ")))
+	}
+
+	header := fmt.Sprintf("Merged %d duplicate entries into %d problems:\n", duplicates, len(groups))
+	if dryRun {
+		header = fmt.Sprintf("Found %d duplicate entries of %d problems. Run /dedupe without dry_run to merge them:\n", duplicates, len(groups))
+	}
+	return ephemeralResponse(truncateString(header+lines.String(), discord.MaxMessageLength)), nil
 }
 
 // Helper functions
 
+// perceivedDifficulty normalizes a perceived difficulty option, storing nothing when it matches official
+func perceivedDifficulty(value, official string) (string, error) {
+	if value == perceivedSameAsOfficial {
+		return "", nil
+	}
+	perceived, err := leetcode.NormalizeDifficulty(value)
+	if err != nil {
+		return "", err
+	}
+	if perceived == official {
+		return "", nil
+	}
+	return perceived, nil
+}
+
+// privacyMessage describes the effect of the user's profile and response visibility settings
+func privacyMessage(settings *database.UserSettings) string {
+	message := "Your profile is **private**: other members can't view or compare against your stats."
+	if settings.ProfilePublic {
+		message = "Your profile is **public**: other members can compare against your stats."
+	}
+
+	switch settings.ResponseVisibility {
+	case database.ResponseVisibilityPublic:
+		message += "\nReplies to /list and /get are shown to **everyone** unless you ask for them to be ephemeral."
+	case database.ResponseVisibilityPrivate:
+		message += "\nReplies to /list and /get are shown **only to you** unless you set ephemeral to False."
+	default:
+		message += "\nReplies to /list and /get are shown to everyone; set ephemeral on a command to keep one to yourself."
+	}
+	return message
+}
+
+// reminderFilterAny is the option value that removes a reminder filter
+const reminderFilterAny = "any"
+
+// reminderFilterValue converts an option value to the stored filter, where empty means no filter
+func reminderFilterValue(value string) string {
+	if strings.EqualFold(value, reminderFilterAny) {
+		return ""
+	}
+	return value
+}
+
+// remindersMessage describes which problems the user's daily review reminders cover
+func remindersMessage(settings *database.UserSettings) string {
+	difficulty, category := "any difficulty", "any category"
+	if settings.ReminderDifficulty != "" {
+		difficulty = fmt.Sprintf("**%s** difficulty", settings.ReminderDifficulty)
+	}
+	if settings.ReminderCategory != "" {
+		category = fmt.Sprintf("the **%s** category", settings.ReminderCategory)
+	}
+	return fmt.Sprintf("Your daily review reminders cover problems of %s in %s.", difficulty, category)
+}
+
+// ambiguousProblemMessage lists the problems sharing a name so the user can pick one by ID
+func ambiguousProblemMessage(err *database.AmbiguousProblemError, locale discordgo.Locale) string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("You have %d problems named '%s'. Use /get with one of these IDs:\n", len(err.Matches), err.Name))
+	for _, problem := range err.Matches {
+		sb.WriteString(fmt.Sprintf("- **ID %d**, solved %s\n", problem.ID, formatDate(problem.SolvedAt, locale)))
+	}
+	return sb.String()
+}
+
+// problemValidationMessage explains which field of a problem was rejected and how to fix it
+func problemValidationMessage(err *database.ValidationError) string {
+	switch err.Field {
+	case database.FieldProblemName:
+		return "the problem name can't be empty."
+	case database.FieldDifficulty:
+		return fmt.Sprintf("'%s' isn't a difficulty. Choose Easy, Medium or Hard.", err.Value)
+	case database.FieldPerceivedDifficulty:
+		return fmt.Sprintf("'%s' isn't a perceived difficulty. Choose Easy, Medium or Hard.", err.Value)
+	case database.FieldStatus:
+		return fmt.Sprintf("'%s' isn't a status. Choose Solved, Needed Hint or Stuck.", err.Value)
+	case database.FieldCategory:
+		return "the category can't be empty."
+	case database.FieldTags:
+		return fmt.Sprintf("a problem can have at most %d tags and %d were given. Remove some and try again.", err.Limit, err.Count)
+	default:
+		return err.Error() + "."
+	}
+}
+
+// statsColumn formats a user's stats as one side of a comparison
+func statsColumn(stats *database.UserStats) string {
+	return fmt.Sprintf("**Total:** %d\n**Easy:** %d\n**Medium:** %d\n**Hard:** %d\n**Solved:** %d\n**Needed Hint:** %d\n**Stuck:** %d",
+		stats.TotalProblems, stats.Easy, stats.Medium, stats.Hard, stats.Solved, stats.NeededHint, stats.Stuck)
+}
+
+// averageStatsColumn formats the per-user average of the given stats as one side of a comparison
+func averageStatsColumn(all []*database.UserStats) string {
+	if len(all) == 0 {
+		return "No data yet"
+	}
+
+	var total, easy, medium, hard, solved, neededHint, stuck float64
+	for _, stats := range all {
+		total += float64(stats.TotalProblems)
+		easy += float64(stats.Easy)
+		medium += float64(stats.Medium)
+		hard += float64(stats.Hard)
+		solved += float64(stats.Solved)
+		neededHint += float64(stats.NeededHint)
+		stuck += float64(stats.Stuck)
+	}
+
+	n := float64(len(all))
+	return fmt.Sprintf("**Total:** %.1f\n**Easy:** %.1f\n**Medium:** %.1f\n**Hard:** %.1f\n**Solved:** %.1f\n**Needed Hint:** %.1f\n**Stuck:** %.1f",
+		total/n, easy/n, medium/n, hard/n, solved/n, neededHint/n, stuck/n)
+}
+
+// statsEmbed builds the /stats embed from a user's aggregates
+func statsEmbed(stats *database.UserStats, categories, tags, solves map[string]int, effectiveness float64) *discordgo.MessageEmbed {
+	recent := 0
+	for _, count := range solves {
+		recent += count
+	}
+
+	return &discordgo.MessageEmbed{
+		Title: "Your Stats",
+		Color: ProgressColor(effectiveness),
+		Fields: []*discordgo.MessageEmbedField{
+			{Name: "Overview", Value: statsColumn(stats), Inline: true},
+			{Name: "Top Categories", Value: topCounts(categories, statsTopCount), Inline: true},
+			{Name: "Top Tags", Value: topCounts(tags, statsTopCount), Inline: true},
+			{Name: fmt.Sprintf("Solved in the Last %d Days", statsActivityDays), Value: fmt.Sprintf("%d", recent), Inline: true},
+			{Name: "Review Effectiveness", Value: fmt.Sprintf("%.0f%% of reviewed Stuck/Needed Hint problems are now Solved", effectiveness*100), Inline: true},
+		},
+	}
+}
+
+// topCounts formats the n largest counts as a list, breaking ties alphabetically
+func topCounts(counts map[string]int, n int) string {
+	if len(counts) == 0 {
+		return "None yet"
+	}
+
+	labels := make([]string, 0, len(counts))
+	for label := range counts {
+		labels = append(labels, label)
+	}
+	sort.Slice(labels, func(a, b int) bool {
+		if counts[labels[a]] != counts[labels[b]] {
+			return counts[labels[a]] > counts[labels[b]]
+		}
+		return labels[a] < labels[b]
+	})
+	if len(labels) > n {
+		labels = labels[:n]
+	}
+
+	lines := make([]string, len(labels))
+	for idx, label := range labels {
+		lines[idx] = fmt.Sprintf("**%s:** %d", label, counts[label])
+	}
+	return strings.Join(lines, "\n")
+}
+
+// compareEmbed builds a side-by-side comparison embed
+func compareEmbed(leftName, rightName, left, right string) *discordgo.MessageEmbed {
+	return &discordgo.MessageEmbed{
+		Title: fmt.Sprintf("%s vs %s", leftName, rightName),
+		Color: ColorInfo,
+		Fields: []*discordgo.MessageEmbedField{
+			{Name: leftName, Value: left, Inline: true},
+			{Name: rightName, Value: right, Inline: true},
+		},
+	}
+}
+
+// deferResponse acknowledges an interaction immediately so slow work can finish after
+// Discord's 3 second response window; the result must be sent with InteractionResponseEdit
+func deferResponse(s DiscordSession, i *discordgo.InteractionCreate, ephemeral bool) error {
+	response := &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseDeferredChannelMessageWithSource,
+	}
+	if ephemeral {
+		response.Data = &discordgo.InteractionResponseData{
+			Flags: discordgo.MessageFlagsEphemeral,
+		}
+	}
+	return s.InteractionRespond(i.Interaction, response)
+}
+
+// respondedError carries a handler error whose reply has already been sent, so middleware
+// further out records and logs it without responding again
+type respondedError struct {
+	err error
+}
+
+// Error implements error
+func (e *respondedError) Error() string {
+	return e.err.Error()
+}
+
+// Unwrap lets errors.Is and errors.As see the handler's error
+func (e *respondedError) Unwrap() error {
+	return e.err
+}
+
+// deferred wraps a slow handler so the interaction is acknowledged before the handler runs
+// and the handler's response is delivered by editing the deferred message. The handler's
+// error is still returned, as a *respondedError, so it is counted and logged.
+func (b *Bot) deferred(ephemeral bool, next CommandHandler) CommandHandler {
+	return func(ctx context.Context, s DiscordSession, i *discordgo.InteractionCreate) (*discordgo.InteractionResponse, error) {
+		if err := deferResponse(s, i, ephemeral); err != nil {
+			return nil, fmt.Errorf("failed to defer response: %w", err)
+		}
+
+		response, err := next(ctx, s, i)
+		edit := &discordgo.WebhookEdit{}
+		switch {
+		case errors.Is(ctx.Err(), context.DeadlineExceeded):
+			content := commandTimeoutMessage
+			edit.Content = &content
+		case err != nil:
+			reply := handlerErrorResponse(err).Data
+			edit.Content = &reply.Content
+			edit.Embeds = &reply.Embeds
+		case response != nil && response.Data != nil:
+			edit.Content = &response.Data.Content
+			edit.Embeds = &response.Data.Embeds
+			edit.Files = response.Data.Files
+		}
+
+		if _, err := s.InteractionResponseEdit(i.Interaction, edit); err != nil {
+			zerolog.Ctx(ctx).Error().Err(err).Msg("Failed to edit deferred response")
+		}
+		if err != nil {
+			return nil, &respondedError{err: err}
+		}
+		return nil, nil
+	}
+}
+
 // truncateString truncates a string to max length and adds ellipsis if needed
 func truncateString(s string, maxLen int) string {
 	if len(s) <= maxLen {
@@ -325,6 +1026,58 @@ func errorResponse(content string) *discordgo.InteractionResponse {
 	}
 }
 
+// ephemeralResponse creates a message response visible only to the invoking user
+func ephemeralResponse(content string) *discordgo.InteractionResponse {
+	return &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Content: content,
+			Flags:   discordgo.MessageFlagsEphemeral,
+		},
+	}
+}
+
+// cooldownResponse creates an ephemeral embed telling the user when they may retry a command
+func cooldownResponse(remaining time.Duration) *discordgo.InteractionResponse {
+	seconds := int(remaining.Round(time.Second) / time.Second)
+	if seconds < 1 {
+		seconds = 1
+	}
+	return handlerErrorResponse(rateLimitError(fmt.Sprintf("This command is on cooldown. Try again in %d seconds.", seconds)))
+}
+
+// jsonResponse creates an ephemeral response carrying v as an indented JSON code block.
+// Output too long for one message is attached as name instead, so it's never truncated.
+func jsonResponse(name string, v interface{}) (*discordgo.InteractionResponse, error) {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode JSON: %w", err)
+	}
+
+	content := "```json\n" + string(data) + "\n```"
+	if len(content) <= discord.MaxMessageLength {
+		return ephemeralResponse(content), nil
+	}
+
+	response := ephemeralResponse("The JSON output is too long to show inline, so it's attached.")
+	response.Data.Files = []*discordgo.File{{
+		Name:        name,
+		ContentType: "application/json",
+		Reader:      bytes.NewReader(data),
+	}}
+	return response, nil
+}
+
+// embedResponse creates a standard response carrying a single embed
+func embedResponse(embed *discordgo.MessageEmbed) *discordgo.InteractionResponse {
+	return &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Embeds: []*discordgo.MessageEmbed{embed},
+		},
+	}
+}
+
 // messageResponse creates a standard message response
 func messageResponse(content string) *discordgo.InteractionResponse {
 	return &discordgo.InteractionResponse{
@@ -0,0 +1,604 @@
+package bot
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/yugonline/grind_review_bot/config"
+	"github.com/yugonline/grind_review_bot/internal/database"
+)
+
+const testUserID = "user-1"
+
+// commandInteraction builds a guild slash command interaction from testUserID
+func commandInteraction(name string, options ...*discordgo.ApplicationCommandInteractionDataOption) *discordgo.InteractionCreate {
+	return &discordgo.InteractionCreate{Interaction: &discordgo.Interaction{
+		Type:      discordgo.InteractionApplicationCommand,
+		GuildID:   "guild-1",
+		ChannelID: "channel-1",
+		Member:    &discordgo.Member{User: &discordgo.User{ID: testUserID, Username: "tester"}},
+		Data:      discordgo.ApplicationCommandInteractionData{Name: name, Options: options},
+	}}
+}
+
+// buttonInteraction builds a guild button click from testUserID
+func buttonInteraction(customID string) *discordgo.InteractionCreate {
+	return &discordgo.InteractionCreate{Interaction: &discordgo.Interaction{
+		Type:      discordgo.InteractionMessageComponent,
+		GuildID:   "guild-1",
+		ChannelID: "channel-1",
+		Member:    &discordgo.Member{User: &discordgo.User{ID: testUserID, Username: "tester"}},
+		Data:      discordgo.MessageComponentInteractionData{CustomID: customID, ComponentType: discordgo.ButtonComponent},
+	}}
+}
+
+func stringOption(name, value string) *discordgo.ApplicationCommandInteractionDataOption {
+	return &discordgo.ApplicationCommandInteractionDataOption{Name: name, Type: discordgo.ApplicationCommandOptionString, Value: value}
+}
+
+func userOption(name, userID string) *discordgo.ApplicationCommandInteractionDataOption {
+	return &discordgo.ApplicationCommandInteractionDataOption{Name: name, Type: discordgo.ApplicationCommandOptionUser, Value: userID}
+}
+
+func boolOption(name string, value bool) *discordgo.ApplicationCommandInteractionDataOption {
+	return &discordgo.ApplicationCommandInteractionDataOption{Name: name, Type: discordgo.ApplicationCommandOptionBoolean, Value: value}
+}
+
+func intOption(name string, value int) *discordgo.ApplicationCommandInteractionDataOption {
+	return &discordgo.ApplicationCommandInteractionDataOption{Name: name, Type: discordgo.ApplicationCommandOptionInteger, Value: float64(value)}
+}
+
+// dispatch runs an interaction through the bot and returns the response sent to Discord
+func dispatch(t *testing.T, bot *Bot, session *MockSession, i *discordgo.InteractionCreate) *discordgo.InteractionResponse {
+	t.Helper()
+	before := len(session.Calls("InteractionRespond"))
+	bot.interactionCreate(session, i)
+
+	calls := session.Calls("InteractionRespond")
+	if len(calls) != before+1 {
+		t.Fatalf("interaction got %d responses, want 1", len(calls)-before)
+	}
+	return calls[len(calls)-1].Args[1].(*discordgo.InteractionResponse)
+}
+
+// deferredResult runs an interaction through a deferred handler and returns the edit
+// that replaced the deferral, as a response so responseText can read it
+func deferredResult(t *testing.T, bot *Bot, session *MockSession, i *discordgo.InteractionCreate) *discordgo.InteractionResponse {
+	t.Helper()
+	before := len(session.Calls("InteractionResponseEdit"))
+	if resp := dispatch(t, bot, session, i); resp.Type != discordgo.InteractionResponseDeferredChannelMessageWithSource {
+		t.Fatalf("response type = %v, want a deferral", resp.Type)
+	}
+
+	edits := session.Calls("InteractionResponseEdit")
+	if len(edits) != before+1 {
+		t.Fatalf("deferred message edited %d times, want 1", len(edits)-before)
+	}
+	edit := edits[len(edits)-1].Args[1].(*discordgo.WebhookEdit)
+	data := &discordgo.InteractionResponseData{}
+	if edit.Content != nil {
+		data.Content = *edit.Content
+	}
+	if edit.Embeds != nil {
+		data.Embeds = *edit.Embeds
+	}
+	return &discordgo.InteractionResponse{Type: discordgo.InteractionResponseChannelMessageWithSource, Data: data}
+}
+
+// responseText returns a response's content followed by the titles and descriptions of its embeds
+func responseText(resp *discordgo.InteractionResponse) string {
+	text := resp.Data.Content
+	for _, embed := range resp.Data.Embeds {
+		text += "\n" + embed.Title + "\n" + embed.Description
+	}
+	return text
+}
+
+// addTestProblem stores a problem for testUserID
+func addTestProblem(t *testing.T, bot *Bot, name, difficulty string, tags ...string) *database.ProblemEntry {
+	t.Helper()
+	entry := &database.ProblemEntry{UserID: testUserID, ProblemName: name, Difficulty: difficulty, Category: "Array", Status: database.StatusSolved, SolvedAt: time.Date(2024, time.January, 1, 12, 0, 0, 0, time.UTC), Tags: tags}
+	if err := bot.repo.CreateProblem(context.Background(), entry); err != nil {
+		t.Fatalf("CreateProblem: %v", err)
+	}
+	return entry
+}
+
+func TestAddCommand(t *testing.T) {
+	bot, session := newTestBot(t, config.DiscordConfig{})
+
+	resp := dispatch(t, bot, session, commandInteraction("add",
+		stringOption("name", "Two Sum"),
+		stringOption("difficulty", "Easy"),
+		stringOption("status", database.StatusSolved),
+		stringOption("solved_at", "2024-03-05"),
+		stringOption("category", "Array"),
+		stringOption("tags", "array, hash-table"),
+	))
+
+	if resp.Type != discordgo.InteractionResponseChannelMessageWithSource || !strings.Contains(resp.Data.Content, "Successfully added problem 'Two Sum'") {
+		t.Fatalf("response = %+v, want a success message", resp.Data)
+	}
+	problems, err := bot.repo.ListProblems(context.Background(), testUserID, "", "", "", nil, true, 0, 0)
+	if err != nil {
+		t.Fatalf("ListProblems: %v", err)
+	}
+	if len(problems) != 1 || problems[0].Category != "Array" || len(problems[0].Tags) != 2 {
+		t.Fatalf("stored problems = %+v, want Two Sum with two tags", problems)
+	}
+	if got := problems[0].SolvedAt.Format(time.DateOnly); got != "2024-03-05" {
+		t.Errorf("SolvedAt = %s, want 2024-03-05", got)
+	}
+}
+
+func TestAddCommandRejectsInvalidInput(t *testing.T) {
+	bot, session := newTestBot(t, config.DiscordConfig{})
+
+	resp := dispatch(t, bot, session, commandInteraction("add",
+		stringOption("name", "Two Sum"),
+		stringOption("difficulty", "Easy"),
+		stringOption("status", "Skipped"),
+		stringOption("solved_at", "2024-03-05"),
+		stringOption("category", "Array"),
+	))
+
+	if resp.Data.Flags&discordgo.MessageFlagsEphemeral == 0 || !strings.Contains(responseText(resp), "'Skipped' isn't a status") {
+		t.Errorf("response = %+v, want an ephemeral status error", resp.Data)
+	}
+	if problems, _ := bot.repo.ListProblems(context.Background(), testUserID, "", "", "", nil, true, 0, 0); len(problems) != 0 {
+		t.Errorf("an invalid problem was stored: %+v", problems)
+	}
+}
+
+func TestAddCommandNormalizesDifficulty(t *testing.T) {
+	bot, session := newTestBot(t, config.DiscordConfig{})
+	add := func(difficulty string) *discordgo.InteractionResponse {
+		return dispatch(t, bot, session, commandInteraction("add",
+			stringOption("name", "Coin Change"),
+			stringOption("difficulty", difficulty),
+			stringOption("status", database.StatusSolved),
+			stringOption("solved_at", "2024-03-05"),
+			stringOption("category", "Dynamic Programming"),
+		))
+	}
+
+	add(" med ")
+	problems, err := bot.repo.ListProblems(context.Background(), testUserID, "", "", "", nil, true, 0, 0)
+	if err != nil {
+		t.Fatalf("ListProblems: %v", err)
+	}
+	if len(problems) != 1 || problems[0].Difficulty != database.DifficultyMedium {
+		t.Fatalf("stored %+v, want one Medium problem", problems)
+	}
+
+	if text := responseText(add("extreme")); !strings.Contains(text, "Easy, Medium or Hard") {
+		t.Errorf("response = %q, want the valid difficulties listed", text)
+	}
+}
+
+func TestAddCommandConfirmsDuplicates(t *testing.T) {
+	bot, session := newTestBot(t, config.DiscordConfig{})
+	addTestProblem(t, bot, "Two Sum", database.DifficultyEasy)
+
+	resp := dispatch(t, bot, session, commandInteraction("add",
+		stringOption("name", "two sum"),
+		stringOption("difficulty", "Easy"),
+		stringOption("status", database.StatusSolved),
+		stringOption("solved_at", "2024-03-05"),
+		stringOption("category", "Array"),
+	))
+	if len(resp.Data.Components) != 1 {
+		t.Fatalf("response = %+v, want an Add anyway button", resp.Data)
+	}
+	button := resp.Data.Components[0].(discordgo.ActionsRow).Components[0].(discordgo.Button)
+
+	resp = dispatch(t, bot, session, buttonInteraction(button.CustomID))
+	if !strings.Contains(resp.Data.Content, "Successfully added problem 'two sum'") {
+		t.Errorf("response = %+v, want a success message", resp.Data)
+	}
+	if problems, _ := bot.repo.ListProblems(context.Background(), testUserID, "", "", "", nil, true, 0, 0); len(problems) != 2 {
+		t.Errorf("stored %d problems, want 2", len(problems))
+	}
+
+	// The button works once
+	resp = dispatch(t, bot, session, buttonInteraction(button.CustomID))
+	if !strings.Contains(resp.Data.Content, "expired") {
+		t.Errorf("second click = %+v, want an expiry message", resp.Data)
+	}
+}
+
+func TestListCommand(t *testing.T) {
+	bot, session := newTestBot(t, config.DiscordConfig{})
+	addTestProblem(t, bot, "Two Sum", database.DifficultyEasy)
+	addTestProblem(t, bot, "Coin Change", database.DifficultyMedium)
+
+	resp := dispatch(t, bot, session, commandInteraction("list", stringOption("difficulty", database.DifficultyMedium)))
+
+	if !strings.Contains(resp.Data.Content, "Coin Change") || strings.Contains(resp.Data.Content, "Two Sum") {
+		t.Errorf("content = %q, want only Coin Change", resp.Data.Content)
+	}
+
+	resp = dispatch(t, bot, session, commandInteraction("list", stringOption("status", database.StatusStuck)))
+	if resp.Data.Content != "No problems found matching your criteria." {
+		t.Errorf("content = %q, want the empty message", resp.Data.Content)
+	}
+}
+
+func TestListCommandAttachesLargeOutput(t *testing.T) {
+	bot, session := newTestBot(t, config.DiscordConfig{})
+	for idx := range 40 {
+		addTestProblem(t, bot, fmt.Sprintf("Problem %02d", idx), database.DifficultyMedium)
+	}
+
+	// The default limit of 10 still fits in a message
+	resp := dispatch(t, bot, session, commandInteraction("list"))
+	if len(resp.Data.Files) != 0 || !strings.Contains(resp.Data.Content, "Problem 00") {
+		t.Errorf("response = %+v, want the table inline", resp.Data)
+	}
+
+	resp = dispatch(t, bot, session, commandInteraction("list", intOption("limit", 40)))
+	if !strings.Contains(resp.Data.Content, "40 problems") {
+		t.Errorf("content = %q, want a note about the attachment", resp.Data.Content)
+	}
+	if len(resp.Data.Files) != 1 {
+		t.Fatalf("attached %d files, want 1", len(resp.Data.Files))
+	}
+	file := resp.Data.Files[0]
+	if file.Name != "problems.txt" || file.ContentType != "text/plain" {
+		t.Errorf("file = %q (%s), want problems.txt as text/plain", file.Name, file.ContentType)
+	}
+	table, err := io.ReadAll(file.Reader)
+	if err != nil {
+		t.Fatalf("failed to read the attachment: %v", err)
+	}
+	for idx := range 40 {
+		if name := fmt.Sprintf("Problem %02d", idx); !strings.Contains(string(table), name) {
+			t.Errorf("attachment is missing %q", name)
+		}
+	}
+}
+
+func TestGetCommand(t *testing.T) {
+	bot, session := newTestBot(t, config.DiscordConfig{})
+	problem := addTestProblem(t, bot, "Merge k Sorted Lists", database.DifficultyHard, "heap")
+
+	resp := dispatch(t, bot, session, commandInteraction("get", intOption("id", int(problem.ID))))
+
+	if len(resp.Data.Embeds) != 1 {
+		t.Fatalf("response = %+v, want one embed", resp.Data)
+	}
+	embed := resp.Data.Embeds[0]
+	if embed.Title != "Problem: Merge k Sorted Lists" || embed.Color != ColorHard || !strings.Contains(embed.Description, "**Tags:** heap") {
+		t.Errorf("embed = %+v, want the problem's details", embed)
+	}
+}
+
+func TestGetCommandByName(t *testing.T) {
+	bot, session := newTestBot(t, config.DiscordConfig{})
+	addTestProblem(t, bot, "Merge k Sorted Lists", database.DifficultyHard, "heap")
+
+	resp := dispatch(t, bot, session, commandInteraction("get", stringOption("name", "merge k sorted lists")))
+	if len(resp.Data.Embeds) != 1 || resp.Data.Embeds[0].Title != "Problem: Merge k Sorted Lists" {
+		t.Errorf("response = %q, want the problem's details", responseText(resp))
+	}
+
+	// With two matches the user is asked to pick by ID
+	first := addTestProblem(t, bot, "Two Sum", database.DifficultyEasy)
+	second := addTestProblem(t, bot, "Two Sum", database.DifficultyEasy)
+	resp = dispatch(t, bot, session, commandInteraction("get", stringOption("name", "Two Sum")))
+	text := responseText(resp)
+	if resp.Data.Flags&discordgo.MessageFlagsEphemeral == 0 || !strings.Contains(text, "2 problems named 'Two Sum'") {
+		t.Errorf("response = %q, want an ephemeral disambiguation list", text)
+	}
+	for _, p := range []*database.ProblemEntry{first, second} {
+		if !strings.Contains(text, fmt.Sprintf("**ID %d**, solved 2024-01-01", p.ID)) {
+			t.Errorf("response = %q, want it to list ID %d", text, p.ID)
+		}
+	}
+
+	resp = dispatch(t, bot, session, commandInteraction("get", stringOption("name", "Jump Game")))
+	if !strings.Contains(responseText(resp), "You haven't logged a problem named 'Jump Game'.") {
+		t.Errorf("response = %q, want a not found message", responseText(resp))
+	}
+}
+
+func TestGetCommandChecksOwnership(t *testing.T) {
+	bot, session := newTestBot(t, config.DiscordConfig{})
+	other := &database.ProblemEntry{UserID: "user-2", ProblemName: "Two Sum", Difficulty: database.DifficultyEasy, Category: "Array", Status: database.StatusSolved, SolvedAt: time.Now()}
+	if err := bot.repo.CreateProblem(context.Background(), other); err != nil {
+		t.Fatalf("CreateProblem: %v", err)
+	}
+
+	resp := dispatch(t, bot, session, commandInteraction("get", intOption("id", int(other.ID))))
+	if !strings.Contains(responseText(resp), "don't have permission") {
+		t.Errorf("response = %+v, want a permission error", resp.Data)
+	}
+
+	resp = dispatch(t, bot, session, commandInteraction("get", intOption("id", 404)))
+	if !strings.Contains(responseText(resp), "not found") {
+		t.Errorf("response = %+v, want a not found error", resp.Data)
+	}
+}
+
+func TestEditCommand(t *testing.T) {
+	bot, session := newTestBot(t, config.DiscordConfig{})
+	problem := addTestProblem(t, bot, "Two Sum", database.DifficultyEasy, "array")
+
+	resp := dispatch(t, bot, session, commandInteraction("edit",
+		intOption("id", int(problem.ID)),
+		stringOption("status", database.StatusNeededHint),
+		stringOption("tags", "hash-table"),
+	))
+	if !strings.Contains(resp.Data.Content, "Successfully updated problem 'Two Sum'") {
+		t.Fatalf("response = %+v, want a success message", resp.Data)
+	}
+
+	got, err := bot.repo.GetProblem(context.Background(), problem.ID)
+	if err != nil {
+		t.Fatalf("GetProblem: %v", err)
+	}
+	if got.Status != database.StatusNeededHint || len(got.Tags) != 1 || got.Tags[0] != "hash-table" {
+		t.Errorf("stored problem = %+v, want Needed Hint tagged hash-table", got)
+	}
+}
+
+func TestPerceivedDifficulty(t *testing.T) {
+	ctx := context.Background()
+	bot, session := newTestBot(t, config.DiscordConfig{})
+
+	resp := dispatch(t, bot, session, commandInteraction("add",
+		stringOption("name", "Two Sum"),
+		stringOption("difficulty", "Easy"),
+		stringOption("status", database.StatusSolved),
+		stringOption("solved_at", "2024-03-05"),
+		stringOption("category", "Array"),
+		stringOption("perceived_difficulty", "Hard"),
+	))
+	if !strings.Contains(resp.Data.Content, "Successfully added problem 'Two Sum'") {
+		t.Fatalf("response = %q, want a success message", responseText(resp))
+	}
+	problems, err := bot.repo.ListProblems(ctx, testUserID, "", "", "", nil, true, 0, 0)
+	if err != nil || len(problems) != 1 {
+		t.Fatalf("ListProblems = %d problems, %v, want 1", len(problems), err)
+	}
+	problem := problems[0]
+	if problem.Difficulty != database.DifficultyEasy || problem.PerceivedDifficulty != database.DifficultyHard {
+		t.Errorf("stored difficulty %q felt %q, want Easy felt Hard", problem.Difficulty, problem.PerceivedDifficulty)
+	}
+
+	// /get shows both, and stats still count the official difficulty
+	text := responseText(dispatch(t, bot, session, commandInteraction("get", intOption("id", int(problem.ID)))))
+	if !strings.Contains(text, "**Difficulty:** Easy") || !strings.Contains(text, "**Felt Like:** Hard") {
+		t.Errorf("/get = %q, want both difficulties", text)
+	}
+	if stats, err := bot.repo.GetUserStats(ctx, testUserID); err != nil || stats.Easy != 1 || stats.Hard != 0 {
+		t.Errorf("GetUserStats = %+v, %v, want the problem counted as Easy", stats, err)
+	}
+
+	resp = dispatch(t, bot, session, commandInteraction("edit", intOption("id", int(problem.ID)), stringOption("perceived_difficulty", "Trivial")))
+	if resp.Data.Flags&discordgo.MessageFlagsEphemeral == 0 || !strings.Contains(responseText(resp), "Trivial") {
+		t.Errorf("response = %q, want the invalid difficulty rejected", responseText(resp))
+	}
+
+	// Matching the official difficulty, or choosing it outright, clears the perceived one
+	for _, perceived := range []string{"Easy", perceivedSameAsOfficial} {
+		dispatch(t, bot, session, commandInteraction("edit", intOption("id", int(problem.ID)), stringOption("perceived_difficulty", "Medium")))
+		dispatch(t, bot, session, commandInteraction("edit", intOption("id", int(problem.ID)), stringOption("perceived_difficulty", perceived)))
+		got, err := bot.repo.GetProblem(ctx, problem.ID)
+		if err != nil {
+			t.Fatalf("GetProblem: %v", err)
+		}
+		if got.PerceivedDifficulty != "" {
+			t.Errorf("perceived difficulty = %q after editing it to %s, want it cleared", got.PerceivedDifficulty, perceived)
+		}
+	}
+	if text := responseText(dispatch(t, bot, session, commandInteraction("get", intOption("id", int(problem.ID))))); strings.Contains(text, "Felt Like") {
+		t.Errorf("/get = %q, want no perceived difficulty", text)
+	}
+}
+
+func TestDeleteCommand(t *testing.T) {
+	bot, session := newTestBot(t, config.DiscordConfig{})
+	problem := addTestProblem(t, bot, "Two Sum", database.DifficultyEasy)
+
+	resp := dispatch(t, bot, session, commandInteraction("delete", intOption("id", int(problem.ID))))
+	if !strings.Contains(resp.Data.Content, "Successfully deleted problem 'Two Sum'") {
+		t.Fatalf("response = %+v, want a success message", resp.Data)
+	}
+
+	if _, err := bot.repo.GetProblem(context.Background(), problem.ID); !errors.Is(err, database.ErrProblemNotFound) {
+		t.Errorf("GetProblem after delete = %v, want ErrProblemNotFound", err)
+	}
+}
+
+// decodeJSONResponse checks resp is ephemeral JSON, either a code block or an attached file, and
+// decodes it into v
+func decodeJSONResponse(t *testing.T, resp *discordgo.InteractionResponse, v any) {
+	t.Helper()
+	if resp.Data.Flags&discordgo.MessageFlagsEphemeral == 0 {
+		t.Error("JSON output was shown to the channel")
+	}
+
+	var body []byte
+	if len(resp.Data.Files) > 0 {
+		file := resp.Data.Files[0]
+		if !strings.HasSuffix(file.Name, ".json") || file.ContentType != "application/json" {
+			t.Errorf("file = %q (%s), want a .json file as application/json", file.Name, file.ContentType)
+		}
+		data, err := io.ReadAll(file.Reader)
+		if err != nil {
+			t.Fatalf("failed to read the attachment: %v", err)
+		}
+		body = data
+	} else {
+		content, ok := strings.CutPrefix(resp.Data.Content, "```json\n")
+		if content, ok = strings.CutSuffix(content, "\n```"); !ok {
+			t.Fatalf("content = %q, want a JSON code block", resp.Data.Content)
+		}
+		body = []byte(content)
+	}
+	if err := json.Unmarshal(body, v); err != nil {
+		t.Fatalf("failed to decode %q: %v", body, err)
+	}
+}
+
+func TestJSONOutputRoundTrips(t *testing.T) {
+	bot, session := newTestBot(t, config.DiscordConfig{})
+	want := addTestProblem(t, bot, "Merge k Sorted Lists", database.DifficultyHard, "heap", "linked-list")
+	addTestProblem(t, bot, "Two Sum", database.DifficultyEasy)
+
+	var got database.ProblemEntry
+	decodeJSONResponse(t, dispatch(t, bot, session, commandInteraction("get", intOption("id", int(want.ID)), boolOption("json", true))), &got)
+	if got.ID != want.ID || got.ProblemName != want.ProblemName || got.Difficulty != want.Difficulty || got.Category != want.Category || !got.SolvedAt.Equal(want.SolvedAt) {
+		t.Errorf("decoded %+v, want %+v", got, *want)
+	}
+	if tags := slices.Sorted(slices.Values(got.Tags)); !slices.Equal(tags, []string{"heap", "linked-list"}) {
+		t.Errorf("decoded tags = %v, want [heap linked-list]", tags)
+	}
+
+	var list []database.ProblemEntry
+	decodeJSONResponse(t, dispatch(t, bot, session, commandInteraction("list", boolOption("json", true))), &list)
+	var names []string
+	for _, p := range list {
+		names = append(names, p.ProblemName)
+	}
+	slices.Sort(names)
+	if !slices.Equal(names, []string{"Merge k Sorted Lists", "Two Sum"}) {
+		t.Errorf("decoded list = %v, want both problems", names)
+	}
+}
+
+func TestJSONOutputAttachesLargeOutput(t *testing.T) {
+	bot, session := newTestBot(t, config.DiscordConfig{})
+	for idx := range 40 {
+		addTestProblem(t, bot, fmt.Sprintf("Problem %02d", idx), database.DifficultyMedium)
+	}
+
+	resp := dispatch(t, bot, session, commandInteraction("list", intOption("limit", 40), boolOption("json", true)))
+	if len(resp.Data.Files) != 1 {
+		t.Fatalf("attached %d files, want 1", len(resp.Data.Files))
+	}
+	if name := resp.Data.Files[0].Name; name != "problems.json" {
+		t.Errorf("file name = %q, want problems.json", name)
+	}
+
+	var list []database.ProblemEntry
+	decodeJSONResponse(t, resp, &list)
+	if len(list) != 40 {
+		t.Errorf("decoded %d problems, want all 40", len(list))
+	}
+}
+
+func TestAdminBackupCommand(t *testing.T) {
+	bot, session := newTestBot(t, config.DiscordConfig{AdminRoleID: "role-admin"})
+	addTestProblem(t, bot, "Two Sum", database.DifficultyEasy)
+	backup := func(roles ...string) *discordgo.InteractionCreate {
+		i := commandInteraction("admin-backup")
+		i.Member.Roles = roles
+		return i
+	}
+
+	// Without a destination there is nowhere to write
+	resp := deferredResult(t, bot, session, backup("role-admin"))
+	if !strings.Contains(responseText(resp), "No backup destination is configured.") {
+		t.Errorf("response = %q, want the missing destination error", responseText(resp))
+	}
+
+	dir := t.TempDir()
+	bot.SetBackupConfig(config.BackupConfig{DestinationPath: filepath.Join(dir, "grind-{date}.db")})
+
+	resp = dispatch(t, bot, session, backup("role-member"))
+	if !strings.Contains(responseText(resp), "Permission denied") {
+		t.Errorf("response = %q, want non-admins turned away", responseText(resp))
+	}
+	if entries, _ := os.ReadDir(dir); len(entries) != 0 {
+		t.Errorf("a non-admin wrote %d backup files", len(entries))
+	}
+
+	resp = deferredResult(t, bot, session, backup("role-admin"))
+	matches, err := filepath.Glob(filepath.Join(dir, "grind-*.db"))
+	if err != nil || len(matches) != 1 {
+		t.Fatalf("backup files = %v, %v, want one timestamped file", matches, err)
+	}
+	if strings.Contains(matches[0], "{date}") {
+		t.Errorf("backup file %q has no timestamp", matches[0])
+	}
+	if text := responseText(resp); !strings.Contains(text, matches[0]) || !strings.Contains(text, "KB") {
+		t.Errorf("response = %q, want the backup's path and size", text)
+	}
+}
+
+func TestAdminRecalcCommand(t *testing.T) {
+	ctx := context.Background()
+	bot, session := newTestBot(t, config.DiscordConfig{AdminRoleID: "role-admin"})
+	problem := addTestProblem(t, bot, "Two Sum", database.DifficultyEasy)
+	if err := bot.repo.IncrementReviewCount(ctx, problem.ID); err != nil {
+		t.Fatalf("IncrementReviewCount: %v", err)
+	}
+	recalc := func(roles []string, options ...*discordgo.ApplicationCommandInteractionDataOption) *discordgo.InteractionCreate {
+		i := commandInteraction("admin-recalc", options...)
+		i.Member.Roles = roles
+		return i
+	}
+
+	resp := dispatch(t, bot, session, recalc([]string{"role-member"}))
+	if !strings.Contains(responseText(resp), "Permission denied") {
+		t.Errorf("response = %q, want non-admins turned away", responseText(resp))
+	}
+
+	resp = deferredResult(t, bot, session, recalc([]string{"role-admin"}, userOption("user", testUserID)))
+	if text := responseText(resp); !strings.Contains(text, "Recalculated stats for <@"+testUserID+">: 0 problems corrected.") {
+		t.Errorf("response = %q, want nothing corrected", text)
+	}
+
+	// A lost update leaves the stored count behind the review history
+	problem.ReviewCount = 0
+	if err := bot.repo.UpdateProblem(ctx, problem); err != nil {
+		t.Fatalf("UpdateProblem: %v", err)
+	}
+	resp = deferredResult(t, bot, session, recalc([]string{"role-admin"}))
+	if text := responseText(resp); !strings.Contains(text, "Recalculated stats for all users: 1 problems corrected.") {
+		t.Errorf("response = %q, want one problem corrected", text)
+	}
+	if got, err := bot.repo.GetProblem(ctx, problem.ID); err != nil || got.ReviewCount != 1 {
+		t.Errorf("review count = %d (%v) after /admin-recalc, want 1", got.ReviewCount, err)
+	}
+}
+
+func TestDedupeCommand(t *testing.T) {
+	bot, session := newTestBot(t, config.DiscordConfig{})
+	keep := addTestProblem(t, bot, "Two Sum", database.DifficultyEasy, "array")
+	dup := addTestProblem(t, bot, "two sum", database.DifficultyEasy, "hash-table")
+
+	resp := dispatch(t, bot, session, commandInteraction("dedupe", boolOption("dry_run", true)))
+	want := fmt.Sprintf("**Two Sum**: keeping ID %d, merging ID %d", keep.ID, dup.ID)
+	if !strings.HasPrefix(resp.Data.Content, "Found 1 duplicate entries of 1 problems.") || !strings.Contains(resp.Data.Content, want) {
+		t.Errorf("dry run = %q, want the duplicate listed", resp.Data.Content)
+	}
+	if _, err := bot.repo.GetProblem(context.Background(), dup.ID); err != nil {
+		t.Errorf("the dry run deleted the duplicate: %v", err)
+	}
+
+	resp = dispatch(t, bot, session, commandInteraction("dedupe"))
+	if !strings.HasPrefix(resp.Data.Content, "Merged 1 duplicate entries into 1 problems:") {
+		t.Errorf("content = %q, want the merge reported", resp.Data.Content)
+	}
+	if _, err := bot.repo.GetProblem(context.Background(), dup.ID); err == nil {
+		t.Error("the duplicate survived the merge")
+	}
+
+	resp = dispatch(t, bot, session, commandInteraction("dedupe"))
+	if resp.Data.Content != "You don't have any duplicate problems." {
+		t.Errorf("content = %q, want nothing left to merge", resp.Data.Content)
+	}
+}
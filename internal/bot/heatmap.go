@@ -0,0 +1,87 @@
+package bot
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// yearHeatmapWeeks is how many weeks the /heatmap calendar covers, enough to always span a full year
+const yearHeatmapWeeks = 53
+
+func (b *Bot) handleHeatmapCommand(ctx context.Context, s DiscordSession, i *discordgo.InteractionCreate) (*discordgo.InteractionResponse, error) {
+	options := i.ApplicationCommandData().Options
+	optionMap := make(map[string]*discordgo.ApplicationCommandInteractionDataOption, len(options))
+	for _, opt := range options {
+		optionMap[opt.Name] = opt
+	}
+
+	loc := time.UTC
+	if tzOpt, ok := optionMap["timezone"]; ok && strings.TrimSpace(tzOpt.StringValue()) != "" {
+		name := strings.TrimSpace(tzOpt.StringValue())
+		parsed, err := time.LoadLocation(name)
+		if err != nil {
+			return nil, validationError(fmt.Sprintf("Unknown timezone %q. Use a name like America/New_York or Europe/Berlin.", name))
+		}
+		loc = parsed
+	}
+
+	counts, err := b.repo.GetSolveHeatmap(ctx, interactionUserID(i), yearHeatmapWeeks, loc)
+	if err != nil {
+		return nil, databaseError("Failed to retrieve your solve history from the database.", err)
+	}
+
+	return embedResponse(b.branded(yearHeatmapEmbed(counts, time.Now().In(loc)))), nil
+}
+
+// yearHeatmapEmbed builds the /heatmap embed from daily solve counts in now's time zone
+func yearHeatmapEmbed(counts map[string]int, now time.Time) *discordgo.MessageEmbed {
+	total, bestDay, bestCount := 0, "", 0
+	for day, count := range counts {
+		total += count
+		if count > bestCount || (count == bestCount && day < bestDay) {
+			bestDay, bestCount = day, count
+		}
+	}
+
+	best := "-"
+	if bestCount > 0 {
+		best = fmt.Sprintf("%d on %s", bestCount, bestDay)
+	}
+
+	return &discordgo.MessageEmbed{
+		Title:       "Your Year of Solving",
+		Color:       ColorInfo,
+		Description: "```\n" + yearHeatmap(counts, now) + "```",
+		Fields: []*discordgo.MessageEmbedField{
+			{Name: "Problems Solved", Value: fmt.Sprintf("%d", total), Inline: true},
+			{Name: "Active Days", Value: fmt.Sprintf("%d", len(counts)), Inline: true},
+			{Name: "Best Day", Value: best, Inline: true},
+		},
+		Footer: &discordgo.MessageEmbedFooter{Text: "Days are counted in " + now.Location().String()},
+	}
+}
+
+// yearHeatmap draws a compact year-long calendar, labelling each column that contains the first of a month
+func yearHeatmap(counts map[string]int, now time.Time) string {
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	start := heatmapStart(today, yearHeatmapWeeks)
+
+	// Labels are three characters wide, so one that would run into the previous label is skipped
+	header := []rune(strings.Repeat(" ", 4+yearHeatmapWeeks+3))
+	lastLabel := -4
+	for week := 0; week < yearHeatmapWeeks; week++ {
+		saturday := start.AddDate(0, 0, 7*week+6)
+		if saturday.Day() > 7 || week-lastLabel < 4 {
+			continue
+		}
+		copy(header[4+week:], []rune(saturday.Month().String()[:3]))
+		lastLabel = week
+	}
+
+	return strings.TrimRight(string(header), " ") + "\n" +
+		heatmapGrid(counts, yearHeatmapWeeks, now, "") + heatmapLegend()
+}
@@ -0,0 +1,78 @@
+package bot
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/yugonline/grind_review_bot/config"
+	"github.com/yugonline/grind_review_bot/internal/database"
+	"github.com/yugonline/grind_review_bot/pkg/discord"
+)
+
+func TestYearHeatmap(t *testing.T) {
+	now := time.Date(2024, time.March, 13, 15, 0, 0, 0, time.UTC) // A Wednesday
+	counts := map[string]int{"2024-03-12": 3, "2023-03-19": 1}
+
+	rows := strings.Split(yearHeatmap(counts, now), "\n")
+	if !strings.HasPrefix(strings.TrimSpace(rows[0]), "Apr") || !strings.HasSuffix(rows[0], "Mar") {
+		t.Errorf("header = %q, want month labels from Apr to Mar", rows[0])
+	}
+	// The first column starts on Sunday March 12, 2023; the last holds this week
+	if !strings.HasPrefix(rows[1], "Sun ░▒") {
+		t.Errorf("Sun row = %q, want March 19, 2023 in the second column", rows[1])
+	}
+	if !strings.HasSuffix(rows[3], "░█") {
+		t.Errorf("Tue row = %q, want yesterday's 3 solves at the end", rows[3])
+	}
+	if !strings.HasSuffix(rows[5], "░ ") {
+		t.Errorf("Thu row = %q, want tomorrow left blank", rows[5])
+	}
+	for _, row := range rows[1:8] {
+		if cells := []rune(row)[4:]; len(cells) != yearHeatmapWeeks {
+			t.Errorf("row %q has %d cells, want %d", row[:3], len(cells), yearHeatmapWeeks)
+		}
+	}
+
+	embed := yearHeatmapEmbed(counts, now)
+	if len(embed.Description) > discord.MaxMessageLength {
+		t.Errorf("heatmap is %d bytes, over %d", len(embed.Description), discord.MaxMessageLength)
+	}
+	for name, want := range map[string]string{"Problems Solved": "4", "Active Days": "2", "Best Day": "3 on 2024-03-12"} {
+		if got := embedField(embed, name); got != want {
+			t.Errorf("%s = %q, want %q", name, got, want)
+		}
+	}
+}
+
+func TestHeatmapCommand(t *testing.T) {
+	newYork, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skipf("time zone data unavailable: %v", err)
+	}
+	bot, session := newTestBot(t, config.DiscordConfig{})
+	// 02:00 UTC yesterday falls on the day before in New York
+	solvedAt := time.Now().UTC().Truncate(24*time.Hour).AddDate(0, 0, -1).Add(2 * time.Hour)
+	entry := &database.ProblemEntry{UserID: testUserID, ProblemName: "Two Sum", Difficulty: database.DifficultyEasy, Category: "Array", Status: database.StatusSolved, SolvedAt: solvedAt}
+	if err := bot.repo.CreateProblem(context.Background(), entry); err != nil {
+		t.Fatalf("CreateProblem: %v", err)
+	}
+
+	resp := deferredResult(t, bot, session, commandInteraction("heatmap", stringOption("timezone", "America/New_York")))
+	if len(resp.Data.Embeds) != 1 {
+		t.Fatalf("response = %q, want one embed", responseText(resp))
+	}
+	embed := resp.Data.Embeds[0]
+	if want := "1 on " + solvedAt.In(newYork).Format("2006-01-02"); embedField(embed, "Best Day") != want {
+		t.Errorf("Best Day = %q, want %q", embedField(embed, "Best Day"), want)
+	}
+	if embed.Footer == nil || !strings.Contains(embed.Footer.Text, "America/New_York") {
+		t.Errorf("footer = %+v, want the time zone named", embed.Footer)
+	}
+
+	resp = deferredResult(t, bot, session, commandInteraction("heatmap", stringOption("timezone", "Mars/Olympus_Mons")))
+	if !strings.Contains(responseText(resp), `Unknown timezone "Mars/Olympus_Mons"`) {
+		t.Errorf("response = %q, want an unknown timezone error", responseText(resp))
+	}
+}
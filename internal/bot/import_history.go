@@ -0,0 +1,171 @@
+package bot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/yugonline/grind_review_bot/internal/database"
+	"github.com/yugonline/grind_review_bot/pkg/leetcode"
+)
+
+// defaultImportMessageCount is how many messages /import-history reads when no count is given
+const defaultImportMessageCount = 50
+
+// maxImportMessageCount is the most messages Discord returns in one request
+const maxImportMessageCount = 100
+
+// maxListedAmbiguousLines caps how many unparseable lines the /import-history reply quotes
+const maxListedAmbiguousLines = 10
+
+// solvedLinePrefix marks a line as a solve announcement
+var solvedLinePrefix = regexp.MustCompile(`(?i)^solved\s*:`)
+
+// solvedLinePattern matches "Solved: <name> [difficulty] [category]", with both bracketed parts optional
+var solvedLinePattern = regexp.MustCompile(`(?i)^solved\s*:\s*([^\[\]]*?)\s*(?:\[([^\[\]]*)\]\s*)?(?:\[([^\[\]]*)\])?$`)
+
+// errNotSolvedLine is returned for lines that aren't solve announcements at all
+var errNotSolvedLine = errors.New("not a solved line")
+
+// parseSolvedLine parses a "Solved: <name> [difficulty] [category]" line into a problem the user
+// solved at solvedAt. It returns errNotSolvedLine for other lines, and a descriptive error for solve
+// lines it can't turn into a valid problem. A missing category is guessed from the name.
+func parseSolvedLine(line, userID string, solvedAt time.Time) (*database.ProblemEntry, error) {
+	line = strings.TrimSpace(line)
+	if !solvedLinePrefix.MatchString(line) {
+		return nil, errNotSolvedLine
+	}
+
+	match := solvedLinePattern.FindStringSubmatch(line)
+	if match == nil {
+		return nil, errors.New("expected `Solved: <name> [difficulty] [category]`")
+	}
+
+	parsed := &database.ProblemEntry{
+		UserID:      userID,
+		ProblemName: match[1],
+		Status:      database.StatusSolved,
+		SolvedAt:    solvedAt,
+		Tags:        make([]string, 0),
+	}
+	if parsed.ProblemName == "" {
+		return nil, errors.New("missing problem name")
+	}
+
+	if match[2] == "" {
+		return nil, errors.New("missing [difficulty]")
+	}
+	difficulty, err := leetcode.NormalizeDifficulty(match[2])
+	if err != nil {
+		return nil, err
+	}
+	parsed.Difficulty = difficulty
+
+	if category := strings.TrimSpace(match[3]); category != "" {
+		parsed.Category = category
+		if canonical, confidence := leetcode.NormalizeCategory(category); confidence > canonicalCategoryConfidence {
+			parsed.Category = canonical
+		}
+	} else {
+		category, confidence := classifyCategory(parsed.ProblemName)
+		if confidence < minCategoryConfidence {
+			return nil, errors.New("missing [category] and couldn't guess one from the name")
+		}
+		parsed.Category = category
+	}
+
+	// Catch anything the database would reject so one bad line can't fail the whole import
+	var invalid *database.ValidationError
+	if err := database.ValidateProblemEntry(parsed, 0); errors.As(err, &invalid) {
+		return nil, errors.New(problemValidationMessage(invalid))
+	} else if err != nil {
+		return nil, err
+	}
+	return parsed, nil
+}
+
+// ambiguousLine is a solve line /import-history couldn't parse or validate, with the reason
+type ambiguousLine struct {
+	Line   string
+	Reason error
+}
+
+// handleImportHistoryCommand logs the requester's "Solved: ..." messages from the channel's recent history
+func (b *Bot) handleImportHistoryCommand(ctx context.Context, s DiscordSession, i *discordgo.InteractionCreate) (*discordgo.InteractionResponse, error) {
+	options := i.ApplicationCommandData().Options
+	optionMap := make(map[string]*discordgo.ApplicationCommandInteractionDataOption, len(options))
+	for _, opt := range options {
+		optionMap[opt.Name] = opt
+	}
+
+	count := defaultImportMessageCount
+	if countOpt, ok := optionMap["count"]; ok {
+		count = int(countOpt.IntValue())
+	}
+
+	messages, err := s.ChannelMessages(i.ChannelID, count, "", "", "")
+	if err != nil {
+		// Usually missing the Read Message History permission; keep the cause for the logs
+		return nil, &UserError{Kind: ErrUnavailable, Message: "Couldn't read this channel's messages. Make sure the bot can view its history.", Err: err}
+	}
+
+	userID := interactionUserID(i)
+	var entries []*database.ProblemEntry
+	var ambiguous []ambiguousLine
+	skipped := 0
+
+	// Discord returns the newest messages first; import oldest first so IDs follow solve order
+	for idx := len(messages) - 1; idx >= 0; idx-- {
+		message := messages[idx]
+		if message.Author == nil || message.Author.ID != userID {
+			continue
+		}
+
+		for _, line := range strings.Split(message.Content, "\n") {
+			entry, err := parseSolvedLine(line, userID, message.Timestamp)
+			switch {
+			case errors.Is(err, errNotSolvedLine):
+				continue
+			case err != nil:
+				ambiguous = append(ambiguous, ambiguousLine{Line: strings.TrimSpace(line), Reason: err})
+				continue
+			}
+			entries = append(entries, entry)
+		}
+	}
+
+	if len(entries) > 0 {
+		duplicates, err := b.repo.ImportProblems(ctx, userID, entries)
+		if err != nil {
+			return nil, databaseError("Failed to import problems into the database.", err)
+		}
+		skipped = len(duplicates)
+	}
+
+	return ephemeralResponse(importHistoryMessage(len(messages), len(entries)-skipped, skipped, ambiguous)), nil
+}
+
+// importHistoryMessage summarizes an /import-history run
+func importHistoryMessage(scanned, imported, duplicates int, ambiguous []ambiguousLine) string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("Scanned %d messages and imported **%d** problems.", scanned, imported))
+	if duplicates > 0 {
+		sb.WriteString(fmt.Sprintf(" Skipped %d already logged.", duplicates))
+	}
+
+	if len(ambiguous) > 0 {
+		sb.WriteString(fmt.Sprintf("\nCouldn't parse %d lines:\n", len(ambiguous)))
+		for idx, line := range ambiguous {
+			if idx == maxListedAmbiguousLines {
+				sb.WriteString(fmt.Sprintf("…and %d more\n", len(ambiguous)-idx))
+				break
+			}
+			sb.WriteString(fmt.Sprintf("- `%s`: %v\n", truncateString(strings.ReplaceAll(line.Line, "`", "'"), 80), line.Reason))
+		}
+	}
+	return sb.String()
+}
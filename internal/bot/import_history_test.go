@@ -0,0 +1,58 @@
+package bot
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/yugonline/grind_review_bot/internal/database"
+)
+
+func TestParseSolvedLine(t *testing.T) {
+	solvedAt := time.Date(2024, time.March, 1, 9, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		line       string
+		name       string
+		difficulty string
+		wantErr    bool
+	}{
+		{line: "Solved: Two Sum [easy] [Array]", name: "Two Sum", difficulty: database.DifficultyEasy},
+		{line: "  solved:Coin Change [Medium] [Dynamic Programming]  ", name: "Coin Change", difficulty: database.DifficultyMedium},
+		{line: "Solved: Two Sum", wantErr: true},
+		{line: "Solved: Two Sum [impossible] [Array]", wantErr: true},
+		{line: "Solved: [Easy] [Array]", wantErr: true},
+		{line: "Solved: Two [Sum] [Easy] [Array]", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.line, func(t *testing.T) {
+			entry, err := parseSolvedLine(tt.line, "user-1", solvedAt)
+			if tt.wantErr {
+				if err == nil || errors.Is(err, errNotSolvedLine) {
+					t.Fatalf("err = %v, want a reason the line is invalid", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if entry.ProblemName != tt.name || entry.Difficulty != tt.difficulty {
+				t.Errorf("got %q (%s), want %q (%s)", entry.ProblemName, entry.Difficulty, tt.name, tt.difficulty)
+			}
+			if entry.UserID != "user-1" || !entry.SolvedAt.Equal(solvedAt) || entry.Status != database.StatusSolved {
+				t.Errorf("entry = %+v, want it owned by user-1, solved at %v", entry, solvedAt)
+			}
+			if err := database.ValidateProblemEntry(entry, 0); err != nil {
+				t.Errorf("parsed entry doesn't validate: %v", err)
+			}
+		})
+	}
+}
+
+func TestParseSolvedLineIgnoresOtherLines(t *testing.T) {
+	for _, line := range []string{"", "just chatting", "I solved: nothing"} {
+		if _, err := parseSolvedLine(line, "user-1", time.Now()); !errors.Is(err, errNotSolvedLine) {
+			t.Errorf("parseSolvedLine(%q) err = %v, want errNotSolvedLine", line, err)
+		}
+	}
+}
@@ -0,0 +1,106 @@
+package bot
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/yugonline/grind_review_bot/pkg/cache"
+)
+
+// pendingInteractionTTL is how long a follow-up modal or button stays usable
+const pendingInteractionTTL = 5 * time.Minute
+
+// stateTokenPrefix marks the argument of a custom ID that refers to stored interaction state
+const stateTokenPrefix = "state="
+
+// pendingInteractionKey is the context key for the state consumed by a follow-up interaction
+type pendingInteractionKey struct{}
+
+// PendingInteraction is what a command hands over to the modal or button that follows it
+type PendingInteraction struct {
+	UserID  string      // Only this user may continue the interaction
+	Command string      // The command that started it
+	Payload interface{} // Whatever the follow-up handler needs
+}
+
+// InteractionState holds pending interactions until their follow-up arrives. Each one can be
+// consumed once; unused ones are dropped after pendingInteractionTTL.
+type InteractionState struct {
+	mu      sync.Mutex
+	pending *cache.TypedCache[*PendingInteraction]
+}
+
+// NewInteractionState creates an empty interaction state store
+func NewInteractionState() *InteractionState {
+	return &InteractionState{
+		pending: cache.NewTyped[*PendingInteraction](pendingInteractionTTL, time.Minute),
+	}
+}
+
+// Store saves a pending interaction and returns the token its follow-up carries
+func (s *InteractionState) Store(pending *PendingInteraction) string {
+	token := newStateToken()
+	s.pending.Set(token, pending)
+	return token
+}
+
+// Consume returns and removes the pending interaction for token if userID started it. It reports
+// false when the token is unknown, expired, already used, or belongs to another user.
+func (s *InteractionState) Consume(token, userID string) (*PendingInteraction, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	pending, ok := s.pending.Get(token)
+	if !ok || pending.UserID != userID {
+		return nil, false
+	}
+	s.pending.Delete(token)
+	return pending, true
+}
+
+// Close stops the background sweep of expired pending interactions
+func (s *InteractionState) Close() {
+	s.pending.Close()
+}
+
+// stateCustomID builds the custom ID of a modal or component whose handler receives stored state
+func stateCustomID(prefix, token string) string {
+	return prefix + ":" + stateTokenPrefix + token
+}
+
+// stateToken returns the state token in a modal or component custom ID, if it carries one
+func stateToken(customID string) (string, bool) {
+	_, arg, _ := strings.Cut(customID, ":")
+	return strings.CutPrefix(arg, stateTokenPrefix)
+}
+
+// interactionCustomID returns the custom ID of a modal submission or component click
+func interactionCustomID(i *discordgo.InteractionCreate) string {
+	switch i.Type {
+	case discordgo.InteractionModalSubmit:
+		return i.ModalSubmitData().CustomID
+	case discordgo.InteractionMessageComponent:
+		return i.MessageComponentData().CustomID
+	}
+	return ""
+}
+
+// pendingInteraction returns the state consumed for the current follow-up interaction, if any
+func pendingInteraction(ctx context.Context) (*PendingInteraction, bool) {
+	pending, ok := ctx.Value(pendingInteractionKey{}).(*PendingInteraction)
+	return pending, ok
+}
+
+// newStateToken generates an unguessable token for a pending interaction
+func newStateToken() string {
+	buf := make([]byte, 12)
+	if _, err := rand.Read(buf); err != nil {
+		return newCorrelationID() + newCorrelationID() + newCorrelationID()
+	}
+	return hex.EncodeToString(buf)
+}
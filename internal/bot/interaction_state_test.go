@@ -0,0 +1,80 @@
+package bot
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/yugonline/grind_review_bot/pkg/cache"
+)
+
+func TestInteractionStateConsumedOnce(t *testing.T) {
+	state := NewInteractionState()
+	defer state.Close()
+	token := state.Store(&PendingInteraction{UserID: testUserID, Command: "delete", Payload: uint(7)})
+
+	// Another user's click neither succeeds nor uses up the state
+	if _, ok := state.Consume(token, "user-2"); ok {
+		t.Error("another user consumed the pending interaction")
+	}
+
+	pending, ok := state.Consume(token, testUserID)
+	if !ok || pending.Command != "delete" || pending.Payload != uint(7) {
+		t.Fatalf("Consume = %+v, %v, want the stored delete", pending, ok)
+	}
+	if _, ok := state.Consume(token, testUserID); ok {
+		t.Error("the pending interaction was consumed twice")
+	}
+	if _, ok := state.Consume("unknown", testUserID); ok {
+		t.Error("an unknown token was consumed")
+	}
+}
+
+func TestInteractionStateConcurrentConsume(t *testing.T) {
+	state := NewInteractionState()
+	defer state.Close()
+	token := state.Store(&PendingInteraction{UserID: testUserID, Command: "add"})
+
+	// Double clicks race; exactly one of them gets the state
+	var consumed atomic.Int32
+	var wg sync.WaitGroup
+	for range 20 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, ok := state.Consume(token, testUserID); ok {
+				consumed.Add(1)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if consumed.Load() != 1 {
+		t.Errorf("state consumed %d times, want 1", consumed.Load())
+	}
+}
+
+func TestInteractionStateExpires(t *testing.T) {
+	state := &InteractionState{pending: cache.NewTyped[*PendingInteraction](10*time.Millisecond, time.Millisecond)}
+	defer state.Close()
+	token := state.Store(&PendingInteraction{UserID: testUserID})
+
+	time.Sleep(30 * time.Millisecond)
+	if _, ok := state.Consume(token, testUserID); ok {
+		t.Error("an expired pending interaction was consumed")
+	}
+}
+
+func TestStateCustomID(t *testing.T) {
+	customID := stateCustomID("confirm_delete", "abc123")
+	if token, ok := stateToken(customID); !ok || token != "abc123" {
+		t.Errorf("stateToken(%q) = %q, %v, want abc123", customID, token, ok)
+	}
+	if _, ok := stateToken("review_done:42"); ok {
+		t.Error("a custom ID without state reported a token")
+	}
+	if a, b := newStateToken(), newStateToken(); a == b || len(a) != 24 {
+		t.Errorf("tokens %q and %q, want two distinct 24-character tokens", a, b)
+	}
+}
@@ -0,0 +1,67 @@
+package bot
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// errAlreadyResponded is returned when an interaction that has been answered is answered again
+var errAlreadyResponded = errors.New("interaction already responded to")
+
+// onceResponder is a DiscordSession that lets a single response through, so the timeout reply and
+// a handler still running past its deadline can't both answer the interaction
+type onceResponder struct {
+	DiscordSession
+	mu        sync.Mutex
+	responded bool
+}
+
+// InteractionRespond implements DiscordSession
+func (s *onceResponder) InteractionRespond(interaction *discordgo.Interaction, resp *discordgo.InteractionResponse, options ...discordgo.RequestOption) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.responded {
+		return errAlreadyResponded
+	}
+	if err := s.DiscordSession.InteractionRespond(interaction, resp, options...); err != nil {
+		return err
+	}
+	s.responded = true
+	return nil
+}
+
+// acknowledged reports whether the interaction has been answered, by a reply or a deferral
+func (s *onceResponder) acknowledged() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.responded
+}
+
+// runHandler runs handler until it returns or ctx ends. Discord fails interactions that aren't
+// answered within 3 seconds, so a handler that hasn't answered by then is left to finish on its
+// own; one that deferred is waited for, since it reports the outcome by editing its message.
+func runHandler(ctx context.Context, handler CommandHandler, s *onceResponder, i *discordgo.InteractionCreate) (*discordgo.InteractionResponse, error) {
+	type result struct {
+		response *discordgo.InteractionResponse
+		err      error
+	}
+	done := make(chan result, 1)
+	go func() {
+		response, err := handler(ctx, s, i)
+		done <- result{response, err}
+	}()
+
+	select {
+	case r := <-done:
+		return r.response, r.err
+	case <-ctx.Done():
+		if s.acknowledged() {
+			r := <-done
+			return r.response, r.err
+		}
+		return nil, ctx.Err()
+	}
+}
@@ -0,0 +1,157 @@
+package bot
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/yugonline/grind_review_bot/config"
+)
+
+// blockingHandler waits until its context ends, as a handler stuck on a degraded database would
+func blockingHandler(ctx context.Context, s DiscordSession, i *discordgo.InteractionCreate) (*discordgo.InteractionResponse, error) {
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
+
+func TestCommandTimeout(t *testing.T) {
+	bot, session := newTestBot(t, config.DiscordConfig{CommandsTimeout: 20 * time.Millisecond})
+	bot.commandHandlers["slow"] = chain(blockingHandler, bot.commandMiddleware()...)
+
+	start := time.Now()
+	resp := dispatch(t, bot, session, commandInteraction("slow"))
+
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("interaction took %s, want it cut off after the timeout", elapsed)
+	}
+	if resp.Type != discordgo.InteractionResponseChannelMessageWithSource || resp.Data.Flags&discordgo.MessageFlagsEphemeral == 0 {
+		t.Errorf("response = %+v, want an ephemeral message", resp)
+	}
+	if resp.Data.Content != commandTimeoutMessage {
+		t.Errorf("content = %q, want %q", resp.Data.Content, commandTimeoutMessage)
+	}
+}
+
+func TestCommandTimeoutRepliesAtDeadline(t *testing.T) {
+	bot, session := newTestBot(t, config.DiscordConfig{CommandsTimeout: 20 * time.Millisecond})
+	release := make(chan struct{})
+	lateErr := make(chan error, 1)
+	// A handler that ignores its context, as one blocked in a call without one would
+	bot.commandHandlers["stuck"] = chain(func(ctx context.Context, s DiscordSession, i *discordgo.InteractionCreate) (*discordgo.InteractionResponse, error) {
+		<-release
+		lateErr <- s.InteractionRespond(i.Interaction, messageResponse("Done."))
+		return nil, nil
+	}, bot.commandMiddleware()...)
+
+	start := time.Now()
+	resp := dispatch(t, bot, session, commandInteraction("stuck"))
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("interaction took %s, want the reply sent at the deadline", elapsed)
+	}
+	if resp.Data.Content != commandTimeoutMessage {
+		t.Errorf("content = %q, want %q", resp.Data.Content, commandTimeoutMessage)
+	}
+
+	// The handler finishing later can't answer the interaction a second time
+	close(release)
+	if err := <-lateErr; !errors.Is(err, errAlreadyResponded) {
+		t.Errorf("late InteractionRespond = %v, want %v", err, errAlreadyResponded)
+	}
+	if calls := session.Calls("InteractionRespond"); len(calls) != 1 {
+		t.Errorf("interaction got %d responses, want 1", len(calls))
+	}
+}
+
+func TestDeferredCommandTimeout(t *testing.T) {
+	bot, session := newTestBot(t, config.DiscordConfig{CommandsTimeout: 20 * time.Millisecond})
+	bot.commandHandlers["slow"] = chain(bot.deferred(true, blockingHandler), bot.commandMiddleware()...)
+
+	// The only response is the deferral; the timeout is reported by editing it
+	resp := dispatch(t, bot, session, commandInteraction("slow"))
+	if resp.Type != discordgo.InteractionResponseDeferredChannelMessageWithSource {
+		t.Errorf("response type = %v, want a deferral", resp.Type)
+	}
+
+	edits := session.Calls("InteractionResponseEdit")
+	if len(edits) != 1 {
+		t.Fatalf("deferred message edited %d times, want 1", len(edits))
+	}
+	if edit := edits[0].Args[1].(*discordgo.WebhookEdit); edit.Content == nil || *edit.Content != commandTimeoutMessage {
+		t.Errorf("edit = %+v, want the timeout message", edit)
+	}
+}
+
+func TestCommandErrorWithinTimeout(t *testing.T) {
+	bot, session := newTestBot(t, config.DiscordConfig{CommandsTimeout: time.Second})
+	bot.commandHandlers["failing"] = chain(func(ctx context.Context, s DiscordSession, i *discordgo.InteractionCreate) (*discordgo.InteractionResponse, error) {
+		return nil, validationError("That won't work.")
+	}, bot.commandMiddleware()...)
+
+	resp := dispatch(t, bot, session, commandInteraction("failing"))
+
+	if resp.Data.Flags&discordgo.MessageFlagsEphemeral == 0 || !strings.Contains(responseText(resp), "That won't work.") {
+		t.Errorf("response = %+v, want the handler's error", resp.Data)
+	}
+	if strings.Contains(responseText(resp), commandTimeoutMessage) {
+		t.Error("a failed command was reported as timed out")
+	}
+}
+
+func TestCommandWithinTimeout(t *testing.T) {
+	bot, session := newTestBot(t, config.DiscordConfig{CommandsTimeout: time.Second})
+	bot.commandHandlers["quick"] = chain(func(ctx context.Context, s DiscordSession, i *discordgo.InteractionCreate) (*discordgo.InteractionResponse, error) {
+		if _, ok := ctx.Deadline(); !ok {
+			t.Error("handler context has no deadline")
+		}
+		return messageResponse("Done."), nil
+	}, bot.commandMiddleware()...)
+
+	if resp := dispatch(t, bot, session, commandInteraction("quick")); resp.Data.Content != "Done." {
+		t.Errorf("content = %q, want Done.", resp.Data.Content)
+	}
+}
+
+// timeoutCount returns bot_command_timeouts_total for command from the default registry
+func timeoutCount(t *testing.T, command string) float64 {
+	t.Helper()
+	families, err := prometheus.DefaultGatherer.Gather()
+	if err != nil {
+		t.Fatalf("Gather: %v", err)
+	}
+	for _, family := range families {
+		if family.GetName() != "bot_command_timeouts_total" {
+			continue
+		}
+		for _, metric := range family.GetMetric() {
+			for _, label := range metric.GetLabel() {
+				if label.GetName() == "command" && label.GetValue() == command {
+					return metric.GetCounter().GetValue()
+				}
+			}
+		}
+	}
+	return 0
+}
+
+func TestCommandTimeoutCounted(t *testing.T) {
+	bot, session := newTestBot(t, config.DiscordConfig{CommandsTimeout: 20 * time.Millisecond})
+	bot.commandHandlers["counted-slow"] = chain(blockingHandler, bot.commandMiddleware()...)
+	bot.commandHandlers["counted-quick"] = chain(func(ctx context.Context, s DiscordSession, i *discordgo.InteractionCreate) (*discordgo.InteractionResponse, error) {
+		return messageResponse("Done."), nil
+	}, bot.commandMiddleware()...)
+
+	dispatch(t, bot, session, commandInteraction("counted-slow"))
+	dispatch(t, bot, session, commandInteraction("counted-slow"))
+	dispatch(t, bot, session, commandInteraction("counted-quick"))
+
+	if got := timeoutCount(t, "counted-slow"); got != 2 {
+		t.Errorf("timeouts for counted-slow = %v, want 2", got)
+	}
+	if got := timeoutCount(t, "counted-quick"); got != 0 {
+		t.Errorf("timeouts for counted-quick = %v, want 0", got)
+	}
+}
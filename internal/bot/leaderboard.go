@@ -0,0 +1,74 @@
+package bot
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/yugonline/grind_review_bot/internal/database"
+)
+
+// leaderboardPageSize is how many members each /leaderboard page lists
+const leaderboardPageSize = 10
+
+// leaderboardMetricLabels describes each leaderboard metric's value
+var leaderboardMetricLabels = map[string]string{
+	database.LeaderboardSolved:  "problems solved",
+	database.LeaderboardReviews: "reviews",
+}
+
+func (b *Bot) handleLeaderboardCommand(ctx context.Context, s DiscordSession, i *discordgo.InteractionCreate) (*discordgo.InteractionResponse, error) {
+	options := i.ApplicationCommandData().Options
+	optionMap := make(map[string]*discordgo.ApplicationCommandInteractionDataOption, len(options))
+	for _, opt := range options {
+		optionMap[opt.Name] = opt
+	}
+
+	metric := database.LeaderboardSolved
+	if metricOpt, ok := optionMap["metric"]; ok {
+		metric = metricOpt.StringValue()
+	}
+	page := 1
+	if pageOpt, ok := optionMap["page"]; ok {
+		page = int(pageOpt.IntValue())
+	}
+
+	result, err := b.leaderboard.GetOrLoad(i.GuildID, metric, page, func() (*database.LeaderboardResult, error) {
+		return b.repo.GetLeaderboard(ctx, metric, page, leaderboardPageSize)
+	})
+	if err != nil {
+		return nil, databaseError("Failed to retrieve the leaderboard from the database.", err)
+	}
+
+	return embedResponse(b.branded(leaderboardEmbed(result))), nil
+}
+
+// leaderboardEmbed renders one page of a leaderboard. Members are shown as mentions, which
+// embeds display without pinging anyone.
+func leaderboardEmbed(result *database.LeaderboardResult) *discordgo.MessageEmbed {
+	label := leaderboardMetricLabels[result.Metric]
+	pages := max(1, (result.TotalUsers+leaderboardPageSize-1)/leaderboardPageSize)
+
+	embed := &discordgo.MessageEmbed{
+		Title: fmt.Sprintf("Leaderboard: %s", label),
+		Color: ColorInfo,
+	}
+
+	switch {
+	case result.TotalUsers == 0:
+		embed.Description = "Nobody has a public profile yet. Use /privacy to join the leaderboard."
+	case len(result.Entries) == 0:
+		embed.Description = fmt.Sprintf("Page %d is past the end of the leaderboard, which has %d ranked members.", result.Page, result.TotalUsers)
+	default:
+		var sb strings.Builder
+		for _, entry := range result.Entries {
+			sb.WriteString(fmt.Sprintf("**%d.** <@%s> - %d %s\n", entry.Rank, entry.UserID, entry.Value, label))
+		}
+		embed.Description = sb.String()
+		embed.Footer = &discordgo.MessageEmbedFooter{
+			Text: fmt.Sprintf("Page %d of %d · Only members with public profiles are ranked", result.Page, pages),
+		}
+	}
+	return embed
+}
@@ -0,0 +1,63 @@
+package bot
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/yugonline/grind_review_bot/internal/database"
+	"github.com/yugonline/grind_review_bot/pkg/cache"
+)
+
+// leaderboardCacheTTL is how long a computed leaderboard page is served before it's recomputed
+const leaderboardCacheTTL = 5 * time.Minute
+
+// leaderboardStore is the cache backing a LeaderboardCache, either TTL-only or size-bounded
+type leaderboardStore interface {
+	GetOrSet(key string, ttl time.Duration, loader func() (*database.LeaderboardResult, error)) (*database.LeaderboardResult, error)
+	Keys() []string
+	Delete(key string)
+	Close()
+}
+
+// LeaderboardCache holds computed leaderboard pages so repeated /leaderboard calls skip the
+// aggregation query
+type LeaderboardCache struct {
+	results leaderboardStore
+}
+
+// NewLeaderboardCache creates an empty leaderboard cache. With a maxSize above 0 it keeps at most
+// that many pages, evicting the least recently used; otherwise pages are only dropped on expiry.
+func NewLeaderboardCache(maxSize int) *LeaderboardCache {
+	if maxSize > 0 {
+		return &LeaderboardCache{
+			results: cache.NewLRU[string, *database.LeaderboardResult](maxSize, leaderboardCacheTTL),
+		}
+	}
+	return &LeaderboardCache{
+		results: cache.NewTyped[*database.LeaderboardResult](leaderboardCacheTTL, time.Minute),
+	}
+}
+
+// leaderboardCacheKey identifies one page of one guild's leaderboard
+func leaderboardCacheKey(guildID, metric string, page int) string {
+	return fmt.Sprintf("leaderboard:%s:%s:%d", guildID, metric, page)
+}
+
+// GetOrLoad returns the cached leaderboard page, or calls load to compute and cache it. Concurrent
+// requests for the same uncached page share one load.
+func (c *LeaderboardCache) GetOrLoad(guildID, metric string, page int, load func() (*database.LeaderboardResult, error)) (*database.LeaderboardResult, error) {
+	return c.results.GetOrSet(leaderboardCacheKey(guildID, metric, page), leaderboardCacheTTL, load)
+}
+
+// InvalidateUser drops every cached page. A change to one member's problems can move them
+// across pages of any metric, so no page is safe to keep.
+func (c *LeaderboardCache) InvalidateUser(userID string) {
+	for _, key := range c.results.Keys() {
+		c.results.Delete(key)
+	}
+}
+
+// Close stops the background sweep of expired pages, if the cache runs one
+func (c *LeaderboardCache) Close() {
+	c.results.Close()
+}
@@ -0,0 +1,100 @@
+package bot
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/yugonline/grind_review_bot/config"
+	"github.com/yugonline/grind_review_bot/internal/database"
+	"github.com/yugonline/grind_review_bot/pkg/cache"
+)
+
+// countingLoader returns a leaderboard loader and the number of times it has run
+func countingLoader() (func() (*database.LeaderboardResult, error), *int) {
+	calls := 0
+	return func() (*database.LeaderboardResult, error) {
+		calls++
+		return &database.LeaderboardResult{Metric: database.LeaderboardSolved, Page: 1, TotalUsers: calls}, nil
+	}, &calls
+}
+
+func TestLeaderboardCache(t *testing.T) {
+	for _, maxSize := range []int{0, 10} {
+		c := NewLeaderboardCache(maxSize)
+		t.Cleanup(c.Close)
+		load, calls := countingLoader()
+
+		for range 2 {
+			if _, err := c.GetOrLoad("guild-1", database.LeaderboardSolved, 1, load); err != nil {
+				t.Fatalf("GetOrLoad: %v", err)
+			}
+		}
+		if *calls != 1 {
+			t.Errorf("max size %d: loaded %d times for two requests, want 1", maxSize, *calls)
+		}
+
+		// Each guild, metric and page is cached separately
+		c.GetOrLoad("guild-2", database.LeaderboardSolved, 1, load)
+		c.GetOrLoad("guild-1", database.LeaderboardReviews, 1, load)
+		c.GetOrLoad("guild-1", database.LeaderboardSolved, 2, load)
+		if *calls != 4 {
+			t.Errorf("max size %d: loaded %d times, want once per page", maxSize, *calls)
+		}
+
+		c.InvalidateUser("user-1")
+		c.GetOrLoad("guild-1", database.LeaderboardSolved, 1, load)
+		if *calls != 5 {
+			t.Errorf("max size %d: loaded %d times, want a reload after invalidation", maxSize, *calls)
+		}
+	}
+}
+
+func TestLeaderboardCacheExpiry(t *testing.T) {
+	store := cache.NewTyped[*database.LeaderboardResult](leaderboardCacheTTL, time.Minute)
+	c := &LeaderboardCache{results: store}
+	t.Cleanup(c.Close)
+	load, calls := countingLoader()
+
+	first, _ := c.GetOrLoad("guild-1", database.LeaderboardSolved, 1, load)
+
+	// Stand in for the five minutes passing by shortening the page's remaining lifetime
+	store.SetWithExpiration(leaderboardCacheKey("guild-1", database.LeaderboardSolved, 1), first, time.Millisecond)
+	time.Sleep(5 * time.Millisecond)
+
+	second, err := c.GetOrLoad("guild-1", database.LeaderboardSolved, 1, load)
+	if err != nil {
+		t.Fatalf("GetOrLoad: %v", err)
+	}
+	if *calls != 2 || second.TotalUsers != 2 {
+		t.Errorf("loaded %d times, want the expired page recomputed", *calls)
+	}
+}
+
+func TestLeaderboardCacheInvalidation(t *testing.T) {
+	ctx := context.Background()
+	bot, session := newTestBot(t, config.DiscordConfig{})
+	addOtherUserProblem(t, bot, "user-2", "Course Schedule")
+	if err := bot.repo.SetProfilePublic(ctx, "user-2", true); err != nil {
+		t.Fatalf("SetProfilePublic: %v", err)
+	}
+
+	if text := responseText(dispatch(t, bot, session, commandInteraction("leaderboard"))); !strings.Contains(text, "**1.** <@user-2> - 1") {
+		t.Fatalf("leaderboard = %q, want user-2 ranked with one problem", text)
+	}
+
+	// Logging a problem drops the cached page
+	addOtherUserProblem(t, bot, "user-2", "Word Ladder")
+	if text := responseText(dispatch(t, bot, session, commandInteraction("leaderboard"))); !strings.Contains(text, "**1.** <@user-2> - 2") {
+		t.Errorf("leaderboard = %q, want the new problem counted", text)
+	}
+
+	// So does opting in, which puts a member on the leaderboard
+	addTestProblem(t, bot, "Two Sum", database.DifficultyEasy)
+	dispatch(t, bot, session, commandInteraction("leaderboard"))
+	dispatch(t, bot, session, commandInteraction("privacy", boolOption("public", true)))
+	if text := responseText(dispatch(t, bot, session, commandInteraction("leaderboard"))); !strings.Contains(text, "<@"+testUserID+">") {
+		t.Errorf("leaderboard = %q, want %s ranked after opting in", text, testUserID)
+	}
+}
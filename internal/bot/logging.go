@@ -0,0 +1,37 @@
+package bot
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/rs/zerolog/log"
+)
+
+// newCorrelationID generates a short random identifier used to correlate log lines for one interaction
+func newCorrelationID() string {
+	buf := make([]byte, 4)
+	if _, err := rand.Read(buf); err != nil {
+		return "00000000"
+	}
+	return hex.EncodeToString(buf)
+}
+
+// withInteractionLogger attaches a sub-logger carrying the interaction's identifying fields to the context
+func withInteractionLogger(ctx context.Context, i *discordgo.InteractionCreate, cmdName string) context.Context {
+	logCtx := log.With().
+		Str("correlation_id", newCorrelationID()).
+		Str("interaction_id", i.ID).
+		Str("guild_id", i.GuildID)
+
+	if cmdName != "" {
+		logCtx = logCtx.Str("command", cmdName)
+	}
+	if userID := interactionUserID(i); userID != "" {
+		logCtx = logCtx.Str("user_id", userID)
+	}
+
+	logger := logCtx.Logger()
+	return logger.WithContext(ctx)
+}
@@ -0,0 +1,74 @@
+package bot
+
+import (
+	"fmt"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+
+	"github.com/yugonline/grind_review_bot/config"
+	"github.com/yugonline/grind_review_bot/internal/database"
+)
+
+// reminderTemplateData is what the reminder message templates are executed with
+type reminderTemplateData struct {
+	UserMention  string
+	Problem      *database.ProblemEntry // Only set for the problem line
+	ProblemCount int
+	Locale       discordgo.Locale // Decides how FormatDate writes dates
+}
+
+// FormatDate writes t for the reminder's locale, e.g. {{.FormatDate .Problem.SolvedAt}}
+func (d reminderTemplateData) FormatDate(t time.Time) string {
+	return formatDate(t, d.Locale)
+}
+
+// renderTemplate executes a message template, falling back to fallback when text is empty.
+// Templates are checked by config.Validate at startup, so a parse failure is a programming error.
+func renderTemplate(text, fallback string, data reminderTemplateData) (string, error) {
+	if text == "" {
+		text = fallback
+	}
+
+	var sb strings.Builder
+	if err := template.Must(template.New("").Parse(text)).Execute(&sb, data); err != nil {
+		return "", fmt.Errorf("failed to render message template: %w", err)
+	}
+	return sb.String(), nil
+}
+
+// renderReminder builds the body of a daily review reminder from the configured templates. remaining
+// is how many more problems are due than are listed; dates are written for locale.
+func renderReminder(templates config.MessageTemplates, locale discordgo.Locale, userMention string, problems []*database.ProblemEntry, remaining int) (string, error) {
+	data := reminderTemplateData{UserMention: userMention, ProblemCount: len(problems), Locale: locale}
+
+	var sb strings.Builder
+	intro, err := renderTemplate(templates.ReminderIntro, config.DefaultReminderIntro, data)
+	if err != nil {
+		return "", err
+	}
+	sb.WriteString(intro + "\n")
+
+	for _, p := range problems {
+		data.Problem = p
+		line, err := renderTemplate(templates.ReminderProblemLine, config.DefaultReminderProblemLine, data)
+		if err != nil {
+			return "", err
+		}
+		sb.WriteString(line + "\n")
+	}
+	data.Problem = nil
+
+	if remaining > 0 {
+		sb.WriteString(fmt.Sprintf("…and %d more\n", remaining))
+	}
+
+	outro, err := renderTemplate(templates.ReminderOutro, config.DefaultReminderOutro, data)
+	if err != nil {
+		return "", err
+	}
+	sb.WriteString("\n" + outro)
+	return sb.String(), nil
+}
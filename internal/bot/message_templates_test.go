@@ -0,0 +1,81 @@
+package bot
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/yugonline/grind_review_bot/config"
+	"github.com/yugonline/grind_review_bot/internal/database"
+)
+
+func TestRenderReminderDefaults(t *testing.T) {
+	problems := []*database.ProblemEntry{
+		{ProblemName: "Two Sum", Link: "https://leetcode.com/problems/two-sum/", SolvedAt: time.Date(2024, time.March, 5, 12, 0, 0, 0, time.UTC)},
+		{ProblemName: "Coin Change", SolvedAt: time.Date(2024, time.March, 6, 12, 0, 0, 0, time.UTC)},
+	}
+
+	content, err := renderReminder(config.MessageTemplates{}, discordgo.Unknown, "<@1>", problems, 3)
+	if err != nil {
+		t.Fatalf("renderReminder: %v", err)
+	}
+	want := "Hey <@1>! Here are some problems you might want to review today:\n" +
+		"- Two Sum (Solved: 2024-03-05) - <https://leetcode.com/problems/two-sum/>\n" +
+		"- Coin Change (Solved: 2024-03-06)\n" +
+		"…and 3 more\n" +
+		"\n" + config.DefaultReminderOutro
+	if content != want {
+		t.Errorf("reminder = %q, want %q", content, want)
+	}
+}
+
+func TestRenderReminderCustomTemplates(t *testing.T) {
+	templates := config.MessageTemplates{
+		ReminderIntro:       "{{.UserMention}}, {{.ProblemCount}} to review:",
+		ReminderProblemLine: "* {{.Problem.ProblemName}} [{{.Problem.Difficulty}}] x{{.Problem.ReviewCount}}",
+		ReminderOutro:       "Go {{.UserMention}}!",
+	}
+	problems := []*database.ProblemEntry{
+		{ProblemName: "Two Sum", Difficulty: database.DifficultyEasy, ReviewCount: 2},
+		{ProblemName: "Word Ladder", Difficulty: database.DifficultyHard},
+	}
+
+	content, err := renderReminder(templates, discordgo.Unknown, "<@42>", problems, 0)
+	if err != nil {
+		t.Fatalf("renderReminder: %v", err)
+	}
+	want := "<@42>, 2 to review:\n* Two Sum [Easy] x2\n* Word Ladder [Hard] x0\n\nGo <@42>!"
+	if content != want {
+		t.Errorf("reminder = %q, want %q", content, want)
+	}
+
+	// A template that parses but fails on the data is reported, not sent half-written
+	templates.ReminderOutro = "{{.Problem.ProblemName}}"
+	if _, err := renderReminder(templates, discordgo.Unknown, "<@42>", problems, 0); err == nil || !strings.Contains(err.Error(), "failed to render message template") {
+		t.Errorf("renderReminder = %v, want a render error for .Problem in the outro", err)
+	}
+}
+
+func TestReminderUsesConfiguredTemplates(t *testing.T) {
+	ctx := context.Background()
+	bot, session := newTestBot(t, config.DiscordConfig{MessageTemplates: config.MessageTemplates{ReminderIntro: "Review time, {{.UserMention}}"}})
+	scheduler := &Scheduler{bot: bot, config: config.SchedulerConfig{ReviewChannel: "review-channel", LookbackPeriod: 7 * 24 * time.Hour}}
+	addTestProblem(t, bot, "Two Sum", database.DifficultyEasy)
+
+	scheduler.sendDailyReviewReminder(ctx)
+
+	sent := session.Calls("ChannelMessageSendComplex")
+	if len(sent) != 1 {
+		t.Fatalf("sent %d reminders, want 1", len(sent))
+	}
+	content := sent[0].Args[1].(*discordgo.MessageSend).Content
+	if !strings.HasPrefix(content, "Review time, <@"+testUserID+">\n") {
+		t.Errorf("reminder = %q, want the configured intro", content)
+	}
+	// Templates left unset keep their defaults
+	if !strings.Contains(content, "- Two Sum (Solved: 2024-01-01)") || !strings.HasSuffix(content, config.DefaultReminderOutro) {
+		t.Errorf("reminder = %q, want the default problem line and outro", content)
+	}
+}
@@ -1,26 +1,123 @@
 package bot
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
 	"github.com/bwmarrin/discordgo"
-	"github.com/rs/zerolog/log"
+	"github.com/rs/zerolog"
+	"github.com/yugonline/grind_review_bot/internal/database"
+	"github.com/yugonline/grind_review_bot/internal/metrics"
 )
 
-// Middleware for handling errors during command execution.
-// This is a placeholder and can be expanded with more sophisticated error handling.
-func (b *Bot) errorMiddleware(next func(s *discordgo.Session, i *discordgo.InteractionCreate) (*discordgo.InteractionResponse, error)) func(s *discordgo.Session, i *discordgo.InteractionCreate) (*discordgo.InteractionResponse, error) {
-	return func(s *discordgo.Session, i *discordgo.InteractionCreate) (*discordgo.InteractionResponse, error) {
-		response, err := next(s, i)
+// ownedProblemKey is the context key for the problem loaded by requireOwnedProblem
+type ownedProblemKey struct{}
+
+// Middleware wraps a command handler with behaviour shared by every command
+type Middleware func(next CommandHandler) CommandHandler
+
+// chain wraps handler in middlewares, the first listed being the outermost
+func chain(handler CommandHandler, middlewares ...Middleware) CommandHandler {
+	for i := len(middlewares) - 1; i >= 0; i-- {
+		handler = middlewares[i](handler)
+	}
+	return handler
+}
+
+// commandMiddleware lists the middleware applied to every registered handler, outermost first.
+// errorMiddleware comes first so the others still see the handler's error.
+func (b *Bot) commandMiddleware() []Middleware {
+	return []Middleware{
+		b.errorMiddleware,
+		b.instrumentMiddleware,
+	}
+}
+
+// errorMiddleware logs handler errors and turns them into a reply. A UserError is described by
+// kind with its message; any other error gets a generic reply so internal error text never
+// reaches the user, unless the handler returned a response alongside it or already replied.
+func (b *Bot) errorMiddleware(next CommandHandler) CommandHandler {
+	return func(ctx context.Context, s DiscordSession, i *discordgo.InteractionCreate) (*discordgo.InteractionResponse, error) {
+		response, err := next(ctx, s, i)
 		if err != nil {
-			log.Error().Err(err).Str("command", i.ApplicationCommandData().Name).Msg("Error during command execution")
-			// Optionally send a user-friendly error message back to Discord
-			return &discordgo.InteractionResponse{
-				Type: discordgo.InteractionResponseChannelMessageWithSource,
-				Data: &discordgo.InteractionResponseData{
-					Content: "An unexpected error occurred while processing your command.",
-					Flags:   discordgo.MessageFlagsEphemeral,
-				},
-			}, nil // Return nil error to prevent further propagation if already handled
+			logHandlerError(ctx, err)
+			var responded *respondedError
+			if response == nil && !errors.As(err, &responded) {
+				response = handlerErrorResponse(err)
+			}
 		}
 		return response, nil
 	}
-}
\ No newline at end of file
+}
+
+// handlerErrorResponse creates the reply for a handler error
+func handlerErrorResponse(err error) *discordgo.InteractionResponse {
+	var userErr *UserError
+	if errors.As(err, &userErr) {
+		return userErrorResponse(userErr)
+	}
+	return errorResponse("An unexpected error occurred while processing your command.")
+}
+
+// instrumentMiddleware records how long each command took and how it ended, both in the
+// metrics and in the invoking user's usage counts
+func (b *Bot) instrumentMiddleware(next CommandHandler) CommandHandler {
+	return func(ctx context.Context, s DiscordSession, i *discordgo.InteractionCreate) (*discordgo.InteractionResponse, error) {
+		start := time.Now()
+		response, err := next(ctx, s, i)
+		elapsed := time.Since(start)
+
+		outcome := "ok"
+		switch {
+		case errors.Is(ctx.Err(), context.DeadlineExceeded):
+			outcome = "timeout"
+		case err != nil:
+			outcome = "error"
+		}
+		metrics.RecordCommand(b.commandKey(i), outcome, elapsed)
+		b.recordUsage(ctx, i, outcome == "ok")
+		zerolog.Ctx(ctx).Debug().Str("outcome", outcome).Dur("elapsed", elapsed).Msg("Command handled")
+
+		return response, err
+	}
+}
+
+// requireOwnedProblem wraps a handler for a command with an "id" option so it only runs when that
+// problem exists and belongs to the invoking user. action names what the command does to the problem
+// in error messages. The handler reads the problem with ownedProblem; commands where the ID is
+// optional run the handler without one when it is omitted.
+func (b *Bot) requireOwnedProblem(action string, next CommandHandler) CommandHandler {
+	return func(ctx context.Context, s DiscordSession, i *discordgo.InteractionCreate) (*discordgo.InteractionResponse, error) {
+		_, options := getSubcommand(i)
+		var idOpt *discordgo.ApplicationCommandInteractionDataOption
+		for _, opt := range options {
+			if opt.Name == "id" {
+				idOpt = opt
+			}
+		}
+		if idOpt == nil {
+			return next(ctx, s, i)
+		}
+
+		problemID := uint(idOpt.IntValue())
+		problem, err := b.repo.GetProblem(ctx, problemID)
+		if err != nil {
+			zerolog.Ctx(ctx).Error().Err(err).Uint("id", problemID).Msg("Failed to get problem")
+			return nil, notFoundError(fmt.Sprintf("Problem with ID %d not found or you don't have permission to %s it.", problemID, action))
+		}
+		if problem.UserID != interactionUserID(i) {
+			zerolog.Ctx(ctx).Warn().Uint("id", problemID).Msg("Problem belongs to another user")
+			return nil, permissionError(fmt.Sprintf("You don't have permission to %s this problem.", action))
+		}
+
+		return next(context.WithValue(ctx, ownedProblemKey{}, problem), s, i)
+	}
+}
+
+// ownedProblem returns the problem loaded and ownership-checked by requireOwnedProblem
+func ownedProblem(ctx context.Context) (*database.ProblemEntry, bool) {
+	problem, ok := ctx.Value(ownedProblemKey{}).(*database.ProblemEntry)
+	return problem, ok
+}
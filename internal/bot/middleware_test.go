@@ -0,0 +1,147 @@
+package bot
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/yugonline/grind_review_bot/config"
+	"github.com/yugonline/grind_review_bot/internal/database"
+)
+
+func TestRequireOwnedProblem(t *testing.T) {
+	bot, session := newTestBot(t, config.DiscordConfig{})
+	own := addTestProblem(t, bot, "Two Sum", database.DifficultyEasy)
+	addOtherUserProblem(t, bot, "user-2", "Coin Change")
+	others, err := bot.repo.ListProblems(context.Background(), "user-2", "", "", "", nil, true, 0, 0)
+	if err != nil || len(others) != 1 {
+		t.Fatalf("ListProblems = %d problems, %v, want user-2's one", len(others), err)
+	}
+
+	tests := []struct {
+		name     string
+		options  []*discordgo.ApplicationCommandInteractionDataOption
+		wantErr  error
+		wantName string
+	}{
+		{name: "own problem", options: []*discordgo.ApplicationCommandInteractionDataOption{intOption("id", int(own.ID))}, wantName: "Two Sum"},
+		{name: "another user's problem", options: []*discordgo.ApplicationCommandInteractionDataOption{intOption("id", int(others[0].ID))}, wantErr: ErrPermissionDenied},
+		{name: "missing problem", options: []*discordgo.ApplicationCommandInteractionDataOption{intOption("id", 999)}, wantErr: ErrNotFound},
+		{name: "no id", options: []*discordgo.ApplicationCommandInteractionDataOption{stringOption("name", "Two Sum")}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			called := false
+			var got *database.ProblemEntry
+			handler := bot.requireOwnedProblem("inspect", func(ctx context.Context, s DiscordSession, i *discordgo.InteractionCreate) (*discordgo.InteractionResponse, error) {
+				called = true
+				got, _ = ownedProblem(ctx)
+				return messageResponse("Done."), nil
+			})
+
+			_, err := handler(context.Background(), session, commandInteraction("inspect", tt.options...))
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) || called {
+					t.Errorf("handler = %v (called %v), want %v without calling it", err, called, tt.wantErr)
+				}
+				return
+			}
+			if err != nil || !called {
+				t.Fatalf("handler = %v (called %v), want it called", err, called)
+			}
+			switch {
+			case tt.wantName == "" && got != nil:
+				t.Errorf("ownedProblem = %q, want none without an id", got.ProblemName)
+			case tt.wantName != "" && (got == nil || got.ProblemName != tt.wantName):
+				t.Errorf("ownedProblem = %+v, want %s", got, tt.wantName)
+			}
+		})
+	}
+}
+
+func TestOwnerScopedCommandsDenyOtherUsers(t *testing.T) {
+	bot, session := newTestBot(t, config.DiscordConfig{})
+	addOtherUserProblem(t, bot, "user-2", "Coin Change")
+	others, err := bot.repo.ListProblems(context.Background(), "user-2", "", "", "", nil, true, 0, 0)
+	if err != nil || len(others) != 1 {
+		t.Fatalf("ListProblems = %d problems, %v, want user-2's one", len(others), err)
+	}
+	id := int(others[0].ID)
+
+	for _, i := range []*discordgo.InteractionCreate{
+		commandInteraction("get", intOption("id", id)),
+		commandInteraction("edit", intOption("id", id), stringOption("name", "Stolen")),
+		commandInteraction("delete", intOption("id", id)),
+	} {
+		name := i.ApplicationCommandData().Name
+		resp := dispatch(t, bot, session, i)
+		if resp.Data.Flags&discordgo.MessageFlagsEphemeral == 0 || !strings.Contains(responseText(resp), "You don't have permission to") {
+			t.Errorf("/%s response = %q, want an ephemeral permission error", name, responseText(resp))
+		}
+		if strings.Contains(responseText(resp), "Coin Change") {
+			t.Errorf("/%s leaked the other user's problem: %q", name, responseText(resp))
+		}
+	}
+
+	problem, err := bot.repo.GetProblem(context.Background(), others[0].ID)
+	if err != nil || problem.ProblemName != "Coin Change" {
+		t.Errorf("GetProblem = %+v, %v, want Coin Change untouched", problem, err)
+	}
+}
+
+func TestChainOrder(t *testing.T) {
+	var calls []string
+	record := func(name string) Middleware {
+		return func(next CommandHandler) CommandHandler {
+			return func(ctx context.Context, s DiscordSession, i *discordgo.InteractionCreate) (*discordgo.InteractionResponse, error) {
+				calls = append(calls, name+" in")
+				resp, err := next(ctx, s, i)
+				calls = append(calls, name+" out")
+				return resp, err
+			}
+		}
+	}
+	handler := chain(func(ctx context.Context, s DiscordSession, i *discordgo.InteractionCreate) (*discordgo.InteractionResponse, error) {
+		calls = append(calls, "handler")
+		return nil, nil
+	}, record("outer"), record("inner"))
+
+	handler(context.Background(), nil, commandInteraction("ordered"))
+	if got, want := strings.Join(calls, ", "), "outer in, inner in, handler, inner out, outer out"; got != want {
+		t.Errorf("calls = %s, want %s", got, want)
+	}
+}
+
+func TestErrorMiddlewareFallback(t *testing.T) {
+	bot, session := newTestBot(t, config.DiscordConfig{})
+	handlerFor := func(resp *discordgo.InteractionResponse, err error) CommandHandler {
+		return chain(func(ctx context.Context, s DiscordSession, i *discordgo.InteractionCreate) (*discordgo.InteractionResponse, error) {
+			return resp, err
+		}, bot.commandMiddleware()...)
+	}
+
+	// Internal error text never reaches the user
+	bot.commandHandlers["broken"] = handlerFor(nil, errors.New("sql: connection refused"))
+	resp := dispatch(t, bot, session, commandInteraction("broken"))
+	if resp.Data.Flags&discordgo.MessageFlagsEphemeral == 0 || !strings.Contains(resp.Data.Content, "An unexpected error occurred") {
+		t.Errorf("response = %+v, want the ephemeral fallback", resp.Data)
+	}
+	if strings.Contains(responseText(resp), "connection refused") {
+		t.Errorf("response %q leaks the internal error", responseText(resp))
+	}
+
+	// A database error shows its message, not its cause
+	bot.commandHandlers["failing"] = handlerFor(nil, databaseError("Failed to save the problem.", errors.New("disk I/O error")))
+	resp = dispatch(t, bot, session, commandInteraction("failing"))
+	if text := responseText(resp); !strings.Contains(text, "Failed to save the problem.") || strings.Contains(text, "disk I/O") {
+		t.Errorf("response = %q, want the message without the cause", text)
+	}
+
+	// A response returned alongside the error is sent as is
+	bot.commandHandlers["partial"] = handlerFor(messageResponse("Saved, but the reminder failed."), errors.New("reminder failed"))
+	if resp := dispatch(t, bot, session, commandInteraction("partial")); resp.Data.Content != "Saved, but the reminder failed." {
+		t.Errorf("content = %q, want the handler's response", resp.Data.Content)
+	}
+}
@@ -0,0 +1,78 @@
+package bot
+
+import (
+	"context"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/rs/zerolog"
+)
+
+// Permission identifies a privilege required to run a command
+type Permission int
+
+const (
+	// PermissionModerator allows managing other members' review activity
+	PermissionModerator Permission = iota
+	// PermissionAdmin allows operational commands that affect the whole bot
+	PermissionAdmin
+)
+
+// String returns a human-readable name for the permission
+func (p Permission) String() string {
+	switch p {
+	case PermissionModerator:
+		return "moderator"
+	case PermissionAdmin:
+		return "admin"
+	default:
+		return "unknown"
+	}
+}
+
+// PermissionChecker decides whether a guild member holds a permission
+type PermissionChecker interface {
+	HasPermission(member *discordgo.Member, perm Permission) bool
+}
+
+// DiscordPermissionChecker checks permissions using the member's Discord permission bits
+// and, when configured, membership of the admin role
+type DiscordPermissionChecker struct {
+	AdminRoleID string
+}
+
+// HasPermission implements PermissionChecker
+func (c DiscordPermissionChecker) HasPermission(member *discordgo.Member, perm Permission) bool {
+	if member == nil {
+		return false
+	}
+
+	// Server administrators and holders of the configured admin role can do everything
+	if member.Permissions&discordgo.PermissionAdministrator != 0 {
+		return true
+	}
+	if c.AdminRoleID != "" {
+		for _, role := range member.Roles {
+			if role == c.AdminRoleID {
+				return true
+			}
+		}
+	}
+
+	switch perm {
+	case PermissionModerator:
+		return member.Permissions&discordgo.PermissionManageServer != 0
+	default:
+		return false
+	}
+}
+
+// requirePermission wraps a handler so it only runs for members holding perm
+func (b *Bot) requirePermission(perm Permission, next CommandHandler) CommandHandler {
+	return func(ctx context.Context, s DiscordSession, i *discordgo.InteractionCreate) (*discordgo.InteractionResponse, error) {
+		if !b.permissions.HasPermission(i.Member, perm) {
+			zerolog.Ctx(ctx).Warn().Str("permission", perm.String()).Msg("Permission denied")
+			return nil, permissionError("You don't have permission to use this command.")
+		}
+		return next(ctx, s, i)
+	}
+}
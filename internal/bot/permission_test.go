@@ -0,0 +1,60 @@
+package bot
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/yugonline/grind_review_bot/config"
+)
+
+func TestDiscordPermissionChecker(t *testing.T) {
+	checker := DiscordPermissionChecker{AdminRoleID: "role-admin"}
+	tests := []struct {
+		name          string
+		member        *discordgo.Member
+		wantModerator bool
+		wantAdmin     bool
+	}{
+		{name: "no member", member: nil},
+		{name: "regular member", member: &discordgo.Member{Permissions: discordgo.PermissionSendMessages, Roles: []string{"role-member"}}},
+		{name: "manage server", member: &discordgo.Member{Permissions: discordgo.PermissionManageServer}, wantModerator: true},
+		{name: "administrator", member: &discordgo.Member{Permissions: discordgo.PermissionAdministrator}, wantModerator: true, wantAdmin: true},
+		{name: "admin role", member: &discordgo.Member{Roles: []string{"role-member", "role-admin"}}, wantModerator: true, wantAdmin: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := checker.HasPermission(tt.member, PermissionModerator); got != tt.wantModerator {
+				t.Errorf("HasPermission(moderator) = %v, want %v", got, tt.wantModerator)
+			}
+			if got := checker.HasPermission(tt.member, PermissionAdmin); got != tt.wantAdmin {
+				t.Errorf("HasPermission(admin) = %v, want %v", got, tt.wantAdmin)
+			}
+		})
+	}
+
+	// Without a configured role, no role grants admin
+	if (DiscordPermissionChecker{}).HasPermission(&discordgo.Member{Roles: []string{""}}, PermissionAdmin) {
+		t.Error("an empty admin role ID matched a role")
+	}
+}
+
+func TestRequirePermission(t *testing.T) {
+	bot, _ := newTestBot(t, config.DiscordConfig{AdminRoleID: "role-admin"})
+	called := false
+	handler := bot.requirePermission(PermissionAdmin, func(ctx context.Context, s DiscordSession, i *discordgo.InteractionCreate) (*discordgo.InteractionResponse, error) {
+		called = true
+		return messageResponse("Done."), nil
+	})
+
+	i := commandInteraction("admin")
+	if _, err := handler(context.Background(), nil, i); err == nil || !strings.Contains(err.Error(), "permission") || called {
+		t.Errorf("handler = %v (ran %v) for a regular member, want it refused", err, called)
+	}
+
+	i.Member.Roles = []string{"role-admin"}
+	if resp, err := handler(context.Background(), nil, i); err != nil || !called || resp.Data.Content != "Done." {
+		t.Errorf("handler = %v (ran %v) for an admin, want it to run", err, called)
+	}
+}
@@ -0,0 +1,108 @@
+package bot
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/yugonline/grind_review_bot/config"
+	"github.com/yugonline/grind_review_bot/internal/database"
+)
+
+// addOtherUserProblem stores a solved problem for a member other than testUserID
+func addOtherUserProblem(t *testing.T, bot *Bot, userID, name string) {
+	t.Helper()
+	entry := &database.ProblemEntry{UserID: userID, ProblemName: name, Difficulty: database.DifficultyMedium, Category: "Graph", Status: database.StatusSolved, SolvedAt: time.Date(2024, time.January, 2, 12, 0, 0, 0, time.UTC)}
+	if err := bot.repo.CreateProblem(context.Background(), entry); err != nil {
+		t.Fatalf("CreateProblem: %v", err)
+	}
+}
+
+// compareWith builds a /compare interaction naming another member
+func compareWith(userID, username string) *discordgo.InteractionCreate {
+	i := commandInteraction("compare", userOption("user", userID))
+	i.Data = discordgo.ApplicationCommandInteractionData{
+		Name:     "compare",
+		Options:  i.ApplicationCommandData().Options,
+		Resolved: &discordgo.ApplicationCommandInteractionDataResolved{Users: map[string]*discordgo.User{userID: {ID: userID, Username: username}}},
+	}
+	return i
+}
+
+func TestCompareRequiresPublicProfile(t *testing.T) {
+	bot, session := newTestBot(t, config.DiscordConfig{})
+	addTestProblem(t, bot, "Two Sum", database.DifficultyEasy)
+	addOtherUserProblem(t, bot, "user-2", "Course Schedule")
+
+	// Profiles are private until the member opts in
+	resp := deferredResult(t, bot, session, compareWith("user-2", "rival"))
+	if !strings.Contains(responseText(resp), "hasn't made their profile public") {
+		t.Errorf("compare with a private profile = %q, want it refused", responseText(resp))
+	}
+
+	if err := bot.repo.SetProfilePublic(context.Background(), "user-2", true); err != nil {
+		t.Fatalf("SetProfilePublic: %v", err)
+	}
+	resp = deferredResult(t, bot, session, compareWith("user-2", "rival"))
+	if len(resp.Data.Embeds) != 1 || resp.Data.Embeds[0].Title != "You vs rival" {
+		t.Fatalf("compare with a public profile = %q, want a side-by-side embed", responseText(resp))
+	}
+	if fields := resp.Data.Embeds[0].Fields; len(fields) != 2 || fields[0].Value == fields[1].Value {
+		t.Errorf("fields = %+v, want a column per member", fields)
+	}
+}
+
+func TestCompareWithServerAverage(t *testing.T) {
+	bot, session := newTestBot(t, config.DiscordConfig{})
+	addTestProblem(t, bot, "Two Sum", database.DifficultyEasy)
+	addOtherUserProblem(t, bot, "user-2", "Course Schedule")
+
+	// The average needs no opt-in, and comparing with yourself falls back to it
+	for _, i := range []*discordgo.InteractionCreate{commandInteraction("compare"), compareWith(testUserID, "tester")} {
+		resp := deferredResult(t, bot, session, i)
+		if len(resp.Data.Embeds) != 1 || resp.Data.Embeds[0].Title != "You vs Server Average" {
+			t.Errorf("compare = %q, want the server average", responseText(resp))
+		}
+	}
+}
+
+func TestPrivacyCommand(t *testing.T) {
+	bot, session := newTestBot(t, config.DiscordConfig{})
+	public := &discordgo.ApplicationCommandInteractionDataOption{Name: "public", Type: discordgo.ApplicationCommandOptionBoolean, Value: true}
+
+	// Profiles start private
+	dispatch(t, bot, session, commandInteraction("privacy"))
+	if settings, err := bot.repo.GetUserSettings(context.Background(), testUserID); err != nil || settings.ProfilePublic {
+		t.Fatalf("new profile public = %v (%v), want private", settings.ProfilePublic, err)
+	}
+
+	resp := dispatch(t, bot, session, commandInteraction("privacy", public))
+	if resp.Data.Flags&discordgo.MessageFlagsEphemeral == 0 {
+		t.Error("privacy settings were shown to the channel")
+	}
+	if settings, err := bot.repo.GetUserSettings(context.Background(), testUserID); err != nil || !settings.ProfilePublic {
+		t.Errorf("profile public = %v (%v) after opting in, want public", settings.ProfilePublic, err)
+	}
+}
+
+func TestLeaderboardExcludesPrivateProfiles(t *testing.T) {
+	bot, session := newTestBot(t, config.DiscordConfig{})
+	addTestProblem(t, bot, "Two Sum", database.DifficultyEasy)
+	addOtherUserProblem(t, bot, "user-2", "Course Schedule")
+	addOtherUserProblem(t, bot, "user-3", "Word Ladder")
+	if err := bot.repo.SetProfilePublic(context.Background(), "user-2", true); err != nil {
+		t.Fatalf("SetProfilePublic: %v", err)
+	}
+
+	text := responseText(dispatch(t, bot, session, commandInteraction("leaderboard")))
+	if !strings.Contains(text, "<@user-2>") {
+		t.Errorf("leaderboard = %q, want the public member ranked", text)
+	}
+	for _, private := range []string{"<@user-1>", "<@user-3>"} {
+		if strings.Contains(text, private) {
+			t.Errorf("leaderboard = %q, want %s left out", text, private)
+		}
+	}
+}
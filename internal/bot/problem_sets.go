@@ -0,0 +1,234 @@
+package bot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/rs/zerolog"
+	"github.com/yugonline/grind_review_bot/internal/database"
+)
+
+// setReviewListLimit caps how many problems a /set review reply lists so it fits in one message
+const setReviewListLimit = 25
+
+// ensureBuiltinProblemSets seeds the built-in problem sets the first time a guild runs a command
+// since the bot started. Failures are logged and retried on the next command.
+func (b *Bot) ensureBuiltinProblemSets(ctx context.Context, guildID string) {
+	if guildID == "" {
+		return
+	}
+	if _, seeded := b.seededGuilds.Load(guildID); seeded {
+		return
+	}
+
+	if err := b.repo.EnsureBuiltinProblemSets(ctx, guildID); err != nil {
+		zerolog.Ctx(ctx).Error().Err(err).Msg("Failed to seed built-in problem sets")
+		return
+	}
+	b.seededGuilds.Store(guildID, struct{}{})
+}
+
+// accessibleProblemSet loads a set the user may view: a built-in set of this guild or one of their own
+func (b *Bot) accessibleProblemSet(ctx context.Context, i *discordgo.InteractionCreate, setID uint) (*database.ProblemSet, error) {
+	set, err := b.repo.GetProblemSet(ctx, setID)
+	if errors.Is(err, database.ErrProblemSetNotFound) {
+		return nil, notFoundError(fmt.Sprintf("Problem set %d not found.", setID))
+	}
+	if err != nil {
+		return nil, databaseError("Failed to retrieve the problem set from the database.", err)
+	}
+	if set.GuildID != i.GuildID || (!set.Builtin() && set.OwnerUserID != interactionUserID(i)) {
+		return nil, notFoundError(fmt.Sprintf("Problem set %d not found.", setID))
+	}
+	return set, nil
+}
+
+// ownedProblemSet loads a set the user may change, which excludes the built-in sets
+func (b *Bot) ownedProblemSet(ctx context.Context, i *discordgo.InteractionCreate, setID uint) (*database.ProblemSet, error) {
+	set, err := b.accessibleProblemSet(ctx, i, setID)
+	if err != nil {
+		return nil, err
+	}
+	if set.Builtin() {
+		return nil, permissionError(fmt.Sprintf("%s is a built-in set and can't be changed.", set.Name))
+	}
+	return set, nil
+}
+
+func (b *Bot) handleSetCreateCommand(ctx context.Context, s DiscordSession, i *discordgo.InteractionCreate) (*discordgo.InteractionResponse, error) {
+	_, options := getSubcommand(i)
+	optionMap := make(map[string]*discordgo.ApplicationCommandInteractionDataOption, len(options))
+	for _, opt := range options {
+		optionMap[opt.Name] = opt
+	}
+
+	set := &database.ProblemSet{
+		Name:        strings.TrimSpace(optionMap["name"].StringValue()),
+		OwnerUserID: interactionUserID(i),
+		GuildID:     i.GuildID,
+	}
+	if set.Name == "" {
+		return nil, validationError("The set needs a name.")
+	}
+	if descriptionOpt, ok := optionMap["description"]; ok {
+		set.Description = strings.TrimSpace(descriptionOpt.StringValue())
+	}
+
+	if err := b.repo.CreateProblemSet(ctx, set); err != nil {
+		if errors.Is(err, database.ErrProblemSetExists) {
+			return nil, validationError(fmt.Sprintf("You already have a set named '%s'.", set.Name))
+		}
+		return nil, databaseError("Failed to create the problem set.", err)
+	}
+
+	return ephemeralResponse(fmt.Sprintf("Created problem set '%s' with ID %d. Add problems with /set add.", set.Name, set.ID)), nil
+}
+
+func (b *Bot) handleSetAddCommand(ctx context.Context, s DiscordSession, i *discordgo.InteractionCreate) (*discordgo.InteractionResponse, error) {
+	set, problem, err := b.setAndProblemOptions(ctx, i)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := b.repo.AddProblemToSet(ctx, set.ID, problem.ID); err != nil {
+		return nil, databaseError("Failed to add the problem to the set.", err)
+	}
+	return ephemeralResponse(fmt.Sprintf("Added '%s' to '%s'.", problem.ProblemName, set.Name)), nil
+}
+
+func (b *Bot) handleSetRemoveCommand(ctx context.Context, s DiscordSession, i *discordgo.InteractionCreate) (*discordgo.InteractionResponse, error) {
+	set, problem, err := b.setAndProblemOptions(ctx, i)
+	if err != nil {
+		return nil, err
+	}
+
+	removed, err := b.repo.RemoveProblemFromSet(ctx, set.ID, problem.ID)
+	if err != nil {
+		return nil, databaseError("Failed to remove the problem from the set.", err)
+	}
+	if !removed {
+		return nil, notFoundError(fmt.Sprintf("'%s' isn't in '%s'.", problem.ProblemName, set.Name))
+	}
+	return ephemeralResponse(fmt.Sprintf("Removed '%s' from '%s'.", problem.ProblemName, set.Name)), nil
+}
+
+// setAndProblemOptions loads the set and problem named by the set_id and problem_id options,
+// checking the user owns both
+func (b *Bot) setAndProblemOptions(ctx context.Context, i *discordgo.InteractionCreate) (*database.ProblemSet, *database.ProblemEntry, error) {
+	_, options := getSubcommand(i)
+	optionMap := make(map[string]*discordgo.ApplicationCommandInteractionDataOption, len(options))
+	for _, opt := range options {
+		optionMap[opt.Name] = opt
+	}
+
+	set, err := b.ownedProblemSet(ctx, i, uint(optionMap["set_id"].IntValue()))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	problemID := uint(optionMap["problem_id"].IntValue())
+	problem, err := b.repo.GetProblem(ctx, problemID)
+	if err != nil || problem.UserID != interactionUserID(i) {
+		return nil, nil, notFoundError(fmt.Sprintf("Problem with ID %d not found.", problemID))
+	}
+	return set, problem, nil
+}
+
+func (b *Bot) handleSetListCommand(ctx context.Context, s DiscordSession, i *discordgo.InteractionCreate) (*discordgo.InteractionResponse, error) {
+	sets, err := b.repo.ListProblemSets(ctx, interactionUserID(i), i.GuildID)
+	if err != nil {
+		return nil, databaseError("Failed to retrieve problem sets from the database.", err)
+	}
+	if len(sets) == 0 {
+		return ephemeralResponse("There are no problem sets yet. Create one with /set create."), nil
+	}
+
+	var sb strings.Builder
+	sb.WriteString("# Problem Sets\n")
+	for _, set := range sets {
+		count := len(set.Items)
+		label := ""
+		if set.Builtin() {
+			count = len(set.ProblemNames)
+			label = " (built-in)"
+		}
+		sb.WriteString(fmt.Sprintf("- **[%d] %s**%s: %d problems", set.ID, set.Name, label, count))
+		if set.Description != "" {
+			sb.WriteString(" - " + set.Description)
+		}
+		sb.WriteString("\n")
+	}
+	return ephemeralResponse(sb.String()), nil
+}
+
+func (b *Bot) handleSetReviewCommand(ctx context.Context, s DiscordSession, i *discordgo.InteractionCreate) (*discordgo.InteractionResponse, error) {
+	_, options := getSubcommand(i)
+	optionMap := make(map[string]*discordgo.ApplicationCommandInteractionDataOption, len(options))
+	for _, opt := range options {
+		optionMap[opt.Name] = opt
+	}
+
+	set, err := b.accessibleProblemSet(ctx, i, uint(optionMap["set_id"].IntValue()))
+	if err != nil {
+		return nil, err
+	}
+
+	userID := interactionUserID(i)
+	problems, err := b.repo.ListProblemSetProblems(ctx, set, userID)
+	if err != nil {
+		return nil, databaseError("Failed to retrieve the set's problems from the database.", err)
+	}
+	if len(problems) == 0 {
+		if set.Builtin() {
+			return ephemeralResponse(fmt.Sprintf("You haven't logged any of the problems in %s yet. Problems count once you /add them under the same name.", set.Name)), nil
+		}
+		return ephemeralResponse(fmt.Sprintf("'%s' has no problems to review. Add some with /set add.", set.Name)), nil
+	}
+
+	response := messageResponse(setReviewMessage(set, problems))
+	response.Data.Components = reminderComponents(userID, problems)
+	return response, nil
+}
+
+// setReviewMessage lists a set's problems for an immediate review session
+func setReviewMessage(set *database.ProblemSet, problems []*database.ProblemEntry) string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("# Review: %s\n", set.Name))
+	if set.Builtin() {
+		sb.WriteString(fmt.Sprintf("You've logged %d of its %d problems.\n", len(problems), len(set.ProblemNames)))
+	}
+	for idx, p := range problems {
+		if idx == setReviewListLimit {
+			sb.WriteString(fmt.Sprintf("…and %d more\n", len(problems)-idx))
+			break
+		}
+		sb.WriteString(fmt.Sprintf("- [%d] %s (%s)", p.ID, p.ProblemName, p.Difficulty))
+		if p.Link != "" {
+			sb.WriteString(fmt.Sprintf(" - <%s>", p.Link))
+		}
+		sb.WriteString("\n")
+	}
+	sb.WriteString("\nPress a problem's button once you've reviewed it.")
+	return sb.String()
+}
+
+func (b *Bot) handleSetDeleteCommand(ctx context.Context, s DiscordSession, i *discordgo.InteractionCreate) (*discordgo.InteractionResponse, error) {
+	_, options := getSubcommand(i)
+	optionMap := make(map[string]*discordgo.ApplicationCommandInteractionDataOption, len(options))
+	for _, opt := range options {
+		optionMap[opt.Name] = opt
+	}
+
+	set, err := b.ownedProblemSet(ctx, i, uint(optionMap["set_id"].IntValue()))
+	if err != nil {
+		return nil, err
+	}
+
+	if err := b.repo.DeleteProblemSet(ctx, set.ID); err != nil {
+		return nil, databaseError("Failed to delete the problem set.", err)
+	}
+	return ephemeralResponse(fmt.Sprintf("Deleted problem set '%s'. Its problems are unaffected.", set.Name)), nil
+}
@@ -0,0 +1,166 @@
+package bot
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/rs/zerolog"
+	"github.com/yugonline/grind_review_bot/internal/database"
+)
+
+// Custom ID prefixes of the buttons attached to daily review reminders.
+// Per-problem buttons carry the problem ID and "Mark all" carries the reminded user's ID.
+const (
+	reviewDoneButtonPrefix = "review_done"
+	reviewAllButtonPrefix  = "review_all"
+)
+
+// Reminder button layout. Discord allows five rows of five buttons; the last row holds "Mark all reviewed".
+const (
+	buttonsPerRow      = 5
+	maxReminderButtons = 20
+	maxButtonLabel     = 80
+)
+
+// reminderComponents builds a "reviewed" button for each listed problem and a "Mark all reviewed"
+// button. Only the first maxReminderButtons problems get their own button.
+func reminderComponents(userID string, problems []*database.ProblemEntry) []discordgo.MessageComponent {
+	if len(problems) > maxReminderButtons {
+		problems = problems[:maxReminderButtons]
+	}
+
+	var rows []discordgo.MessageComponent
+	for start := 0; start < len(problems); start += buttonsPerRow {
+		row := discordgo.ActionsRow{}
+		for _, p := range problems[start:min(start+buttonsPerRow, len(problems))] {
+			row.Components = append(row.Components, discordgo.Button{
+				Label:    truncateString(p.ProblemName, maxButtonLabel),
+				Style:    discordgo.SecondaryButton,
+				CustomID: fmt.Sprintf("%s:%d", reviewDoneButtonPrefix, p.ID),
+			})
+		}
+		rows = append(rows, row)
+	}
+
+	rows = append(rows, discordgo.ActionsRow{
+		Components: []discordgo.MessageComponent{
+			discordgo.Button{
+				Label:    "Mark all reviewed",
+				Style:    discordgo.PrimaryButton,
+				CustomID: reviewAllButtonPrefix + ":" + userID,
+			},
+		},
+	})
+	return rows
+}
+
+// handleReviewDoneButton records a review of the problem on the clicked reminder button
+func (b *Bot) handleReviewDoneButton(ctx context.Context, s DiscordSession, i *discordgo.InteractionCreate) (*discordgo.InteractionResponse, error) {
+	customID := i.MessageComponentData().CustomID
+	_, arg, _ := strings.Cut(customID, ":")
+	problemID, err := strconv.ParseUint(arg, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid review button ID %q: %w", customID, err)
+	}
+
+	problem, err := b.repo.GetProblem(ctx, uint(problemID))
+	if err != nil {
+		zerolog.Ctx(ctx).Error().Err(err).Uint64("id", problemID).Msg("Failed to get problem for review button")
+		return nil, notFoundError("That problem no longer exists.")
+	}
+	if problem.UserID != interactionUserID(i) {
+		return nil, permissionError("Only the member being reminded can mark this problem reviewed.")
+	}
+
+	if err := b.repo.IncrementReviewCount(ctx, problem.ID); err != nil {
+		return nil, databaseError("Failed to record the review in the database.", err)
+	}
+
+	markButtonsReviewed(i.Message.Components, customID)
+	return updateMessageResponse(i.Message), nil
+}
+
+// handleReviewAllButton records a review of every problem on the reminder that hasn't been marked yet
+func (b *Bot) handleReviewAllButton(ctx context.Context, s DiscordSession, i *discordgo.InteractionCreate) (*discordgo.InteractionResponse, error) {
+	_, userID, _ := strings.Cut(i.MessageComponentData().CustomID, ":")
+	if userID != interactionUserID(i) {
+		return nil, permissionError("Only the member being reminded can mark these problems reviewed.")
+	}
+
+	var marked []string
+	for _, button := range reminderButtons(i.Message.Components) {
+		arg, ok := strings.CutPrefix(button.CustomID, reviewDoneButtonPrefix+":")
+		if !ok || button.Disabled {
+			continue
+		}
+		problemID, err := strconv.ParseUint(arg, 10, 64)
+		if err != nil {
+			continue
+		}
+		if err := b.repo.IncrementReviewCount(ctx, uint(problemID)); err != nil {
+			zerolog.Ctx(ctx).Error().Err(err).Uint64("id", problemID).Msg("Failed to mark problem reviewed")
+			continue
+		}
+		marked = append(marked, button.CustomID)
+	}
+
+	markButtonsReviewed(i.Message.Components, marked...)
+	return updateMessageResponse(i.Message), nil
+}
+
+// markButtonsReviewed turns the buttons with the given custom IDs into disabled check marks, and
+// disables "Mark all reviewed" once no problem buttons are left
+func markButtonsReviewed(components []discordgo.MessageComponent, customIDs ...string) {
+	done := make(map[string]bool, len(customIDs))
+	for _, id := range customIDs {
+		done[id] = true
+	}
+
+	pending := 0
+	var markAll *discordgo.Button
+	for _, button := range reminderButtons(components) {
+		switch {
+		case strings.HasPrefix(button.CustomID, reviewAllButtonPrefix+":"):
+			markAll = button
+		case done[button.CustomID]:
+			button.Disabled = true
+			button.Style = discordgo.SuccessButton
+			button.Label = truncateString("✓ "+button.Label, maxButtonLabel)
+		case !button.Disabled:
+			pending++
+		}
+	}
+	if markAll != nil && pending == 0 {
+		markAll.Disabled = true
+	}
+}
+
+// reminderButtons returns the buttons of a received message so they can be read and updated in place
+func reminderButtons(components []discordgo.MessageComponent) []*discordgo.Button {
+	var buttons []*discordgo.Button
+	for _, component := range components {
+		row, ok := component.(*discordgo.ActionsRow)
+		if !ok {
+			continue
+		}
+		for _, inner := range row.Components {
+			if button, ok := inner.(*discordgo.Button); ok {
+				buttons = append(buttons, button)
+			}
+		}
+	}
+	return buttons
+}
+
+// updateMessageResponse replaces the components of the message a button was clicked on
+func updateMessageResponse(message *discordgo.Message) *discordgo.InteractionResponse {
+	return &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseUpdateMessage,
+		Data: &discordgo.InteractionResponseData{
+			Components: message.Components,
+		},
+	}
+}
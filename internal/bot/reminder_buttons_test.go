@@ -0,0 +1,113 @@
+package bot
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/yugonline/grind_review_bot/config"
+	"github.com/yugonline/grind_review_bot/internal/database"
+)
+
+// receivedReminder returns the reminder for problems as Discord sends it back with a button click,
+// its components decoded into pointers
+func receivedReminder(t *testing.T, problems []*database.ProblemEntry) *discordgo.Message {
+	t.Helper()
+	data, err := json.Marshal(discordgo.MessageSend{Components: reminderComponents(testUserID, problems)})
+	if err != nil {
+		t.Fatalf("failed to encode reminder: %v", err)
+	}
+	var message discordgo.Message
+	if err := json.Unmarshal(data, &message); err != nil {
+		t.Fatalf("failed to decode reminder: %v", err)
+	}
+	return &message
+}
+
+// clickReminder clicks the button with customID on message
+func clickReminder(message *discordgo.Message, customID string) *discordgo.InteractionCreate {
+	i := buttonInteraction(customID)
+	i.Message = message
+	return i
+}
+
+// reviewCount returns the stored review count of the problem with id
+func reviewCount(t *testing.T, bot *Bot, id uint) int {
+	t.Helper()
+	problem, err := bot.repo.GetProblem(context.Background(), id)
+	if err != nil {
+		t.Fatalf("GetProblem: %v", err)
+	}
+	return problem.ReviewCount
+}
+
+func TestReviewDoneButton(t *testing.T) {
+	bot, session := newTestBot(t, config.DiscordConfig{})
+	twoSum := addTestProblem(t, bot, "Two Sum", database.DifficultyEasy)
+	coinChange := addTestProblem(t, bot, "Coin Change", database.DifficultyMedium)
+	message := receivedReminder(t, []*database.ProblemEntry{twoSum, coinChange})
+	buttons := reminderButtons(message.Components)
+	if len(buttons) != 3 {
+		t.Fatalf("reminder has %d buttons, want one per problem and Mark all", len(buttons))
+	}
+
+	resp := dispatch(t, bot, session, clickReminder(message, buttons[1].CustomID))
+	if resp.Type != discordgo.InteractionResponseUpdateMessage {
+		t.Fatalf("response type = %v, want the reminder updated", resp.Type)
+	}
+	if reviewCount(t, bot, coinChange.ID) != 1 || reviewCount(t, bot, twoSum.ID) != 0 {
+		t.Error("the click didn't review exactly the clicked problem")
+	}
+
+	updated := reminderButtons(resp.Data.Components)
+	if !updated[1].Disabled || !strings.HasPrefix(updated[1].Label, "✓ ") {
+		t.Errorf("clicked button = %+v, want a disabled check mark", updated[1])
+	}
+	if updated[0].Disabled || updated[2].Disabled {
+		t.Error("buttons that weren't clicked were disabled")
+	}
+}
+
+func TestReviewDoneButtonOtherUser(t *testing.T) {
+	bot, session := newTestBot(t, config.DiscordConfig{})
+	problem := addTestProblem(t, bot, "Two Sum", database.DifficultyEasy)
+	message := receivedReminder(t, []*database.ProblemEntry{problem})
+
+	i := clickReminder(message, reminderButtons(message.Components)[0].CustomID)
+	i.Member.User.ID = "user-2"
+	resp := dispatch(t, bot, session, i)
+	if resp.Data.Flags&discordgo.MessageFlagsEphemeral == 0 || !strings.Contains(responseText(resp), "Only the member being reminded") {
+		t.Errorf("response = %q, want an ephemeral permission error", responseText(resp))
+	}
+	if reviewCount(t, bot, problem.ID) != 0 {
+		t.Error("another member's click recorded a review")
+	}
+}
+
+func TestReviewAllButton(t *testing.T) {
+	bot, session := newTestBot(t, config.DiscordConfig{})
+	problems := []*database.ProblemEntry{
+		addTestProblem(t, bot, "Two Sum", database.DifficultyEasy),
+		addTestProblem(t, bot, "Coin Change", database.DifficultyMedium),
+		addTestProblem(t, bot, "Word Ladder", database.DifficultyHard),
+	}
+	message := receivedReminder(t, problems)
+
+	// One problem is marked on its own first and isn't reviewed twice
+	resp := dispatch(t, bot, session, clickReminder(message, reminderButtons(message.Components)[0].CustomID))
+	message.Components = resp.Data.Components
+	resp = dispatch(t, bot, session, clickReminder(message, reviewAllButtonPrefix+":"+testUserID))
+
+	for _, p := range problems {
+		if got := reviewCount(t, bot, p.ID); got != 1 {
+			t.Errorf("%s reviewed %d times, want 1", p.ProblemName, got)
+		}
+	}
+	for _, button := range reminderButtons(resp.Data.Components) {
+		if !button.Disabled {
+			t.Errorf("button %q still enabled after Mark all reviewed", button.Label)
+		}
+	}
+}
@@ -0,0 +1,104 @@
+package bot
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/yugonline/grind_review_bot/config"
+	"github.com/yugonline/grind_review_bot/internal/database"
+	"github.com/yugonline/grind_review_bot/internal/metrics"
+)
+
+// failingSendSession is a MockSession whose reminder sends all fail with err
+type failingSendSession struct {
+	*MockSession
+	err   error
+	sends int
+}
+
+// ChannelMessageSendComplex implements DiscordSession
+func (s *failingSendSession) ChannelMessageSendComplex(channelID string, data *discordgo.MessageSend, _ ...discordgo.RequestOption) (*discordgo.Message, error) {
+	s.sends++
+	return nil, s.err
+}
+
+// reminderCounter returns a reminder counter from the default registry, or 0 if it hasn't been
+// incremented. An empty reason reads the unlabelled bot_reminders_sent_total.
+func reminderCounter(t *testing.T, name, reason string) float64 {
+	t.Helper()
+	families, err := prometheus.DefaultGatherer.Gather()
+	if err != nil {
+		t.Fatalf("Gather: %v", err)
+	}
+	for _, family := range families {
+		if family.GetName() != name {
+			continue
+		}
+		for _, metric := range family.GetMetric() {
+			if reason == "" {
+				return metric.GetCounter().GetValue()
+			}
+			for _, label := range metric.GetLabel() {
+				if label.GetName() == "reason" && label.GetValue() == reason {
+					return metric.GetCounter().GetValue()
+				}
+			}
+		}
+	}
+	return 0
+}
+
+func TestReminderFailureMetrics(t *testing.T) {
+	tests := []struct {
+		name   string
+		err    error
+		reason string
+	}{
+		{name: "rate limited", err: &discordgo.RateLimitError{RateLimit: &discordgo.RateLimit{TooManyRequests: &discordgo.TooManyRequests{RetryAfter: time.Second}, URL: "/channels/review-channel/messages"}}, reason: metrics.ReminderFailureRateLimit},
+		{name: "too many requests", err: &discordgo.RESTError{Response: &http.Response{StatusCode: http.StatusTooManyRequests}}, reason: metrics.ReminderFailureRateLimit},
+		{name: "forbidden", err: &discordgo.RESTError{Response: &http.Response{StatusCode: http.StatusForbidden}}, reason: metrics.ReminderFailureForbidden},
+		{name: "other", err: errors.New("connection reset"), reason: metrics.ReminderFailureOther},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			bot, mock := newTestBot(t, config.DiscordConfig{})
+			session := &failingSendSession{MockSession: mock, err: tt.err}
+			bot.session = session
+			scheduler := &Scheduler{bot: bot, config: config.SchedulerConfig{ReviewChannel: "review-channel", LookbackPeriod: 7 * 24 * time.Hour, RetryAttempts: 2, RetryDelay: time.Millisecond}}
+			addTestProblem(t, bot, "Two Sum", database.DifficultyEasy)
+
+			failedBefore := reminderCounter(t, "bot_reminders_failed_total", tt.reason)
+			sentBefore := reminderCounter(t, "bot_reminders_sent_total", "")
+			scheduler.sendDailyReviewReminder(ctx)
+
+			if session.sends != 3 {
+				t.Errorf("tried to send %d times, want the first attempt and 2 retries", session.sends)
+			}
+			if got := reminderCounter(t, "bot_reminders_failed_total", tt.reason) - failedBefore; got != 1 {
+				t.Errorf("failures counted as %s = %v, want 1", tt.reason, got)
+			}
+			if got := reminderCounter(t, "bot_reminders_sent_total", "") - sentBefore; got != 0 {
+				t.Errorf("sent count rose by %v for a failed reminder", got)
+			}
+		})
+	}
+}
+
+func TestReminderSentMetric(t *testing.T) {
+	ctx := context.Background()
+	bot, _ := newTestBot(t, config.DiscordConfig{})
+	scheduler := &Scheduler{bot: bot, config: config.SchedulerConfig{ReviewChannel: "review-channel", LookbackPeriod: 7 * 24 * time.Hour}}
+	addTestProblem(t, bot, "Two Sum", database.DifficultyEasy)
+
+	before := reminderCounter(t, "bot_reminders_sent_total", "")
+	scheduler.sendDailyReviewReminder(ctx)
+	if got := reminderCounter(t, "bot_reminders_sent_total", "") - before; got != 1 {
+		t.Errorf("sent count rose by %v, want 1", got)
+	}
+}
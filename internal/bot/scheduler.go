@@ -2,30 +2,40 @@ package bot
 
 import (
 	"context"
-	"fmt"
-	"strings"
+	"errors"
+	"net/http"
+	"sort"
 	"time"
 
+	"github.com/bwmarrin/discordgo"
 	"github.com/go-co-op/gocron"
 	"github.com/rs/zerolog/log"
 	"github.com/yugonline/grind_review_bot/config"
+	"github.com/yugonline/grind_review_bot/internal/database"
+	"github.com/yugonline/grind_review_bot/internal/metrics"
+	"github.com/yugonline/grind_review_bot/pkg/discord"
 )
 
+// maintenanceTime is the time of day routine maintenance jobs run
+const maintenanceTime = "03:00"
+
 // Scheduler manages the daily review reminders
 type Scheduler struct {
 	cron    *gocron.Scheduler
 	bot     *Bot
 	config  config.SchedulerConfig
+	backup  config.BackupConfig
 	stop    chan bool
 	running bool
 }
 
 // StartScheduler initializes and starts the daily review scheduler
-func StartScheduler(ctx context.Context, b *Bot, cfg config.SchedulerConfig) *Scheduler {
+func StartScheduler(ctx context.Context, b *Bot, cfg config.SchedulerConfig, backup config.BackupConfig) *Scheduler {
 	s := &Scheduler{
 		cron:    gocron.NewScheduler(time.Local),
 		bot:     b,
 		config:  cfg,
+		backup:  backup,
 		stop:    make(chan bool),
 		running: false,
 	}
@@ -35,12 +45,53 @@ func StartScheduler(ctx context.Context, b *Bot, cfg config.SchedulerConfig) *Sc
 		return s
 	}
 
+	// Compact the database at a quiet hour on the first day of every month
+	if _, err := s.cron.Every(1).Month(1).At(maintenanceTime).Do(s.compactDatabase, ctx); err != nil {
+		log.Error().Err(err).Msg("Failed to schedule monthly database compaction")
+	}
+
+	// Sweep tags left unused by edits and deletions once a week
+	if _, err := s.cron.Every(1).Sunday().At(maintenanceTime).Do(s.cleanupOrphanedTags, ctx); err != nil {
+		log.Error().Err(err).Msg("Failed to schedule weekly orphaned tag cleanup")
+	}
+
+	if backup.Enabled {
+		if _, err := s.cron.Cron(backup.Schedule).Do(s.runScheduledBackup, ctx); err != nil {
+			log.Error().Err(err).Str("schedule", backup.Schedule).Msg("Failed to schedule database backups")
+		}
+	}
+
+	b.scheduler.Store(s)
 	s.cron.StartAsync()
 	s.running = true
 	log.Info().Str("review_time", cfg.ReviewTime).Msg("Daily review scheduler started")
 	return s
 }
 
+// compactDatabase runs routine database maintenance
+func (s *Scheduler) compactDatabase(ctx context.Context) {
+	if err := s.bot.repo.Compact(ctx); err != nil {
+		log.Error().Err(err).Msg("Scheduled database compaction failed")
+	}
+}
+
+// cleanupOrphanedTags removes tags no problem uses anymore
+func (s *Scheduler) cleanupOrphanedTags(ctx context.Context) {
+	removed, err := s.bot.repo.CleanupOrphanedTags(ctx)
+	if err != nil {
+		log.Error().Err(err).Msg("Scheduled orphaned tag cleanup failed")
+		return
+	}
+	metrics.RecordOrphanedTagsCleaned(removed)
+}
+
+// runScheduledBackup takes a database backup on the configured schedule
+func (s *Scheduler) runScheduledBackup(ctx context.Context) {
+	if err := s.bot.repo.BackupDatabase(ctx, s.backup.Destination(time.Now())); err != nil {
+		log.Error().Err(err).Msg("Scheduled database backup failed")
+	}
+}
+
 // Stop halts the scheduler
 func (s *Scheduler) Stop() {
 	if s.running {
@@ -53,7 +104,7 @@ func (s *Scheduler) Stop() {
 
 // sendDailyReviewReminder fetches problems needing review and sends a message to Discord
 func (s *Scheduler) sendDailyReviewReminder(ctx context.Context) {
-	if s.config.ReviewChannel == "" {
+	if !s.config.HasReviewChannel() {
 		log.Warn().Msg("Review channel not configured, skipping daily reminder.")
 		return
 	}
@@ -64,53 +115,184 @@ func (s *Scheduler) sendDailyReviewReminder(ctx context.Context) {
 		return
 	}
 
+	// Loaded up front so the loop doesn't query once per user
+	preferences, err := s.bot.repo.GetAllUserPreferences(ctx)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to load user preferences for review reminders")
+		return
+	}
+	settings, err := s.bot.repo.GetAllUserSettings(ctx)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to load user settings for review reminders")
+		return
+	}
+
+	locale := s.reminderLocale()
 	for _, userID := range users {
-		problems, err := s.bot.repo.ListProblemsForReview(ctx, userID, s.config.LookbackPeriod)
+		if prefs, ok := preferences[userID]; ok && !prefs.RemindersEnabled {
+			log.Debug().Str("user_id", userID).Msg("Review reminders disabled, skipping user")
+			continue
+		}
+
+		var scopes []database.ProblemScope
+		if userSettings, ok := settings[userID]; ok {
+			scopes = append(scopes, userSettings.ReminderScope())
+		}
+
+		problems, err := s.bot.repo.ListProblemsForReview(ctx, userID, s.config.LookbackPeriod, scopes...)
 		if err != nil {
 			log.Error().Err(err).Str("user_id", userID).Msg("Failed to list problems for review")
 			continue
 		}
 
 		if len(problems) > 0 {
+			if s.config.PrioritizeByDifficulty {
+				sortByReviewUrgency(problems)
+			}
+
 			user, err := s.bot.session.User(userID)
 			if err != nil {
 				log.Error().Err(err).Str("user_id", userID).Msg("Failed to get Discord user")
+				metrics.RecordReminderFailed(reminderFailureReason(err))
 				continue
 			}
 
-			var sb strings.Builder
-			sb.WriteString(fmt.Sprintf("Hey %s! Here are some problems you might want to review today:\n", user.Mention()))
-			for _, p := range problems {
-				sb.WriteString(fmt.Sprintf("- %s (Solved: %s)", p.ProblemName, p.SolvedAt.Format("2006-01-02")))
-				if p.Link != "" {
-					sb.WriteString(fmt.Sprintf(" - <%s>", p.Link))
+			channels, byChannel := s.groupByReviewChannel(problems)
+			for _, channelID := range channels {
+				if channelID == "" {
+					log.Warn().Str("user_id", userID).Int("problem_count", len(byChannel[channelID])).Msg("No review channel configured for category, skipping problems")
+					continue
 				}
-				sb.WriteString("\n")
+				s.sendReviewReminder(user, channelID, locale, byChannel[channelID])
 			}
-			sb.WriteString("\nRemember, consistent review helps reinforce your understanding!")
+		}
+	}
+}
 
-			_, err = s.bot.session.ChannelMessageSend(s.config.ReviewChannel, sb.String())
-			if err != nil {
-				log.Error().Err(err).Str("channel_id", s.config.ReviewChannel).Str("user_id", userID).Msg("Failed to send review reminder")
-				// Implement retry logic if needed
-				for i := 0; i < s.config.RetryAttempts; i++ {
-					time.Sleep(s.config.RetryDelay)
-					_, retryErr := s.bot.session.ChannelMessageSend(s.config.ReviewChannel, sb.String())
-					if retryErr == nil {
-						log.Info().Str("channel_id", s.config.ReviewChannel).Str("user_id", userID).Int("attempt", i+1).Msg("Successfully sent review reminder after retry")
-						break
-					}
-					log.Error().Err(retryErr).Str("channel_id", s.config.ReviewChannel).Str("user_id", userID).Int("attempt", i+1).Msg("Failed to send review reminder (retry)")
-				}
-			} else {
-				log.Info().Str("channel_id", s.config.ReviewChannel).Str("user_id", userID).Int("problem_count", len(problems)).Msg("Sent daily review reminder")
-				// Update last reviewed at for these problems to avoid repeated reminders too soon
-				for _, p := range problems {
-					if err := s.bot.repo.IncrementReviewCount(ctx, p.ID); err != nil {
-						log.Error().Err(err).Uint("problem_id", p.ID).Msg("Failed to update review count")
-					}
-				}
-			}
+// reminderLocale returns the configured guild's preferred locale. Reminders are posted to shared
+// channels, so they follow the server's language rather than any one member's; without a guild
+// or if it can't be fetched, dates fall back to ISO.
+func (s *Scheduler) reminderLocale() discordgo.Locale {
+	if s.bot.cfg.GuildID == "" {
+		return discordgo.Unknown
+	}
+	guild, err := s.bot.session.Guild(s.bot.cfg.GuildID)
+	if err != nil {
+		log.Warn().Err(err).Str("guild_id", s.bot.cfg.GuildID).Msg("Failed to get guild locale for review reminders")
+		return discordgo.Unknown
+	}
+	return discordgo.Locale(guild.PreferredLocale)
+}
+
+// groupByReviewChannel splits problems by the channel their category's reminders go to, keeping
+// their order. Channels are returned in the order their first problem appears.
+func (s *Scheduler) groupByReviewChannel(problems []*database.ProblemEntry) ([]string, map[string][]*database.ProblemEntry) {
+	var channels []string
+	byChannel := make(map[string][]*database.ProblemEntry)
+	for _, p := range problems {
+		channelID := s.config.ReviewChannelFor(p.Category)
+		if _, ok := byChannel[channelID]; !ok {
+			channels = append(channels, channelID)
+		}
+		byChannel[channelID] = append(byChannel[channelID], p)
+	}
+	return channels, byChannel
+}
+
+// sendReviewReminder posts a user's reminder for problems to channelID, writing dates for locale
+func (s *Scheduler) sendReviewReminder(user *discordgo.User, channelID string, locale discordgo.Locale, problems []*database.ProblemEntry) {
+	// Cap the list so the reminder stays within Discord's message limit
+	remaining := 0
+	if limit := s.config.MaxProblemsPerReminder; limit > 0 && len(problems) > limit {
+		remaining = len(problems) - limit
+		problems = problems[:limit]
+	}
+
+	content, err := renderReminder(s.bot.cfg.MessageTemplates, locale, user.Mention(), problems, remaining)
+	if err != nil {
+		log.Error().Err(err).Str("user_id", user.ID).Msg("Failed to render review reminder")
+		metrics.RecordReminderFailed(metrics.ReminderFailureOther)
+		return
+	}
+
+	// Long reminders are split so each part fits in a single Discord message. The buttons for
+	// marking problems reviewed go on the last part; problems stay in rotation until marked.
+	parts := discord.SplitMessage(content, discord.MaxMessageLength)
+	for idx, part := range parts {
+		message := &discordgo.MessageSend{Content: part}
+		if idx == len(parts)-1 {
+			message.Components = reminderComponents(user.ID, problems)
+		}
+		if err := s.sendWithRetry(channelID, user.ID, message); err != nil {
+			metrics.RecordReminderFailed(reminderFailureReason(err))
+			return
+		}
+	}
+
+	metrics.RecordReminderSent()
+	log.Info().Str("channel_id", channelID).Str("user_id", user.ID).Int("problem_count", len(problems)).Msg("Sent daily review reminder")
+}
+
+// sendWithRetry sends a reminder message to a review channel, retrying on failure
+func (s *Scheduler) sendWithRetry(channelID, userID string, message *discordgo.MessageSend) error {
+	_, err := s.bot.session.ChannelMessageSendComplex(channelID, message)
+	if err == nil {
+		return nil
+	}
+	log.Error().Err(err).Str("channel_id", channelID).Str("user_id", userID).Msg("Failed to send review reminder")
+
+	for i := 0; i < s.config.RetryAttempts; i++ {
+		time.Sleep(s.config.RetryDelay)
+		_, err = s.bot.session.ChannelMessageSendComplex(channelID, message)
+		if err == nil {
+			log.Info().Str("channel_id", channelID).Str("user_id", userID).Int("attempt", i+1).Msg("Successfully sent review reminder after retry")
+			return nil
 		}
+		log.Error().Err(err).Str("channel_id", channelID).Str("user_id", userID).Int("attempt", i+1).Msg("Failed to send review reminder (retry)")
 	}
-}
\ No newline at end of file
+	return err
+}
+
+// reminderFailureReason classifies a failed reminder send for the bot_reminders_failed_total metric
+func reminderFailureReason(err error) string {
+	var rateLimitErr *discordgo.RateLimitError
+	if errors.As(err, &rateLimitErr) {
+		return metrics.ReminderFailureRateLimit
+	}
+
+	var restErr *discordgo.RESTError
+	if errors.As(err, &restErr) && restErr.Response != nil {
+		switch restErr.Response.StatusCode {
+		case http.StatusTooManyRequests:
+			return metrics.ReminderFailureRateLimit
+		case http.StatusForbidden:
+			return metrics.ReminderFailureForbidden
+		}
+	}
+	return metrics.ReminderFailureOther
+}
+
+// difficultyRank orders difficulties from hardest to easiest
+var difficultyRank = map[string]int{
+	database.DifficultyHard:   0,
+	database.DifficultyMedium: 1,
+	database.DifficultyEasy:   2,
+}
+
+// statusRank orders statuses from least to most confidently solved
+var statusRank = map[string]int{
+	database.StatusStuck:      0,
+	database.StatusNeededHint: 1,
+	database.StatusSolved:     2,
+}
+
+// sortByReviewUrgency orders problems so the hardest-to-remember ones come first:
+// harder difficulties first, as the user perceived them, then problems the user struggled with
+func sortByReviewUrgency(problems []*database.ProblemEntry) {
+	sort.SliceStable(problems, func(i, j int) bool {
+		if di, dj := difficultyRank[problems[i].EffectiveDifficulty()], difficultyRank[problems[j].EffectiveDifficulty()]; di != dj {
+			return di < dj
+		}
+		return statusRank[problems[i].Status] < statusRank[problems[j].Status]
+	})
+}
@@ -0,0 +1,351 @@
+package bot
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/yugonline/grind_review_bot/config"
+	"github.com/yugonline/grind_review_bot/internal/database"
+)
+
+// mockRepository stands in for the database in scheduler tests. Methods it doesn't override
+// panic through the nil embedded interface, so a test fails loudly if the scheduler reaches
+// for anything unexpected.
+type mockRepository struct {
+	database.RepositoryInterface
+
+	users       []string
+	preferences map[string]*database.UserPreferences
+	settings    map[string]*database.UserSettings
+	due         map[string][]*database.ProblemEntry // user ID -> problems due for review
+	calls       []string                            // Methods called, in order
+	reviewed    []uint                              // Problem IDs passed to IncrementReviewCount
+}
+
+// ListAllUsers implements database.RepositoryInterface
+func (m *mockRepository) ListAllUsers(ctx context.Context) ([]string, error) {
+	m.calls = append(m.calls, "ListAllUsers")
+	return m.users, nil
+}
+
+// GetAllUserPreferences implements database.RepositoryInterface
+func (m *mockRepository) GetAllUserPreferences(ctx context.Context) (map[string]*database.UserPreferences, error) {
+	m.calls = append(m.calls, "GetAllUserPreferences")
+	return m.preferences, nil
+}
+
+// GetAllUserSettings implements database.RepositoryInterface
+func (m *mockRepository) GetAllUserSettings(ctx context.Context) (map[string]*database.UserSettings, error) {
+	m.calls = append(m.calls, "GetAllUserSettings")
+	return m.settings, nil
+}
+
+// ListProblemsForReview implements database.RepositoryInterface. Scopes are ignored; due holds
+// the problems after filtering.
+func (m *mockRepository) ListProblemsForReview(ctx context.Context, userID string, lookbackPeriod time.Duration, scopes ...database.ProblemScope) ([]*database.ProblemEntry, error) {
+	m.calls = append(m.calls, "ListProblemsForReview")
+	return m.due[userID], nil
+}
+
+// IncrementReviewCount implements database.RepositoryInterface
+func (m *mockRepository) IncrementReviewCount(ctx context.Context, problemID uint) error {
+	m.calls = append(m.calls, "IncrementReviewCount")
+	m.reviewed = append(m.reviewed, problemID)
+	return nil
+}
+
+func TestSendDailyReviewReminder(t *testing.T) {
+	bot, session := newTestBot(t, config.DiscordConfig{})
+	solvedAt := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	repo := &mockRepository{
+		users: []string{"user-1", "user-2"},
+		due: map[string][]*database.ProblemEntry{
+			"user-1": {
+				{ID: 1, UserID: "user-1", ProblemName: "Two Sum", Difficulty: database.DifficultyEasy, Status: database.StatusSolved, SolvedAt: solvedAt},
+				{ID: 2, UserID: "user-1", ProblemName: "Coin Change", Difficulty: database.DifficultyMedium, Status: database.StatusSolved, SolvedAt: solvedAt},
+				{ID: 3, UserID: "user-1", ProblemName: "Word Ladder", Difficulty: database.DifficultyHard, Status: database.StatusSolved, SolvedAt: solvedAt},
+			},
+		},
+	}
+	bot.repo = repo
+	scheduler := &Scheduler{bot: bot, config: config.SchedulerConfig{ReviewChannel: "review-channel", LookbackPeriod: 7 * 24 * time.Hour}}
+
+	scheduler.sendDailyReviewReminder(context.Background())
+
+	// Only user-1 has anything due
+	sent := session.Calls("ChannelMessageSendComplex")
+	if len(sent) != 1 {
+		t.Fatalf("sent %d reminders, want 1", len(sent))
+	}
+	channelID, message := sent[0].Args[0].(string), sent[0].Args[1].(*discordgo.MessageSend)
+	content := message.Content
+	if channelID != "review-channel" {
+		t.Errorf("reminder sent to %q, want review-channel", channelID)
+	}
+	if !strings.Contains(content, "<@user-1>") {
+		t.Errorf("reminder %q doesn't mention user-1", content)
+	}
+	for _, p := range repo.due["user-1"] {
+		if !strings.Contains(content, p.ProblemName) {
+			t.Errorf("reminder doesn't list %s", p.ProblemName)
+		}
+	}
+	// One row of problem buttons plus the "Mark all reviewed" row
+	if len(message.Components) != 2 {
+		t.Errorf("reminder has %d component rows, want 2", len(message.Components))
+	}
+	// Problems stay due until someone presses a button
+	if len(repo.reviewed) != 0 {
+		t.Errorf("review counts incremented for %v by sending the reminder", repo.reviewed)
+	}
+}
+
+func TestSendDailyReviewReminderSkipsDisabledUsers(t *testing.T) {
+	bot, session := newTestBot(t, config.DiscordConfig{})
+	solvedAt := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	repo := &mockRepository{
+		users: []string{"user-1", "user-2"},
+		preferences: map[string]*database.UserPreferences{
+			"user-1": {UserID: "user-1", RemindersEnabled: false},
+		},
+		due: map[string][]*database.ProblemEntry{
+			"user-1": {{ID: 1, UserID: "user-1", ProblemName: "Two Sum", Difficulty: database.DifficultyEasy, Status: database.StatusSolved, SolvedAt: solvedAt}},
+			"user-2": {{ID: 2, UserID: "user-2", ProblemName: "Coin Change", Difficulty: database.DifficultyMedium, Status: database.StatusSolved, SolvedAt: solvedAt}},
+		},
+	}
+	bot.repo = repo
+	scheduler := &Scheduler{bot: bot, config: config.SchedulerConfig{ReviewChannel: "review-channel", LookbackPeriod: 7 * 24 * time.Hour}}
+
+	scheduler.sendDailyReviewReminder(context.Background())
+
+	// user-2 has no saved preferences, so keeps the default of getting reminders
+	sent := session.Calls("ChannelMessageSendComplex")
+	if len(sent) != 1 {
+		t.Fatalf("sent %d reminders, want 1", len(sent))
+	}
+	if content := sent[0].Args[1].(*discordgo.MessageSend).Content; !strings.Contains(content, "<@user-2>") || strings.Contains(content, "<@user-1>") {
+		t.Errorf("reminder %q, want one for user-2 only", content)
+	}
+}
+
+func TestReminderRoutesByCategory(t *testing.T) {
+	ctx := context.Background()
+	bot, session := newTestBot(t, config.DiscordConfig{})
+	scheduler := &Scheduler{bot: bot, config: config.SchedulerConfig{
+		ReviewChannel:        "review-channel",
+		DefaultReviewChannel: "default-channel",
+		ReviewChannels:       map[string]string{"dynamic programming": "dp-channel", "trees": "trees-channel"},
+		LookbackPeriod:       7 * 24 * time.Hour,
+	}}
+	for name, category := range map[string]string{"Coin Change": "Dynamic Programming", "Climbing Stairs": "dynamic programming", "Binary Tree Level Order Traversal": "Trees", "Two Sum": "Array"} {
+		entry := &database.ProblemEntry{UserID: testUserID, ProblemName: name, Difficulty: database.DifficultyMedium, Category: category, Status: database.StatusSolved, SolvedAt: time.Date(2024, time.January, 1, 12, 0, 0, 0, time.UTC)}
+		if err := bot.repo.CreateProblem(ctx, entry); err != nil {
+			t.Fatalf("CreateProblem: %v", err)
+		}
+	}
+
+	scheduler.sendDailyReviewReminder(ctx)
+
+	want := map[string][]string{
+		"dp-channel":      {"Coin Change", "Climbing Stairs"},
+		"trees-channel":   {"Binary Tree Level Order Traversal"},
+		"default-channel": {"Two Sum"},
+	}
+	sent := session.Calls("ChannelMessageSendComplex")
+	if len(sent) != len(want) {
+		t.Fatalf("sent %d reminders, want one to each of %d channels", len(sent), len(want))
+	}
+	for _, call := range sent {
+		channelID, content := call.Args[0].(string), call.Args[1].(*discordgo.MessageSend).Content
+		names, ok := want[channelID]
+		if !ok {
+			t.Errorf("reminder sent to %q, want only the category and default channels", channelID)
+			continue
+		}
+		delete(want, channelID)
+		for _, name := range names {
+			if !strings.Contains(content, name) {
+				t.Errorf("reminder in %s = %q, want it to list %s", channelID, content, name)
+			}
+		}
+		if !strings.Contains(content, "<@"+testUserID+">") {
+			t.Errorf("reminder in %s doesn't mention %s", channelID, testUserID)
+		}
+	}
+	for channelID := range want {
+		t.Errorf("no reminder sent to %s", channelID)
+	}
+}
+
+func TestReminderRespectsFilters(t *testing.T) {
+	ctx := context.Background()
+	bot, session := newTestBot(t, config.DiscordConfig{})
+	scheduler := &Scheduler{bot: bot, config: config.SchedulerConfig{ReviewChannel: "review-channel", LookbackPeriod: 7 * 24 * time.Hour}}
+	addTestProblem(t, bot, "Two Sum", database.DifficultyEasy)
+	addTestProblem(t, bot, "Word Ladder", database.DifficultyHard)
+
+	resp := dispatch(t, bot, session, commandInteraction("reminders", stringOption("difficulty", database.DifficultyHard)))
+	if !strings.Contains(resp.Data.Content, "**Hard** difficulty") {
+		t.Fatalf("response = %q, want the Hard filter confirmed", resp.Data.Content)
+	}
+
+	scheduler.sendDailyReviewReminder(ctx)
+	sent := session.Calls("ChannelMessageSendComplex")
+	if len(sent) != 1 {
+		t.Fatalf("sent %d reminders, want 1", len(sent))
+	}
+	if content := sent[0].Args[1].(*discordgo.MessageSend).Content; !strings.Contains(content, "Word Ladder") || strings.Contains(content, "Two Sum") {
+		t.Errorf("reminder = %q, want only the Hard problem", content)
+	}
+
+	// Filtering to a category with nothing due sends no reminder at all
+	dispatch(t, bot, session, commandInteraction("reminders", stringOption("category", "Graph")))
+	scheduler.sendDailyReviewReminder(ctx)
+	if sent := session.Calls("ChannelMessageSendComplex"); len(sent) != 1 {
+		t.Errorf("sent %d reminders in total, want none for an empty filter", len(sent))
+	}
+
+	// Clearing the filters goes back to every due problem
+	dispatch(t, bot, session, commandInteraction("reminders", stringOption("difficulty", reminderFilterAny), stringOption("category", reminderFilterAny)))
+	scheduler.sendDailyReviewReminder(ctx)
+	sent = session.Calls("ChannelMessageSendComplex")
+	if content := sent[len(sent)-1].Args[1].(*discordgo.MessageSend).Content; !strings.Contains(content, "Word Ladder") || !strings.Contains(content, "Two Sum") {
+		t.Errorf("reminder = %q, want both problems without filters", content)
+	}
+}
+
+func TestSendDailyReviewReminderWithoutChannel(t *testing.T) {
+	bot, session := newTestBot(t, config.DiscordConfig{})
+	repo := &mockRepository{users: []string{"user-1"}}
+	bot.repo = repo
+	scheduler := &Scheduler{bot: bot, config: config.SchedulerConfig{LookbackPeriod: 7 * 24 * time.Hour}}
+
+	scheduler.sendDailyReviewReminder(context.Background())
+
+	if len(repo.calls) != 0 {
+		t.Errorf("called %v with no review channel configured", repo.calls)
+	}
+	if sent := session.Calls("ChannelMessageSendComplex"); len(sent) != 0 {
+		t.Errorf("sent %d reminders with no review channel configured", len(sent))
+	}
+}
+
+func TestReminderStaysUnderMessageLimit(t *testing.T) {
+	ctx := context.Background()
+	bot, session := newTestBot(t, config.DiscordConfig{})
+	scheduler := &Scheduler{bot: bot, config: config.SchedulerConfig{ReviewChannel: "review-channel", LookbackPeriod: 7 * 24 * time.Hour, MaxProblemsPerReminder: 5}}
+
+	// Enough long-named problems that listing them all would overflow a message many times
+	for idx := range 300 {
+		addTestProblem(t, bot, fmt.Sprintf("%03d %s", idx, strings.Repeat("Longest Palindromic Substring ", 3)), database.DifficultyMedium)
+	}
+
+	scheduler.sendDailyReviewReminder(ctx)
+
+	sent := session.Calls("ChannelMessageSendComplex")
+	if len(sent) != 1 {
+		t.Fatalf("sent %d messages, want the capped reminder in 1", len(sent))
+	}
+	content := sent[0].Args[1].(*discordgo.MessageSend).Content
+	if n := utf8.RuneCountInString(content); n > 1900 {
+		t.Errorf("reminder is %d characters, want at most 1900", n)
+	}
+	if !strings.Contains(content, "and 295 more") {
+		t.Errorf("reminder %q doesn't say how many problems were left out", content)
+	}
+}
+
+func TestReminderPrioritizesByDifficulty(t *testing.T) {
+	ctx := context.Background()
+	bot, session := newTestBot(t, config.DiscordConfig{})
+	scheduler := &Scheduler{bot: bot, config: config.SchedulerConfig{ReviewChannel: "review-channel", LookbackPeriod: 7 * 24 * time.Hour, MaxProblemsPerReminder: 2, PrioritizeByDifficulty: true}}
+	addTestProblem(t, bot, "Two Sum", database.DifficultyEasy)
+	addTestProblem(t, bot, "Coin Change", database.DifficultyMedium)
+	addTestProblem(t, bot, "Word Ladder", database.DifficultyHard)
+
+	scheduler.sendDailyReviewReminder(ctx)
+
+	sent := session.Calls("ChannelMessageSendComplex")
+	if len(sent) != 1 {
+		t.Fatalf("sent %d reminders, want 1", len(sent))
+	}
+	content := sent[0].Args[1].(*discordgo.MessageSend).Content
+	hard, medium := strings.Index(content, "Word Ladder"), strings.Index(content, "Coin Change")
+	if hard < 0 || medium < 0 || hard > medium {
+		t.Errorf("reminder %q, want Word Ladder listed before Coin Change", content)
+	}
+	if strings.Contains(content, "Two Sum") {
+		t.Errorf("reminder %q lists the easiest problem past the cap", content)
+	}
+}
+
+func TestReminderPrioritizesByPerceivedDifficulty(t *testing.T) {
+	ctx := context.Background()
+	bot, session := newTestBot(t, config.DiscordConfig{})
+	scheduler := &Scheduler{bot: bot, config: config.SchedulerConfig{ReviewChannel: "review-channel", LookbackPeriod: 7 * 24 * time.Hour, MaxProblemsPerReminder: 1, PrioritizeByDifficulty: true}}
+
+	// Two Sum is officially Easy but felt Hard, so it outranks the officially Hard Word Ladder that felt Medium
+	feltHard := addTestProblem(t, bot, "Two Sum", database.DifficultyEasy)
+	feltMedium := addTestProblem(t, bot, "Word Ladder", database.DifficultyHard)
+	for problem, perceived := range map[*database.ProblemEntry]string{feltHard: database.DifficultyHard, feltMedium: database.DifficultyMedium} {
+		problem.PerceivedDifficulty = perceived
+		if err := bot.repo.UpdateProblem(ctx, problem); err != nil {
+			t.Fatalf("UpdateProblem: %v", err)
+		}
+	}
+
+	scheduler.sendDailyReviewReminder(ctx)
+
+	sent := session.Calls("ChannelMessageSendComplex")
+	if len(sent) != 1 {
+		t.Fatalf("sent %d reminders, want 1", len(sent))
+	}
+	if content := sent[0].Args[1].(*discordgo.MessageSend).Content; !strings.Contains(content, "Two Sum") || strings.Contains(content, "Word Ladder") {
+		t.Errorf("reminder %q, want the problem that felt Hard ahead of the one that felt Medium", content)
+	}
+}
+
+func TestReminderSkipsArchivedProblems(t *testing.T) {
+	ctx := context.Background()
+	bot, session := newTestBot(t, config.DiscordConfig{})
+	scheduler := &Scheduler{bot: bot, config: config.SchedulerConfig{ReviewChannel: "review-channel", LookbackPeriod: 7 * 24 * time.Hour}}
+	addTestProblem(t, bot, "Two Sum", database.DifficultyEasy)
+	archived := addTestProblem(t, bot, "Coin Change", database.DifficultyMedium)
+
+	resp := dispatch(t, bot, session, commandInteraction("archive", intOption("id", int(archived.ID))))
+	if !strings.Contains(resp.Data.Content, "Archived problem 'Coin Change'") {
+		t.Fatalf("response = %q, want the problem archived", resp.Data.Content)
+	}
+
+	scheduler.sendDailyReviewReminder(ctx)
+	sent := session.Calls("ChannelMessageSendComplex")
+	if len(sent) != 1 {
+		t.Fatalf("sent %d reminders, want 1", len(sent))
+	}
+	if message := sent[0].Args[1].(*discordgo.MessageSend); !strings.Contains(message.Content, "Two Sum") || strings.Contains(message.Content, "Coin Change") {
+		t.Errorf("reminder = %q, want Two Sum without the archived Coin Change", message.Content)
+	}
+
+	// /list hides the archived problem unless asked for it
+	resp = dispatch(t, bot, session, commandInteraction("list"))
+	if strings.Contains(resp.Data.Content, "Coin Change") {
+		t.Error("/list shows an archived problem by default")
+	}
+	resp = dispatch(t, bot, session, commandInteraction("list", boolOption("include_archived", true)))
+	if !strings.Contains(resp.Data.Content, "Coin Change") {
+		t.Error("/list include_archived:true leaves out the archived problem")
+	}
+
+	// Once unarchived it's due again
+	dispatch(t, bot, session, commandInteraction("unarchive", intOption("id", int(archived.ID))))
+	scheduler.sendDailyReviewReminder(ctx)
+	sent = session.Calls("ChannelMessageSendComplex")
+	if message := sent[len(sent)-1].Args[1].(*discordgo.MessageSend); !strings.Contains(message.Content, "Coin Change") {
+		t.Errorf("reminder = %q, want the unarchived Coin Change", message.Content)
+	}
+}
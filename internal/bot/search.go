@@ -0,0 +1,141 @@
+package bot
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/yugonline/grind_review_bot/internal/database"
+	"github.com/yugonline/grind_review_bot/pkg/discord"
+)
+
+// Search result layout
+const (
+	defaultSearchLimit = 10
+	snippetRadius      = 60
+)
+
+// handleSearchCommand searches the user's problem names and notes, showing where each note matched
+func (b *Bot) handleSearchCommand(ctx context.Context, s DiscordSession, i *discordgo.InteractionCreate) (*discordgo.InteractionResponse, error) {
+	options := i.ApplicationCommandData().Options
+	optionMap := make(map[string]*discordgo.ApplicationCommandInteractionDataOption, len(options))
+	for _, opt := range options {
+		optionMap[opt.Name] = opt
+	}
+
+	query := strings.TrimSpace(optionMap["query"].StringValue())
+	if query == "" {
+		return nil, validationError("Please enter something to search for.")
+	}
+
+	limit := defaultSearchLimit
+	if limitOpt, ok := optionMap["limit"]; ok {
+		limit = int(limitOpt.IntValue())
+	}
+
+	problems, err := b.repo.FTSSearch(ctx, interactionUserID(i), query, limit)
+	if err != nil {
+		return nil, databaseError("Failed to search your problems.", err)
+	}
+
+	if len(problems) == 0 {
+		return messageResponse(fmt.Sprintf("No problems match \"%s\".", query)), nil
+	}
+
+	lines := make([]string, len(problems))
+	for idx, p := range problems {
+		lines[idx] = searchResultLine(p, query)
+	}
+	return messageResponse(searchResultsMessage(fmt.Sprintf("Problems matching \"%s\":\n", query), lines)), nil
+}
+
+// searchResultsMessage joins the header and as many whole result lines as fit in one message,
+// noting how many were left out
+func searchResultsMessage(header string, lines []string) string {
+	var sb strings.Builder
+	sb.WriteString(header)
+	for idx, line := range lines {
+		// Keep room to say how many of the remaining lines were left out
+		reserve := 0
+		if remaining := len(lines) - idx - 1; remaining > 0 {
+			reserve = len(moreResultsLine(remaining))
+		}
+		if sb.Len()+len(line)+reserve > discord.MaxMessageLength {
+			sb.WriteString(moreResultsLine(len(lines) - idx))
+			break
+		}
+		sb.WriteString(line)
+	}
+	return sb.String()
+}
+
+// moreResultsLine notes how many search results didn't fit in the message
+func moreResultsLine(count int) string {
+	return fmt.Sprintf("…and %d more", count)
+}
+
+// searchResultLine formats one search result with a snippet of its notes, if it has any
+func searchResultLine(p *database.ProblemEntry, query string) string {
+	line := fmt.Sprintf("- **ID %d** %s (%s)\n", p.ID, p.ProblemName, p.Difficulty)
+	if snippet := noteSnippet(p.Notes, query, snippetRadius); snippet != "" {
+		line += "  > " + snippet + "\n"
+	}
+	return line
+}
+
+// noteSnippet returns the part of notes around the first match of any query term, with the match
+// in bold and up to radius bytes of context on each side. Formatting in the notes is escaped. When no term matches, as when only the
+// problem name did, it returns the start of the notes instead.
+func noteSnippet(notes, query string, radius int) string {
+	terms := strings.Fields(query)
+	if notes == "" || len(terms) == 0 {
+		return ""
+	}
+
+	quoted := make([]string, len(terms))
+	for idx, term := range terms {
+		quoted[idx] = regexp.QuoteMeta(term)
+	}
+	match := regexp.MustCompile("(?i)" + strings.Join(quoted, "|")).FindStringIndex(notes)
+	if match == nil {
+		end := runeStart(notes, min(2*radius, len(notes)))
+		if end < len(notes) {
+			return discord.EscapeMarkdown(strings.TrimSpace(collapseSpaces(notes[:end]))) + "…"
+		}
+		return discord.EscapeMarkdown(strings.TrimSpace(collapseSpaces(notes)))
+	}
+
+	start := runeStart(notes, max(match[0]-radius, 0))
+	end := runeStart(notes, min(match[1]+radius, len(notes)))
+
+	var sb strings.Builder
+	if start > 0 {
+		sb.WriteString("…")
+	}
+	sb.WriteString(discord.EscapeMarkdown(collapseSpaces(notes[start:match[0]])))
+	sb.WriteString("**" + discord.EscapeMarkdown(notes[match[0]:match[1]]) + "**")
+	sb.WriteString(discord.EscapeMarkdown(collapseSpaces(notes[match[1]:end])))
+	if end < len(notes) {
+		sb.WriteString("…")
+	}
+	return sb.String()
+}
+
+// runeStart moves offset back to the start of the UTF-8 character it falls in
+func runeStart(text string, offset int) int {
+	for offset > 0 && offset < len(text) && !utf8.RuneStart(text[offset]) {
+		offset--
+	}
+	return offset
+}
+
+// whitespaceRun matches runs of whitespace, including newlines
+var whitespaceRun = regexp.MustCompile(`\s+`)
+
+// collapseSpaces replaces runs of whitespace with single spaces so a snippet stays on one quoted line
+func collapseSpaces(text string) string {
+	return whitespaceRun.ReplaceAllString(text, " ")
+}
@@ -0,0 +1,77 @@
+package bot
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/yugonline/grind_review_bot/pkg/discord"
+)
+
+func TestSearchResultsMessageDropsWholeLines(t *testing.T) {
+	var lines []string
+	for idx := range 40 {
+		lines = append(lines, fmt.Sprintf("- **ID %d** Problem %d (Medium)\n  > %s **héllo** %s\n", idx, idx, strings.Repeat("ü", 20), strings.Repeat("é", 10)))
+	}
+
+	message := searchResultsMessage("Problems matching \"héllo\":\n", lines)
+	if len(message) > discord.MaxMessageLength {
+		t.Fatalf("message is %d bytes, over the %d limit", len(message), discord.MaxMessageLength)
+	}
+	if !utf8.ValidString(message) {
+		t.Error("message splits a UTF-8 character")
+	}
+	if strings.Count(message, "**")%2 != 0 {
+		t.Error("message leaves bold markers unbalanced")
+	}
+
+	kept := strings.Count(message, "- **ID")
+	if want := fmt.Sprintf("…and %d more", len(lines)-kept); !strings.HasSuffix(message, want) {
+		t.Errorf("message ends %q, want %q", message[len(message)-20:], want)
+	}
+}
+
+func TestSearchResultsMessageFits(t *testing.T) {
+	lines := []string{"- **ID 1** Two Sum (Easy)\n", "- **ID 2** Coin Change (Medium)\n"}
+	if got, want := searchResultsMessage("Results:\n", lines), "Results:\n"+lines[0]+lines[1]; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestNoteSnippetEscapesMarkdown(t *testing.T) {
+	snippet := noteSnippet("use a **max heap** of size_k, not `sort`", "heap", snippetRadius)
+	want := `use a \*\*max **heap**\*\* of size\_k, not \` + "`sort\\`"
+	if snippet != want {
+		t.Errorf("noteSnippet = %q, want %q", snippet, want)
+	}
+}
+
+func TestNoteSnippet(t *testing.T) {
+	notes := "Binary search on the answer, then check feasibility greedily for each candidate value."
+	tests := []struct {
+		name  string
+		query string
+		want  string
+	}{
+		{name: "match at the start", query: "binary", want: "**Binary** search on…"},
+		{name: "match in the middle", query: "CHECK", want: "…wer, then **check** feasibili…"},
+		{name: "match at the end", query: "value", want: "…candidate **value**."},
+		{name: "first match of several terms", query: "candidate feasibility", want: "…hen check **feasibility** greedily …"},
+		{name: "no match falls back to the start", query: "heap", want: "Binary search on the…"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := noteSnippet(notes, tt.query, 10); got != tt.want {
+				t.Errorf("noteSnippet(%q) = %q, want %q", tt.query, got, tt.want)
+			}
+		})
+	}
+
+	if got := noteSnippet("", "heap", 10); got != "" {
+		t.Errorf("noteSnippet of empty notes = %q, want none", got)
+	}
+	if got := noteSnippet("line one\n\n  line two", "two", 40); got != "line one line **two**" {
+		t.Errorf("noteSnippet = %q, want the line breaks collapsed", got)
+	}
+}
@@ -0,0 +1,206 @@
+package bot
+
+import (
+	"sync"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// MockCall records the arguments of one call made on a MockSession
+type MockCall struct {
+	Method string
+	Args   []interface{}
+}
+
+// MockSession is an in-memory DiscordSession that records every call instead of talking to Discord.
+// Users, Members, Guilds and Channels are looked up by ID; unknown IDs resolve to a stub with just the ID.
+// Messages holds the history ChannelMessages returns for each channel.
+type MockSession struct {
+	Users    sync.Map // user ID -> *discordgo.User
+	Members  sync.Map // user ID -> *discordgo.Member
+	Guilds   sync.Map // guild ID -> *discordgo.Guild
+	Channels sync.Map // channel ID -> *discordgo.Channel
+	Commands sync.Map // command ID -> *discordgo.ApplicationCommand
+	Messages sync.Map // channel ID -> []*discordgo.Message, newest first
+
+	calls sync.Map // method name -> *mockCallLog
+}
+
+// mockCallLog holds the calls made to one method
+type mockCallLog struct {
+	mu    sync.Mutex
+	calls []MockCall
+}
+
+var _ DiscordSession = (*MockSession)(nil)
+
+// NewMockSession creates an empty mock session
+func NewMockSession() *MockSession {
+	return &MockSession{}
+}
+
+// Calls returns the calls made to method, oldest first
+func (m *MockSession) Calls(method string) []MockCall {
+	value, ok := m.calls.Load(method)
+	if !ok {
+		return nil
+	}
+	log := value.(*mockCallLog)
+	log.mu.Lock()
+	defer log.mu.Unlock()
+	return append([]MockCall(nil), log.calls...)
+}
+
+// record appends a call to the method's log
+func (m *MockSession) record(method string, args ...interface{}) {
+	value, _ := m.calls.LoadOrStore(method, &mockCallLog{})
+	log := value.(*mockCallLog)
+	log.mu.Lock()
+	log.calls = append(log.calls, MockCall{Method: method, Args: args})
+	log.mu.Unlock()
+}
+
+// Open implements DiscordSession
+func (m *MockSession) Open() error {
+	m.record("Open")
+	return nil
+}
+
+// Close implements DiscordSession
+func (m *MockSession) Close() error {
+	m.record("Close")
+	return nil
+}
+
+// InteractionRespond implements DiscordSession
+func (m *MockSession) InteractionRespond(interaction *discordgo.Interaction, resp *discordgo.InteractionResponse, _ ...discordgo.RequestOption) error {
+	m.record("InteractionRespond", interaction, resp)
+	return nil
+}
+
+// InteractionResponseEdit implements DiscordSession
+func (m *MockSession) InteractionResponseEdit(interaction *discordgo.Interaction, newresp *discordgo.WebhookEdit, _ ...discordgo.RequestOption) (*discordgo.Message, error) {
+	m.record("InteractionResponseEdit", interaction, newresp)
+	return &discordgo.Message{ChannelID: interaction.ChannelID}, nil
+}
+
+// FollowupMessageCreate implements DiscordSession
+func (m *MockSession) FollowupMessageCreate(interaction *discordgo.Interaction, wait bool, data *discordgo.WebhookParams, _ ...discordgo.RequestOption) (*discordgo.Message, error) {
+	m.record("FollowupMessageCreate", interaction, wait, data)
+	return &discordgo.Message{ChannelID: interaction.ChannelID, Content: data.Content}, nil
+}
+
+// ChannelMessageSend implements DiscordSession
+func (m *MockSession) ChannelMessageSend(channelID string, content string, _ ...discordgo.RequestOption) (*discordgo.Message, error) {
+	m.record("ChannelMessageSend", channelID, content)
+	return &discordgo.Message{ChannelID: channelID, Content: content}, nil
+}
+
+// ChannelMessageSendEmbed implements DiscordSession
+func (m *MockSession) ChannelMessageSendEmbed(channelID string, embed *discordgo.MessageEmbed, _ ...discordgo.RequestOption) (*discordgo.Message, error) {
+	m.record("ChannelMessageSendEmbed", channelID, embed)
+	return &discordgo.Message{ChannelID: channelID, Embeds: []*discordgo.MessageEmbed{embed}}, nil
+}
+
+// ChannelMessageSendComplex implements DiscordSession
+func (m *MockSession) ChannelMessageSendComplex(channelID string, data *discordgo.MessageSend, _ ...discordgo.RequestOption) (*discordgo.Message, error) {
+	m.record("ChannelMessageSendComplex", channelID, data)
+	return &discordgo.Message{ChannelID: channelID, Content: data.Content, Components: data.Components}, nil
+}
+
+// ChannelMessages implements DiscordSession, returning up to limit of the channel's stored messages.
+// The before, after and around IDs are ignored.
+func (m *MockSession) ChannelMessages(channelID string, limit int, beforeID, afterID, aroundID string, _ ...discordgo.RequestOption) ([]*discordgo.Message, error) {
+	m.record("ChannelMessages", channelID, limit, beforeID, afterID, aroundID)
+	value, ok := m.Messages.Load(channelID)
+	if !ok {
+		return nil, nil
+	}
+	messages := value.([]*discordgo.Message)
+	if limit > 0 && len(messages) > limit {
+		messages = messages[:limit]
+	}
+	return append([]*discordgo.Message(nil), messages...), nil
+}
+
+// User implements DiscordSession
+func (m *MockSession) User(userID string, _ ...discordgo.RequestOption) (*discordgo.User, error) {
+	m.record("User", userID)
+	if user, ok := m.Users.Load(userID); ok {
+		return user.(*discordgo.User), nil
+	}
+	return &discordgo.User{ID: userID}, nil
+}
+
+// UserChannelCreate implements DiscordSession
+func (m *MockSession) UserChannelCreate(recipientID string, _ ...discordgo.RequestOption) (*discordgo.Channel, error) {
+	m.record("UserChannelCreate", recipientID)
+	return &discordgo.Channel{ID: "dm-" + recipientID, Type: discordgo.ChannelTypeDM}, nil
+}
+
+// Guild implements DiscordSession
+func (m *MockSession) Guild(guildID string, _ ...discordgo.RequestOption) (*discordgo.Guild, error) {
+	m.record("Guild", guildID)
+	if guild, ok := m.Guilds.Load(guildID); ok {
+		return guild.(*discordgo.Guild), nil
+	}
+	return &discordgo.Guild{ID: guildID}, nil
+}
+
+// GuildMember implements DiscordSession
+func (m *MockSession) GuildMember(guildID, userID string, _ ...discordgo.RequestOption) (*discordgo.Member, error) {
+	m.record("GuildMember", guildID, userID)
+	if member, ok := m.Members.Load(userID); ok {
+		return member.(*discordgo.Member), nil
+	}
+	return &discordgo.Member{GuildID: guildID, User: &discordgo.User{ID: userID}}, nil
+}
+
+// Channel implements DiscordSession
+func (m *MockSession) Channel(channelID string, _ ...discordgo.RequestOption) (*discordgo.Channel, error) {
+	m.record("Channel", channelID)
+	if channel, ok := m.Channels.Load(channelID); ok {
+		return channel.(*discordgo.Channel), nil
+	}
+	return &discordgo.Channel{ID: channelID, Type: discordgo.ChannelTypeGuildText}, nil
+}
+
+// ApplicationCommandCreate implements DiscordSession
+func (m *MockSession) ApplicationCommandCreate(appID string, guildID string, cmd *discordgo.ApplicationCommand, _ ...discordgo.RequestOption) (*discordgo.ApplicationCommand, error) {
+	m.record("ApplicationCommandCreate", appID, guildID, cmd)
+	created := *cmd
+	created.ID = cmd.Name
+	created.ApplicationID = appID
+	created.GuildID = guildID
+	m.Commands.Store(created.ID, &created)
+	return &created, nil
+}
+
+// ApplicationCommandEdit implements DiscordSession
+func (m *MockSession) ApplicationCommandEdit(appID, guildID, cmdID string, cmd *discordgo.ApplicationCommand, _ ...discordgo.RequestOption) (*discordgo.ApplicationCommand, error) {
+	m.record("ApplicationCommandEdit", appID, guildID, cmdID, cmd)
+	edited := *cmd
+	edited.ID = cmdID
+	edited.ApplicationID = appID
+	edited.GuildID = guildID
+	m.Commands.Store(edited.ID, &edited)
+	return &edited, nil
+}
+
+// ApplicationCommandDelete implements DiscordSession
+func (m *MockSession) ApplicationCommandDelete(appID, guildID, cmdID string, _ ...discordgo.RequestOption) error {
+	m.record("ApplicationCommandDelete", appID, guildID, cmdID)
+	m.Commands.Delete(cmdID)
+	return nil
+}
+
+// ApplicationCommands implements DiscordSession
+func (m *MockSession) ApplicationCommands(appID, guildID string, _ ...discordgo.RequestOption) ([]*discordgo.ApplicationCommand, error) {
+	m.record("ApplicationCommands", appID, guildID)
+	var commands []*discordgo.ApplicationCommand
+	m.Commands.Range(func(_, value interface{}) bool {
+		commands = append(commands, value.(*discordgo.ApplicationCommand))
+		return true
+	})
+	return commands, nil
+}
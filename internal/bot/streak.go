@@ -0,0 +1,94 @@
+package bot
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// heatmapWeeks is how many weeks the /streak heatmap covers, including the current one
+const heatmapWeeks = 7
+
+// heatmapShades shade a day by how many problems were solved, the last covering any higher count
+var heatmapShades = []string{"░", "▒", "▓", "█"}
+
+func (b *Bot) handleStreakCommand(ctx context.Context, s DiscordSession, i *discordgo.InteractionCreate) (*discordgo.InteractionResponse, error) {
+	userID := interactionUserID(i)
+
+	current, longest, err := b.repo.GetUserStreaks(ctx, userID)
+	if err != nil {
+		return nil, databaseError("Failed to retrieve your streak from the database.", err)
+	}
+	counts, err := b.repo.GetSolveHeatmap(ctx, userID, heatmapWeeks, time.UTC)
+	if err != nil {
+		return nil, databaseError("Failed to retrieve your streak from the database.", err)
+	}
+
+	return embedResponse(b.branded(streakEmbed(current, longest, counts, time.Now().UTC()))), nil
+}
+
+// streakEmbed builds the /streak embed from the user's streaks and daily solve counts
+func streakEmbed(current, longest int, counts map[string]int, now time.Time) *discordgo.MessageEmbed {
+	total := 0
+	for _, count := range counts {
+		total += count
+	}
+	color := ColorInfo
+	if current > 0 {
+		color = ColorSuccess
+	}
+
+	return &discordgo.MessageEmbed{
+		Title:       "Your Streak",
+		Color:       color,
+		Description: "```\n" + solveHeatmap(counts, heatmapWeeks, now) + "```",
+		Fields: []*discordgo.MessageEmbedField{
+			{Name: "Current Streak", Value: fmt.Sprintf("%d days", current), Inline: true},
+			{Name: "Longest Streak", Value: fmt.Sprintf("%d days", longest), Inline: true},
+			{Name: "Weekly Average", Value: fmt.Sprintf("%.1f problems", float64(total)/heatmapWeeks), Inline: true},
+		},
+	}
+}
+
+// solveHeatmap draws a GitHub-style calendar with a row per weekday and a column per week, oldest
+// week first, using now's time zone. Days after today are left blank.
+func solveHeatmap(counts map[string]int, weeks int, now time.Time) string {
+	return heatmapGrid(counts, weeks, now, " ") + heatmapLegend()
+}
+
+// heatmapGrid draws the weekday rows of a heatmap, separating cells with sep
+func heatmapGrid(counts map[string]int, weeks int, now time.Time, sep string) string {
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	start := heatmapStart(today, weeks)
+
+	var sb strings.Builder
+	for weekday := time.Sunday; weekday <= time.Saturday; weekday++ {
+		sb.WriteString(weekday.String()[:3] + " ")
+		for week := 0; week < weeks; week++ {
+			day := start.AddDate(0, 0, 7*week+int(weekday))
+			cell := " "
+			if !day.After(today) {
+				cell = heatmapShades[min(counts[day.Format("2006-01-02")], len(heatmapShades)-1)]
+			}
+			if week > 0 {
+				sb.WriteString(sep)
+			}
+			sb.WriteString(cell)
+		}
+		sb.WriteString("\n")
+	}
+	return sb.String()
+}
+
+// heatmapStart returns the Sunday that begins the oldest week of a heatmap ending on today
+func heatmapStart(today time.Time, weeks int) time.Time {
+	return today.AddDate(0, 0, -int(today.Weekday())-7*(weeks-1))
+}
+
+// heatmapLegend explains the heatmap shades
+func heatmapLegend() string {
+	return fmt.Sprintf("\n%s none  %s 1  %s 2  %s 3+\n", heatmapShades[0], heatmapShades[1], heatmapShades[2], heatmapShades[3])
+}
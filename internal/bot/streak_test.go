@@ -0,0 +1,101 @@
+package bot
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/yugonline/grind_review_bot/config"
+	"github.com/yugonline/grind_review_bot/internal/database"
+	"github.com/yugonline/grind_review_bot/pkg/discord"
+)
+
+// addSolvesDaysAgo stores a problem for testUserID solved each given number of days ago
+func addSolvesDaysAgo(t *testing.T, bot *Bot, daysAgo ...int) {
+	t.Helper()
+	now := time.Now().UTC()
+	for idx, ago := range daysAgo {
+		entry := &database.ProblemEntry{UserID: testUserID, ProblemName: fmt.Sprintf("Problem %d", idx), Difficulty: database.DifficultyEasy, Category: "Array", Status: database.StatusSolved, SolvedAt: now.AddDate(0, 0, -ago)}
+		if err := bot.repo.CreateProblem(context.Background(), entry); err != nil {
+			t.Fatalf("CreateProblem: %v", err)
+		}
+	}
+}
+
+// embedField returns the value of the embed field called name
+func embedField(embed *discordgo.MessageEmbed, name string) string {
+	for _, field := range embed.Fields {
+		if field.Name == name {
+			return field.Value
+		}
+	}
+	return ""
+}
+
+func TestStreakCommand(t *testing.T) {
+	tests := []struct {
+		name        string
+		daysAgo     []int
+		wantCurrent string
+		wantLongest string
+	}{
+		{name: "no problems", wantCurrent: "0 days", wantLongest: "0 days"},
+		{name: "single day", daysAgo: []int{0, 0}, wantCurrent: "1 days", wantLongest: "1 days"},
+		{name: "gap in the streak", daysAgo: []int{0, 1, 3, 4, 5}, wantCurrent: "2 days", wantLongest: "3 days"},
+		{name: "ended before yesterday", daysAgo: []int{3, 4}, wantCurrent: "0 days", wantLongest: "2 days"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bot, session := newTestBot(t, config.DiscordConfig{})
+			addSolvesDaysAgo(t, bot, tt.daysAgo...)
+
+			resp := deferredResult(t, bot, session, commandInteraction("streak"))
+			if len(resp.Data.Embeds) != 1 {
+				t.Fatalf("response = %q, want one embed", responseText(resp))
+			}
+			embed := resp.Data.Embeds[0]
+			if got := embedField(embed, "Current Streak"); got != tt.wantCurrent {
+				t.Errorf("current streak = %q, want %q", got, tt.wantCurrent)
+			}
+			if got := embedField(embed, "Longest Streak"); got != tt.wantLongest {
+				t.Errorf("longest streak = %q, want %q", got, tt.wantLongest)
+			}
+		})
+	}
+}
+
+func TestSolveHeatmap(t *testing.T) {
+	now := time.Date(2024, time.March, 13, 15, 0, 0, 0, time.UTC) // A Wednesday
+	counts := map[string]int{"2024-03-13": 1, "2024-03-12": 2, "2024-03-10": 7, "2024-01-28": 1}
+
+	heatmap := solveHeatmap(counts, heatmapWeeks, now)
+	rows := strings.Split(heatmap, "\n")
+	want := map[string]string{
+		"Sun": "Sun ▒ ░ ░ ░ ░ ░ █", // The first column starts on January 28; 7 solves shade like 3
+		"Mon": "Mon ░ ░ ░ ░ ░ ░ ░",
+		"Tue": "Tue ░ ░ ░ ░ ░ ░ ▓",
+		"Wed": "Wed ░ ░ ░ ░ ░ ░ ▒",
+		"Thu": "Thu ░ ░ ░ ░ ░ ░  ", // After today
+	}
+	for _, row := range rows[:7] {
+		if expected, ok := want[row[:3]]; ok && row != expected {
+			t.Errorf("row = %q, want %q", row, expected)
+		}
+	}
+
+	// The full embed fits in a message with room to spare
+	embed := streakEmbed(12, 30, counts, now)
+	length := len(embed.Title) + len(embed.Description)
+	for _, field := range embed.Fields {
+		length += len(field.Name) + len(field.Value)
+	}
+	if length > discord.MaxMessageLength {
+		t.Errorf("streak embed is %d bytes, over %d", length, discord.MaxMessageLength)
+	}
+	if got := embedField(embed, "Weekly Average"); got != "1.6 problems" {
+		t.Errorf("weekly average = %q, want 1.6 problems", got)
+	}
+}
@@ -0,0 +1,29 @@
+package bot
+
+import (
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/yugonline/grind_review_bot/internal/database"
+)
+
+// Values of the tag_match option
+const (
+	tagMatchAny = "any"
+	tagMatchAll = "all"
+)
+
+// tagFilterScope narrows a problem query by the comma separated tags option, requiring all of the
+// tags when tag_match is "all" and any of them otherwise
+func tagFilterScope(userID string, optionMap map[string]*discordgo.ApplicationCommandInteractionDataOption) database.ProblemScope {
+	var tags []string
+	if tagsOpt, ok := optionMap["tags"]; ok && tagsOpt.StringValue() != "" {
+		tags = strings.Split(tagsOpt.StringValue(), ",")
+	}
+
+	matchAll := false
+	if matchOpt, ok := optionMap["tag_match"]; ok {
+		matchAll = matchOpt.StringValue() == tagMatchAll
+	}
+	return database.TaggedWith(userID, tags, matchAll)
+}
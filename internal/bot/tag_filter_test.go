@@ -0,0 +1,49 @@
+package bot
+
+import (
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/yugonline/grind_review_bot/config"
+	"github.com/yugonline/grind_review_bot/internal/database"
+)
+
+func TestTagMatchOption(t *testing.T) {
+	bot, session := newTestBot(t, config.DiscordConfig{})
+	bot.scheduler.Store(&Scheduler{bot: bot, config: config.SchedulerConfig{LookbackPeriod: 7 * 24 * time.Hour}})
+	addTestProblem(t, bot, "Cheapest Flights", database.DifficultyMedium, "dp", "graph")
+	addTestProblem(t, bot, "Coin Change", database.DifficultyMedium, "dp")
+	addTestProblem(t, bot, "Course Schedule", database.DifficultyMedium, "graph")
+	addTestProblem(t, bot, "Two Sum", database.DifficultyEasy, "array")
+
+	all := []string{"Cheapest Flights", "Coin Change", "Course Schedule", "Two Sum"}
+	tests := []struct {
+		name  string
+		match string
+		want  []string
+	}{
+		{name: "default", want: []string{"Cheapest Flights", "Coin Change", "Course Schedule"}},
+		{name: "any", match: tagMatchAny, want: []string{"Cheapest Flights", "Coin Change", "Course Schedule"}},
+		{name: "all", match: tagMatchAll, want: []string{"Cheapest Flights"}},
+	}
+	for _, command := range []string{"list", "due"} {
+		for _, tt := range tests {
+			t.Run(command+" "+tt.name, func(t *testing.T) {
+				options := []*discordgo.ApplicationCommandInteractionDataOption{stringOption("tags", "dp, graph")}
+				if tt.match != "" {
+					options = append(options, stringOption("tag_match", tt.match))
+				}
+				content := dispatch(t, bot, session, commandInteraction(command, options...)).Data.Content
+
+				for _, name := range all {
+					if listed, want := strings.Contains(content, name), slices.Contains(tt.want, name); listed != want {
+						t.Errorf("/%s lists %s = %v, want %v in %q", command, name, listed, want, content)
+					}
+				}
+			})
+		}
+	}
+}
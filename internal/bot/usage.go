@@ -0,0 +1,62 @@
+package bot
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/rs/zerolog"
+)
+
+// usageRecordTimeout bounds the background write of a command's usage
+const usageRecordTimeout = 5 * time.Second
+
+// topUsageUsers is how many users /admin-usage lists
+const topUsageUsers = 10
+
+// recordUsage counts the command towards the invoking user's usage in the background, so a slow
+// write never delays the reply
+func (b *Bot) recordUsage(ctx context.Context, i *discordgo.InteractionCreate, success bool) {
+	userID := interactionUserID(i)
+	if userID == "" {
+		return
+	}
+	command := b.commandKey(i)
+
+	go func() {
+		// The interaction's context ends with the handler, but the logger it carries is still wanted
+		ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), usageRecordTimeout)
+		defer cancel()
+
+		if err := b.repo.RecordCommandUsage(ctx, userID, command, success); err != nil {
+			zerolog.Ctx(ctx).Warn().Err(err).Msg("Failed to record command usage")
+		}
+	}()
+}
+
+func (b *Bot) handleAdminUsageCommand(ctx context.Context, s DiscordSession, i *discordgo.InteractionCreate) (*discordgo.InteractionResponse, error) {
+	users, err := b.repo.GetTopCommandUsers(ctx, topUsageUsers)
+	if err != nil {
+		return nil, databaseError("Failed to retrieve command usage from the database.", err)
+	}
+	if len(users) == 0 {
+		return ephemeralResponse("No commands have been recorded yet."), nil
+	}
+
+	var sb strings.Builder
+	for rank, user := range users {
+		sb.WriteString(fmt.Sprintf("%d. <@%s> - %d commands", rank+1, user.UserID, user.Commands))
+		if user.Errors > 0 {
+			sb.WriteString(fmt.Sprintf(" (%d failed)", user.Errors))
+		}
+		sb.WriteString(fmt.Sprintf(", last <t:%d:R>\n", user.LastUsedAt.Unix()))
+	}
+
+	return embedResponse(b.branded(&discordgo.MessageEmbed{
+		Title:       "Top Command Users",
+		Color:       ColorInfo,
+		Description: sb.String(),
+	})), nil
+}
@@ -0,0 +1,115 @@
+package bot
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/yugonline/grind_review_bot/internal/database"
+)
+
+// profileChartWidth is the length of the longest bar in the category chart
+const profileChartWidth = 10
+
+// statusDots colour-codes problem statuses in the recent problems list
+var statusDots = map[string]string{
+	database.StatusSolved:     "🟢",
+	database.StatusNeededHint: "🟡",
+	database.StatusStuck:      "🔴",
+}
+
+func (b *Bot) handleProfileCommand(ctx context.Context, s DiscordSession, i *discordgo.InteractionCreate) (*discordgo.InteractionResponse, error) {
+	options := i.ApplicationCommandData().Options
+	optionMap := make(map[string]*discordgo.ApplicationCommandInteractionDataOption, len(options))
+	for _, opt := range options {
+		optionMap[opt.Name] = opt
+	}
+
+	user := interactionUser(i)
+	own := true
+	if userOpt, ok := optionMap["user"]; ok && userOpt.UserValue(nil).ID != user.ID {
+		user = optionUser(i, userOpt)
+		own = false
+
+		// Other members' profiles are only visible once they opt in
+		settings, err := b.repo.GetUserSettings(ctx, user.ID)
+		if err != nil {
+			return nil, databaseError("Failed to retrieve the member's settings.", err)
+		}
+		if !settings.ProfilePublic {
+			return nil, permissionError("That member hasn't made their profile public.")
+		}
+	}
+
+	profile, err := b.repo.GetUserProfile(ctx, user.ID)
+	if err != nil {
+		return nil, databaseError("Failed to retrieve the profile from the database.", err)
+	}
+
+	return embedResponse(b.branded(profileEmbed(user, profile, own))), nil
+}
+
+// profileEmbed renders a user's profile. Links are only shown on the user's own profile.
+func profileEmbed(user *discordgo.User, profile *database.UserProfile, own bool) *discordgo.MessageEmbed {
+	return &discordgo.MessageEmbed{
+		Title:     fmt.Sprintf("%s's Profile", user.Username),
+		Color:     ColorInfo,
+		Thumbnail: &discordgo.MessageEmbedThumbnail{URL: user.AvatarURL("128")},
+		Fields: []*discordgo.MessageEmbedField{
+			{Name: "Stats", Value: statsColumn(profile.Stats), Inline: true},
+			{Name: "Streak", Value: fmt.Sprintf("**Current:** %d days\n**Longest:** %d days", profile.CurrentStreak, profile.LongestStreak), Inline: true},
+			{Name: "Top Categories", Value: categoryChart(profile.Categories, statsTopCount)},
+			{Name: "Recent Problems", Value: recentProblemsList(profile.RecentProblems, own)},
+		},
+	}
+}
+
+// categoryChart draws the n largest categories as a bar chart scaled to the biggest one
+func categoryChart(categories map[string]int, n int) string {
+	if len(categories) == 0 {
+		return "None yet"
+	}
+
+	names := make([]string, 0, len(categories))
+	for name := range categories {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(a, b int) bool {
+		if categories[names[a]] != categories[names[b]] {
+			return categories[names[a]] > categories[names[b]]
+		}
+		return names[a] < names[b]
+	})
+	if len(names) > n {
+		names = names[:n]
+	}
+
+	largest := categories[names[0]]
+	var sb strings.Builder
+	sb.WriteString("```\n")
+	for _, name := range names {
+		width := max(categories[name]*profileChartWidth/largest, 1)
+		sb.WriteString(fmt.Sprintf("%-15s %s %d\n", truncateString(name, 15), strings.Repeat("█", width), categories[name]))
+	}
+	sb.WriteString("```")
+	return sb.String()
+}
+
+// recentProblemsList formats the latest problems with a status dot, linking them when showLinks is set
+func recentProblemsList(problems []*database.ProblemEntry, showLinks bool) string {
+	if len(problems) == 0 {
+		return "None yet"
+	}
+
+	lines := make([]string, len(problems))
+	for idx, p := range problems {
+		name := p.ProblemName
+		if showLinks && p.Link != "" {
+			name = fmt.Sprintf("[%s](%s)", p.ProblemName, p.Link)
+		}
+		lines[idx] = fmt.Sprintf("%s %s (%s) - %s", statusDots[p.Status], name, p.Difficulty, p.SolvedAt.Format("2006-01-02"))
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,26 @@
+package bot
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/yugonline/grind_review_bot/internal/database"
+)
+
+func TestProblemValidationMessage(t *testing.T) {
+	tests := []struct {
+		err  *database.ValidationError
+		want string
+	}{
+		{err: &database.ValidationError{Field: database.FieldDifficulty, Reason: database.ReasonInvalid, Value: "Extreme"}, want: "'Extreme' isn't a difficulty"},
+		{err: &database.ValidationError{Field: database.FieldStatus, Reason: database.ReasonInvalid, Value: "Skipped"}, want: "Choose Solved, Needed Hint or Stuck"},
+		{err: &database.ValidationError{Field: database.FieldCategory, Reason: database.ReasonRequired}, want: "the category can't be empty"},
+		{err: &database.ValidationError{Field: database.FieldTags, Reason: database.ReasonTooMany, Count: 12, Limit: 10}, want: "at most 10 tags and 12 were given"},
+		{err: &database.ValidationError{Field: database.FieldUserID, Reason: database.ReasonRequired}, want: "user ID is required."},
+	}
+	for _, tt := range tests {
+		if got := problemValidationMessage(tt.err); !strings.Contains(got, tt.want) {
+			t.Errorf("problemValidationMessage(%s) = %q, want it to contain %q", tt.err.Field, got, tt.want)
+		}
+	}
+}
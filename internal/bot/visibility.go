@@ -0,0 +1,69 @@
+package bot
+
+import (
+	"context"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/rs/zerolog"
+	"github.com/yugonline/grind_review_bot/internal/database"
+)
+
+// ephemeralOption lets a single invocation override where the reply is shown
+var ephemeralOption = &discordgo.ApplicationCommandOption{
+	Type:        discordgo.ApplicationCommandOptionBoolean,
+	Name:        "ephemeral",
+	Description: "Only show the reply to you (overrides your /privacy responses setting)",
+	Required:    false,
+}
+
+// withVisibility wraps a handler whose replies are public unless defaultEphemeral is set, letting the
+// command's ephemeral option or the user's response visibility setting decide instead. Replies the
+// handler already made ephemeral, such as prompts, stay that way.
+func (b *Bot) withVisibility(defaultEphemeral bool, next CommandHandler) CommandHandler {
+	return func(ctx context.Context, s DiscordSession, i *discordgo.InteractionCreate) (*discordgo.InteractionResponse, error) {
+		response, err := next(ctx, s, i)
+		if err != nil || response == nil || response.Data == nil {
+			return response, err
+		}
+
+		var override *bool
+		_, options := getSubcommand(i)
+		for _, opt := range options {
+			if opt.Name == ephemeralOption.Name {
+				value := opt.BoolValue()
+				override = &value
+			}
+		}
+
+		visibility := database.ResponseVisibilityDefault
+		if override == nil {
+			settings, err := b.repo.GetUserSettings(ctx, interactionUserID(i))
+			if err != nil {
+				// The reply is ready, so fall back to the command's default rather than failing it
+				zerolog.Ctx(ctx).Warn().Err(err).Msg("Failed to load response visibility setting")
+			} else {
+				visibility = settings.ResponseVisibility
+			}
+		}
+
+		if replyEphemeral(defaultEphemeral, visibility, override) {
+			response.Data.Flags |= discordgo.MessageFlagsEphemeral
+		}
+		return response, nil
+	}
+}
+
+// replyEphemeral decides whether a reply is ephemeral: the invocation's override wins, then the user's
+// visibility setting, then the command's default
+func replyEphemeral(defaultEphemeral bool, visibility string, override *bool) bool {
+	if override != nil {
+		return *override
+	}
+	switch visibility {
+	case database.ResponseVisibilityPrivate:
+		return true
+	case database.ResponseVisibilityPublic:
+		return false
+	}
+	return defaultEphemeral
+}
@@ -0,0 +1,76 @@
+package bot
+
+import (
+	"testing"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/yugonline/grind_review_bot/config"
+	"github.com/yugonline/grind_review_bot/internal/database"
+)
+
+func TestReplyEphemeral(t *testing.T) {
+	yes, no := true, false
+	tests := []struct {
+		name             string
+		defaultEphemeral bool
+		visibility       string
+		override         *bool
+		want             bool
+	}{
+		{name: "public command default", visibility: database.ResponseVisibilityDefault, want: false},
+		{name: "ephemeral command default", defaultEphemeral: true, visibility: database.ResponseVisibilityDefault, want: true},
+		{name: "private setting", visibility: database.ResponseVisibilityPrivate, want: true},
+		{name: "public setting", defaultEphemeral: true, visibility: database.ResponseVisibilityPublic, want: false},
+		{name: "override over public setting", visibility: database.ResponseVisibilityPublic, override: &yes, want: true},
+		{name: "override over private setting", visibility: database.ResponseVisibilityPrivate, override: &no, want: false},
+		{name: "override over command default", defaultEphemeral: true, override: &no, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := replyEphemeral(tt.defaultEphemeral, tt.visibility, tt.override); got != tt.want {
+				t.Errorf("replyEphemeral(%v, %q, %v) = %v, want %v", tt.defaultEphemeral, tt.visibility, tt.override, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResponseVisibility(t *testing.T) {
+	bot, session := newTestBot(t, config.DiscordConfig{})
+	problem := addTestProblem(t, bot, "Two Sum", database.DifficultyEasy)
+	ephemeral := func(resp *discordgo.InteractionResponse) bool {
+		return resp.Data.Flags&discordgo.MessageFlagsEphemeral != 0
+	}
+
+	// /list and /get are public until the user says otherwise
+	if resp := dispatch(t, bot, session, commandInteraction("list")); ephemeral(resp) {
+		t.Error("/list is ephemeral by default")
+	}
+	if resp := dispatch(t, bot, session, commandInteraction("get", intOption("id", int(problem.ID)))); ephemeral(resp) {
+		t.Error("/get is ephemeral by default")
+	}
+	if resp := dispatch(t, bot, session, commandInteraction("list", boolOption("ephemeral", true))); !ephemeral(resp) {
+		t.Error("/list ephemeral:true was shown to the channel")
+	}
+
+	dispatch(t, bot, session, commandInteraction("privacy", stringOption("responses", database.ResponseVisibilityPrivate)))
+	if resp := dispatch(t, bot, session, commandInteraction("list")); !ephemeral(resp) {
+		t.Error("/list was shown to the channel with responses set to private")
+	}
+	if resp := dispatch(t, bot, session, commandInteraction("get", intOption("id", int(problem.ID)))); !ephemeral(resp) {
+		t.Error("/get was shown to the channel with responses set to private")
+	}
+	if resp := dispatch(t, bot, session, commandInteraction("list", boolOption("ephemeral", false))); ephemeral(resp) {
+		t.Error("/list ephemeral:false didn't override the private setting")
+	}
+
+	// Errors stay ephemeral however replies are shown
+	dispatch(t, bot, session, commandInteraction("privacy", stringOption("responses", database.ResponseVisibilityPublic)))
+	if resp := dispatch(t, bot, session, commandInteraction("get", intOption("id", 9999))); !ephemeral(resp) {
+		t.Error("an error was shown to the channel with responses set to public")
+	}
+
+	dispatch(t, bot, session, commandInteraction("privacy", stringOption("responses", "default")))
+	if resp := dispatch(t, bot, session, commandInteraction("list")); ephemeral(resp) {
+		t.Error("/list is ephemeral after going back to the default")
+	}
+}
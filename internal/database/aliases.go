@@ -0,0 +1,78 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"gorm.io/gorm"
+)
+
+// ErrAliasTaken is returned when creating an alias whose name is already in use
+var ErrAliasTaken = errors.New("alias name is already taken")
+
+// ErrAliasNotFound is returned when a user has no alias with the given name
+var ErrAliasNotFound = errors.New("alias not found")
+
+// CreateAlias stores a new command alias. Alias names are shared by every user, so one that's
+// already taken, by anyone, fails with ErrAliasTaken.
+func (r *Repository) CreateAlias(ctx context.Context, alias *CommandAlias) error {
+	if alias.DefaultOptions == "" {
+		alias.DefaultOptions = "{}"
+	}
+
+	err := r.WithRetry(ctx, defaultTxRetries, func(tx *gorm.DB) error {
+		var count int64
+		if err := tx.Model(&CommandAlias{}).Where("alias = ?", alias.Alias).Count(&count).Error; err != nil {
+			return err
+		}
+		if count > 0 {
+			return ErrAliasTaken
+		}
+		return tx.Create(alias).Error
+	})
+
+	if err != nil {
+		return fmt.Errorf("failed to create alias %q: %w", alias.Alias, err)
+	}
+	return nil
+}
+
+// GetAlias retrieves an alias by name, whoever owns it
+func (r *Repository) GetAlias(ctx context.Context, name string) (*CommandAlias, error) {
+	var alias CommandAlias
+	err := r.withContext(ctx).Where("alias = ?", name).First(&alias).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("failed to get alias %q: %w", name, ErrAliasNotFound)
+		}
+		return nil, fmt.Errorf("failed to get alias %q: %w", name, err)
+	}
+	return &alias, nil
+}
+
+// ListAliases retrieves a user's aliases ordered by name, or every user's when userID is empty
+func (r *Repository) ListAliases(ctx context.Context, userID string) ([]*CommandAlias, error) {
+	query := r.withContext(ctx).Order("alias ASC")
+	if userID != "" {
+		query = query.Where("user_id = ?", userID)
+	}
+
+	var aliases []*CommandAlias
+	if err := query.Find(&aliases).Error; err != nil {
+		return nil, fmt.Errorf("failed to list aliases: %w", err)
+	}
+	return aliases, nil
+}
+
+// DeleteAlias removes one of the user's aliases, returning ErrAliasNotFound if they have none by that name
+func (r *Repository) DeleteAlias(ctx context.Context, userID, name string) error {
+	result := r.withContext(ctx).Where("user_id = ? AND alias = ?", userID, name).Delete(&CommandAlias{})
+	if result.Error != nil {
+		return fmt.Errorf("failed to delete alias %q: %w", name, result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("failed to delete alias %q: %w", name, ErrAliasNotFound)
+	}
+	return nil
+}
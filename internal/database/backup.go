@@ -0,0 +1,52 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/rs/zerolog"
+)
+
+// ErrBackupUnsupported is returned by BackupDatabase for drivers it cannot snapshot
+var ErrBackupUnsupported = errors.New("backups are only supported for SQLite")
+
+// BackupDatabase writes a consistent copy of the repository's database to destPath
+// over the existing connection pool
+func (r *Repository) BackupDatabase(ctx context.Context, destPath string) error {
+	if r.config.Driver != "sqlite3" {
+		return ErrBackupUnsupported
+	}
+
+	return vacuumInto(ctx, destPath, func(tmpPath string) error {
+		return r.withContext(ctx).Exec("VACUUM INTO ?", tmpPath).Error
+	})
+}
+
+// vacuumInto runs vacuum against a temporary file next to destPath and renames it into place,
+// so an existing backup is only replaced by a complete one
+func vacuumInto(ctx context.Context, destPath string, vacuum func(tmpPath string) error) error {
+	if err := os.MkdirAll(filepath.Dir(destPath), 0o755); err != nil {
+		return fmt.Errorf("failed to create backup directory: %w", err)
+	}
+
+	// VACUUM INTO refuses to overwrite an existing file
+	tmpPath := destPath + ".tmp"
+	if err := os.Remove(tmpPath); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed to remove stale backup file: %w", err)
+	}
+
+	if err := vacuum(tmpPath); err != nil {
+		os.Remove(tmpPath)
+		return fmt.Errorf("failed to back up database: %w", err)
+	}
+	if err := os.Rename(tmpPath, destPath); err != nil {
+		os.Remove(tmpPath)
+		return fmt.Errorf("failed to move backup into place: %w", err)
+	}
+
+	zerolog.Ctx(ctx).Info().Str("path", destPath).Int64("size", fileSize(destPath)).Msg("Database backed up")
+	return nil
+}
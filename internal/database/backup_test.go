@@ -0,0 +1,57 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func TestRepositoryBackupDatabase(t *testing.T) {
+	ctx := context.Background()
+	repo := newTestRepository(t)
+	seeded := seedTestData(t, repo, "backup-user")
+
+	path := filepath.Join(t.TempDir(), "backups", "grind.db")
+	// Backups replace an earlier one at the same path
+	for range 2 {
+		if err := repo.BackupDatabase(ctx, path); err != nil {
+			t.Fatalf("BackupDatabase: %v", err)
+		}
+	}
+	if _, err := os.Stat(path + ".tmp"); !os.IsNotExist(err) {
+		t.Errorf("temporary backup file left behind: %v", err)
+	}
+
+	backup, err := gorm.Open(sqlite.Open(path), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("failed to open backup: %v", err)
+	}
+	if sqlDB, err := backup.DB(); err == nil {
+		defer sqlDB.Close()
+	}
+	var count int64
+	if err := backup.Model(&Problem{}).Where("user_id = ?", "backup-user").Count(&count).Error; err != nil {
+		t.Fatalf("failed to count backed up problems: %v", err)
+	}
+	if count != int64(len(seeded)) {
+		t.Errorf("backup holds %d problems, want %d", count, len(seeded))
+	}
+}
+
+func TestRepositoryBackupDatabaseUnsupportedDriver(t *testing.T) {
+	repo := newTestRepository(t)
+	repo.config.Driver = "postgres"
+
+	path := filepath.Join(t.TempDir(), "grind.db")
+	if err := repo.BackupDatabase(context.Background(), path); !errors.Is(err, ErrBackupUnsupported) {
+		t.Errorf("BackupDatabase = %v, want ErrBackupUnsupported", err)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("backup file written for an unsupported driver: %v", err)
+	}
+}
@@ -0,0 +1,109 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/yugonline/grind_review_bot/config"
+)
+
+// newPoolRepository opens a migrated WAL-mode SQLite database with the given pool limits
+func newPoolRepository(t *testing.T, maxOpen int, maxLife time.Duration) *Repository {
+	t.Helper()
+	ctx := context.Background()
+
+	dsn := filepath.Join(t.TempDir(), "pool.db") + "?_journal_mode=WAL&_busy_timeout=5000"
+	repo, err := New(ctx, config.DatabaseConfig{
+		Driver:            "sqlite3",
+		DSN:               dsn,
+		MaxOpenConns:      maxOpen,
+		MaxIdleConns:      maxOpen,
+		ConnMaxLife:       maxLife,
+		MaxTagsPerProblem: 10,
+	})
+	if err != nil {
+		t.Fatalf("failed to open test database: %v", err)
+	}
+	if err := Migrate(ctx, repo); err != nil {
+		t.Fatalf("failed to migrate test database: %v", err)
+	}
+	t.Cleanup(func() {
+		if sqlDB, err := repo.db.DB(); err == nil {
+			sqlDB.Close()
+		}
+	})
+	return repo
+}
+
+func TestMaxOpenConnsUnderConcurrentWrites(t *testing.T) {
+	repo := newPoolRepository(t, 2, 0)
+	sqlDB, err := repo.db.DB()
+	if err != nil {
+		t.Fatalf("DB: %v", err)
+	}
+
+	const writers = 10
+	var wg sync.WaitGroup
+	errs := make(chan error, writers)
+	for idx := range writers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			entry := &ProblemEntry{
+				UserID:      "pool-user",
+				ProblemName: fmt.Sprintf("Problem %d", idx),
+				Difficulty:  DifficultyMedium,
+				Category:    "Array",
+				Status:      StatusSolved,
+				SolvedAt:    seedBaseTime,
+				Tags:        []string{"array"},
+			}
+			errs <- repo.CreateProblem(context.Background(), entry)
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		if err != nil {
+			t.Errorf("CreateProblem: %v", err)
+		}
+	}
+	if open := sqlDB.Stats().MaxOpenConnections; open != 2 {
+		t.Errorf("MaxOpenConnections = %d, want 2", open)
+	}
+
+	stats, err := repo.GetUserStats(context.Background(), "pool-user")
+	if err != nil {
+		t.Fatalf("GetUserStats: %v", err)
+	}
+	if stats.TotalProblems != writers {
+		t.Errorf("TotalProblems = %d, want %d", stats.TotalProblems, writers)
+	}
+}
+
+func TestConnMaxLifeRecyclesConnections(t *testing.T) {
+	repo := newPoolRepository(t, 2, 50*time.Millisecond)
+	sqlDB, err := repo.db.DB()
+	if err != nil {
+		t.Fatalf("DB: %v", err)
+	}
+
+	if err := sqlDB.Ping(); err != nil {
+		t.Fatalf("Ping: %v", err)
+	}
+	before := sqlDB.Stats().MaxLifetimeClosed
+
+	time.Sleep(100 * time.Millisecond)
+	if err := sqlDB.Ping(); err != nil {
+		t.Fatalf("Ping: %v", err)
+	}
+
+	if after := sqlDB.Stats().MaxLifetimeClosed; after <= before {
+		t.Errorf("MaxLifetimeClosed = %d after the lifetime passed, want more than %d", after, before)
+	}
+}
@@ -0,0 +1,38 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ErrInvalidDateRange is returned when a date range ends before it starts
+var ErrInvalidDateRange = errors.New("start date is after end date")
+
+// ProblemScope narrows a problem query beyond the standard ListProblems filters
+type ProblemScope func(*gorm.DB) *gorm.DB
+
+// SolvedBetween limits a problem query to those solved within [from, to]; a zero bound is left open
+func SolvedBetween(from, to time.Time) ProblemScope {
+	return func(db *gorm.DB) *gorm.DB {
+		if !from.IsZero() {
+			db = db.Where("problems.solved_at >= ?", from.UTC())
+		}
+		if !to.IsZero() {
+			db = db.Where("problems.solved_at <= ?", to.UTC())
+		}
+		return db
+	}
+}
+
+// GetProblemsSolvedBetween retrieves a user's problems solved within [from, to], newest first
+func (r *Repository) GetProblemsSolvedBetween(ctx context.Context, userID string, from, to time.Time) ([]*ProblemEntry, error) {
+	if from.After(to) {
+		return nil, fmt.Errorf("failed to get problems solved between %s and %s: %w",
+			from.Format(time.DateOnly), to.Format(time.DateOnly), ErrInvalidDateRange)
+	}
+	return r.ListProblems(ctx, userID, "", "", "", nil, true, 0, 0, SolvedBetween(from, to))
+}
@@ -0,0 +1,68 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestSolvedBetween(t *testing.T) {
+	repo := newTestRepository(t)
+	seedTestData(t, repo, "range-user")
+
+	// The fixtures were solved a day apart, starting at seedBaseTime
+	day := func(n int) time.Time { return seedBaseTime.AddDate(0, 0, n) }
+	tests := []struct {
+		name     string
+		from, to time.Time
+		want     int
+	}{
+		{name: "inclusive bounds", from: day(2), to: day(4), want: 3},
+		{name: "single instant", from: day(5), to: day(5), want: 1},
+		{name: "open start", to: day(1), want: 2},
+		{name: "open end", from: day(8), want: 2},
+		{name: "unbounded", want: 10},
+		{name: "between solves", from: day(3).Add(time.Hour), to: day(4).Add(-time.Hour), want: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			problems, err := repo.ListProblems(context.Background(), "range-user", "", "", "", nil, true, 0, 0, SolvedBetween(tt.from, tt.to))
+			if err != nil {
+				t.Fatalf("ListProblems: %v", err)
+			}
+			if len(problems) != tt.want {
+				t.Errorf("got %d problems, want %d", len(problems), tt.want)
+			}
+		})
+	}
+}
+
+func TestGetProblemsSolvedBetween(t *testing.T) {
+	ctx := context.Background()
+	repo := newTestRepository(t)
+	seedTestData(t, repo, "range-user")
+	seedTestData(t, repo, "other-user")
+
+	from, to := seedBaseTime.AddDate(0, 0, 2), seedBaseTime.AddDate(0, 0, 4)
+	problems, err := repo.GetProblemsSolvedBetween(ctx, "range-user", from, to)
+	if err != nil {
+		t.Fatalf("GetProblemsSolvedBetween: %v", err)
+	}
+	if len(problems) != 3 {
+		t.Fatalf("got %d problems, want 3", len(problems))
+	}
+	for idx, p := range problems {
+		if p.UserID != "range-user" {
+			t.Errorf("got %s's problem %q", p.UserID, p.ProblemName)
+		}
+		if idx > 0 && p.SolvedAt.After(problems[idx-1].SolvedAt) {
+			t.Errorf("%q is listed after an older problem, want newest first", p.ProblemName)
+		}
+	}
+
+	// An inverted range is rejected rather than silently matching nothing
+	if _, err := repo.GetProblemsSolvedBetween(ctx, "range-user", to, from); !errors.Is(err, ErrInvalidDateRange) {
+		t.Errorf("GetProblemsSolvedBetween with from after to = %v, want ErrInvalidDateRange", err)
+	}
+}
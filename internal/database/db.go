@@ -2,9 +2,12 @@ package database
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"strings"
 	"time"
 
+	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/log"
 	"github.com/yugonline/grind_review_bot/config"
 
@@ -14,27 +17,117 @@ import (
 	"gorm.io/gorm/logger"
 )
 
+// ErrProblemNotFound is returned when no problem has the requested ID or name
+var ErrProblemNotFound = errors.New("problem not found")
+
 // Repository represents a database repository with ORM
 type Repository struct {
 	db     *gorm.DB
 	config config.DatabaseConfig
+
+	// searchIndex is set once the FTS5 index exists so FTSSearch can use it
+	searchIndex bool
+
+	// invalidator, when set, is told about problem changes so cached aggregates stay fresh
+	invalidator CacheInvalidator
 }
 
+// RepositoryInterface lists the public Repository methods so callers can be tested against a
+// stand-in instead of a real database
+type RepositoryInterface interface {
+	GetDB() *gorm.DB
+	AutoMigrate() error
+	WithRetry(ctx context.Context, maxRetries int, fn func(*gorm.DB) error) error
+	Compact(ctx context.Context) error
+	BackupDatabase(ctx context.Context, destPath string) error
+	SetCacheInvalidator(invalidator CacheInvalidator)
+
+	// Problems
+	CreateProblem(ctx context.Context, entry *ProblemEntry) error
+	ImportProblems(ctx context.Context, userID string, entries []*ProblemEntry) ([]*ProblemEntry, error)
+	GetProblem(ctx context.Context, id uint) (*ProblemEntry, error)
+	GetProblemByName(ctx context.Context, userID, name string) (*ProblemEntry, error)
+	UpdateProblem(ctx context.Context, entry *ProblemEntry) error
+	DeleteProblem(ctx context.Context, id uint) error
+	SetArchived(ctx context.Context, id uint, archived bool) error
+	ListProblems(ctx context.Context, userID, status, difficulty, category string, tagNames []string, includeArchived bool, limit, offset int, scopes ...ProblemScope) ([]*ProblemEntry, error)
+	GetProblemsSolvedBetween(ctx context.Context, userID string, from, to time.Time) ([]*ProblemEntry, error)
+	ListProblemsByTag(ctx context.Context, userID string, tagNames []string, matchAll bool) ([]*ProblemEntry, error)
+	FTSSearch(ctx context.Context, userID, query string, limit int) ([]*ProblemEntry, error)
+	SearchProblemsByPartialName(ctx context.Context, userID, partial string, limit int) ([]*ProblemEntry, error)
+	DeleteTag(ctx context.Context, userID, name, reassignTo string) (int, error)
+	CleanupOrphanedTags(ctx context.Context) (int64, error)
+	MergeDuplicateProblems(ctx context.Context, userID string, dryRun bool) ([]DuplicateGroup, error)
+
+	// Reviews
+	ListProblemsForReview(ctx context.Context, userID string, lookbackPeriod time.Duration, scopes ...ProblemScope) ([]*ProblemEntry, error)
+	IncrementReviewCount(ctx context.Context, problemID uint) error
+	ListReviewHistory(ctx context.Context, problemID uint, limit int) ([]time.Time, error)
+
+	// Users and stats
+	ListAllUsers(ctx context.Context) ([]string, error)
+	CountAllProblems(ctx context.Context) (int64, error)
+	CountActiveUsers(ctx context.Context, since time.Time) (int64, error)
+	TruncateUserData(ctx context.Context, userID string) (int64, error)
+	GetUserSettings(ctx context.Context, userID string) (*UserSettings, error)
+	GetAllUserSettings(ctx context.Context) (map[string]*UserSettings, error)
+	SetProfilePublic(ctx context.Context, userID string, public bool) error
+	SetAPITokenHash(ctx context.Context, userID, hash string) error
+	SetResponseVisibility(ctx context.Context, userID, visibility string) error
+	SetReminderFilters(ctx context.Context, userID, difficulty, category string) error
+	GetAllUserPreferences(ctx context.Context) (map[string]*UserPreferences, error)
+	GetUserProfile(ctx context.Context, userID string) (*UserProfile, error)
+	GetUserStats(ctx context.Context, userID string) (*UserStats, error)
+	GetAllUserStats(ctx context.Context) ([]*UserStats, error)
+	GetLeaderboard(ctx context.Context, metric string, page, pageSize int) (*LeaderboardResult, error)
+	RecalculateUserStats(ctx context.Context, userID string) (int, error)
+	RecalculateAllUserStats(ctx context.Context) (int, error)
+	GetCategoryDistribution(ctx context.Context, userID string) (map[string]int, error)
+	GetTagDistribution(ctx context.Context, userID string) (map[string]int, error)
+	GetSolveCountByDate(ctx context.Context, userID string, days int) (map[string]int, error)
+	GetReviewEffectiveness(ctx context.Context, userID string) (float64, error)
+	GetStreaks(ctx context.Context, userID string) (current, longest int, err error)
+	GetStreakData(ctx context.Context, userID string, allowedGap int) (current, longest int, err error)
+	GetUserStreaks(ctx context.Context, userID string) (current, longest int, err error)
+	GetSolveHeatmap(ctx context.Context, userID string, weeks int, loc *time.Location) (map[string]int, error)
+	RecordCommandUsage(ctx context.Context, userID, command string, success bool) error
+	GetTopCommandUsers(ctx context.Context, limit int) ([]UserUsage, error)
+	GetCommandsPerUserPerDay(ctx context.Context, since time.Time) ([]float64, error)
+
+	// Command aliases
+	CreateAlias(ctx context.Context, alias *CommandAlias) error
+	GetAlias(ctx context.Context, name string) (*CommandAlias, error)
+	ListAliases(ctx context.Context, userID string) ([]*CommandAlias, error)
+	DeleteAlias(ctx context.Context, userID, name string) error
+
+	// Problem sets
+	EnsureBuiltinProblemSets(ctx context.Context, guildID string) error
+	CreateProblemSet(ctx context.Context, set *ProblemSet) error
+	AddProblemToSet(ctx context.Context, setID, problemID uint) error
+	RemoveProblemFromSet(ctx context.Context, setID, problemID uint) (bool, error)
+	GetProblemSet(ctx context.Context, id uint) (*ProblemSet, error)
+	ListProblemSets(ctx context.Context, userID, guildID string) ([]*ProblemSet, error)
+	DeleteProblemSet(ctx context.Context, id uint) error
+	ListProblemSetProblems(ctx context.Context, set *ProblemSet, userID string) ([]*ProblemEntry, error)
+}
+
+var _ RepositoryInterface = (*Repository)(nil)
+
 // New creates a new database repository
 func New(ctx context.Context, cfg config.DatabaseConfig) (*Repository, error) {
 	// Configure GORM logger
-	gormLogger := logger.New(
-		&GormLogWriter{},
-		logger.Config{
-			SlowThreshold:             time.Second,
-			LogLevel:                  logger.Info,
-			IgnoreRecordNotFoundError: true,
-			Colorful:                  false,
-		},
-	)
+	gormLogConfig := logger.Config{
+		SlowThreshold:             time.Second,
+		LogLevel:                  logger.Info,
+		IgnoreRecordNotFoundError: true,
+		Colorful:                  false,
+	}
+	gormLogger := logger.New(&GormLogWriter{}, gormLogConfig)
 
 	gormConfig := &gorm.Config{
-		Logger: gormLogger,
+		Logger: contextLogger{Interface: gormLogger, level: gormLogConfig.LogLevel, slowThreshold: gormLogConfig.SlowThreshold},
+		// Timestamps are compared as text in SQLite, so they must all share one offset
+		NowFunc: func() time.Time { return time.Now().UTC() },
 	}
 
 	var db *gorm.DB
@@ -91,6 +184,44 @@ func (w *GormLogWriter) Printf(format string, args ...interface{}) {
 	log.Debug().Msgf(format, args...)
 }
 
+// contextLogger routes GORM query traces through the logger carried in the query context,
+// so SQL emitted while handling an interaction shares that interaction's correlation ID.
+// Like GORM's default logger, it logs failed queries at Error, queries slower than
+// slowThreshold at Warn and every other query only at the Info level.
+type contextLogger struct {
+	logger.Interface
+	level         logger.LogLevel
+	slowThreshold time.Duration // Zero disables slow query warnings
+}
+
+// LogMode implements logger.Interface while keeping the context-aware wrapper
+func (l contextLogger) LogMode(level logger.LogLevel) logger.Interface {
+	return contextLogger{Interface: l.Interface.LogMode(level), level: level, slowThreshold: l.slowThreshold}
+}
+
+// Trace implements logger.Interface
+func (l contextLogger) Trace(ctx context.Context, begin time.Time, fc func() (string, int64), err error) {
+	if l.level <= logger.Silent {
+		return
+	}
+
+	elapsed := time.Since(begin)
+	var event *zerolog.Event
+	switch {
+	case err != nil && l.level >= logger.Error && !errors.Is(err, gorm.ErrRecordNotFound):
+		event = zerolog.Ctx(ctx).Error().Err(err)
+	case l.slowThreshold > 0 && elapsed > l.slowThreshold && l.level >= logger.Warn:
+		event = zerolog.Ctx(ctx).Warn().Dur("threshold", l.slowThreshold)
+	case l.level >= logger.Info:
+		event = zerolog.Ctx(ctx).Debug()
+	default:
+		return
+	}
+
+	sql, rows := fc()
+	event.Dur("elapsed", elapsed).Int64("rows", rows).Str("sql", sql).Msg("Database query")
+}
+
 // GetDB returns the underlying GORM DB instance
 func (r *Repository) GetDB() *gorm.DB {
 	return r.db
@@ -108,26 +239,43 @@ func (r *Repository) withContext(ctx context.Context) *gorm.DB {
 
 // CreateProblem creates a new problem entry with transaction support
 func (r *Repository) CreateProblem(ctx context.Context, entry *ProblemEntry) error {
-	if err := ValidateProblemEntry(entry); err != nil {
+	if err := ValidateProblemEntry(entry, r.config.MaxTagsPerProblem); err != nil {
+		return err
+	}
+
+	// Execute in a transaction
+	err := r.WithRetry(ctx, defaultTxRetries, func(tx *gorm.DB) error {
+		return insertProblem(tx, entry)
+	})
+	if err != nil {
 		return err
 	}
 
+	r.invalidateUser(entry.UserID)
+	return nil
+}
+
+// insertProblem creates a validated problem entry and its tags within tx, setting the entry's ID
+func insertProblem(tx *gorm.DB, entry *ProblemEntry) error {
 	// Convert DTO to model
 	problem := entry.ToProblem()
+	problem.InitialStatus = problem.Status
 
-	// Execute in a transaction
-	err := r.withContext(ctx).Transaction(func(tx *gorm.DB) error {
-		// Create problem with associations
-		if err := tx.Create(problem).Error; err != nil {
-			return fmt.Errorf("failed to create problem: %w", err)
-		}
+	// Reuse the user's existing tags so associations point at the right rows
+	tags, err := findOrCreateTags(tx, problem.UserID, problem.Tags)
+	if err != nil {
+		return err
+	}
+	problem.Tags = tags
 
-		// Update the ID in the entry
-		entry.ID = problem.ID
-		return nil
-	})
+	// Create problem with associations
+	if err := tx.Create(problem).Error; err != nil {
+		return fmt.Errorf("failed to create problem: %w", err)
+	}
 
-	return err
+	// Update the ID in the entry
+	entry.ID = problem.ID
+	return nil
 }
 
 // GetProblem retrieves a problem by ID with its associated tags
@@ -136,7 +284,7 @@ func (r *Repository) GetProblem(ctx context.Context, id uint) (*ProblemEntry, er
 	err := r.withContext(ctx).Preload("Tags").First(&problem, id).Error
 	if err != nil {
 		if err == gorm.ErrRecordNotFound {
-			return nil, fmt.Errorf("problem not found: %d", id)
+			return nil, fmt.Errorf("%w: %d", ErrProblemNotFound, id)
 		}
 		return nil, fmt.Errorf("failed to get problem: %w", err)
 	}
@@ -144,9 +292,45 @@ func (r *Repository) GetProblem(ctx context.Context, id uint) (*ProblemEntry, er
 	return FromProblem(&problem), nil
 }
 
+// AmbiguousProblemError is returned by GetProblemByName when several of the user's problems
+// share the requested name
+type AmbiguousProblemError struct {
+	Name    string
+	Matches []*ProblemEntry
+}
+
+// Error implements error
+func (e *AmbiguousProblemError) Error() string {
+	return fmt.Sprintf("%d problems named %q", len(e.Matches), e.Name)
+}
+
+// GetProblemByName retrieves one of the user's problems by name, ignoring case and surrounding
+// whitespace. It returns an *AmbiguousProblemError listing the candidates when more than one matches.
+func (r *Repository) GetProblemByName(ctx context.Context, userID, name string) (*ProblemEntry, error) {
+	name = strings.TrimSpace(name)
+
+	var problems []Problem
+	err := r.withContext(ctx).Preload("Tags").
+		Where("user_id = ? AND LOWER(problem_name) = LOWER(?)", userID, name).
+		Order("solved_at ASC").
+		Find(&problems).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get problem: %w", err)
+	}
+
+	switch len(problems) {
+	case 0:
+		return nil, fmt.Errorf("%w: %s", ErrProblemNotFound, name)
+	case 1:
+		return FromProblem(&problems[0]), nil
+	default:
+		return nil, &AmbiguousProblemError{Name: name, Matches: toEntries(problems)}
+	}
+}
+
 // UpdateProblem updates an existing problem entry with its tags
 func (r *Repository) UpdateProblem(ctx context.Context, entry *ProblemEntry) error {
-	if err := ValidateProblemEntry(entry); err != nil {
+	if err := ValidateProblemEntry(entry, r.config.MaxTagsPerProblem); err != nil {
 		return err
 	}
 
@@ -154,28 +338,29 @@ func (r *Repository) UpdateProblem(ctx context.Context, entry *ProblemEntry) err
 	problem := entry.ToProblem()
 
 	// Execute in a transaction
-	err := r.withContext(ctx).Transaction(func(tx *gorm.DB) error {
+	err := r.WithRetry(ctx, defaultTxRetries, func(tx *gorm.DB) error {
 		// First, find the existing problem to update
 		var existingProblem Problem
 		if err := tx.First(&existingProblem, problem.ID).Error; err != nil {
 			if err == gorm.ErrRecordNotFound {
-				return fmt.Errorf("problem not found: %d", problem.ID)
+				return fmt.Errorf("%w: %d", ErrProblemNotFound, problem.ID)
 			}
 			return fmt.Errorf("failed to find problem: %w", err)
 		}
 
 		// Update the problem fields (excluding associations)
 		if err := tx.Model(&existingProblem).Omit("Tags").Updates(map[string]interface{}{
-			"UserID":         problem.UserID,
-			"ProblemName":    problem.ProblemName,
-			"Link":           problem.Link,
-			"Difficulty":     problem.Difficulty,
-			"Category":       problem.Category,
-			"Status":         problem.Status,
-			"SolvedAt":       problem.SolvedAt,
-			"LastReviewedAt": problem.LastReviewedAt,
-			"ReviewCount":    problem.ReviewCount,
-			"Notes":          problem.Notes,
+			"UserID":              problem.UserID,
+			"ProblemName":         problem.ProblemName,
+			"Link":                problem.Link,
+			"Difficulty":          problem.Difficulty,
+			"PerceivedDifficulty": problem.PerceivedDifficulty,
+			"Category":            problem.Category,
+			"Status":              problem.Status,
+			"SolvedAt":            problem.SolvedAt,
+			"LastReviewedAt":      problem.LastReviewedAt,
+			"ReviewCount":         problem.ReviewCount,
+			"Notes":               problem.Notes,
 		}).Error; err != nil {
 			return fmt.Errorf("failed to update problem: %w", err)
 		}
@@ -186,48 +371,50 @@ func (r *Repository) UpdateProblem(ctx context.Context, entry *ProblemEntry) err
 		}
 
 		// Add new tags
-		for _, tag := range problem.Tags {
-			var existingTag Tag
-			// First check if the tag exists
-			result := tx.Where("name = ?", tag.Name).First(&existingTag)
-			if result.Error != nil {
-				if result.Error == gorm.ErrRecordNotFound {
-					// Create the tag if it doesn't exist
-					if err := tx.Create(&tag).Error; err != nil {
-						return fmt.Errorf("failed to create tag: %w", err)
-					}
-					existingTag = tag
-				} else {
-					return fmt.Errorf("failed to query tag: %w", result.Error)
-				}
-			}
-
-			// Associate the tag with the problem
-			if err := tx.Model(&existingProblem).Association("Tags").Append(&existingTag); err != nil {
-				return fmt.Errorf("failed to associate tag: %w", err)
+		tags, err := findOrCreateTags(tx, problem.UserID, problem.Tags)
+		if err != nil {
+			return err
+		}
+		if len(tags) > 0 {
+			if err := tx.Model(&existingProblem).Association("Tags").Append(tags); err != nil {
+				return fmt.Errorf("failed to associate tags: %w", err)
 			}
 		}
 
 		return nil
 	})
+	if err != nil {
+		return err
+	}
 
-	return err
+	r.invalidateUser(problem.UserID)
+	return nil
 }
 
-// DeleteProblem deletes a problem by ID
+// DeleteProblem deletes a problem by ID and removes any of its owner's tags left unused
 func (r *Repository) DeleteProblem(ctx context.Context, id uint) error {
-	return r.withContext(ctx).Transaction(func(tx *gorm.DB) error {
-		// Delete the problem (this will automatically handle the problem_tags junction table)
-		result := tx.Delete(&Problem{}, id)
-		if result.Error != nil {
-			return fmt.Errorf("failed to delete problem: %w", result.Error)
+	return r.WithRetry(ctx, defaultTxRetries, func(tx *gorm.DB) error {
+		var problem Problem
+		if err := tx.Select("id", "user_id").First(&problem, id).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return fmt.Errorf("%w: %d", ErrProblemNotFound, id)
+			}
+			return fmt.Errorf("failed to find problem: %w", err)
+		}
+
+		// Delete the problem and detach its tags
+		if err := tx.Delete(&problem).Error; err != nil {
+			return fmt.Errorf("failed to delete problem: %w", err)
 		}
-		if result.RowsAffected == 0 {
-			return fmt.Errorf("problem not found: %d", id)
+		if err := tx.Exec("DELETE FROM problem_tags WHERE problem_id = ?", id).Error; err != nil {
+			return fmt.Errorf("failed to remove problem tags: %w", err)
+		}
+		if err := tx.Exec("DELETE FROM problem_set_items WHERE problem_id = ?", id).Error; err != nil {
+			return fmt.Errorf("failed to remove problem from sets: %w", err)
 		}
 
-		// Optionally, clean up orphaned tags
-		if err := tx.Exec("DELETE FROM tags WHERE id NOT IN (SELECT tag_id FROM problem_tags)").Error; err != nil {
+		// Clean up the owner's orphaned tags; other users' tags are never touched
+		if err := tx.Exec("DELETE FROM tags WHERE user_id = ? AND id NOT IN (SELECT tag_id FROM problem_tags)", problem.UserID).Error; err != nil {
 			return fmt.Errorf("failed to clean up orphaned tags: %w", err)
 		}
 
@@ -235,32 +422,45 @@ func (r *Repository) DeleteProblem(ctx context.Context, id uint) error {
 	})
 }
 
-// ListProblems retrieves a list of problems based on filters
-func (r *Repository) ListProblems(ctx context.Context, userID, status, difficulty, category string, tagNames []string, limit, offset int) ([]*ProblemEntry, error) {
+// SetArchived archives or restores a problem. Archived problems are left out of reviews.
+func (r *Repository) SetArchived(ctx context.Context, id uint, archived bool) error {
+	result := r.withContext(ctx).Model(&Problem{}).Where("id = ?", id).Update("archived", archived)
+	if result.Error != nil {
+		return fmt.Errorf("failed to update archived state: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("%w: %d", ErrProblemNotFound, id)
+	}
+	return nil
+}
+
+// ListProblems retrieves a list of problems based on filters, narrowed further by any scopes
+func (r *Repository) ListProblems(ctx context.Context, userID, status, difficulty, category string, tagNames []string, includeArchived bool, limit, offset int, scopes ...ProblemScope) ([]*ProblemEntry, error) {
 	query := r.withContext(ctx).Model(&Problem{}).Preload("Tags")
+	for _, scope := range scopes {
+		query = scope(query)
+	}
 
 	// Apply filters
 	if userID != "" {
-		query = query.Where("user_id = ?", userID)
+		query = query.Where("problems.user_id = ?", userID)
 	}
 	if status != "" {
-		query = query.Where("status = ?", status)
+		query = query.Where("problems.status = ?", status)
 	}
 	if difficulty != "" {
-		query = query.Where("difficulty = ?", difficulty)
+		query = query.Where("problems.difficulty = ?", difficulty)
 	}
 	if category != "" {
-		query = query.Where("category = ?", category)
+		query = query.Where("problems.category = ?", category)
 	}
-
-	// Filter by tags if provided
-	if len(tagNames) > 0 {
-		// Join with problem_tags and tags tables to filter by tag names
-		query = query.Joins("JOIN problem_tags ON problems.id = problem_tags.problem_id").
-			Joins("JOIN tags ON problem_tags.tag_id = tags.id").
-			Where("tags.name IN ?", tagNames)
+	if !includeArchived {
+		query = query.Where("problems.archived = ?", false)
 	}
 
+	// Filter by tags if provided, matching any of them
+	query = TaggedWith(userID, tagNames, false)(query)
+
 	// Apply pagination
 	if limit > 0 {
 		query = query.Limit(limit)
@@ -271,7 +471,7 @@ func (r *Repository) ListProblems(ctx context.Context, userID, status, difficult
 
 	// Execute query
 	var problems []Problem
-	if err := query.Order("solved_at DESC").Find(&problems).Error; err != nil {
+	if err := query.Order("problems.solved_at DESC").Find(&problems).Error; err != nil {
 		return nil, fmt.Errorf("failed to list problems: %w", err)
 	}
 
@@ -284,18 +484,24 @@ func (r *Repository) ListProblems(ctx context.Context, userID, status, difficult
 	return result, nil
 }
 
-// ListProblemsForReview retrieves problems that need to be reviewed based on the lookback period
-func (r *Repository) ListProblemsForReview(ctx context.Context, userID string, lookbackPeriod time.Duration) ([]*ProblemEntry, error) {
-	cutoff := time.Now().Add(-lookbackPeriod)
+// ListProblemsForReview retrieves problems that need to be reviewed based on the lookback period,
+// highest review priority first, narrowed by any scopes. Shortening the lookback includes problems
+// that become due soon.
+func (r *Repository) ListProblemsForReview(ctx context.Context, userID string, lookbackPeriod time.Duration, scopes ...ProblemScope) ([]*ProblemEntry, error) {
+	cutoff := time.Now().UTC().Add(-lookbackPeriod)
+
+	query := r.withContext(ctx).Model(&Problem{})
+	for _, scope := range scopes {
+		query = scope(query)
+	}
 
 	var problems []Problem
-	err := r.withContext(ctx).Model(&Problem{}).
+	err := query.
 		Preload("Tags").
 		Where("user_id = ?", userID).
+		Where("archived = ?", false).
 		Where("solved_at <= ?", cutoff).
-		Where(func(db *gorm.DB) *gorm.DB {
-			return db.Where("last_reviewed_at IS NULL OR last_reviewed_at <= ?", cutoff)
-		}).
+		Where("(last_reviewed_at IS NULL OR last_reviewed_at <= ?)", cutoff).
 		Order("solved_at ASC").
 		Find(&problems).Error
 
@@ -309,18 +515,27 @@ func (r *Repository) ListProblemsForReview(ctx context.Context, userID string, l
 		result[i] = FromProblem(&problem)
 	}
 
+	// Most valuable reviews first; solved_at order breaks ties
+	sortByReviewPriority(result, time.Now())
+
 	return result, nil
 }
 
-// IncrementReviewCount increments the review count and updates the last reviewed timestamp
+// IncrementReviewCount increments the review count, updates the last reviewed timestamp,
+// and records the review in the problem's history
 func (r *Repository) IncrementReviewCount(ctx context.Context, problemID uint) error {
-	now := time.Now()
-	err := r.withContext(ctx).Model(&Problem{}).
-		Where("id = ?", problemID).
-		Updates(map[string]interface{}{
-			"review_count":     gorm.Expr("review_count + 1"),
-			"last_reviewed_at": now,
-		}).Error
+	now := time.Now().UTC()
+	err := r.WithRetry(ctx, defaultTxRetries, func(tx *gorm.DB) error {
+		if err := tx.Model(&Problem{}).
+			Where("id = ?", problemID).
+			Updates(map[string]interface{}{
+				"review_count":     gorm.Expr("review_count + 1"),
+				"last_reviewed_at": now,
+			}).Error; err != nil {
+			return err
+		}
+		return tx.Create(&ReviewHistory{ProblemID: problemID, ReviewedAt: now}).Error
+	})
 
 	if err != nil {
 		return fmt.Errorf("failed to increment review count: %w", err)
@@ -328,6 +543,22 @@ func (r *Repository) IncrementReviewCount(ctx context.Context, problemID uint) e
 	return nil
 }
 
+// ListReviewHistory returns when a problem was reviewed, most recent first
+func (r *Repository) ListReviewHistory(ctx context.Context, problemID uint, limit int) ([]time.Time, error) {
+	query := r.withContext(ctx).Model(&ReviewHistory{}).
+		Where("problem_id = ?", problemID).
+		Order("reviewed_at DESC")
+	if limit > 0 {
+		query = query.Limit(limit)
+	}
+
+	var reviewedAt []time.Time
+	if err := query.Pluck("reviewed_at", &reviewedAt).Error; err != nil {
+		return nil, fmt.Errorf("failed to list review history: %w", err)
+	}
+	return reviewedAt, nil
+}
+
 // ListAllUsers lists all unique user IDs in the database
 func (r *Repository) ListAllUsers(ctx context.Context) ([]string, error) {
 	var userIDs []string
@@ -341,6 +572,29 @@ func (r *Repository) ListAllUsers(ctx context.Context) ([]string, error) {
 	return userIDs, nil
 }
 
+// CountAllProblems returns the total number of problems across all users
+func (r *Repository) CountAllProblems(ctx context.Context) (int64, error) {
+	var count int64
+	if err := r.withContext(ctx).Model(&Problem{}).Count(&count).Error; err != nil {
+		return 0, fmt.Errorf("failed to count problems: %w", err)
+	}
+	return count, nil
+}
+
+// CountActiveUsers returns the number of distinct users who solved a problem since the given time
+func (r *Repository) CountActiveUsers(ctx context.Context, since time.Time) (int64, error) {
+	var count int64
+	err := r.withContext(ctx).Model(&Problem{}).
+		Where("solved_at >= ?", since.UTC()).
+		Distinct("user_id").
+		Count(&count).Error
+
+	if err != nil {
+		return 0, fmt.Errorf("failed to count active users: %w", err)
+	}
+	return count, nil
+}
+
 // AutoMigrate runs GORM's auto-migration for database tables
 // Note: We're keeping the existing migration system, but this is useful for development
 func (r *Repository) AutoMigrate() error {
@@ -0,0 +1,52 @@
+package database
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func TestContextLoggerTrace(t *testing.T) {
+	tests := []struct {
+		name    string
+		level   logger.LogLevel
+		elapsed time.Duration
+		err     error
+		want    string // Level of the logged line, or empty for none
+	}{
+		{name: "query at info", level: logger.Info, want: "debug"},
+		{name: "query at warn", level: logger.Warn},
+		{name: "silent", level: logger.Silent, err: errors.New("disk I/O error")},
+		{name: "failed query", level: logger.Error, err: errors.New("disk I/O error"), want: "error"},
+		{name: "record not found", level: logger.Error, err: gorm.ErrRecordNotFound},
+		{name: "slow query", level: logger.Warn, elapsed: 2 * time.Second, want: "warn"},
+		{name: "slow query at error", level: logger.Error, elapsed: 2 * time.Second},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			ctx := zerolog.New(&buf).Level(zerolog.DebugLevel).WithContext(context.Background())
+			l := contextLogger{Interface: logger.Discard, slowThreshold: time.Second}.LogMode(tt.level)
+
+			l.Trace(ctx, time.Now().Add(-tt.elapsed), func() (string, int64) { return "SELECT 1", 1 }, tt.err)
+
+			got := buf.String()
+			if tt.want == "" {
+				if got != "" {
+					t.Errorf("logged %q, want nothing", got)
+				}
+				return
+			}
+			if !strings.Contains(got, `"level":"`+tt.want+`"`) || !strings.Contains(got, "SELECT 1") {
+				t.Errorf("logged %q, want the query at %s", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,159 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// DuplicateGroup is a set of a user's problems with the same normalized name. Keep is the
+// earliest solved, which the others are merged into.
+type DuplicateGroup struct {
+	Keep       *ProblemEntry
+	Duplicates []*ProblemEntry
+}
+
+// MergeDuplicateProblems finds the user's problems whose names match ignoring case and spacing
+// and merges each set into its earliest entry: tags are combined, review counts are added up to
+// match the review history moved over, the latest review is kept and the other entries are deleted. With
+// dryRun nothing is changed. It returns the groups found.
+func (r *Repository) MergeDuplicateProblems(ctx context.Context, userID string, dryRun bool) ([]DuplicateGroup, error) {
+	var groups []DuplicateGroup
+	err := r.WithRetry(ctx, defaultTxRetries, func(tx *gorm.DB) error {
+		var problems []Problem
+		if err := tx.Preload("Tags").
+			Where("user_id = ?", userID).
+			Order("solved_at ASC, id ASC").
+			Find(&problems).Error; err != nil {
+			return fmt.Errorf("failed to list problems: %w", err)
+		}
+
+		groups = findDuplicateGroups(problems)
+		if dryRun {
+			return nil
+		}
+		for _, group := range groups {
+			if err := mergeDuplicateGroup(tx, group); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return groups, nil
+}
+
+// findDuplicateGroups groups problems, given earliest first, by normalized name and returns
+// the groups with more than one problem in order of their earliest entry
+func findDuplicateGroups(problems []Problem) []DuplicateGroup {
+	byName := make(map[string]*DuplicateGroup)
+	var order []string
+	for idx := range problems {
+		entry := FromProblem(&problems[idx])
+		name := normalizeProblemName(entry.ProblemName)
+		if group, ok := byName[name]; ok {
+			group.Duplicates = append(group.Duplicates, entry)
+			continue
+		}
+		byName[name] = &DuplicateGroup{Keep: entry}
+		order = append(order, name)
+	}
+
+	var groups []DuplicateGroup
+	for _, name := range order {
+		if group := byName[name]; len(group.Duplicates) > 0 {
+			groups = append(groups, *group)
+		}
+	}
+	return groups
+}
+
+// mergeDuplicateGroup folds a group's duplicates into the problem it keeps and deletes them
+func mergeDuplicateGroup(tx *gorm.DB, group DuplicateGroup) error {
+	keep := group.Keep
+	updates := map[string]interface{}{}
+	duplicateIDs := make([]uint, len(group.Duplicates))
+
+	reviewCount := keep.ReviewCount
+	lastReviewedAt := keep.LastReviewedAt
+	notes := keep.Notes
+	for idx, dup := range group.Duplicates {
+		duplicateIDs[idx] = dup.ID
+		reviewCount += dup.ReviewCount // Each duplicate's reviews move to the kept problem below
+		if dup.LastReviewedAt != nil && (lastReviewedAt == nil || dup.LastReviewedAt.After(*lastReviewedAt)) {
+			lastReviewedAt = dup.LastReviewedAt
+		}
+		if notes == "" {
+			notes = dup.Notes
+		}
+	}
+	if reviewCount != keep.ReviewCount {
+		updates["review_count"] = reviewCount
+	}
+	if lastReviewedAt != keep.LastReviewedAt {
+		updates["last_reviewed_at"] = lastReviewedAt
+	}
+	if notes != keep.Notes {
+		updates["notes"] = notes
+	}
+
+	if len(updates) > 0 {
+		if err := tx.Model(&Problem{}).Where("id = ?", keep.ID).Updates(updates).Error; err != nil {
+			return fmt.Errorf("failed to update merged problem: %w", err)
+		}
+	}
+
+	// Tags are per user, so the duplicates' tag rows can be linked to the kept problem as they are
+	var tagIDs []uint
+	if err := tx.Table("problem_tags").Where("problem_id IN ?", duplicateIDs).Pluck("tag_id", &tagIDs).Error; err != nil {
+		return fmt.Errorf("failed to find duplicate tags: %w", err)
+	}
+	links := make([]map[string]interface{}, 0, len(tagIDs))
+	for _, tagID := range tagIDs {
+		links = append(links, map[string]interface{}{"problem_id": keep.ID, "tag_id": tagID})
+	}
+	if len(links) > 0 {
+		if err := tx.Table("problem_tags").Clauses(clause.OnConflict{DoNothing: true}).Create(links).Error; err != nil {
+			return fmt.Errorf("failed to merge tags: %w", err)
+		}
+	}
+
+	if err := tx.Model(&ReviewHistory{}).Where("problem_id IN ?", duplicateIDs).Update("problem_id", keep.ID).Error; err != nil {
+		return fmt.Errorf("failed to move review history: %w", err)
+	}
+
+	// Sets holding a duplicate hold the kept problem instead
+	var setIDs []uint
+	if err := tx.Model(&ProblemSetItem{}).Where("problem_id IN ?", duplicateIDs).Pluck("set_id", &setIDs).Error; err != nil {
+		return fmt.Errorf("failed to find duplicate set items: %w", err)
+	}
+	items := make([]ProblemSetItem, 0, len(setIDs))
+	for _, setID := range setIDs {
+		items = append(items, ProblemSetItem{SetID: setID, ProblemID: keep.ID})
+	}
+	if len(items) > 0 {
+		if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&items).Error; err != nil {
+			return fmt.Errorf("failed to merge set items: %w", err)
+		}
+	}
+	if err := tx.Where("problem_id IN ?", duplicateIDs).Delete(&ProblemSetItem{}).Error; err != nil {
+		return fmt.Errorf("failed to remove duplicate set items: %w", err)
+	}
+	if err := tx.Exec("DELETE FROM problem_tags WHERE problem_id IN ?", duplicateIDs).Error; err != nil {
+		return fmt.Errorf("failed to remove duplicate tags: %w", err)
+	}
+	if err := tx.Delete(&Problem{}, duplicateIDs).Error; err != nil {
+		return fmt.Errorf("failed to delete duplicates: %w", err)
+	}
+	return nil
+}
+
+// normalizeProblemName reduces a problem name to the form duplicates are compared in
+func normalizeProblemName(name string) string {
+	return strings.ToLower(strings.Join(strings.Fields(name), " "))
+}
@@ -0,0 +1,109 @@
+package database
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+)
+
+// addDuplicate stores a problem for dedupe-user solved daysLater days after seedBaseTime
+func addDuplicate(t *testing.T, repo *Repository, name string, daysLater int, tags ...string) *ProblemEntry {
+	t.Helper()
+	entry := &ProblemEntry{UserID: "dedupe-user", ProblemName: name, Difficulty: DifficultyEasy, Category: "Array", Status: StatusSolved, SolvedAt: seedBaseTime.AddDate(0, 0, daysLater), Tags: tags}
+	if err := repo.CreateProblem(context.Background(), entry); err != nil {
+		t.Fatalf("CreateProblem: %v", err)
+	}
+	return entry
+}
+
+// reviewTimes records n reviews of the problem with id
+func reviewTimes(t *testing.T, repo *Repository, id uint, n int) {
+	t.Helper()
+	for range n {
+		if err := repo.IncrementReviewCount(context.Background(), id); err != nil {
+			t.Fatalf("IncrementReviewCount: %v", err)
+		}
+	}
+}
+
+func TestMergeDuplicateProblems(t *testing.T) {
+	ctx := context.Background()
+	repo := newTestRepository(t)
+
+	later := addDuplicate(t, repo, "two  sum", 3, "hash-table")
+	earliest := addDuplicate(t, repo, "Two Sum", 0, "array")
+	addDuplicate(t, repo, "TWO SUM", 5, "array", "two-pointers")
+	addDuplicate(t, repo, "Coin Change", 1, "dp")
+	reviewTimes(t, repo, earliest.ID, 1)
+	reviewTimes(t, repo, later.ID, 2)
+
+	groups, err := repo.MergeDuplicateProblems(ctx, "dedupe-user", false)
+	if err != nil {
+		t.Fatalf("MergeDuplicateProblems: %v", err)
+	}
+	if len(groups) != 1 || groups[0].Keep.ID != earliest.ID || len(groups[0].Duplicates) != 2 {
+		t.Fatalf("groups = %+v, want Two Sum kept with two duplicates", groups)
+	}
+
+	problems, err := repo.ListProblems(ctx, "dedupe-user", "", "", "", nil, true, 0, 0)
+	if err != nil {
+		t.Fatalf("ListProblems: %v", err)
+	}
+	if got := problemNames(problems); !slices.Equal(got, []string{"Coin Change", "Two Sum"}) {
+		t.Fatalf("problems = %v, want Coin Change and the kept Two Sum", got)
+	}
+
+	merged, err := repo.GetProblem(ctx, earliest.ID)
+	if err != nil {
+		t.Fatalf("GetProblem: %v", err)
+	}
+	// Tags are combined and every review moves over with its history
+	if got, want := sortedTags(merged.Tags), []string{"array", "hash-table", "two-pointers"}; !slices.Equal(got, want) {
+		t.Errorf("merged tags = %v, want %v", got, want)
+	}
+	history, err := repo.ListReviewHistory(ctx, merged.ID, 0)
+	if err != nil {
+		t.Fatalf("ListReviewHistory: %v", err)
+	}
+	if merged.ReviewCount != 3 || len(history) != 3 {
+		t.Errorf("merged problem has %d reviews and %d history entries, want 3 of each", merged.ReviewCount, len(history))
+	}
+	if merged.LastReviewedAt == nil || time.Since(*merged.LastReviewedAt) > time.Minute {
+		t.Errorf("LastReviewedAt = %v, want the latest review", merged.LastReviewedAt)
+	}
+
+	// Running again finds nothing left to merge
+	if groups, err := repo.MergeDuplicateProblems(ctx, "dedupe-user", false); err != nil || len(groups) != 0 {
+		t.Errorf("second MergeDuplicateProblems = %d groups, %v, want none", len(groups), err)
+	}
+}
+
+func TestMergeDuplicateProblemsDryRun(t *testing.T) {
+	ctx := context.Background()
+	repo := newTestRepository(t)
+	addDuplicate(t, repo, "Two Sum", 0, "array")
+	addDuplicate(t, repo, "two sum", 1, "hash-table")
+	seedTestData(t, repo, "other-user")
+
+	groups, err := repo.MergeDuplicateProblems(ctx, "dedupe-user", true)
+	if err != nil {
+		t.Fatalf("MergeDuplicateProblems: %v", err)
+	}
+	if len(groups) != 1 || len(groups[0].Duplicates) != 1 || groups[0].Keep.ProblemName != "Two Sum" {
+		t.Errorf("groups = %+v, want Two Sum with one duplicate", groups)
+	}
+
+	problems, err := repo.ListProblems(ctx, "dedupe-user", "", "", "", nil, true, 0, 0)
+	if err != nil {
+		t.Fatalf("ListProblems: %v", err)
+	}
+	if len(problems) != 2 {
+		t.Errorf("dry run left %d problems, want both untouched", len(problems))
+	}
+	for _, p := range problems {
+		if len(p.Tags) != 1 {
+			t.Errorf("dry run changed the tags of %q to %v", p.ProblemName, p.Tags)
+		}
+	}
+}
@@ -0,0 +1,53 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// ImportProblems creates a user's problem entries in one transaction. Entries whose name, ignoring
+// case, the user has already logged or that repeat an earlier entry are skipped and returned.
+func (r *Repository) ImportProblems(ctx context.Context, userID string, entries []*ProblemEntry) ([]*ProblemEntry, error) {
+	for _, entry := range entries {
+		entry.UserID = userID
+		if err := ValidateProblemEntry(entry, r.config.MaxTagsPerProblem); err != nil {
+			return nil, fmt.Errorf("failed to import problem %q: %w", entry.ProblemName, err)
+		}
+	}
+
+	var skipped []*ProblemEntry
+	err := r.WithRetry(ctx, defaultTxRetries, func(tx *gorm.DB) error {
+		skipped = nil
+
+		var names []string
+		if err := tx.Model(&Problem{}).Where("user_id = ?", userID).Pluck("LOWER(problem_name)", &names).Error; err != nil {
+			return err
+		}
+		seen := make(map[string]bool, len(names)+len(entries))
+		for _, name := range names {
+			seen[strings.TrimSpace(name)] = true
+		}
+
+		for _, entry := range entries {
+			key := strings.ToLower(strings.TrimSpace(entry.ProblemName))
+			if seen[key] {
+				skipped = append(skipped, entry)
+				continue
+			}
+			seen[key] = true
+
+			if err := insertProblem(tx, entry); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to import problems: %w", err)
+	}
+	return skipped, nil
+}
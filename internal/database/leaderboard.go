@@ -0,0 +1,100 @@
+package database
+
+import (
+	"context"
+	"fmt"
+
+	"gorm.io/gorm"
+)
+
+// Leaderboard metrics
+const (
+	LeaderboardSolved  = "solved"
+	LeaderboardReviews = "reviews"
+)
+
+// LeaderboardEntry is one member's position on a leaderboard
+type LeaderboardEntry struct {
+	Rank   int
+	UserID string
+	Value  int
+}
+
+// LeaderboardResult is one page of a leaderboard
+type LeaderboardResult struct {
+	Metric     string
+	Page       int
+	TotalUsers int // Members ranked across all pages
+	Entries    []LeaderboardEntry
+}
+
+// CacheInvalidator is told when a user's problems change so cached aggregates can be dropped
+type CacheInvalidator interface {
+	InvalidateUser(userID string)
+}
+
+// SetCacheInvalidator registers the invalidator notified when problems are created or updated
+func (r *Repository) SetCacheInvalidator(invalidator CacheInvalidator) {
+	r.invalidator = invalidator
+}
+
+// invalidateUser notifies the registered cache invalidator, if any, that a user's problems changed
+func (r *Repository) invalidateUser(userID string) {
+	if r.invalidator != nil {
+		r.invalidator.InvalidateUser(userID)
+	}
+}
+
+// leaderboardColumns maps each metric to the aggregate it ranks by
+var leaderboardColumns = map[string]string{
+	LeaderboardSolved:  "COUNT(*)",
+	LeaderboardReviews: "COALESCE(SUM(problems.review_count), 0)",
+}
+
+// GetLeaderboard ranks members with public profiles by the given metric, returning one page of
+// pageSize entries. Pages start at 1.
+func (r *Repository) GetLeaderboard(ctx context.Context, metric string, page, pageSize int) (*LeaderboardResult, error) {
+	column, ok := leaderboardColumns[metric]
+	if !ok {
+		return nil, fmt.Errorf("unknown leaderboard metric: %s", metric)
+	}
+	if page < 1 {
+		page = 1
+	}
+
+	// Private profiles are left out entirely rather than anonymized
+	publicProblems := func() *gorm.DB {
+		return r.withContext(ctx).Model(&Problem{}).
+			Joins("JOIN user_settings ON user_settings.user_id = problems.user_id AND user_settings.profile_public = ?", true)
+	}
+
+	var total int64
+	if err := publicProblems().Distinct("problems.user_id").Count(&total).Error; err != nil {
+		return nil, fmt.Errorf("failed to count leaderboard members: %w", err)
+	}
+
+	var rows []struct {
+		UserID string
+		Value  int
+	}
+	err := publicProblems().
+		Select("problems.user_id AS user_id, " + column + " AS value").
+		Group("problems.user_id").
+		Order("value DESC, problems.user_id ASC").
+		Limit(pageSize).
+		Offset((page - 1) * pageSize).
+		Scan(&rows).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get %s leaderboard: %w", metric, err)
+	}
+
+	result := &LeaderboardResult{Metric: metric, Page: page, TotalUsers: int(total)}
+	for i, row := range rows {
+		result.Entries = append(result.Entries, LeaderboardEntry{
+			Rank:   (page-1)*pageSize + i + 1,
+			UserID: row.UserID,
+			Value:  row.Value,
+		})
+	}
+	return result, nil
+}
@@ -0,0 +1,63 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/rs/zerolog"
+)
+
+// Compact reclaims free pages and refreshes query planner statistics.
+// For SQLite this runs VACUUM followed by ANALYZE; other drivers are left to their own maintenance.
+func (r *Repository) Compact(ctx context.Context) error {
+	logger := zerolog.Ctx(ctx)
+
+	if r.config.Driver != "sqlite3" {
+		logger.Info().Str("driver", r.config.Driver).Msg("Compaction not needed for driver, skipping")
+		return nil
+	}
+
+	path := sqliteFilePath(r.config.DSN)
+	before := fileSize(path)
+
+	if err := r.withContext(ctx).Exec("VACUUM").Error; err != nil {
+		return fmt.Errorf("failed to vacuum database: %w", err)
+	}
+	if err := r.withContext(ctx).Exec("ANALYZE").Error; err != nil {
+		return fmt.Errorf("failed to analyze database: %w", err)
+	}
+
+	logger.Info().
+		Str("path", path).
+		Int64("size_before", before).
+		Int64("size_after", fileSize(path)).
+		Msg("Database compacted")
+	return nil
+}
+
+// sqliteFilePath extracts the database file path from a SQLite DSN,
+// returning an empty string for in-memory databases
+func sqliteFilePath(dsn string) string {
+	path := strings.TrimPrefix(dsn, "file:")
+	if idx := strings.Index(path, "?"); idx >= 0 {
+		path = path[:idx]
+	}
+	if path == ":memory:" || path == "" {
+		return ""
+	}
+	return path
+}
+
+// fileSize returns the size of the file at path, or -1 if it cannot be determined
+func fileSize(path string) int64 {
+	if path == "" {
+		return -1
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return -1
+	}
+	return info.Size()
+}
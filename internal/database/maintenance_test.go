@@ -0,0 +1,57 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"testing"
+)
+
+func TestCompact(t *testing.T) {
+	ctx := context.Background()
+	repo := newTestRepository(t)
+	seedTestData(t, repo, "kept-user")
+
+	// Fill the database, then purge most of it to leave free pages behind
+	for idx := range 20 {
+		seedTestData(t, repo, fmt.Sprintf("purged-user-%d", idx))
+	}
+	for idx := range 20 {
+		if _, err := repo.TruncateUserData(ctx, fmt.Sprintf("purged-user-%d", idx)); err != nil {
+			t.Fatalf("TruncateUserData: %v", err)
+		}
+	}
+	path := sqliteFilePath(repo.config.DSN)
+	before := fileSize(path)
+
+	if err := repo.Compact(ctx); err != nil {
+		t.Fatalf("Compact: %v", err)
+	}
+
+	if after := fileSize(path); after <= 0 || after > before {
+		t.Errorf("file size went from %d to %d bytes, want it not to grow", before, after)
+	}
+	problems, err := repo.ListProblems(ctx, "kept-user", "", "", "", nil, true, 0, 0)
+	if err != nil {
+		t.Fatalf("ListProblems: %v", err)
+	}
+	if len(problems) != 10 {
+		t.Errorf("%d problems left after compacting, want 10", len(problems))
+	}
+}
+
+func TestSQLiteFilePath(t *testing.T) {
+	tests := []struct {
+		dsn  string
+		want string
+	}{
+		{dsn: "grind_review.db", want: "grind_review.db"},
+		{dsn: "file:data/bot.db?_journal_mode=WAL", want: "data/bot.db"},
+		{dsn: ":memory:", want: ""},
+		{dsn: "file::memory:?cache=shared", want: ""},
+	}
+	for _, tt := range tests {
+		if got := sqliteFilePath(tt.dsn); got != tt.want {
+			t.Errorf("sqliteFilePath(%q) = %q, want %q", tt.dsn, got, tt.want)
+		}
+	}
+}
@@ -7,6 +7,9 @@ import (
 	"net/url"
 	"os"
 	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
 
 	"github.com/golang-migrate/migrate/v4"
 	"github.com/golang-migrate/migrate/v4/database/sqlite3"
@@ -20,22 +23,145 @@ const MigrationDir = "internal/database/migrations"
 
 // Migrate runs database migrations to ensure schema is up to date
 func Migrate(ctx context.Context, repo *Repository) error {
+	m, err := newMigrator(repo)
+	if err != nil {
+		return err
+	}
+
+	// Execute migration
+	if err := m.Up(); err != nil {
+		if !errors.Is(err, migrate.ErrNoChange) {
+			return fmt.Errorf("failed to run migrations: %w", err)
+		}
+		log.Info().Msg("Database schema is already up to date")
+	} else {
+		log.Info().Msg("Database migrations completed successfully")
+	}
+
+	return repo.ensureSearchIndex(ctx)
+}
+
+// MigrateDown rolls the schema back to targetVersion by applying down migrations in reverse order.
+// A targetVersion of 0 reverts every migration.
+func MigrateDown(ctx context.Context, repo *Repository, targetVersion uint) error {
+	m, err := newMigrator(repo)
+	if err != nil {
+		return err
+	}
+
+	current, dirty, err := m.Version()
+	if err != nil && !errors.Is(err, migrate.ErrNilVersion) {
+		return fmt.Errorf("failed to read schema version: %w", err)
+	}
+	if dirty {
+		return fmt.Errorf("database schema is dirty at version %d, fix it before rolling back", current)
+	}
+	if targetVersion > current {
+		return fmt.Errorf("target version %d is newer than current version %d", targetVersion, current)
+	}
+
+	// The search index triggers depend on the problems table, so drop them before reshaping it
+	if err := repo.dropSearchIndex(ctx); err != nil {
+		return err
+	}
+
+	if targetVersion == 0 {
+		err = m.Down()
+	} else {
+		err = m.Migrate(targetVersion)
+	}
+	if err != nil {
+		if errors.Is(err, migrate.ErrNoChange) {
+			log.Info().Uint("version", current).Msg("Database schema is already at target version")
+			return nil
+		}
+		return fmt.Errorf("failed to roll back migrations: %w", err)
+	}
+
+	log.Info().Uint("from", current).Uint("to", targetVersion).Msg("Database migrations rolled back successfully")
+	return nil
+}
+
+// MigrationInfo describes one migration file and whether the schema includes it
+type MigrationInfo struct {
+	Version uint
+	Name    string
+	Applied bool
+}
+
+// SchemaStatus is the database's schema version alongside every known migration
+type SchemaStatus struct {
+	Version    uint // 0 when no migration has been applied
+	Dirty      bool // A migration failed part way and needs fixing by hand
+	Migrations []MigrationInfo
+}
+
+// migrationFilePattern matches up migration files, capturing their version and name
+var migrationFilePattern = regexp.MustCompile(`^(\d+)_(.+)\.up\.sql$`)
+
+// GetSchemaStatus reports the schema version and lists the migrations, oldest first, marking
+// those at or below the current version as applied
+func GetSchemaStatus(repo *Repository) (*SchemaStatus, error) {
+	m, err := newMigrator(repo)
+	if err != nil {
+		return nil, err
+	}
+
+	status := &SchemaStatus{}
+	status.Version, status.Dirty, err = m.Version()
+	if err != nil && !errors.Is(err, migrate.ErrNilVersion) {
+		return nil, fmt.Errorf("failed to read schema version: %w", err)
+	}
+
+	migrationPath, err := findMigrationDir()
+	if err != nil {
+		return nil, fmt.Errorf("failed to find migrations directory: %w", err)
+	}
+	entries, err := os.ReadDir(migrationPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list migrations: %w", err)
+	}
+
+	for _, entry := range entries {
+		match := migrationFilePattern.FindStringSubmatch(entry.Name())
+		if match == nil {
+			continue
+		}
+		version, err := strconv.ParseUint(match[1], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid migration version in %s: %w", entry.Name(), err)
+		}
+		status.Migrations = append(status.Migrations, MigrationInfo{
+			Version: uint(version),
+			Name:    match[2],
+			Applied: uint(version) <= status.Version,
+		})
+	}
+
+	sort.Slice(status.Migrations, func(i, j int) bool {
+		return status.Migrations[i].Version < status.Migrations[j].Version
+	})
+	return status, nil
+}
+
+// newMigrator creates a migration instance bound to the repository's connection
+func newMigrator(repo *Repository) (*migrate.Migrate, error) {
 	// Get the underlying SQL database instance from GORM
 	sqlDB, err := repo.db.DB()
 	if err != nil {
-		return fmt.Errorf("failed to get database connection: %w", err)
+		return nil, fmt.Errorf("failed to get database connection: %w", err)
 	}
 
 	// Create migration instance using the SQL DB
 	driver, err := sqlite3.WithInstance(sqlDB, &sqlite3.Config{})
 	if err != nil {
-		return fmt.Errorf("failed to create sqlite driver: %w", err)
+		return nil, fmt.Errorf("failed to create sqlite driver: %w", err)
 	}
 
 	// Find the project root directory to locate migrations
 	migrationPath, err := findMigrationDir()
 	if err != nil {
-		return fmt.Errorf("failed to find migrations directory: %w", err)
+		return nil, fmt.Errorf("failed to find migrations directory: %w", err)
 	}
 
 	// Create migration source URL
@@ -49,20 +175,9 @@ func Migrate(ctx context.Context, repo *Repository) error {
 		driver,
 	)
 	if err != nil {
-		return fmt.Errorf("failed to create migration instance: %w", err)
+		return nil, fmt.Errorf("failed to create migration instance: %w", err)
 	}
-
-	// Execute migration
-	if err := m.Up(); err != nil {
-		if errors.Is(err, migrate.ErrNoChange) {
-			log.Info().Msg("Database schema is already up to date")
-			return nil
-		}
-		return fmt.Errorf("failed to run migrations: %w", err)
-	}
-
-	log.Info().Msg("Database migrations completed successfully")
-	return nil
+	return m, nil
 }
 
 // findMigrationDir attempts to locate the migrations directory by walking up
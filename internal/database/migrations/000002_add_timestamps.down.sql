@@ -0,0 +1,12 @@
+-- Drop indices
+DROP INDEX IF EXISTS idx_tags_deleted_at;
+DROP INDEX IF EXISTS idx_problems_deleted_at;
+
+-- Drop bookkeeping columns
+ALTER TABLE tags DROP COLUMN deleted_at;
+ALTER TABLE tags DROP COLUMN updated_at;
+ALTER TABLE tags DROP COLUMN created_at;
+
+ALTER TABLE problems DROP COLUMN deleted_at;
+ALTER TABLE problems DROP COLUMN updated_at;
+ALTER TABLE problems DROP COLUMN created_at;
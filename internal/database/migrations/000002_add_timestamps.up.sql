@@ -0,0 +1,12 @@
+-- Add the bookkeeping columns expected by the GORM models
+ALTER TABLE problems ADD COLUMN created_at TIMESTAMP;
+ALTER TABLE problems ADD COLUMN updated_at TIMESTAMP;
+ALTER TABLE problems ADD COLUMN deleted_at TIMESTAMP;
+
+ALTER TABLE tags ADD COLUMN created_at TIMESTAMP;
+ALTER TABLE tags ADD COLUMN updated_at TIMESTAMP;
+ALTER TABLE tags ADD COLUMN deleted_at TIMESTAMP;
+
+-- Soft-delete lookups filter on deleted_at
+CREATE INDEX IF NOT EXISTS idx_problems_deleted_at ON problems(deleted_at);
+CREATE INDEX IF NOT EXISTS idx_tags_deleted_at ON tags(deleted_at);
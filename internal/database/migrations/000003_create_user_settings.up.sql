@@ -0,0 +1,7 @@
+-- Create user_settings table (one row per user, absent rows mean defaults)
+CREATE TABLE IF NOT EXISTS user_settings (
+    user_id TEXT PRIMARY KEY,
+    profile_public BOOLEAN NOT NULL DEFAULT 0,
+    created_at TIMESTAMP,
+    updated_at TIMESTAMP
+);
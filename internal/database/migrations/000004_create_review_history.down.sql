@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_review_history_problem_id;
+DROP TABLE IF EXISTS review_history;
@@ -0,0 +1,9 @@
+-- Create review_history table (one row per completed review)
+CREATE TABLE IF NOT EXISTS review_history (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    problem_id INTEGER NOT NULL,
+    reviewed_at TIMESTAMP NOT NULL,
+    FOREIGN KEY (problem_id) REFERENCES problems(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_review_history_problem_id ON review_history(problem_id);
@@ -0,0 +1,36 @@
+-- Merge per-user tags back into a single global namespace
+CREATE TABLE tags_global (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    name TEXT NOT NULL UNIQUE,
+    created_at TIMESTAMP,
+    updated_at TIMESTAMP,
+    deleted_at TIMESTAMP
+);
+
+INSERT INTO tags_global (name, created_at, updated_at)
+SELECT name, MIN(created_at), MAX(updated_at)
+FROM tags
+GROUP BY name;
+
+CREATE TABLE problem_tags_global (
+    problem_id INTEGER NOT NULL,
+    tag_id INTEGER NOT NULL,
+    PRIMARY KEY (problem_id, tag_id),
+    FOREIGN KEY (problem_id) REFERENCES problems(id) ON DELETE CASCADE,
+    FOREIGN KEY (tag_id) REFERENCES tags(id) ON DELETE CASCADE
+);
+
+INSERT OR IGNORE INTO problem_tags_global (problem_id, tag_id)
+SELECT problem_tags.problem_id, tags_global.id
+FROM problem_tags
+JOIN tags ON tags.id = problem_tags.tag_id
+JOIN tags_global ON tags_global.name = tags.name;
+
+DROP TABLE problem_tags;
+DROP TABLE tags;
+ALTER TABLE tags_global RENAME TO tags;
+ALTER TABLE problem_tags_global RENAME TO problem_tags;
+
+CREATE INDEX IF NOT EXISTS idx_tags_deleted_at ON tags(deleted_at);
+CREATE INDEX IF NOT EXISTS idx_problem_tags_problem_id ON problem_tags(problem_id);
+CREATE INDEX IF NOT EXISTS idx_problem_tags_tag_id ON problem_tags(tag_id);
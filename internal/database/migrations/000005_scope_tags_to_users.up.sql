@@ -0,0 +1,44 @@
+-- Recreate tags with a per-user namespace: each user gets their own copy of the tags they use
+CREATE TABLE tags_scoped (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    user_id TEXT NOT NULL,
+    name TEXT NOT NULL,
+    created_at TIMESTAMP,
+    updated_at TIMESTAMP,
+    deleted_at TIMESTAMP,
+    UNIQUE (user_id, name)
+);
+
+INSERT INTO tags_scoped (user_id, name, created_at, updated_at)
+SELECT DISTINCT problems.user_id, tags.name, tags.created_at, tags.updated_at
+FROM problem_tags
+JOIN problems ON problems.id = problem_tags.problem_id
+JOIN tags ON tags.id = problem_tags.tag_id;
+
+-- Point every association at the owning user's copy of the tag
+CREATE TABLE problem_tags_scoped (
+    problem_id INTEGER NOT NULL,
+    tag_id INTEGER NOT NULL,
+    PRIMARY KEY (problem_id, tag_id),
+    FOREIGN KEY (problem_id) REFERENCES problems(id) ON DELETE CASCADE,
+    FOREIGN KEY (tag_id) REFERENCES tags(id) ON DELETE CASCADE
+);
+
+INSERT INTO problem_tags_scoped (problem_id, tag_id)
+SELECT problem_tags.problem_id, tags_scoped.id
+FROM problem_tags
+JOIN problems ON problems.id = problem_tags.problem_id
+JOIN tags ON tags.id = problem_tags.tag_id
+JOIN tags_scoped ON tags_scoped.user_id = problems.user_id AND tags_scoped.name = tags.name;
+
+-- Swap the new tables in
+DROP TABLE problem_tags;
+DROP TABLE tags;
+ALTER TABLE tags_scoped RENAME TO tags;
+ALTER TABLE problem_tags_scoped RENAME TO problem_tags;
+
+-- Recreate indices
+CREATE INDEX IF NOT EXISTS idx_tags_user_id ON tags(user_id);
+CREATE INDEX IF NOT EXISTS idx_tags_deleted_at ON tags(deleted_at);
+CREATE INDEX IF NOT EXISTS idx_problem_tags_problem_id ON problem_tags(problem_id);
+CREATE INDEX IF NOT EXISTS idx_problem_tags_tag_id ON problem_tags(tag_id);
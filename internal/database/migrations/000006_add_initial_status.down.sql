@@ -0,0 +1 @@
+ALTER TABLE problems DROP COLUMN initial_status;
@@ -0,0 +1,4 @@
+-- Remember the status a problem was first logged with so review effectiveness can be measured.
+-- Existing problems have no history, so their current status is the best available baseline.
+ALTER TABLE problems ADD COLUMN initial_status TEXT NOT NULL DEFAULT '';
+UPDATE problems SET initial_status = status;
@@ -0,0 +1,2 @@
+-- Store a SHA-256 hash of each user's HTTP API token; the token itself is never persisted
+ALTER TABLE user_settings ADD COLUMN api_token_hash TEXT NOT NULL DEFAULT '';
@@ -0,0 +1,3 @@
+-- The original offsets are not recoverable and UTC values read back as the same instants,
+-- so there is nothing to undo.
+SELECT 1;
@@ -0,0 +1,14 @@
+-- Timestamps are compared as text, so rows written with a local offset or without one
+-- (date-only or "YYYY-MM-DD HH:MM:SS") sort inconsistently against UTC rows.
+-- Rewrite everything to the UTC layout the driver now writes.
+UPDATE problems
+SET solved_at = strftime('%Y-%m-%d %H:%M:%f+00:00', solved_at)
+WHERE solved_at NOT LIKE '%+00:00';
+
+UPDATE problems
+SET last_reviewed_at = strftime('%Y-%m-%d %H:%M:%f+00:00', last_reviewed_at)
+WHERE last_reviewed_at IS NOT NULL AND last_reviewed_at NOT LIKE '%+00:00';
+
+UPDATE review_history
+SET reviewed_at = strftime('%Y-%m-%d %H:%M:%f+00:00', reviewed_at)
+WHERE reviewed_at NOT LIKE '%+00:00';
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_problems_archived;
+ALTER TABLE problems DROP COLUMN archived;
@@ -0,0 +1,3 @@
+-- Archived problems stay in the user's history but are left out of review reminders
+ALTER TABLE problems ADD COLUMN archived BOOLEAN NOT NULL DEFAULT 0;
+CREATE INDEX IF NOT EXISTS idx_problems_archived ON problems(archived);
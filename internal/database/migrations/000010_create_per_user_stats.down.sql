@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_per_user_stats_last_used_at;
+DROP TABLE IF EXISTS per_user_stats;
@@ -0,0 +1,12 @@
+-- Per-user command usage for admin dashboards, kept out of Prometheus to avoid a user_id label
+CREATE TABLE IF NOT EXISTS per_user_stats (
+    user_id TEXT NOT NULL,
+    command TEXT NOT NULL,
+    success_count INTEGER NOT NULL DEFAULT 0,
+    error_count INTEGER NOT NULL DEFAULT 0,
+    first_used_at TIMESTAMP NOT NULL,
+    last_used_at TIMESTAMP NOT NULL,
+    PRIMARY KEY (user_id, command)
+);
+
+CREATE INDEX IF NOT EXISTS idx_per_user_stats_last_used_at ON per_user_stats(last_used_at);
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_problems_guild_id;
+ALTER TABLE problems DROP COLUMN guild_id;
@@ -0,0 +1,3 @@
+-- Problems logged before guilds were tracked keep an empty guild_id
+ALTER TABLE problems ADD COLUMN guild_id TEXT NOT NULL DEFAULT '';
+CREATE INDEX IF NOT EXISTS idx_problems_guild_id ON problems(guild_id);
@@ -0,0 +1,2 @@
+DROP TRIGGER IF EXISTS user_preferences_after_update;
+DROP TABLE IF EXISTS user_preferences;
@@ -0,0 +1,20 @@
+-- Create user_preferences table (one row per user, absent rows mean defaults)
+CREATE TABLE IF NOT EXISTS user_preferences (
+    user_id TEXT PRIMARY KEY,
+    reminder_time TEXT,
+    timezone TEXT NOT NULL DEFAULT 'UTC',
+    reminders_enabled BOOLEAN NOT NULL DEFAULT 1,
+    created_at TIMESTAMP DEFAULT (strftime('%Y-%m-%d %H:%M:%f+00:00', 'now')),
+    updated_at TIMESTAMP DEFAULT (strftime('%Y-%m-%d %H:%M:%f+00:00', 'now'))
+);
+
+-- Keep updated_at current however the row is changed. The WHEN clause skips updates that already
+-- set it, including the trigger's own.
+CREATE TRIGGER IF NOT EXISTS user_preferences_after_update
+AFTER UPDATE ON user_preferences
+FOR EACH ROW WHEN NEW.updated_at IS OLD.updated_at
+BEGIN
+    UPDATE user_preferences
+    SET updated_at = strftime('%Y-%m-%d %H:%M:%f+00:00', 'now')
+    WHERE user_id = NEW.user_id;
+END;
@@ -0,0 +1,2 @@
+-- Empty keeps each command's own choice of public or ephemeral replies
+ALTER TABLE user_settings ADD COLUMN response_visibility TEXT NOT NULL DEFAULT '';
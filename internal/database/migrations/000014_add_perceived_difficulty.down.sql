@@ -0,0 +1 @@
+ALTER TABLE problems DROP COLUMN perceived_difficulty;
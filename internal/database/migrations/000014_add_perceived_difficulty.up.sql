@@ -0,0 +1,2 @@
+-- How hard the user found the problem; empty means it matched the official difficulty
+ALTER TABLE problems ADD COLUMN perceived_difficulty TEXT NOT NULL DEFAULT '';
@@ -0,0 +1,3 @@
+-- Empty filters send reminders for every difficulty and category
+ALTER TABLE user_settings ADD COLUMN reminder_difficulty TEXT NOT NULL DEFAULT '';
+ALTER TABLE user_settings ADD COLUMN reminder_category TEXT NOT NULL DEFAULT '';
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_command_aliases_user_id;
+DROP TABLE IF EXISTS command_aliases;
@@ -0,0 +1,11 @@
+-- Create command_aliases table. Each alias is registered as a guild command, so names are unique
+-- across users; the owner is the only one who can run it.
+CREATE TABLE IF NOT EXISTS command_aliases (
+    alias TEXT PRIMARY KEY,
+    user_id TEXT NOT NULL,
+    command TEXT NOT NULL,
+    default_options TEXT NOT NULL DEFAULT '{}', -- JSON object of option name to value
+    created_at TIMESTAMP DEFAULT (strftime('%Y-%m-%d %H:%M:%f+00:00', 'now'))
+);
+
+CREATE INDEX IF NOT EXISTS idx_command_aliases_user_id ON command_aliases(user_id);
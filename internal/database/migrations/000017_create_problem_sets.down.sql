@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_problem_set_items_problem_id;
+DROP TABLE IF EXISTS problem_set_items;
+DROP INDEX IF EXISTS idx_problem_sets_guild_owner_name;
+DROP TABLE IF EXISTS problem_sets;
@@ -0,0 +1,23 @@
+-- Create problem_sets table. Built-in sets such as "Blind 75" have no owner and list their
+-- problems by name in code rather than in problem_set_items.
+CREATE TABLE IF NOT EXISTS problem_sets (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    name TEXT NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    owner_user_id TEXT NOT NULL DEFAULT '',
+    guild_id TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP DEFAULT (strftime('%Y-%m-%d %H:%M:%f+00:00', 'now'))
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_problem_sets_guild_owner_name ON problem_sets(guild_id, owner_user_id, name);
+
+-- Create problem_set_items join table
+CREATE TABLE IF NOT EXISTS problem_set_items (
+    set_id INTEGER NOT NULL,
+    problem_id INTEGER NOT NULL,
+    PRIMARY KEY (set_id, problem_id),
+    FOREIGN KEY (set_id) REFERENCES problem_sets(id) ON DELETE CASCADE,
+    FOREIGN KEY (problem_id) REFERENCES problems(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_problem_set_items_problem_id ON problem_set_items(problem_id);
@@ -0,0 +1,199 @@
+package database
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestMigrateDownAndUp(t *testing.T) {
+	ctx := context.Background()
+	repo := newTestRepository(t)
+	seedTestData(t, repo, "migrate-user")
+
+	if err := MigrateDown(ctx, repo, 0); err != nil {
+		t.Fatalf("MigrateDown: %v", err)
+	}
+	if repo.db.Migrator().HasTable("problems") {
+		t.Error("problems table survived rolling back every migration")
+	}
+
+	if err := Migrate(ctx, repo); err != nil {
+		t.Fatalf("Migrate after rolling back: %v", err)
+	}
+	status, err := GetSchemaStatus(repo)
+	if err != nil {
+		t.Fatalf("GetSchemaStatus: %v", err)
+	}
+	if latest := status.Migrations[len(status.Migrations)-1].Version; status.Version != latest || status.Dirty {
+		t.Errorf("schema at version %d (dirty %v), want %d", status.Version, status.Dirty, latest)
+	}
+}
+
+func TestMigrateDownToVersion(t *testing.T) {
+	ctx := context.Background()
+	repo := newTestRepository(t)
+
+	// Roll back to v2, then to v1, which drops the timestamp columns v2 added
+	if err := MigrateDown(ctx, repo, 2); err != nil {
+		t.Fatalf("MigrateDown(2): %v", err)
+	}
+	if !repo.db.Migrator().HasColumn("problems", "created_at") {
+		t.Fatal("v2 columns missing at version 2")
+	}
+
+	if err := MigrateDown(ctx, repo, 1); err != nil {
+		t.Fatalf("MigrateDown(1): %v", err)
+	}
+	for _, table := range []string{"problems", "tags"} {
+		for _, column := range []string{"created_at", "updated_at", "deleted_at"} {
+			if repo.db.Migrator().HasColumn(table, column) {
+				t.Errorf("%s.%s survived rolling back to v1", table, column)
+			}
+		}
+	}
+	status, err := GetSchemaStatus(repo)
+	if err != nil {
+		t.Fatalf("GetSchemaStatus: %v", err)
+	}
+	if status.Version != 1 || status.Dirty {
+		t.Errorf("schema at version %d (dirty %v), want 1", status.Version, status.Dirty)
+	}
+}
+
+func TestNormalizeTimestampsToUTC(t *testing.T) {
+	ctx := context.Background()
+	repo := newTestRepository(t)
+	if err := MigrateDown(ctx, repo, 7); err != nil {
+		t.Fatalf("MigrateDown: %v", err)
+	}
+
+	// Rows written before v8 in the layouts older code produced
+	old := []struct {
+		name     string
+		solvedAt string
+		want     time.Time
+	}{
+		{name: "Date Only", solvedAt: "2024-03-05", want: time.Date(2024, time.March, 5, 0, 0, 0, 0, time.UTC)},
+		{name: "No Offset", solvedAt: "2024-03-05 09:30:00", want: time.Date(2024, time.March, 5, 9, 30, 0, 0, time.UTC)},
+		{name: "Local Offset", solvedAt: "2024-03-05T09:30:00-05:00", want: time.Date(2024, time.March, 5, 14, 30, 0, 0, time.UTC)},
+	}
+	for _, row := range old {
+		err := repo.db.Exec("INSERT INTO problems (user_id, problem_name, difficulty, category, status, solved_at) VALUES (?, ?, ?, ?, ?, ?)",
+			"utc-user", row.name, DifficultyEasy, "Array", StatusSolved, row.solvedAt).Error
+		if err != nil {
+			t.Fatalf("failed to insert %q: %v", row.name, err)
+		}
+	}
+
+	if err := Migrate(ctx, repo); err != nil {
+		t.Fatalf("Migrate: %v", err)
+	}
+	newer := &ProblemEntry{UserID: "utc-user", ProblemName: "New Row", Difficulty: DifficultyEasy, Category: "Array", Status: StatusSolved, SolvedAt: time.Date(2024, time.March, 5, 12, 0, 0, 0, time.FixedZone("UTC+2", 2*60*60))}
+	if err := repo.CreateProblem(ctx, newer); err != nil {
+		t.Fatalf("CreateProblem: %v", err)
+	}
+
+	problems, err := repo.ListProblems(ctx, "utc-user", "", "", "", nil, true, 0, 0)
+	if err != nil {
+		t.Fatalf("ListProblems: %v", err)
+	}
+	solved := make(map[string]time.Time, len(problems))
+	for _, p := range problems {
+		solved[p.ProblemName] = p.SolvedAt
+	}
+	for _, row := range old {
+		if got := solved[row.name]; !got.Equal(row.want) || got.Location() != time.UTC {
+			t.Errorf("%s solved at %v, want %v", row.name, got, row.want)
+		}
+	}
+	if got := solved["New Row"]; !got.Equal(newer.SolvedAt) || got.Location() != time.UTC {
+		t.Errorf("New Row solved at %v, want %v in UTC", got, newer.SolvedAt)
+	}
+
+	// Old and new rows sort together by instant, newest first
+	want := []string{"Local Offset", "New Row", "No Offset", "Date Only"}
+	if got := problemNames(problems); !slices.Equal(got, want) {
+		t.Errorf("problems ordered %v, want %v", got, want)
+	}
+}
+
+func TestAddGuildIDKeepsExistingRows(t *testing.T) {
+	ctx := context.Background()
+	repo := newTestRepository(t)
+	if err := MigrateDown(ctx, repo, 1); err != nil {
+		t.Fatalf("MigrateDown: %v", err)
+	}
+	if repo.db.Migrator().HasColumn("problems", "guild_id") {
+		t.Fatal("guild_id exists at version 1")
+	}
+	err := repo.db.Exec("INSERT INTO problems (user_id, problem_name, difficulty, category, status, solved_at) VALUES (?, ?, ?, ?, ?, ?)",
+		"guild-user", "Two Sum", DifficultyEasy, "Array", StatusSolved, "2024-03-05 09:30:00").Error
+	if err != nil {
+		t.Fatalf("failed to insert a v1 row: %v", err)
+	}
+
+	if err := Migrate(ctx, repo); err != nil {
+		t.Fatalf("Migrate: %v", err)
+	}
+	if !repo.db.Migrator().HasColumn("problems", "guild_id") {
+		t.Fatal("guild_id missing after migrating")
+	}
+	if !repo.db.Migrator().HasIndex("problems", "idx_problems_guild_id") {
+		t.Error("idx_problems_guild_id missing after migrating")
+	}
+
+	var guildIDs []string
+	if err := repo.db.Raw("SELECT guild_id FROM problems WHERE user_id = ?", "guild-user").Scan(&guildIDs).Error; err != nil {
+		t.Fatalf("failed to read guild_id: %v", err)
+	}
+	if !slices.Equal(guildIDs, []string{""}) {
+		t.Errorf("guild_id of the existing row = %q, want an empty string", guildIDs)
+	}
+}
+
+func TestUserPreferencesTrigger(t *testing.T) {
+	repo := newTestRepository(t)
+
+	// newTestRepository applied every migration to a fresh database, one version after another
+	status, err := GetSchemaStatus(repo)
+	if err != nil {
+		t.Fatalf("GetSchemaStatus: %v", err)
+	}
+	for idx, migration := range status.Migrations {
+		if migration.Version != uint(idx+1) || !migration.Applied {
+			t.Errorf("migration %d is %d_%s (applied %v), want version %d applied", idx, migration.Version, migration.Name, migration.Applied, idx+1)
+		}
+	}
+
+	if err := repo.db.Exec("INSERT INTO user_preferences (user_id) VALUES (?)", "pref-user").Error; err != nil {
+		t.Fatalf("failed to insert preferences: %v", err)
+	}
+	updatedAt := func() time.Time {
+		t.Helper()
+		var value time.Time
+		if err := repo.db.Raw("SELECT updated_at FROM user_preferences WHERE user_id = ?", "pref-user").Scan(&value).Error; err != nil {
+			t.Fatalf("failed to read updated_at: %v", err)
+		}
+		return value
+	}
+
+	created := updatedAt()
+	time.Sleep(5 * time.Millisecond)
+	if err := repo.db.Exec("UPDATE user_preferences SET reminder_time = ? WHERE user_id = ?", "09:00", "pref-user").Error; err != nil {
+		t.Fatalf("failed to update preferences: %v", err)
+	}
+	if updated := updatedAt(); !updated.After(created) {
+		t.Errorf("updated_at = %v after an update, want it after %v", updated, created)
+	}
+
+	// An update that sets updated_at itself keeps that value
+	explicit := time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+	if err := repo.db.Exec("UPDATE user_preferences SET timezone = ?, updated_at = ? WHERE user_id = ?", "Asia/Tokyo", explicit, "pref-user").Error; err != nil {
+		t.Fatalf("failed to update preferences: %v", err)
+	}
+	if updated := updatedAt(); !updated.Equal(explicit) {
+		t.Errorf("updated_at = %v, want the explicit %v", updated, explicit)
+	}
+}
@@ -2,7 +2,6 @@ package database
 
 import (
 	"errors"
-	"fmt"
 	"strings"
 	"time"
 
@@ -16,6 +15,9 @@ const (
 	StatusStuck      = "Stuck"
 )
 
+// ErrTooManyTags is returned when a problem carries more tags than the configured limit
+var ErrTooManyTags = errors.New("too many tags")
+
 // Difficulty constants
 const (
 	DifficultyEasy   = "Easy"
@@ -25,21 +27,24 @@ const (
 
 // Problem represents a solved problem in the database
 type Problem struct {
-	ID             uint           `gorm:"primaryKey" json:"id"`
-	UserID         string         `gorm:"index:idx_user_id;not null" json:"user_id"`
-	ProblemName    string         `gorm:"not null" json:"problem_name"`
-	Link           string         `json:"link"`
-	Difficulty     string         `gorm:"index:idx_difficulty;not null" json:"difficulty"`
-	Category       string         `gorm:"index:idx_category;not null" json:"category"`
-	Status         string         `gorm:"index:idx_status;not null" json:"status"`
-	SolvedAt       time.Time      `gorm:"index:idx_solved_at;not null" json:"solved_at"`
-	LastReviewedAt *time.Time     `json:"last_reviewed_at"`
-	ReviewCount    int            `gorm:"default:0;not null" json:"review_count"`
-	Notes          string         `json:"notes"`
-	Tags           []Tag          `gorm:"many2many:problem_tags;" json:"tags,omitempty"`
-	CreatedAt      time.Time      `gorm:"autoCreateTime" json:"-"`
-	UpdatedAt      time.Time      `gorm:"autoUpdateTime" json:"-"`
-	DeletedAt      gorm.DeletedAt `gorm:"index" json:"-"`
+	ID                  uint           `gorm:"primaryKey" json:"id"`
+	UserID              string         `gorm:"index:idx_user_id;not null" json:"user_id"`
+	ProblemName         string         `gorm:"not null" json:"problem_name"`
+	Link                string         `json:"link"`
+	Difficulty          string         `gorm:"index:idx_difficulty;not null" json:"difficulty"`
+	PerceivedDifficulty string         `gorm:"not null;default:''" json:"perceived_difficulty"` // How hard the user found it, empty if it matched Difficulty
+	Category            string         `gorm:"index:idx_category;not null" json:"category"`
+	Status              string         `gorm:"index:idx_status;not null" json:"status"`
+	InitialStatus       string         `gorm:"not null;default:''" json:"initial_status"` // Status when first logged, never updated
+	SolvedAt            time.Time      `gorm:"index:idx_solved_at;not null" json:"solved_at"`
+	LastReviewedAt      *time.Time     `json:"last_reviewed_at"`
+	ReviewCount         int            `gorm:"default:0;not null" json:"review_count"`
+	Notes               string         `json:"notes"`
+	Archived            bool           `gorm:"index:idx_problems_archived;not null;default:false" json:"archived"` // Excluded from reviews
+	Tags                []Tag          `gorm:"many2many:problem_tags;" json:"tags,omitempty"`
+	CreatedAt           time.Time      `gorm:"autoCreateTime" json:"-"`
+	UpdatedAt           time.Time      `gorm:"autoUpdateTime" json:"-"`
+	DeletedAt           gorm.DeletedAt `gorm:"index" json:"-"`
 }
 
 // TableName explicitly sets the table name for Problem
@@ -47,10 +52,12 @@ func (Problem) TableName() string {
 	return "problems"
 }
 
-// Tag represents a tag in the database
+// Tag represents a tag in the database. Tags are scoped per user, so two users
+// tagging problems "dp" each get their own row.
 type Tag struct {
 	ID        uint           `gorm:"primaryKey" json:"id"`
-	Name      string         `gorm:"uniqueIndex;not null" json:"name"`
+	UserID    string         `gorm:"uniqueIndex:idx_tags_user_name;not null" json:"user_id"`
+	Name      string         `gorm:"uniqueIndex:idx_tags_user_name;not null" json:"name"`
 	Problems  []Problem      `gorm:"many2many:problem_tags;" json:"-"`
 	CreatedAt time.Time      `gorm:"autoCreateTime" json:"-"`
 	UpdatedAt time.Time      `gorm:"autoUpdateTime" json:"-"`
@@ -62,48 +69,201 @@ func (Tag) TableName() string {
 	return "tags"
 }
 
+// ReviewHistory records a single completed review of a problem
+type ReviewHistory struct {
+	ID         uint      `gorm:"primaryKey" json:"id"`
+	ProblemID  uint      `gorm:"index:idx_review_history_problem_id;not null" json:"problem_id"`
+	ReviewedAt time.Time `gorm:"not null" json:"reviewed_at"`
+}
+
+// TableName explicitly sets the table name for ReviewHistory
+func (ReviewHistory) TableName() string {
+	return "review_history"
+}
+
+// Response visibility preferences
+const (
+	ResponseVisibilityDefault = ""        // Each command decides
+	ResponseVisibilityPublic  = "public"  // Replies are shown to the channel
+	ResponseVisibilityPrivate = "private" // Replies are only shown to the user
+)
+
+// UserSettings holds per-user preferences. Users without a row get the zero-value defaults,
+// which keep profiles private until the user explicitly opts in.
+type UserSettings struct {
+	UserID             string    `gorm:"primaryKey" json:"user_id"`
+	ProfilePublic      bool      `gorm:"not null;default:false" json:"profile_public"`
+	APITokenHash       string    `gorm:"column:api_token_hash;not null;default:''" json:"-"` // SHA-256 of the user's API token, empty if none
+	ResponseVisibility string    `gorm:"not null;default:''" json:"response_visibility"`     // One of the ResponseVisibility constants
+	ReminderDifficulty string    `gorm:"not null;default:''" json:"reminder_difficulty"`     // Only remind about this difficulty, empty for all
+	ReminderCategory   string    `gorm:"not null;default:''" json:"reminder_category"`       // Only remind about this category, empty for all
+	CreatedAt          time.Time `gorm:"autoCreateTime" json:"-"`
+	UpdatedAt          time.Time `gorm:"autoUpdateTime" json:"-"`
+}
+
+// TableName explicitly sets the table name for UserSettings
+func (UserSettings) TableName() string {
+	return "user_settings"
+}
+
+// UserPreferences holds a user's reminder preferences. Users without a row get reminders at the
+// scheduler's default time in UTC.
+type UserPreferences struct {
+	UserID           string    `gorm:"primaryKey" json:"user_id"`
+	ReminderTime     *string   `json:"reminder_time"` // HH:MM, nil for the scheduler's default
+	Timezone         string    `gorm:"not null;default:UTC" json:"timezone"`
+	RemindersEnabled bool      `gorm:"not null;default:true" json:"reminders_enabled"`
+	CreatedAt        time.Time `gorm:"autoCreateTime" json:"-"`
+	UpdatedAt        time.Time `gorm:"autoUpdateTime" json:"-"`
+}
+
+// TableName explicitly sets the table name for UserPreferences
+func (UserPreferences) TableName() string {
+	return "user_preferences"
+}
+
+// CommandUsage counts how often a user has run a command and how many of those runs failed
+type CommandUsage struct {
+	UserID       string    `gorm:"primaryKey" json:"user_id"`
+	Command      string    `gorm:"primaryKey" json:"command"`
+	SuccessCount int64     `gorm:"not null;default:0" json:"success_count"`
+	ErrorCount   int64     `gorm:"not null;default:0" json:"error_count"`
+	FirstUsedAt  time.Time `gorm:"not null" json:"first_used_at"`
+	LastUsedAt   time.Time `gorm:"not null" json:"last_used_at"`
+}
+
+// TableName explicitly sets the table name for CommandUsage
+func (CommandUsage) TableName() string {
+	return "per_user_stats"
+}
+
+// CommandAlias is a user's shortcut for a command, with option values it fills in
+type CommandAlias struct {
+	Alias          string    `gorm:"primaryKey" json:"alias"`
+	UserID         string    `gorm:"not null;index" json:"user_id"`
+	Command        string    `gorm:"not null" json:"command"`
+	DefaultOptions string    `gorm:"not null;default:'{}'" json:"default_options"` // JSON object of option name to value
+	CreatedAt      time.Time `gorm:"autoCreateTime" json:"created_at"`
+}
+
+// TableName explicitly sets the table name for CommandAlias
+func (CommandAlias) TableName() string {
+	return "command_aliases"
+}
+
+// ProblemSet is a named collection of problems, such as a study plan. Built-in sets have no owner.
+type ProblemSet struct {
+	ID           uint             `gorm:"primaryKey" json:"id"`
+	Name         string           `gorm:"not null" json:"name"`
+	Description  string           `gorm:"not null;default:''" json:"description"`
+	OwnerUserID  string           `gorm:"not null;default:''" json:"owner_user_id"`
+	GuildID      string           `gorm:"not null;default:''" json:"guild_id"`
+	CreatedAt    time.Time        `gorm:"autoCreateTime" json:"created_at"`
+	Items        []ProblemSetItem `gorm:"foreignKey:SetID" json:"items,omitempty"`
+	ProblemNames []string         `gorm:"-" json:"problem_names,omitempty"` // Problems of a built-in set, matched by name
+}
+
+// TableName explicitly sets the table name for ProblemSet
+func (ProblemSet) TableName() string {
+	return "problem_sets"
+}
+
+// Builtin reports whether the set is one of the built-in sets seeded into every guild
+func (s *ProblemSet) Builtin() bool {
+	return s.OwnerUserID == ""
+}
+
+// ProblemSetItem links a problem to a set
+type ProblemSetItem struct {
+	SetID     uint `gorm:"primaryKey" json:"set_id"`
+	ProblemID uint `gorm:"primaryKey" json:"problem_id"`
+}
+
+// TableName explicitly sets the table name for ProblemSetItem
+func (ProblemSetItem) TableName() string {
+	return "problem_set_items"
+}
+
 // ProblemEntry is a DTO (Data Transfer Object) used for API interactions
 type ProblemEntry struct {
-	ID             uint       `json:"id"`
-	UserID         string     `json:"user_id"`
-	ProblemName    string     `json:"problem_name"`
-	Link           string     `json:"link"`
-	Difficulty     string     `json:"difficulty"`
-	Category       string     `json:"category"`
-	Status         string     `json:"status"`
-	SolvedAt       time.Time  `json:"solved_at"`
-	LastReviewedAt *time.Time `json:"last_reviewed_at"`
-	ReviewCount    int        `json:"review_count"`
-	Notes          string     `json:"notes"`
-	Tags           []string   `json:"tags"`
+	ID                  uint       `json:"id"`
+	UserID              string     `json:"user_id"`
+	ProblemName         string     `json:"problem_name"`
+	Link                string     `json:"link"`
+	Difficulty          string     `json:"difficulty"`
+	PerceivedDifficulty string     `json:"perceived_difficulty,omitempty"` // How hard the user found it, empty if it matched Difficulty
+	Category            string     `json:"category"`
+	Status              string     `json:"status"`
+	SolvedAt            time.Time  `json:"solved_at"`
+	LastReviewedAt      *time.Time `json:"last_reviewed_at"`
+	ReviewCount         int        `json:"review_count"`
+	Notes               string     `json:"notes"`
+	Archived            bool       `json:"archived"`
+	Tags                []string   `json:"tags"`
+	// ReviewPriority is computed when listing problems for review and is never stored
+	ReviewPriority float64 `json:"review_priority,omitempty"`
+}
+
+// EffectiveDifficulty returns the difficulty reviews are weighted by: the user's perceived difficulty
+// when set, otherwise the official one
+func (p *ProblemEntry) EffectiveDifficulty() string {
+	if p.PerceivedDifficulty != "" {
+		return p.PerceivedDifficulty
+	}
+	return p.Difficulty
+}
+
+// normalizeTags trims and lowercases tag names and removes blanks and duplicates,
+// preserving first-seen order. Tags are stored lowercase only; display case is not kept.
+func normalizeTags(names []string) []string {
+	seen := make(map[string]bool, len(names))
+	result := make([]string, 0, len(names))
+	for _, name := range names {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" || seen[name] {
+			continue
+		}
+		seen[name] = true
+		result = append(result, name)
+	}
+	return result
 }
 
 // ToProblem converts a ProblemEntry to Problem model with related Tag entities
 func (p *ProblemEntry) ToProblem() *Problem {
-	tags := make([]Tag, 0, len(p.Tags))
-	for _, tagName := range p.Tags {
-		tagName = strings.TrimSpace(tagName)
-		if tagName != "" {
-			tags = append(tags, Tag{Name: tagName})
-		}
+	names := normalizeTags(p.Tags)
+	tags := make([]Tag, 0, len(names))
+	for _, tagName := range names {
+		tags = append(tags, Tag{UserID: p.UserID, Name: tagName})
 	}
 
 	return &Problem{
-		ID:             p.ID,
-		UserID:         p.UserID,
-		ProblemName:    p.ProblemName,
-		Link:           p.Link,
-		Difficulty:     p.Difficulty,
-		Category:       p.Category,
-		Status:         p.Status,
-		SolvedAt:       p.SolvedAt,
-		LastReviewedAt: p.LastReviewedAt,
-		ReviewCount:    p.ReviewCount,
-		Notes:          p.Notes,
-		Tags:           tags,
+		ID:                  p.ID,
+		UserID:              p.UserID,
+		ProblemName:         p.ProblemName,
+		Link:                p.Link,
+		Difficulty:          p.Difficulty,
+		PerceivedDifficulty: p.PerceivedDifficulty,
+		Category:            p.Category,
+		Status:              p.Status,
+		SolvedAt:            p.SolvedAt.UTC(),
+		LastReviewedAt:      utcPtr(p.LastReviewedAt),
+		ReviewCount:         p.ReviewCount,
+		Notes:               p.Notes,
+		Archived:            p.Archived,
+		Tags:                tags,
 	}
 }
 
+// utcPtr returns a copy of t in UTC, or nil when t is nil
+func utcPtr(t *time.Time) *time.Time {
+	if t == nil {
+		return nil
+	}
+	utc := t.UTC()
+	return &utc
+}
+
 // FromProblem converts a Problem model to ProblemEntry DTO
 func FromProblem(p *Problem) *ProblemEntry {
 	tags := make([]string, 0, len(p.Tags))
@@ -112,37 +272,49 @@ func FromProblem(p *Problem) *ProblemEntry {
 	}
 
 	return &ProblemEntry{
-		ID:             p.ID,
-		UserID:         p.UserID,
-		ProblemName:    p.ProblemName,
-		Link:           p.Link,
-		Difficulty:     p.Difficulty,
-		Category:       p.Category,
-		Status:         p.Status,
-		SolvedAt:       p.SolvedAt,
-		LastReviewedAt: p.LastReviewedAt,
-		ReviewCount:    p.ReviewCount,
-		Notes:          p.Notes,
-		Tags:           tags,
+		ID:                  p.ID,
+		UserID:              p.UserID,
+		ProblemName:         p.ProblemName,
+		Link:                p.Link,
+		Difficulty:          p.Difficulty,
+		PerceivedDifficulty: p.PerceivedDifficulty,
+		Category:            p.Category,
+		Status:              p.Status,
+		SolvedAt:            p.SolvedAt.UTC(),
+		LastReviewedAt:      utcPtr(p.LastReviewedAt),
+		ReviewCount:         p.ReviewCount,
+		Notes:               p.Notes,
+		Archived:            p.Archived,
+		Tags:                tags,
 	}
 }
 
-// ValidateProblemEntry validates a problem entry
-func ValidateProblemEntry(p *ProblemEntry) error {
+// ValidateProblemEntry validates a problem entry, allowing at most maxTags distinct tags
+// (0 disables the limit). Failures are returned as a *ValidationError.
+func ValidateProblemEntry(p *ProblemEntry, maxTags int) error {
 	if p.UserID == "" {
-		return errors.New("user ID is required")
+		return &ValidationError{Field: FieldUserID, Reason: ReasonRequired}
 	}
 	if p.ProblemName == "" {
-		return errors.New("problem name is required")
+		return &ValidationError{Field: FieldProblemName, Reason: ReasonRequired}
 	}
 	if p.Difficulty != DifficultyEasy && p.Difficulty != DifficultyMedium && p.Difficulty != DifficultyHard {
-		return fmt.Errorf("invalid difficulty: %s", p.Difficulty)
+		return &ValidationError{Field: FieldDifficulty, Reason: ReasonInvalid, Value: p.Difficulty}
+	}
+	switch p.PerceivedDifficulty {
+	case "", DifficultyEasy, DifficultyMedium, DifficultyHard:
+	default:
+		return &ValidationError{Field: FieldPerceivedDifficulty, Reason: ReasonInvalid, Value: p.PerceivedDifficulty}
 	}
 	if p.Status != StatusSolved && p.Status != StatusNeededHint && p.Status != StatusStuck {
-		return fmt.Errorf("invalid status: %s", p.Status)
+		return &ValidationError{Field: FieldStatus, Reason: ReasonInvalid, Value: p.Status}
 	}
 	if p.Category == "" {
-		return errors.New("category is required")
+		return &ValidationError{Field: FieldCategory, Reason: ReasonRequired}
+	}
+	// Count tags as they will be stored so duplicates and blanks don't count against the limit
+	if count := len(normalizeTags(p.Tags)); maxTags > 0 && count > maxTags {
+		return &ValidationError{Field: FieldTags, Reason: ReasonTooMany, Count: count, Limit: maxTags}
 	}
 	return nil
 }
\ No newline at end of file
@@ -0,0 +1,23 @@
+package database
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/rs/zerolog"
+)
+
+// CleanupOrphanedTags deletes every tag no longer attached to any problem, such as tags dropped
+// from a problem by an edit, and returns how many were removed
+func (r *Repository) CleanupOrphanedTags(ctx context.Context) (int64, error) {
+	db := r.withContext(ctx)
+	result := db.Unscoped().
+		Where("id NOT IN (?)", db.Table("problem_tags").Select("tag_id")).
+		Delete(&Tag{})
+	if result.Error != nil {
+		return 0, fmt.Errorf("failed to clean up orphaned tags: %w", result.Error)
+	}
+
+	zerolog.Ctx(ctx).Info().Int64("removed", result.RowsAffected).Msg("Orphaned tags cleaned up")
+	return result.RowsAffected, nil
+}
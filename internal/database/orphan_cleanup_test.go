@@ -0,0 +1,67 @@
+package database
+
+import (
+	"context"
+	"testing"
+)
+
+// tagExists reports whether userID still has a tag row named name
+func tagExists(t *testing.T, repo *Repository, userID, name string) bool {
+	t.Helper()
+	var count int64
+	if err := repo.db.Model(&Tag{}).Unscoped().Where("user_id = ? AND name = ?", userID, name).Count(&count).Error; err != nil {
+		t.Fatalf("failed to count tags: %v", err)
+	}
+	return count > 0
+}
+
+func TestCleanupOrphanedTags(t *testing.T) {
+	ctx := context.Background()
+	repo := newTestRepository(t)
+	seeded := seedTestData(t, repo, "tag-user")
+	seedTestData(t, repo, "other-user")
+
+	// Editing dp off both problems that used it leaves its row behind
+	for _, idx := range []int{2, 4} {
+		problem, err := repo.GetProblem(ctx, seeded[idx].ID)
+		if err != nil {
+			t.Fatalf("GetProblem: %v", err)
+		}
+		problem.Tags = []string{"bfs"}
+		if err := repo.UpdateProblem(ctx, problem); err != nil {
+			t.Fatalf("UpdateProblem: %v", err)
+		}
+	}
+	if !tagExists(t, repo, "tag-user", "dp") {
+		t.Fatal("dp was removed before the cleanup, so there's nothing to test")
+	}
+
+	removed, err := repo.CleanupOrphanedTags(ctx)
+	if err != nil {
+		t.Fatalf("CleanupOrphanedTags: %v", err)
+	}
+	if removed != 1 {
+		t.Errorf("CleanupOrphanedTags removed %d tags, want 1", removed)
+	}
+	if tagExists(t, repo, "tag-user", "dp") {
+		t.Error("the orphaned dp tag survived the cleanup")
+	}
+	for _, tag := range []struct{ user, name string }{{"other-user", "dp"}, {"tag-user", "bfs"}, {"tag-user", "hash-table"}} {
+		if !tagExists(t, repo, tag.user, tag.name) {
+			t.Errorf("%s's tag %s was removed although problems still use it", tag.user, tag.name)
+		}
+	}
+
+	// Deleting the only problem using a tag cleans up after itself, leaving nothing for the next run
+	if err := repo.DeleteProblem(ctx, seeded[7].ID); err != nil {
+		t.Fatalf("DeleteProblem: %v", err)
+	}
+	if removed, err := repo.CleanupOrphanedTags(ctx); err != nil || removed != 0 {
+		t.Errorf("second CleanupOrphanedTags = %d, %v, want 0", removed, err)
+	}
+	for _, name := range []string{"heap", "linked-list"} {
+		if tagExists(t, repo, "tag-user", name) {
+			t.Errorf("tag %s outlived the only problem using it", name)
+		}
+	}
+}
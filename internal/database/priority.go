@@ -0,0 +1,66 @@
+package database
+
+import (
+	"sort"
+	"time"
+)
+
+// Review priority weights. One point per overdue day is the baseline the bonuses are measured against.
+const (
+	overdueDayWeight = 1.0
+	masteryWeight    = 10.0
+)
+
+// difficultyBonus favours harder problems in the review queue, by the difficulty the user perceived
+var difficultyBonus = map[string]float64{
+	DifficultyHard:   6,
+	DifficultyMedium: 3,
+	DifficultyEasy:   0,
+}
+
+// statusBonus favours problems the user struggled with
+var statusBonus = map[string]float64{
+	StatusStuck:      6,
+	StatusNeededHint: 3,
+	StatusSolved:     0,
+}
+
+// ComputeReviewPriority scores how valuable reviewing a problem is now. Problems score higher the
+// longer they've gone without review, the fewer times they've been reviewed, the harder they are
+// and the less confidently they were solved.
+func ComputeReviewPriority(p *ProblemEntry) float64 {
+	return computeReviewPriority(p, time.Now())
+}
+
+// computeReviewPriority scores a problem as of now
+func computeReviewPriority(p *ProblemEntry, now time.Time) float64 {
+	overdueDays := max(now.Sub(lastSeen(p)).Hours()/24, 0)
+
+	return overdueDays*overdueDayWeight +
+		masteryWeight/float64(1+p.ReviewCount) +
+		difficultyBonus[p.EffectiveDifficulty()] +
+		statusBonus[p.Status]
+}
+
+// ReviewDueAt returns when a problem comes up for review: lookback after it was last solved or reviewed
+func ReviewDueAt(p *ProblemEntry, lookback time.Duration) time.Time {
+	return lastSeen(p).Add(lookback)
+}
+
+// lastSeen returns when the user last worked on the problem, by solving or reviewing it
+func lastSeen(p *ProblemEntry) time.Time {
+	if p.LastReviewedAt != nil && p.LastReviewedAt.After(p.SolvedAt) {
+		return *p.LastReviewedAt
+	}
+	return p.SolvedAt
+}
+
+// sortByReviewPriority scores each problem and orders them highest priority first
+func sortByReviewPriority(problems []*ProblemEntry, now time.Time) {
+	for _, p := range problems {
+		p.ReviewPriority = computeReviewPriority(p, now)
+	}
+	sort.SliceStable(problems, func(i, j int) bool {
+		return problems[i].ReviewPriority > problems[j].ReviewPriority
+	})
+}
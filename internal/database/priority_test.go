@@ -0,0 +1,103 @@
+package database
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+// priorityNow is the moment review priorities are computed at in these tests
+var priorityNow = time.Date(2024, time.March, 1, 12, 0, 0, 0, time.UTC)
+
+// reviewFixture returns a medium problem solved the given number of days before priorityNow
+func reviewFixture(name string, daysAgo int) *ProblemEntry {
+	return &ProblemEntry{ProblemName: name, Difficulty: DifficultyMedium, Status: StatusSolved, SolvedAt: priorityNow.AddDate(0, 0, -daysAgo)}
+}
+
+func TestComputeReviewPriority(t *testing.T) {
+	reviewedAt := priorityNow.AddDate(0, 0, -2)
+
+	tests := []struct {
+		name   string
+		higher *ProblemEntry
+		lower  *ProblemEntry
+	}{
+		{
+			name:   "more overdue",
+			higher: reviewFixture("Old", 30),
+			lower:  reviewFixture("Recent", 10),
+		},
+		{
+			name:   "reviewed fewer times",
+			higher: reviewFixture("Unreviewed", 10),
+			lower:  &ProblemEntry{ProblemName: "Reviewed", Difficulty: DifficultyMedium, Status: StatusSolved, SolvedAt: priorityNow.AddDate(0, 0, -10), ReviewCount: 4},
+		},
+		{
+			name:   "harder",
+			higher: &ProblemEntry{ProblemName: "Hard", Difficulty: DifficultyHard, Status: StatusSolved, SolvedAt: priorityNow.AddDate(0, 0, -10)},
+			lower:  &ProblemEntry{ProblemName: "Easy", Difficulty: DifficultyEasy, Status: StatusSolved, SolvedAt: priorityNow.AddDate(0, 0, -10)},
+		},
+		{
+			name:   "perceived as harder",
+			higher: &ProblemEntry{ProblemName: "Felt Hard", Difficulty: DifficultyEasy, PerceivedDifficulty: DifficultyHard, Status: StatusSolved, SolvedAt: priorityNow.AddDate(0, 0, -10)},
+			lower:  &ProblemEntry{ProblemName: "Felt Medium", Difficulty: DifficultyHard, PerceivedDifficulty: DifficultyMedium, Status: StatusSolved, SolvedAt: priorityNow.AddDate(0, 0, -10)},
+		},
+		{
+			name:   "stuck over needed a hint",
+			higher: &ProblemEntry{ProblemName: "Stuck", Difficulty: DifficultyMedium, Status: StatusStuck, SolvedAt: priorityNow.AddDate(0, 0, -10)},
+			lower:  &ProblemEntry{ProblemName: "Hint", Difficulty: DifficultyMedium, Status: StatusNeededHint, SolvedAt: priorityNow.AddDate(0, 0, -10)},
+		},
+		{
+			name:   "needed a hint over solved",
+			higher: &ProblemEntry{ProblemName: "Hint", Difficulty: DifficultyMedium, Status: StatusNeededHint, SolvedAt: priorityNow.AddDate(0, 0, -10)},
+			lower:  reviewFixture("Solved", 10),
+		},
+		{
+			name:   "a recent review resets the overdue days",
+			higher: reviewFixture("Never Reviewed", 20),
+			lower:  &ProblemEntry{ProblemName: "Just Reviewed", Difficulty: DifficultyMedium, Status: StatusSolved, SolvedAt: priorityNow.AddDate(0, 0, -20), LastReviewedAt: &reviewedAt},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			higher, lower := computeReviewPriority(tt.higher, priorityNow), computeReviewPriority(tt.lower, priorityNow)
+			if higher <= lower {
+				t.Errorf("%s scored %.2f, not above %s at %.2f", tt.higher.ProblemName, higher, tt.lower.ProblemName, lower)
+			}
+		})
+	}
+
+	// 10 overdue days, 10 for no reviews, 6 for Hard and 6 for Stuck
+	worst := &ProblemEntry{Difficulty: DifficultyHard, Status: StatusStuck, SolvedAt: priorityNow.AddDate(0, 0, -10)}
+	if got := computeReviewPriority(worst, priorityNow); got != 32 {
+		t.Errorf("computeReviewPriority = %.2f, want 32", got)
+	}
+
+	// A solve in the future isn't negatively overdue
+	future := reviewFixture("Future", -5)
+	if got := computeReviewPriority(future, priorityNow); got != masteryWeight+difficultyBonus[DifficultyMedium] {
+		t.Errorf("computeReviewPriority for a future solve = %.2f, want no overdue days", got)
+	}
+}
+
+func TestSortByReviewPriority(t *testing.T) {
+	problems := []*ProblemEntry{
+		reviewFixture("Recent", 8),
+		{ProblemName: "Stuck Hard", Difficulty: DifficultyHard, Status: StatusStuck, SolvedAt: priorityNow.AddDate(0, 0, -8)},
+		reviewFixture("Old", 40),
+		reviewFixture("Recent Twin", 8),
+	}
+
+	sortByReviewPriority(problems, priorityNow)
+
+	// Equal scores keep the order they were fetched in
+	want := []string{"Old", "Stuck Hard", "Recent", "Recent Twin"}
+	if got := problemNames(problems); !slices.Equal(got, want) {
+		t.Errorf("order = %v, want %v", got, want)
+	}
+	for idx, p := range problems {
+		if p.ReviewPriority == 0 {
+			t.Errorf("problem %d has no ReviewPriority set", idx)
+		}
+	}
+}
@@ -0,0 +1,209 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ErrProblemSetNotFound is returned when no problem set has the given ID
+var ErrProblemSetNotFound = errors.New("problem set not found")
+
+// ErrProblemSetExists is returned when creating a set whose name the owner already uses
+var ErrProblemSetExists = errors.New("a problem set with that name already exists")
+
+// builtinProblemSet is a set seeded into every guild, listing its problems by name
+type builtinProblemSet struct {
+	Name        string
+	Description string
+	Problems    []string
+}
+
+// builtinProblemSets are seeded into every guild by EnsureBuiltinProblemSets
+var builtinProblemSets = []builtinProblemSet{
+	{
+		Name:        "Blind 75",
+		Description: "The 75 classic interview problems covering every core topic",
+		Problems: []string{
+			// Array
+			"Two Sum", "Best Time to Buy and Sell Stock", "Contains Duplicate", "Product of Array Except Self",
+			"Maximum Subarray", "Maximum Product Subarray", "Find Minimum in Rotated Sorted Array",
+			"Search in Rotated Sorted Array", "3Sum", "Container With Most Water",
+			// Binary
+			"Sum of Two Integers", "Number of 1 Bits", "Counting Bits", "Missing Number", "Reverse Bits",
+			// Dynamic Programming
+			"Climbing Stairs", "Coin Change", "Longest Increasing Subsequence", "Longest Common Subsequence",
+			"Word Break", "Combination Sum IV", "House Robber", "House Robber II", "Decode Ways",
+			"Unique Paths", "Jump Game",
+			// Graph
+			"Clone Graph", "Course Schedule", "Pacific Atlantic Water Flow", "Number of Islands",
+			"Longest Consecutive Sequence", "Alien Dictionary", "Graph Valid Tree",
+			"Number of Connected Components in an Undirected Graph",
+			// Interval
+			"Insert Interval", "Merge Intervals", "Non-overlapping Intervals", "Meeting Rooms", "Meeting Rooms II",
+			// Linked List
+			"Reverse Linked List", "Linked List Cycle", "Merge Two Sorted Lists", "Merge k Sorted Lists",
+			"Remove Nth Node From End of List", "Reorder List",
+			// Matrix
+			"Set Matrix Zeroes", "Spiral Matrix", "Rotate Image", "Word Search",
+			// String
+			"Longest Substring Without Repeating Characters", "Longest Repeating Character Replacement",
+			"Minimum Window Substring", "Valid Anagram", "Group Anagrams", "Valid Parentheses",
+			"Valid Palindrome", "Longest Palindromic Substring", "Palindromic Substrings",
+			"Encode and Decode Strings",
+			// Tree
+			"Maximum Depth of Binary Tree", "Same Tree", "Invert Binary Tree", "Binary Tree Maximum Path Sum",
+			"Binary Tree Level Order Traversal", "Serialize and Deserialize Binary Tree", "Subtree of Another Tree",
+			"Construct Binary Tree from Preorder and Inorder Traversal", "Validate Binary Search Tree",
+			"Kth Smallest Element in a BST", "Lowest Common Ancestor of a Binary Search Tree",
+			"Implement Trie (Prefix Tree)", "Design Add and Search Words Data Structure", "Word Search II",
+			// Heap
+			"Top K Frequent Elements", "Find Median from Data Stream",
+		},
+	},
+}
+
+// builtinProblemNames returns the problems of the built-in set with the given name
+func builtinProblemNames(name string) []string {
+	for _, set := range builtinProblemSets {
+		if set.Name == name {
+			return set.Problems
+		}
+	}
+	return nil
+}
+
+// EnsureBuiltinProblemSets creates any built-in problem sets the guild doesn't have yet
+func (r *Repository) EnsureBuiltinProblemSets(ctx context.Context, guildID string) error {
+	sets := make([]ProblemSet, len(builtinProblemSets))
+	for i, builtin := range builtinProblemSets {
+		sets[i] = ProblemSet{Name: builtin.Name, Description: builtin.Description, GuildID: guildID}
+	}
+
+	// The unique index on (guild_id, owner_user_id, name) makes seeding idempotent
+	err := r.withContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(&sets).Error
+	if err != nil {
+		return fmt.Errorf("failed to seed built-in problem sets: %w", err)
+	}
+	return nil
+}
+
+// CreateProblemSet stores a new problem set, failing with ErrProblemSetExists if the owner already
+// has a set by that name in the guild
+func (r *Repository) CreateProblemSet(ctx context.Context, set *ProblemSet) error {
+	err := r.WithRetry(ctx, defaultTxRetries, func(tx *gorm.DB) error {
+		var count int64
+		if err := tx.Model(&ProblemSet{}).
+			Where("guild_id = ? AND owner_user_id = ? AND name = ?", set.GuildID, set.OwnerUserID, set.Name).
+			Count(&count).Error; err != nil {
+			return err
+		}
+		if count > 0 {
+			return ErrProblemSetExists
+		}
+		return tx.Create(set).Error
+	})
+
+	if err != nil {
+		return fmt.Errorf("failed to create problem set %q: %w", set.Name, err)
+	}
+	return nil
+}
+
+// AddProblemToSet adds a problem to a set; adding one that's already there does nothing
+func (r *Repository) AddProblemToSet(ctx context.Context, setID, problemID uint) error {
+	item := &ProblemSetItem{SetID: setID, ProblemID: problemID}
+	if err := r.withContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(item).Error; err != nil {
+		return fmt.Errorf("failed to add problem %d to set %d: %w", problemID, setID, err)
+	}
+	return nil
+}
+
+// RemoveProblemFromSet removes a problem from a set, reporting whether it was in the set
+func (r *Repository) RemoveProblemFromSet(ctx context.Context, setID, problemID uint) (bool, error) {
+	result := r.withContext(ctx).Where("set_id = ? AND problem_id = ?", setID, problemID).Delete(&ProblemSetItem{})
+	if result.Error != nil {
+		return false, fmt.Errorf("failed to remove problem %d from set %d: %w", problemID, setID, result.Error)
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// GetProblemSet retrieves a problem set with its items. Built-in sets carry their problem names instead.
+func (r *Repository) GetProblemSet(ctx context.Context, id uint) (*ProblemSet, error) {
+	var set ProblemSet
+	err := r.withContext(ctx).Preload("Items").First(&set, id).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("failed to get problem set %d: %w", id, ErrProblemSetNotFound)
+		}
+		return nil, fmt.Errorf("failed to get problem set %d: %w", id, err)
+	}
+
+	if set.Builtin() {
+		set.ProblemNames = builtinProblemNames(set.Name)
+	}
+	return &set, nil
+}
+
+// ListProblemSets retrieves the built-in sets of a guild followed by the user's own sets there
+func (r *Repository) ListProblemSets(ctx context.Context, userID, guildID string) ([]*ProblemSet, error) {
+	var sets []*ProblemSet
+	err := r.withContext(ctx).Preload("Items").
+		Where("guild_id = ? AND owner_user_id IN ?", guildID, []string{"", userID}).
+		Order("owner_user_id ASC, name ASC").
+		Find(&sets).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to list problem sets: %w", err)
+	}
+
+	for _, set := range sets {
+		if set.Builtin() {
+			set.ProblemNames = builtinProblemNames(set.Name)
+		}
+	}
+	return sets, nil
+}
+
+// DeleteProblemSet deletes a problem set and its items
+func (r *Repository) DeleteProblemSet(ctx context.Context, id uint) error {
+	return r.WithRetry(ctx, defaultTxRetries, func(tx *gorm.DB) error {
+		if err := tx.Where("set_id = ?", id).Delete(&ProblemSetItem{}).Error; err != nil {
+			return fmt.Errorf("failed to delete problem set items: %w", err)
+		}
+		result := tx.Delete(&ProblemSet{}, id)
+		if result.Error != nil {
+			return fmt.Errorf("failed to delete problem set: %w", result.Error)
+		}
+		if result.RowsAffected == 0 {
+			return fmt.Errorf("failed to delete problem set %d: %w", id, ErrProblemSetNotFound)
+		}
+		return nil
+	})
+}
+
+// ListProblemSetProblems retrieves the user's unarchived problems in a set, oldest solve first.
+// For built-in sets these are the user's problems whose names, ignoring case, the set lists.
+func (r *Repository) ListProblemSetProblems(ctx context.Context, set *ProblemSet, userID string) ([]*ProblemEntry, error) {
+	query := r.withContext(ctx).Model(&Problem{}).Preload("Tags").
+		Where("problems.user_id = ? AND problems.archived = ?", userID, false)
+
+	if set.Builtin() {
+		names := make([]string, len(set.ProblemNames))
+		for i, name := range set.ProblemNames {
+			names[i] = strings.ToLower(name)
+		}
+		query = query.Where("LOWER(problems.problem_name) IN ?", names)
+	} else {
+		query = query.Where("problems.id IN (?)", r.withContext(ctx).Model(&ProblemSetItem{}).Select("problem_id").Where("set_id = ?", set.ID))
+	}
+
+	var problems []Problem
+	if err := query.Order("problems.solved_at ASC").Find(&problems).Error; err != nil {
+		return nil, fmt.Errorf("failed to list problems in set %d: %w", set.ID, err)
+	}
+	return toEntries(problems), nil
+}
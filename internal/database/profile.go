@@ -0,0 +1,48 @@
+package database
+
+import (
+	"context"
+
+	"golang.org/x/sync/errgroup"
+)
+
+// profileRecentProblems is how many of the latest problems a profile includes
+const profileRecentProblems = 3
+
+// UserProfile aggregates everything shown on a user's profile
+type UserProfile struct {
+	Stats          *UserStats
+	Categories     map[string]int
+	CurrentStreak  int
+	LongestStreak  int
+	RecentProblems []*ProblemEntry
+}
+
+// GetUserProfile gathers a user's stats, category distribution, streaks and most recent
+// problems, running the independent queries concurrently. The first failure cancels the rest.
+func (r *Repository) GetUserProfile(ctx context.Context, userID string) (*UserProfile, error) {
+	profile := &UserProfile{}
+	g, ctx := errgroup.WithContext(ctx)
+
+	g.Go(func() (err error) {
+		profile.Stats, err = r.GetUserStats(ctx, userID)
+		return err
+	})
+	g.Go(func() (err error) {
+		profile.Categories, err = r.GetCategoryDistribution(ctx, userID)
+		return err
+	})
+	g.Go(func() (err error) {
+		profile.CurrentStreak, profile.LongestStreak, err = r.GetUserStreaks(ctx, userID)
+		return err
+	})
+	g.Go(func() (err error) {
+		profile.RecentProblems, err = r.ListProblems(ctx, userID, "", "", "", nil, true, profileRecentProblems, 0)
+		return err
+	})
+
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+	return profile, nil
+}
@@ -0,0 +1,43 @@
+package database
+
+import (
+	"context"
+	"testing"
+)
+
+func TestGetUserProfile(t *testing.T) {
+	repo := newTestRepository(t)
+	seeded := seedTestData(t, repo, "profile-user")
+
+	profile, err := repo.GetUserProfile(context.Background(), "profile-user")
+	if err != nil {
+		t.Fatalf("GetUserProfile: %v", err)
+	}
+
+	if profile.Stats.TotalProblems != len(seeded) {
+		t.Errorf("TotalProblems = %d, want %d", profile.Stats.TotalProblems, len(seeded))
+	}
+	if got := profile.Categories["Graph"]; got != 2 {
+		t.Errorf("Graph problems = %d, want 2", got)
+	}
+	// The fixtures were solved on ten consecutive days
+	if profile.LongestStreak != 10 {
+		t.Errorf("LongestStreak = %d, want 10", profile.LongestStreak)
+	}
+	if len(profile.RecentProblems) != profileRecentProblems {
+		t.Fatalf("RecentProblems has %d problems, want %d", len(profile.RecentProblems), profileRecentProblems)
+	}
+	if name := profile.RecentProblems[0].ProblemName; name != "Word Ladder" {
+		t.Errorf("most recent problem = %q, want Word Ladder", name)
+	}
+}
+
+func TestGetUserProfileCancelled(t *testing.T) {
+	repo := newTestRepository(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if _, err := repo.GetUserProfile(ctx, "profile-user"); err == nil {
+		t.Error("GetUserProfile succeeded with a cancelled context")
+	}
+}
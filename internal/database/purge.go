@@ -0,0 +1,57 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"gorm.io/gorm"
+)
+
+// TruncateUserData permanently deletes everything stored about a user: their problems, including
+// soft-deleted ones, with their tags and review history, plus their settings, preferences, usage
+// counts, aliases and problem sets. It returns the number of problems removed.
+func (r *Repository) TruncateUserData(ctx context.Context, userID string) (int64, error) {
+	// Built-in problem sets have an empty owner, so an empty ID must never reach the deletes below
+	if userID == "" {
+		return 0, errors.New("user ID is required")
+	}
+
+	var removed int64
+	err := r.WithRetry(ctx, defaultTxRetries, func(tx *gorm.DB) error {
+		problemIDs := tx.Unscoped().Model(&Problem{}).Select("id").Where("user_id = ?", userID)
+		setIDs := tx.Model(&ProblemSet{}).Select("id").Where("owner_user_id = ?", userID)
+
+		if err := tx.Where("problem_id IN (?)", problemIDs).Delete(&ReviewHistory{}).Error; err != nil {
+			return fmt.Errorf("failed to delete review history: %w", err)
+		}
+		if err := tx.Exec("DELETE FROM problem_tags WHERE problem_id IN (?)", problemIDs).Error; err != nil {
+			return fmt.Errorf("failed to delete problem tags: %w", err)
+		}
+		if err := tx.Where("problem_id IN (?) OR set_id IN (?)", problemIDs, setIDs).Delete(&ProblemSetItem{}).Error; err != nil {
+			return fmt.Errorf("failed to delete problem set items: %w", err)
+		}
+		if err := tx.Where("owner_user_id = ?", userID).Delete(&ProblemSet{}).Error; err != nil {
+			return fmt.Errorf("failed to delete problem sets: %w", err)
+		}
+
+		result := tx.Unscoped().Where("user_id = ?", userID).Delete(&Problem{})
+		if result.Error != nil {
+			return fmt.Errorf("failed to delete problems: %w", result.Error)
+		}
+		removed = result.RowsAffected
+
+		for _, model := range []interface{}{&Tag{}, &UserSettings{}, &UserPreferences{}, &CommandUsage{}, &CommandAlias{}} {
+			if err := tx.Unscoped().Where("user_id = ?", userID).Delete(model).Error; err != nil {
+				return fmt.Errorf("failed to delete user data: %w", err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	r.invalidateUser(userID)
+	return removed, nil
+}
@@ -0,0 +1,87 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// RecalculateUserStats reconciles the review counters stored on each of the user's problems with
+// the review history, in one transaction, and returns how many problems were corrected. History
+// has only been recorded since review_history was added, so a review_count is raised to match the
+// history but never lowered below it.
+func (r *Repository) RecalculateUserStats(ctx context.Context, userID string) (int, error) {
+	corrected := 0
+	err := r.WithRetry(ctx, defaultTxRetries, func(tx *gorm.DB) error {
+		corrected = 0
+
+		var problems []Problem
+		if err := tx.Select("id", "review_count", "last_reviewed_at").
+			Where("user_id = ?", userID).
+			Find(&problems).Error; err != nil {
+			return err
+		}
+
+		var history []ReviewHistory
+		if err := tx.Where("problem_id IN (?)", tx.Model(&Problem{}).Select("id").Where("user_id = ?", userID)).
+			Find(&history).Error; err != nil {
+			return err
+		}
+
+		// Aggregate in Go so the timestamps stay typed
+		counts := make(map[uint]int)
+		latest := make(map[uint]time.Time)
+		for _, review := range history {
+			counts[review.ProblemID]++
+			if review.ReviewedAt.After(latest[review.ProblemID]) {
+				latest[review.ProblemID] = review.ReviewedAt
+			}
+		}
+
+		for _, problem := range problems {
+			reviewCount := max(problem.ReviewCount, counts[problem.ID])
+			lastReviewedAt := problem.LastReviewedAt
+			if last, ok := latest[problem.ID]; ok && (lastReviewedAt == nil || last.After(*lastReviewedAt)) {
+				lastReviewedAt = &last
+			}
+			if reviewCount == problem.ReviewCount && lastReviewedAt == problem.LastReviewedAt {
+				continue
+			}
+
+			if err := tx.Model(&Problem{}).Where("id = ?", problem.ID).UpdateColumns(map[string]interface{}{
+				"review_count":     reviewCount,
+				"last_reviewed_at": utcPtr(lastReviewedAt),
+			}).Error; err != nil {
+				return err
+			}
+			corrected++
+		}
+		return nil
+	})
+
+	if err != nil {
+		return 0, fmt.Errorf("failed to recalculate stats for user %s: %w", userID, err)
+	}
+	return corrected, nil
+}
+
+// RecalculateAllUserStats runs RecalculateUserStats for every user, returning the total number of
+// problems corrected
+func (r *Repository) RecalculateAllUserStats(ctx context.Context) (int, error) {
+	users, err := r.ListAllUsers(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	total := 0
+	for _, userID := range users {
+		corrected, err := r.RecalculateUserStats(ctx, userID)
+		if err != nil {
+			return total, err
+		}
+		total += corrected
+	}
+	return total, nil
+}
@@ -0,0 +1,73 @@
+package database
+
+import (
+	"context"
+	"testing"
+)
+
+// corruptReviewStats overwrites a problem's review counters the way a lost update would
+func corruptReviewStats(t *testing.T, repo *Repository, id uint, reviewCount int) {
+	t.Helper()
+	err := repo.db.Model(&Problem{}).Where("id = ?", id).UpdateColumns(map[string]interface{}{
+		"review_count":     reviewCount,
+		"last_reviewed_at": nil,
+	}).Error
+	if err != nil {
+		t.Fatalf("failed to corrupt review stats: %v", err)
+	}
+}
+
+func TestRecalculateUserStats(t *testing.T) {
+	ctx := context.Background()
+	repo := newTestRepository(t)
+	entries := seedTestData(t, repo, "recalc-user")
+	other := seedTestData(t, repo, "other-user")
+
+	drifted, ahead := entries[0], entries[1]
+	reviewTimes(t, repo, drifted.ID, 3)
+	reviewTimes(t, repo, other[0].ID, 2)
+	want, err := repo.GetProblem(ctx, drifted.ID)
+	if err != nil {
+		t.Fatalf("GetProblem: %v", err)
+	}
+
+	corruptReviewStats(t, repo, drifted.ID, 1)
+	corruptReviewStats(t, repo, other[0].ID, 0)
+	// Reviews from before the history was recorded leave a count above it, which stays
+	corruptReviewStats(t, repo, ahead.ID, 4)
+
+	corrected, err := repo.RecalculateUserStats(ctx, "recalc-user")
+	if err != nil {
+		t.Fatalf("RecalculateUserStats: %v", err)
+	}
+	if corrected != 1 {
+		t.Errorf("corrected %d problems, want 1", corrected)
+	}
+
+	got, err := repo.GetProblem(ctx, drifted.ID)
+	if err != nil {
+		t.Fatalf("GetProblem: %v", err)
+	}
+	if got.ReviewCount != 3 || got.LastReviewedAt == nil || !got.LastReviewedAt.Equal(*want.LastReviewedAt) {
+		t.Errorf("recalculated %d reviews last at %v, want 3 at %v", got.ReviewCount, got.LastReviewedAt, want.LastReviewedAt)
+	}
+	if got, err := repo.GetProblem(ctx, ahead.ID); err != nil || got.ReviewCount != 4 {
+		t.Errorf("review count without history = %d (%v), want 4 kept", got.ReviewCount, err)
+	}
+	if got, err := repo.GetProblem(ctx, other[0].ID); err != nil || got.ReviewCount != 0 {
+		t.Errorf("other user's review count = %d (%v), want it left alone", got.ReviewCount, err)
+	}
+
+	// Stats that already agree aren't touched
+	if corrected, err := repo.RecalculateUserStats(ctx, "recalc-user"); err != nil || corrected != 0 {
+		t.Errorf("second RecalculateUserStats = %d, %v, want 0", corrected, err)
+	}
+
+	corrected, err = repo.RecalculateAllUserStats(ctx)
+	if err != nil || corrected != 1 {
+		t.Errorf("RecalculateAllUserStats = %d, %v, want the other user's problem corrected", corrected, err)
+	}
+	if got, err := repo.GetProblem(ctx, other[0].ID); err != nil || got.ReviewCount != 2 {
+		t.Errorf("other user's review count = %d (%v), want 2", got.ReviewCount, err)
+	}
+}
@@ -0,0 +1,346 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestCreateProblem(t *testing.T) {
+	ctx := context.Background()
+	repo := newTestRepository(t)
+
+	entry := &ProblemEntry{UserID: "crud-user", ProblemName: "Two Sum", Difficulty: DifficultyEasy, Category: "Array", Status: StatusSolved, SolvedAt: seedBaseTime, Tags: []string{"array", "hash-table"}}
+	if err := repo.CreateProblem(ctx, entry); err != nil {
+		t.Fatalf("CreateProblem: %v", err)
+	}
+	if entry.ID == 0 {
+		t.Fatal("CreateProblem left the ID unset")
+	}
+
+	got, err := repo.GetProblem(ctx, entry.ID)
+	if err != nil {
+		t.Fatalf("GetProblem: %v", err)
+	}
+	if got.ProblemName != "Two Sum" || got.Difficulty != DifficultyEasy || !got.SolvedAt.Equal(seedBaseTime) {
+		t.Errorf("GetProblem = %+v, want the created problem", got)
+	}
+	if !slices.Equal(sortedTags(got.Tags), []string{"array", "hash-table"}) {
+		t.Errorf("tags = %v, want [array hash-table]", got.Tags)
+	}
+
+	invalid := &ProblemEntry{UserID: "crud-user", ProblemName: "Bad", Difficulty: "Extreme", Category: "Array", Status: StatusSolved}
+	var validation *ValidationError
+	if err := repo.CreateProblem(ctx, invalid); !errors.As(err, &validation) || validation.Field != FieldDifficulty {
+		t.Errorf("CreateProblem with an invalid difficulty = %v, want a difficulty validation error", err)
+	}
+}
+
+func TestGetProblemNotFound(t *testing.T) {
+	repo := newTestRepository(t)
+
+	if _, err := repo.GetProblem(context.Background(), 404); !errors.Is(err, ErrProblemNotFound) {
+		t.Errorf("GetProblem = %v, want ErrProblemNotFound", err)
+	}
+}
+
+func TestGetProblemByName(t *testing.T) {
+	ctx := context.Background()
+	repo := newTestRepository(t)
+	seedTestData(t, repo, "crud-user")
+	seedTestData(t, repo, "other-user")
+
+	tests := []struct {
+		name    string
+		lookup  string
+		want    string
+		wantErr error
+	}{
+		{name: "exact", lookup: "Coin Change", want: "Coin Change"},
+		{name: "case-insensitive", lookup: "coin CHANGE", want: "Coin Change"},
+		{name: "surrounding whitespace", lookup: "  Word Ladder ", want: "Word Ladder"},
+		{name: "partial name", lookup: "Coin", wantErr: ErrProblemNotFound},
+		{name: "unknown", lookup: "Jump Game", wantErr: ErrProblemNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := repo.GetProblemByName(ctx, "crud-user", tt.lookup)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("GetProblemByName(%q) = %v, want %v", tt.lookup, err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("GetProblemByName(%q): %v", tt.lookup, err)
+			}
+			if got.ProblemName != tt.want || got.UserID != "crud-user" {
+				t.Errorf("GetProblemByName(%q) = %q of %s, want %q of crud-user", tt.lookup, got.ProblemName, got.UserID, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetProblemByNameAmbiguous(t *testing.T) {
+	ctx := context.Background()
+	repo := newTestRepository(t)
+	first := &ProblemEntry{UserID: "crud-user", ProblemName: "Two Sum", Difficulty: DifficultyEasy, Category: "Array", Status: StatusStuck, SolvedAt: seedBaseTime}
+	second := &ProblemEntry{UserID: "crud-user", ProblemName: "two sum", Difficulty: DifficultyEasy, Category: "Array", Status: StatusSolved, SolvedAt: seedBaseTime.AddDate(0, 1, 0)}
+	for _, entry := range []*ProblemEntry{second, first} {
+		if err := repo.CreateProblem(ctx, entry); err != nil {
+			t.Fatalf("CreateProblem: %v", err)
+		}
+	}
+
+	_, err := repo.GetProblemByName(ctx, "crud-user", "Two Sum")
+	var ambiguous *AmbiguousProblemError
+	if !errors.As(err, &ambiguous) {
+		t.Fatalf("GetProblemByName = %v, want an *AmbiguousProblemError", err)
+	}
+	// Candidates are listed oldest first
+	if len(ambiguous.Matches) != 2 || ambiguous.Matches[0].ID != first.ID || ambiguous.Matches[1].ID != second.ID {
+		t.Errorf("matches = %v, want IDs %d then %d", ambiguous.Matches, first.ID, second.ID)
+	}
+}
+
+func TestUpdateProblem(t *testing.T) {
+	ctx := context.Background()
+	repo := newTestRepository(t)
+	entry := seedTestData(t, repo, "crud-user")[0]
+
+	entry.ProblemName = "Two Sum II"
+	entry.Difficulty = DifficultyMedium
+	entry.Status = StatusNeededHint
+	entry.Tags = []string{"two-pointers", "array"}
+	if err := repo.UpdateProblem(ctx, entry); err != nil {
+		t.Fatalf("UpdateProblem: %v", err)
+	}
+
+	got, err := repo.GetProblem(ctx, entry.ID)
+	if err != nil {
+		t.Fatalf("GetProblem: %v", err)
+	}
+	if got.ProblemName != "Two Sum II" || got.Difficulty != DifficultyMedium || got.Status != StatusNeededHint {
+		t.Errorf("GetProblem = %+v, want the updated fields", got)
+	}
+	if !slices.Equal(sortedTags(got.Tags), []string{"array", "two-pointers"}) {
+		t.Errorf("tags = %v, want [array two-pointers]", got.Tags)
+	}
+
+	missing := *entry
+	missing.ID = 404
+	if err := repo.UpdateProblem(ctx, &missing); !errors.Is(err, ErrProblemNotFound) {
+		t.Errorf("UpdateProblem of an unknown ID = %v, want ErrProblemNotFound", err)
+	}
+}
+
+func TestDeleteProblem(t *testing.T) {
+	ctx := context.Background()
+	repo := newTestRepository(t)
+	problems := seedTestData(t, repo, "crud-user")
+	seedTestData(t, repo, "other-user")
+
+	// Merge k Sorted Lists is the only problem tagged heap and linked-list
+	merge := problems[7]
+	if err := repo.DeleteProblem(ctx, merge.ID); err != nil {
+		t.Fatalf("DeleteProblem: %v", err)
+	}
+
+	if _, err := repo.GetProblem(ctx, merge.ID); !errors.Is(err, ErrProblemNotFound) {
+		t.Errorf("GetProblem after delete = %v, want ErrProblemNotFound", err)
+	}
+	for _, name := range []string{"heap", "linked-list"} {
+		var owners []string
+		if err := repo.db.Model(&Tag{}).Where("name = ?", name).Pluck("user_id", &owners).Error; err != nil {
+			t.Fatalf("failed to load tags: %v", err)
+		}
+		if !slices.Equal(owners, []string{"other-user"}) {
+			t.Errorf("%s tag owned by %v, want only other-user", name, owners)
+		}
+	}
+	// Tags still in use stay
+	var stack int64
+	repo.db.Model(&Tag{}).Where("user_id = ? AND name = ?", "crud-user", "stack").Count(&stack)
+	if stack != 1 {
+		t.Error("DeleteProblem removed a tag other problems still use")
+	}
+
+	if err := repo.DeleteProblem(ctx, merge.ID); !errors.Is(err, ErrProblemNotFound) {
+		t.Errorf("deleting twice = %v, want ErrProblemNotFound", err)
+	}
+}
+
+func TestListProblems(t *testing.T) {
+	ctx := context.Background()
+	repo := newTestRepository(t)
+	problems := seedTestData(t, repo, "list-user")
+	seedTestData(t, repo, "other-user")
+	if err := repo.SetArchived(ctx, problems[0].ID, true); err != nil {
+		t.Fatalf("SetArchived: %v", err)
+	}
+
+	tests := []struct {
+		name            string
+		status          string
+		difficulty      string
+		category        string
+		tags            []string
+		includeArchived bool
+		limit, offset   int
+		want            []string
+	}{
+		{name: "no filters", want: []string{"Word Ladder", "Trapping Rain Water", "Merge k Sorted Lists", "Binary Tree Level Order Traversal", "Number of Islands", "Coin Change", "Longest Substring Without Repeating Characters", "Climbing Stairs", "Valid Parentheses"}},
+		{name: "include archived", includeArchived: true, limit: 2, offset: 8, want: []string{"Valid Parentheses", "Two Sum"}},
+		{name: "status", status: StatusStuck, want: []string{"Word Ladder", "Coin Change"}},
+		{name: "difficulty", difficulty: DifficultyEasy, want: []string{"Climbing Stairs", "Valid Parentheses"}},
+		{name: "category", category: "Graph", want: []string{"Word Ladder", "Number of Islands"}},
+		{name: "tags", tags: []string{"dp"}, want: []string{"Coin Change", "Climbing Stairs"}},
+		{name: "combined", difficulty: DifficultyMedium, tags: []string{"bfs"}, status: StatusSolved, want: []string{"Binary Tree Level Order Traversal"}},
+		{name: "page", limit: 2, offset: 1, want: []string{"Trapping Rain Water", "Merge k Sorted Lists"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := repo.ListProblems(ctx, "list-user", tt.status, tt.difficulty, tt.category, tt.tags, tt.includeArchived, tt.limit, tt.offset)
+			if err != nil {
+				t.Fatalf("ListProblems: %v", err)
+			}
+			if names := problemNames(got); !slices.Equal(names, tt.want) {
+				t.Errorf("got %v, want %v", names, tt.want)
+			}
+		})
+	}
+}
+
+func TestListProblemsForReview(t *testing.T) {
+	ctx := context.Background()
+	repo := newTestRepository(t)
+	seeded := seedTestData(t, repo, "review-user")
+
+	recent := &ProblemEntry{UserID: "review-user", ProblemName: "Jump Game", Difficulty: DifficultyMedium, Category: "Greedy", Status: StatusSolved, SolvedAt: time.Now().UTC().AddDate(0, 0, -2)}
+	if err := repo.CreateProblem(ctx, recent); err != nil {
+		t.Fatalf("CreateProblem: %v", err)
+	}
+
+	due, err := repo.ListProblemsForReview(ctx, "review-user", 7*24*time.Hour)
+	if err != nil {
+		t.Fatalf("ListProblemsForReview: %v", err)
+	}
+	if len(due) != len(seeded) || slices.Contains(problemNames(due), "Jump Game") {
+		t.Errorf("due with a week's lookback = %v, want the %d seeded problems only", problemNames(due), len(seeded))
+	}
+
+	due, err = repo.ListProblemsForReview(ctx, "review-user", 24*time.Hour)
+	if err != nil {
+		t.Fatalf("ListProblemsForReview: %v", err)
+	}
+	if !slices.Contains(problemNames(due), "Jump Game") {
+		t.Errorf("due with a day's lookback = %v, want it to include Jump Game", problemNames(due))
+	}
+
+	// A review just now pushes the problem out of the lookback
+	if err := repo.IncrementReviewCount(ctx, recent.ID); err != nil {
+		t.Fatalf("IncrementReviewCount: %v", err)
+	}
+	due, err = repo.ListProblemsForReview(ctx, "review-user", 24*time.Hour)
+	if err != nil {
+		t.Fatalf("ListProblemsForReview: %v", err)
+	}
+	if slices.Contains(problemNames(due), "Jump Game") {
+		t.Error("a problem reviewed just now is still due")
+	}
+}
+
+func TestListProblemsForReviewExcludesArchived(t *testing.T) {
+	ctx := context.Background()
+	repo := newTestRepository(t)
+	seeded := seedTestData(t, repo, "archive-user")
+
+	for _, p := range seeded[:5] {
+		if err := repo.SetArchived(ctx, p.ID, true); err != nil {
+			t.Fatalf("SetArchived: %v", err)
+		}
+	}
+
+	// However long the lookback and whatever the scope, archived problems are never due
+	for _, scopes := range [][]ProblemScope{nil, {TaggedWith("archive-user", []string{"dp"}, false)}} {
+		for _, lookback := range []time.Duration{0, 7 * 24 * time.Hour} {
+			due, err := repo.ListProblemsForReview(ctx, "archive-user", lookback, scopes...)
+			if err != nil {
+				t.Fatalf("ListProblemsForReview: %v", err)
+			}
+			for _, p := range seeded[:5] {
+				if slices.Contains(problemNames(due), p.ProblemName) {
+					t.Errorf("archived problem %q is due with a %s lookback", p.ProblemName, lookback)
+				}
+			}
+		}
+	}
+
+	due, err := repo.ListProblemsForReview(ctx, "archive-user", 0)
+	if err != nil {
+		t.Fatalf("ListProblemsForReview: %v", err)
+	}
+	if len(due) != len(seeded)-5 {
+		t.Errorf("%d problems due, want the %d unarchived ones", len(due), len(seeded)-5)
+	}
+
+	// Unarchiving puts a problem back into rotation
+	if err := repo.SetArchived(ctx, seeded[0].ID, false); err != nil {
+		t.Fatalf("SetArchived: %v", err)
+	}
+	due, err = repo.ListProblemsForReview(ctx, "archive-user", 0)
+	if err != nil {
+		t.Fatalf("ListProblemsForReview: %v", err)
+	}
+	if !slices.Contains(problemNames(due), seeded[0].ProblemName) {
+		t.Errorf("due = %v, want it to include the unarchived %s", problemNames(due), seeded[0].ProblemName)
+	}
+}
+
+func TestIncrementReviewCount(t *testing.T) {
+	ctx := context.Background()
+	repo := newTestRepository(t)
+	problem := seedTestData(t, repo, "review-user")[1]
+
+	before := time.Now().UTC().Add(-time.Second)
+	if err := repo.IncrementReviewCount(ctx, problem.ID); err != nil {
+		t.Fatalf("IncrementReviewCount: %v", err)
+	}
+
+	got, err := repo.GetProblem(ctx, problem.ID)
+	if err != nil {
+		t.Fatalf("GetProblem: %v", err)
+	}
+	if got.ReviewCount != problem.ReviewCount+1 {
+		t.Errorf("ReviewCount = %d, want %d", got.ReviewCount, problem.ReviewCount+1)
+	}
+	if got.LastReviewedAt == nil || got.LastReviewedAt.Before(before) {
+		t.Errorf("LastReviewedAt = %v, want after %v", got.LastReviewedAt, before)
+	}
+
+	history, err := repo.ListReviewHistory(ctx, problem.ID, 0)
+	if err != nil {
+		t.Fatalf("ListReviewHistory: %v", err)
+	}
+	if len(history) != 1 {
+		t.Errorf("review history has %d entries, want 1", len(history))
+	}
+}
+
+// problemNames returns the names of problems in order
+func problemNames(problems []*ProblemEntry) []string {
+	names := make([]string, len(problems))
+	for idx, p := range problems {
+		names[idx] = p.ProblemName
+	}
+	return names
+}
+
+// sortedTags returns a sorted copy of tags
+func sortedTags(tags []string) []string {
+	sorted := slices.Clone(tags)
+	slices.Sort(sorted)
+	return sorted
+}
@@ -0,0 +1,217 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/rs/zerolog"
+	"gorm.io/gorm"
+)
+
+// searchIndexStatements create the problems_fts index over problem names and notes and the
+// triggers that keep it in sync with the problems table. They are applied outside the numbered
+// migrations because FTS5 is a compile-time option of SQLite (build with -tags sqlite_fts5);
+// a schema migration that required it would fail on builds without it.
+var searchIndexStatements = []string{
+	`CREATE VIRTUAL TABLE IF NOT EXISTS problems_fts USING fts5(problem_name, notes, content='problems', content_rowid='id')`,
+	`CREATE TRIGGER IF NOT EXISTS problems_fts_ai AFTER INSERT ON problems BEGIN
+		INSERT INTO problems_fts(rowid, problem_name, notes) VALUES (new.id, new.problem_name, new.notes);
+	END`,
+	`CREATE TRIGGER IF NOT EXISTS problems_fts_ad AFTER DELETE ON problems BEGIN
+		INSERT INTO problems_fts(problems_fts, rowid, problem_name, notes) VALUES ('delete', old.id, old.problem_name, old.notes);
+	END`,
+	`CREATE TRIGGER IF NOT EXISTS problems_fts_au AFTER UPDATE ON problems BEGIN
+		INSERT INTO problems_fts(problems_fts, rowid, problem_name, notes) VALUES ('delete', old.id, old.problem_name, old.notes);
+		INSERT INTO problems_fts(rowid, problem_name, notes) VALUES (new.id, new.problem_name, new.notes);
+	END`,
+}
+
+// dropSearchTriggerStatements remove the triggers that keep the index in sync
+var dropSearchTriggerStatements = []string{
+	`DROP TRIGGER IF EXISTS problems_fts_au`,
+	`DROP TRIGGER IF EXISTS problems_fts_ad`,
+	`DROP TRIGGER IF EXISTS problems_fts_ai`,
+}
+
+// dropSearchTableStatement removes the full-text index itself
+const dropSearchTableStatement = `DROP TABLE IF EXISTS problems_fts`
+
+// dropSearchIndexStatements remove the full-text index and its triggers
+var dropSearchIndexStatements = slices.Concat(dropSearchTriggerStatements, []string{dropSearchTableStatement})
+
+// FTSSearch searches the user's problem names and notes, returning at most limit matches
+// ranked by relevance. When SQLite was built without FTS5 it falls back to a LIKE scan
+// ordered by solve date.
+func (r *Repository) FTSSearch(ctx context.Context, userID, query string, limit int) ([]*ProblemEntry, error) {
+	query = strings.TrimSpace(query)
+	if query == "" {
+		return nil, fmt.Errorf("search query is required")
+	}
+
+	if !r.searchIndex {
+		return r.likeSearch(ctx, userID, query, limit)
+	}
+
+	q := r.withContext(ctx).Model(&Problem{}).Preload("Tags").
+		Joins("JOIN problems_fts ON problems_fts.rowid = problems.id").
+		Where("problems_fts MATCH ?", ftsQuery(query)).
+		Where("problems.user_id = ?", userID).
+		Order("problems_fts.rank")
+	if limit > 0 {
+		q = q.Limit(limit)
+	}
+
+	var problems []Problem
+	if err := q.Find(&problems).Error; err != nil {
+		return nil, fmt.Errorf("failed to search problems: %w", err)
+	}
+	return toEntries(problems), nil
+}
+
+// SearchProblemsByPartialName returns the user's most recently solved problems whose name
+// contains partial (ignoring case) or whose ID starts with it. An empty partial matches everything.
+func (r *Repository) SearchProblemsByPartialName(ctx context.Context, userID, partial string, limit int) ([]*ProblemEntry, error) {
+	q := r.withContext(ctx).Model(&Problem{}).Preload("Tags").Where("user_id = ?", userID)
+
+	if partial = strings.TrimSpace(partial); partial != "" {
+		escaped := escapeLike(strings.ToLower(partial))
+		q = q.Where("(LOWER(problem_name) LIKE ? ESCAPE '\\' OR CAST(id AS TEXT) LIKE ? ESCAPE '\\')", "%"+escaped+"%", escaped+"%")
+	}
+	if limit > 0 {
+		q = q.Limit(limit)
+	}
+
+	var problems []Problem
+	if err := q.Order("solved_at DESC").Find(&problems).Error; err != nil {
+		return nil, fmt.Errorf("failed to search problems: %w", err)
+	}
+	return toEntries(problems), nil
+}
+
+// likeSearch is the unindexed fallback used when the FTS5 index is unavailable
+func (r *Repository) likeSearch(ctx context.Context, userID, query string, limit int) ([]*ProblemEntry, error) {
+	pattern := "%" + escapeLike(query) + "%"
+
+	q := r.withContext(ctx).Model(&Problem{}).Preload("Tags").
+		Where("user_id = ?", userID).
+		Where("(problem_name LIKE ? ESCAPE '\\' OR notes LIKE ? ESCAPE '\\')", pattern, pattern).
+		Order("solved_at DESC")
+	if limit > 0 {
+		q = q.Limit(limit)
+	}
+
+	var problems []Problem
+	if err := q.Find(&problems).Error; err != nil {
+		return nil, fmt.Errorf("failed to search problems: %w", err)
+	}
+	return toEntries(problems), nil
+}
+
+// ensureSearchIndex creates the full-text index when SQLite supports FTS5 and records
+// whether FTSSearch can use it
+func (r *Repository) ensureSearchIndex(ctx context.Context) error {
+	logger := zerolog.Ctx(ctx)
+
+	available, err := r.hasFTS5(ctx)
+	if err != nil {
+		return err
+	}
+	if !available {
+		logger.Warn().Msg("SQLite was built without FTS5, search will fall back to LIKE")
+		r.searchIndex = false
+		return r.dropStaleSearchIndex(ctx)
+	}
+
+	err = r.withContext(ctx).Transaction(func(tx *gorm.DB) error {
+		exists := tx.Migrator().HasTable("problems_fts")
+		for _, stmt := range searchIndexStatements {
+			if err := tx.Exec(stmt).Error; err != nil {
+				return fmt.Errorf("failed to create search index: %w", err)
+			}
+		}
+		// Index problems that were stored before the index existed
+		if !exists {
+			if err := tx.Exec("INSERT INTO problems_fts(problems_fts) VALUES ('rebuild')").Error; err != nil {
+				return fmt.Errorf("failed to build search index: %w", err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	r.searchIndex = true
+	return nil
+}
+
+// dropSearchIndex removes the full-text index so down migrations can reshape the problems table
+func (r *Repository) dropSearchIndex(ctx context.Context) error {
+	for _, stmt := range dropSearchIndexStatements {
+		if err := r.withContext(ctx).Exec(stmt).Error; err != nil {
+			return fmt.Errorf("failed to drop search index: %w", err)
+		}
+	}
+	r.searchIndex = false
+	return nil
+}
+
+// dropStaleSearchIndex removes an index left by a build with FTS5. Its triggers would make every
+// write to problems fail with "no such module: fts5". The table itself can't be dropped without
+// the module, but nothing reads it once the triggers are gone.
+func (r *Repository) dropStaleSearchIndex(ctx context.Context) error {
+	for _, stmt := range dropSearchTriggerStatements {
+		if err := r.withContext(ctx).Exec(stmt).Error; err != nil {
+			return fmt.Errorf("failed to drop search index triggers: %w", err)
+		}
+	}
+	if err := r.withContext(ctx).Exec(dropSearchTableStatement).Error; err != nil {
+		zerolog.Ctx(ctx).Debug().Err(err).Msg("Leaving the unused search index table in place")
+	}
+	return nil
+}
+
+// hasFTS5 reports whether the linked SQLite library was compiled with FTS5
+func (r *Repository) hasFTS5(ctx context.Context) (bool, error) {
+	if r.config.Driver != "sqlite3" {
+		return false, nil
+	}
+
+	var options []string
+	if err := r.withContext(ctx).Raw("PRAGMA compile_options").Scan(&options).Error; err != nil {
+		return false, fmt.Errorf("failed to read sqlite compile options: %w", err)
+	}
+	for _, option := range options {
+		if option == "ENABLE_FTS5" {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// ftsQuery turns free text into an FTS5 query that prefix-matches every word,
+// quoting each term so punctuation in user input is never parsed as query syntax
+func ftsQuery(text string) string {
+	words := strings.Fields(text)
+	terms := make([]string, len(words))
+	for i, word := range words {
+		terms[i] = `"` + strings.ReplaceAll(word, `"`, `""`) + `"*`
+	}
+	return strings.Join(terms, " ")
+}
+
+// escapeLike escapes LIKE wildcards so they match literally
+func escapeLike(text string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(text)
+}
+
+// toEntries converts problem models to DTOs
+func toEntries(problems []Problem) []*ProblemEntry {
+	result := make([]*ProblemEntry, len(problems))
+	for i := range problems {
+		result[i] = FromProblem(&problems[i])
+	}
+	return result
+}
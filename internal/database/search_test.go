@@ -0,0 +1,72 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"testing"
+)
+
+func TestEnsureSearchIndexDropsStaleTriggers(t *testing.T) {
+	ctx := context.Background()
+	repo := newTestRepository(t)
+	if available, err := repo.hasFTS5(ctx); err != nil || available {
+		t.Skip("only applies to SQLite built without FTS5")
+	}
+
+	// A trigger left behind by a build with FTS5 breaks every insert
+	stale := `CREATE TRIGGER problems_fts_ai AFTER INSERT ON problems BEGIN
+		INSERT INTO problems_fts(rowid, problem_name, notes) VALUES (new.id, new.problem_name, new.notes);
+	END`
+	if err := repo.db.Exec(stale).Error; err != nil {
+		t.Fatalf("failed to create stale trigger: %v", err)
+	}
+
+	if err := repo.ensureSearchIndex(ctx); err != nil {
+		t.Fatalf("ensureSearchIndex: %v", err)
+	}
+	seedTestData(t, repo, "search-user")
+
+	found, err := repo.FTSSearch(ctx, "search-user", "complements", 10)
+	if err != nil {
+		t.Fatalf("FTSSearch: %v", err)
+	}
+	if len(found) != 1 || found[0].ProblemName != "Two Sum" {
+		t.Errorf("FTSSearch found %d problems, want Two Sum", len(found))
+	}
+}
+
+func TestSearchProblemsByPartialName(t *testing.T) {
+	ctx := context.Background()
+	repo := newTestRepository(t)
+	seeded := seedTestData(t, repo, "search-user")
+	seedTestData(t, repo, "other-user")
+
+	tests := []struct {
+		name    string
+		partial string
+		limit   int
+		want    []string
+	}{
+		{name: "empty matches the most recent", partial: "", limit: 3, want: []string{"Word Ladder", "Trapping Rain Water", "Merge k Sorted Lists"}},
+		{name: "substring ignores case", partial: "IN", want: []string{"Trapping Rain Water", "Binary Tree Level Order Traversal", "Coin Change", "Longest Substring Without Repeating Characters", "Climbing Stairs"}},
+		{name: "ID prefix", partial: fmt.Sprint(seeded[4].ID), want: []string{"Coin Change"}},
+		{name: "wildcards match literally", partial: "%", want: []string{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			found, err := repo.SearchProblemsByPartialName(ctx, "search-user", tt.partial, tt.limit)
+			if err != nil {
+				t.Fatalf("SearchProblemsByPartialName: %v", err)
+			}
+			if got := problemNames(found); !slices.Equal(got, tt.want) {
+				t.Errorf("found %v, want %v", got, tt.want)
+			}
+			for _, p := range found {
+				if p.UserID != "search-user" {
+					t.Errorf("found %q from %s", p.ProblemName, p.UserID)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,91 @@
+package database
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/yugonline/grind_review_bot/config"
+)
+
+// seedBaseTime anchors fixture dates so seeded data is identical on every run
+var seedBaseTime = time.Date(2024, time.January, 1, 12, 0, 0, 0, time.UTC)
+
+// seedProblems returns the deterministic fixture set: ten problems covering every difficulty,
+// status and several categories and tags, one of them already reviewed
+func seedProblems(userID string) []*ProblemEntry {
+	reviewedAt := seedBaseTime.AddDate(0, 0, 7)
+
+	return []*ProblemEntry{
+		{UserID: userID, ProblemName: "Two Sum", Link: "https://leetcode.com/problems/two-sum/", Difficulty: DifficultyEasy, Category: "Array", Status: StatusSolved, SolvedAt: seedBaseTime, Tags: []string{"hash-table", "array"}, Notes: "Store complements in a map."},
+		{UserID: userID, ProblemName: "Valid Parentheses", Link: "https://leetcode.com/problems/valid-parentheses/", Difficulty: DifficultyEasy, Category: "Stack", Status: StatusSolved, SolvedAt: seedBaseTime.AddDate(0, 0, 1), Tags: []string{"stack", "string"}, LastReviewedAt: &reviewedAt, ReviewCount: 2},
+		{UserID: userID, ProblemName: "Climbing Stairs", Link: "https://leetcode.com/problems/climbing-stairs/", Difficulty: DifficultyEasy, Category: "Dynamic Programming", Status: StatusNeededHint, SolvedAt: seedBaseTime.AddDate(0, 0, 2), Tags: []string{"dp"}},
+		{UserID: userID, ProblemName: "Longest Substring Without Repeating Characters", Link: "https://leetcode.com/problems/longest-substring-without-repeating-characters/", Difficulty: DifficultyMedium, Category: "Sliding Window", Status: StatusSolved, SolvedAt: seedBaseTime.AddDate(0, 0, 3), Tags: []string{"sliding-window", "hash-table", "string"}},
+		{UserID: userID, ProblemName: "Coin Change", Link: "https://leetcode.com/problems/coin-change/", Difficulty: DifficultyMedium, Category: "Dynamic Programming", Status: StatusStuck, SolvedAt: seedBaseTime.AddDate(0, 0, 4), Tags: []string{"dp", "bfs"}, Notes: "Bottom-up table over amounts."},
+		{UserID: userID, ProblemName: "Number of Islands", Link: "https://leetcode.com/problems/number-of-islands/", Difficulty: DifficultyMedium, Category: "Graph", Status: StatusNeededHint, SolvedAt: seedBaseTime.AddDate(0, 0, 5), Tags: []string{"dfs", "bfs", "matrix"}},
+		{UserID: userID, ProblemName: "Binary Tree Level Order Traversal", Link: "https://leetcode.com/problems/binary-tree-level-order-traversal/", Difficulty: DifficultyMedium, Category: "Tree", Status: StatusSolved, SolvedAt: seedBaseTime.AddDate(0, 0, 6), Tags: []string{"bfs", "trees"}},
+		{UserID: userID, ProblemName: "Merge k Sorted Lists", Link: "https://leetcode.com/problems/merge-k-sorted-lists/", Difficulty: DifficultyHard, Category: "Heap", Status: StatusSolved, SolvedAt: seedBaseTime.AddDate(0, 0, 7), Tags: []string{"heap", "linked-list"}},
+		{UserID: userID, ProblemName: "Trapping Rain Water", Link: "https://leetcode.com/problems/trapping-rain-water/", Difficulty: DifficultyHard, Category: "Two Pointers", Status: StatusNeededHint, SolvedAt: seedBaseTime.AddDate(0, 0, 8), Tags: []string{"two-pointers", "stack"}},
+		{UserID: userID, ProblemName: "Word Ladder", Link: "https://leetcode.com/problems/word-ladder/", Difficulty: DifficultyHard, Category: "Graph", Status: StatusStuck, SolvedAt: seedBaseTime.AddDate(0, 0, 9), Tags: []string{"bfs", "hash-table"}},
+	}
+}
+
+// newTestRepository opens a migrated SQLite database in a temporary directory that is removed
+// when the test ends
+func newTestRepository(t testing.TB) *Repository {
+	t.Helper()
+	ctx := context.Background()
+
+	repo, err := New(ctx, config.DatabaseConfig{
+		Driver:            "sqlite3",
+		DSN:               filepath.Join(t.TempDir(), "test.db"),
+		MaxOpenConns:      1,
+		MaxIdleConns:      1,
+		MaxTagsPerProblem: 10,
+	})
+	if err != nil {
+		t.Fatalf("failed to open test database: %v", err)
+	}
+	if err := Migrate(ctx, repo); err != nil {
+		t.Fatalf("failed to migrate test database: %v", err)
+	}
+	t.Cleanup(func() {
+		if sqlDB, err := repo.db.DB(); err == nil {
+			sqlDB.Close()
+		}
+	})
+	return repo
+}
+
+// seedTestData inserts the fixture problems for userID and returns them with their IDs set
+func seedTestData(t testing.TB, repo *Repository, userID string) []*ProblemEntry {
+	t.Helper()
+
+	problems := seedProblems(userID)
+	for _, entry := range problems {
+		if err := repo.CreateProblem(context.Background(), entry); err != nil {
+			t.Fatalf("failed to seed problem %q: %v", entry.ProblemName, err)
+		}
+	}
+	return problems
+}
+
+func TestSeedTestData(t *testing.T) {
+	repo := newTestRepository(t)
+	seedTestData(t, repo, "seed-user")
+
+	stats, err := repo.GetUserStats(context.Background(), "seed-user")
+	if err != nil {
+		t.Fatalf("GetUserStats: %v", err)
+	}
+	if stats.TotalProblems != 10 {
+		t.Errorf("TotalProblems = %d, want 10", stats.TotalProblems)
+	}
+	if stats.Easy < 2 || stats.Medium < 2 || stats.Hard < 2 {
+		t.Errorf("difficulties = %d easy, %d medium, %d hard, want at least 2 of each", stats.Easy, stats.Medium, stats.Hard)
+	}
+	if stats.Solved == 0 || stats.NeededHint == 0 || stats.Stuck == 0 {
+		t.Errorf("statuses = %d solved, %d needed hint, %d stuck, want every status", stats.Solved, stats.NeededHint, stats.Stuck)
+	}
+}
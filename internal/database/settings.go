@@ -0,0 +1,138 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// GetUserSettings retrieves a user's settings, returning the defaults if none have been saved
+func (r *Repository) GetUserSettings(ctx context.Context, userID string) (*UserSettings, error) {
+	var settings UserSettings
+	err := r.withContext(ctx).Where("user_id = ?", userID).First(&settings).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return &UserSettings{UserID: userID}, nil
+		}
+		return nil, fmt.Errorf("failed to get user settings: %w", err)
+	}
+	return &settings, nil
+}
+
+// GetAllUserSettings loads every saved user's settings in one query, keyed by user ID.
+// Users without saved settings are absent from the map.
+func (r *Repository) GetAllUserSettings(ctx context.Context) (map[string]*UserSettings, error) {
+	var settings []*UserSettings
+	if err := r.withContext(ctx).Find(&settings).Error; err != nil {
+		return nil, fmt.Errorf("failed to get user settings: %w", err)
+	}
+
+	result := make(map[string]*UserSettings, len(settings))
+	for _, s := range settings {
+		result[s.UserID] = s
+	}
+	return result, nil
+}
+
+// GetAllUserPreferences loads every saved user's preferences in one query, keyed by user ID.
+// Users without saved preferences are absent from the map.
+func (r *Repository) GetAllUserPreferences(ctx context.Context) (map[string]*UserPreferences, error) {
+	var preferences []*UserPreferences
+	if err := r.withContext(ctx).Find(&preferences).Error; err != nil {
+		return nil, fmt.Errorf("failed to get user preferences: %w", err)
+	}
+
+	result := make(map[string]*UserPreferences, len(preferences))
+	for _, p := range preferences {
+		result[p.UserID] = p
+	}
+	return result, nil
+}
+
+// SetProfilePublic stores whether a user's profile may be shown to other members
+func (r *Repository) SetProfilePublic(ctx context.Context, userID string, public bool) error {
+	settings := &UserSettings{UserID: userID, ProfilePublic: public}
+	err := r.withContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"profile_public", "updated_at"}),
+	}).Create(settings).Error
+
+	if err != nil {
+		return fmt.Errorf("failed to update profile visibility: %w", err)
+	}
+
+	// Only public profiles are ranked, so opting in or out changes the leaderboard
+	r.invalidateUser(userID)
+	return nil
+}
+
+// SetResponseVisibility stores whether the user's command replies are public, private or left to each command
+func (r *Repository) SetResponseVisibility(ctx context.Context, userID, visibility string) error {
+	switch visibility {
+	case ResponseVisibilityDefault, ResponseVisibilityPublic, ResponseVisibilityPrivate:
+	default:
+		return fmt.Errorf("failed to update response visibility: unknown visibility %q", visibility)
+	}
+
+	settings := &UserSettings{UserID: userID, ResponseVisibility: visibility}
+	err := r.withContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"response_visibility", "updated_at"}),
+	}).Create(settings).Error
+
+	if err != nil {
+		return fmt.Errorf("failed to update response visibility: %w", err)
+	}
+	return nil
+}
+
+// SetReminderFilters stores the difficulty and category the user's review reminders are limited to;
+// an empty value removes that filter
+func (r *Repository) SetReminderFilters(ctx context.Context, userID, difficulty, category string) error {
+	switch difficulty {
+	case "", DifficultyEasy, DifficultyMedium, DifficultyHard:
+	default:
+		return fmt.Errorf("failed to update reminder filters: unknown difficulty %q", difficulty)
+	}
+
+	settings := &UserSettings{UserID: userID, ReminderDifficulty: difficulty, ReminderCategory: category}
+	err := r.withContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"reminder_difficulty", "reminder_category", "updated_at"}),
+	}).Create(settings).Error
+
+	if err != nil {
+		return fmt.Errorf("failed to update reminder filters: %w", err)
+	}
+	return nil
+}
+
+// ReminderScope limits a review query to the difficulty and category the user's reminders are filtered to
+func (s *UserSettings) ReminderScope() ProblemScope {
+	return func(db *gorm.DB) *gorm.DB {
+		if s.ReminderDifficulty != "" {
+			db = db.Where("problems.difficulty = ?", s.ReminderDifficulty)
+		}
+		if s.ReminderCategory != "" {
+			db = db.Where("problems.category = ?", s.ReminderCategory)
+		}
+		return db
+	}
+}
+
+// SetAPITokenHash stores the hash of a user's HTTP API token, replacing any previous token
+func (r *Repository) SetAPITokenHash(ctx context.Context, userID, hash string) error {
+	settings := &UserSettings{UserID: userID, APITokenHash: hash}
+	err := r.withContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"api_token_hash", "updated_at"}),
+	}).Create(settings).Error
+
+	if err != nil {
+		return fmt.Errorf("failed to update API token: %w", err)
+	}
+	return nil
+}
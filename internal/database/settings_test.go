@@ -0,0 +1,130 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"testing"
+	"time"
+)
+
+// insertPreferences stores reminder preferences for userIDs. Raw SQL, since GORM would swap a
+// false RemindersEnabled for the column default.
+func insertPreferences(t testing.TB, repo *Repository, remindersEnabled bool, userIDs ...string) {
+	t.Helper()
+	for _, userID := range userIDs {
+		if err := repo.GetDB().Exec("INSERT INTO user_preferences (user_id, reminders_enabled) VALUES (?, ?)", userID, remindersEnabled).Error; err != nil {
+			t.Fatalf("failed to insert preferences for %s: %v", userID, err)
+		}
+	}
+}
+
+func TestGetAllUserPreferences(t *testing.T) {
+	ctx := context.Background()
+	repo := newTestRepository(t)
+
+	preferences, err := repo.GetAllUserPreferences(ctx)
+	if err != nil {
+		t.Fatalf("GetAllUserPreferences: %v", err)
+	}
+	if len(preferences) != 0 {
+		t.Errorf("got %d preferences from an empty table, want 0", len(preferences))
+	}
+
+	insertPreferences(t, repo, true, "user-1")
+	insertPreferences(t, repo, false, "user-2")
+
+	preferences, err = repo.GetAllUserPreferences(ctx)
+	if err != nil {
+		t.Fatalf("GetAllUserPreferences: %v", err)
+	}
+	if len(preferences) != 2 {
+		t.Fatalf("got %d preferences, want 2", len(preferences))
+	}
+	if p := preferences["user-1"]; p == nil || !p.RemindersEnabled || p.Timezone != "UTC" {
+		t.Errorf("user-1 preferences = %+v, want reminders on in UTC", p)
+	}
+	if p := preferences["user-2"]; p == nil || p.RemindersEnabled {
+		t.Errorf("user-2 preferences = %+v, want reminders off", p)
+	}
+}
+
+// BenchmarkUserPreferences compares loading 100 users' preferences one at a time with loading
+// them all in one query, as the reminder loop does
+func BenchmarkUserPreferences(b *testing.B) {
+	ctx := context.Background()
+	repo := newTestRepository(b)
+
+	userIDs := make([]string, 100)
+	for idx := range userIDs {
+		userIDs[idx] = fmt.Sprintf("user-%d", idx)
+		insertPreferences(b, repo, idx%2 == 0, userIDs[idx])
+	}
+
+	b.Run("per-user", func(b *testing.B) {
+		for range b.N {
+			for _, userID := range userIDs {
+				var preferences UserPreferences
+				if err := repo.GetDB().WithContext(ctx).First(&preferences, "user_id = ?", userID).Error; err != nil {
+					b.Fatal(err)
+				}
+			}
+		}
+	})
+	b.Run("bulk", func(b *testing.B) {
+		for range b.N {
+			if _, err := repo.GetAllUserPreferences(ctx); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
+
+func TestReminderScope(t *testing.T) {
+	ctx := context.Background()
+	repo := newTestRepository(t)
+	seedTestData(t, repo, "filter-user")
+
+	tests := []struct {
+		name       string
+		difficulty string
+		category   string
+		want       []string
+	}{
+		{name: "no filter", want: problemNames(seedProblems("filter-user"))},
+		{name: "difficulty", difficulty: DifficultyHard, want: []string{"Merge k Sorted Lists", "Trapping Rain Water", "Word Ladder"}},
+		{name: "category", category: "Graph", want: []string{"Number of Islands", "Word Ladder"}},
+		{name: "both", difficulty: DifficultyMedium, category: "Dynamic Programming", want: []string{"Coin Change"}},
+		{name: "nothing matches", difficulty: DifficultyHard, category: "Stack", want: nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := repo.SetReminderFilters(ctx, "filter-user", tt.difficulty, tt.category); err != nil {
+				t.Fatalf("SetReminderFilters: %v", err)
+			}
+			settings, err := repo.GetUserSettings(ctx, "filter-user")
+			if err != nil {
+				t.Fatalf("GetUserSettings: %v", err)
+			}
+			if settings.ReminderDifficulty != tt.difficulty || settings.ReminderCategory != tt.category {
+				t.Errorf("stored filters %q and %q, want %q and %q", settings.ReminderDifficulty, settings.ReminderCategory, tt.difficulty, tt.category)
+			}
+
+			problems, err := repo.ListProblemsForReview(ctx, "filter-user", 7*24*time.Hour, settings.ReminderScope())
+			if err != nil {
+				t.Fatalf("ListProblemsForReview: %v", err)
+			}
+			// Compared as sets, since the list is ordered by review priority
+			got := problemNames(problems)
+			slices.Sort(got)
+			slices.Sort(tt.want)
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("due problems = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	if err := repo.SetReminderFilters(ctx, "filter-user", "Trivial", ""); err == nil {
+		t.Error("SetReminderFilters accepted an unknown difficulty")
+	}
+}
@@ -0,0 +1,287 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// UserStats summarises a user's logged problems by difficulty and status
+type UserStats struct {
+	UserID        string `json:"user_id"`
+	TotalProblems int    `json:"total_problems"`
+	Easy          int    `json:"easy"`
+	Medium        int    `json:"medium"`
+	Hard          int    `json:"hard"`
+	Solved        int    `json:"solved"`
+	NeededHint    int    `json:"needed_hint"`
+	Stuck         int    `json:"stuck"`
+}
+
+// statsRow is a single grouped count used to build UserStats
+type statsRow struct {
+	UserID     string
+	Difficulty string
+	Status     string
+	Count      int
+}
+
+// add folds a grouped count into the stats
+func (s *UserStats) add(row statsRow) {
+	s.TotalProblems += row.Count
+
+	switch row.Difficulty {
+	case DifficultyEasy:
+		s.Easy += row.Count
+	case DifficultyMedium:
+		s.Medium += row.Count
+	case DifficultyHard:
+		s.Hard += row.Count
+	}
+
+	switch row.Status {
+	case StatusSolved:
+		s.Solved += row.Count
+	case StatusNeededHint:
+		s.NeededHint += row.Count
+	case StatusStuck:
+		s.Stuck += row.Count
+	}
+}
+
+// GetUserStats computes aggregate counts for a single user
+func (r *Repository) GetUserStats(ctx context.Context, userID string) (*UserStats, error) {
+	var rows []statsRow
+	err := r.withContext(ctx).Model(&Problem{}).
+		Select("user_id, difficulty, status, COUNT(*) AS count").
+		Where("user_id = ?", userID).
+		Group("user_id, difficulty, status").
+		Scan(&rows).Error
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user stats: %w", err)
+	}
+
+	stats := &UserStats{UserID: userID}
+	for _, row := range rows {
+		stats.add(row)
+	}
+	return stats, nil
+}
+
+// GetAllUserStats computes aggregate counts for every user with at least one problem
+func (r *Repository) GetAllUserStats(ctx context.Context) ([]*UserStats, error) {
+	var rows []statsRow
+	err := r.withContext(ctx).Model(&Problem{}).
+		Select("user_id, difficulty, status, COUNT(*) AS count").
+		Group("user_id, difficulty, status").
+		Order("user_id").
+		Scan(&rows).Error
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to get all user stats: %w", err)
+	}
+
+	var result []*UserStats
+	byUser := make(map[string]*UserStats)
+	for _, row := range rows {
+		stats, ok := byUser[row.UserID]
+		if !ok {
+			stats = &UserStats{UserID: row.UserID}
+			byUser[row.UserID] = stats
+			result = append(result, stats)
+		}
+		stats.add(row)
+	}
+	return result, nil
+}
+
+// countRow is a single labelled count returned by a grouped query
+type countRow struct {
+	Label string
+	Count int
+}
+
+// GetCategoryDistribution returns how many problems the user has logged in each category
+func (r *Repository) GetCategoryDistribution(ctx context.Context, userID string) (map[string]int, error) {
+	var rows []countRow
+	err := r.withContext(ctx).Model(&Problem{}).
+		Select("category AS label, COUNT(*) AS count").
+		Where("user_id = ?", userID).
+		Group("category").
+		Scan(&rows).Error
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to get category distribution: %w", err)
+	}
+	return countsByLabel(rows), nil
+}
+
+// GetTagDistribution returns how many of the user's problems carry each tag
+func (r *Repository) GetTagDistribution(ctx context.Context, userID string) (map[string]int, error) {
+	var rows []countRow
+	err := r.withContext(ctx).Model(&Problem{}).
+		Select("tags.name AS label, COUNT(*) AS count").
+		Joins("JOIN problem_tags ON problem_tags.problem_id = problems.id").
+		Joins("JOIN tags ON tags.id = problem_tags.tag_id").
+		Where("problems.user_id = ?", userID).
+		Group("tags.name").
+		Scan(&rows).Error
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tag distribution: %w", err)
+	}
+	return countsByLabel(rows), nil
+}
+
+// GetSolveCountByDate returns the number of problems solved on each UTC date (YYYY-MM-DD)
+// over the last days days, including today. Dates without solves are omitted.
+func (r *Repository) GetSolveCountByDate(ctx context.Context, userID string, days int) (map[string]int, error) {
+	if days <= 0 {
+		return nil, fmt.Errorf("days must be positive, got %d", days)
+	}
+
+	start := utcDay(time.Now()).AddDate(0, 0, -(days - 1))
+	return r.solveCountsSince(ctx, userID, start)
+}
+
+// GetSolveHeatmap returns the number of problems solved on each date (YYYY-MM-DD) in loc over the
+// current week and the weeks-1 before it, with weeks starting on Sunday. Dates without solves are omitted.
+func (r *Repository) GetSolveHeatmap(ctx context.Context, userID string, weeks int, loc *time.Location) (map[string]int, error) {
+	if weeks <= 0 {
+		return nil, fmt.Errorf("weeks must be positive, got %d", weeks)
+	}
+
+	now := time.Now().In(loc)
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, loc)
+	start := today.AddDate(0, 0, -int(today.Weekday())-7*(weeks-1))
+	return r.solveCountsSinceIn(ctx, userID, start, loc)
+}
+
+// solveCountsSince counts the user's solves per UTC date from start onwards
+func (r *Repository) solveCountsSince(ctx context.Context, userID string, start time.Time) (map[string]int, error) {
+	return r.solveCountsSinceIn(ctx, userID, start, time.UTC)
+}
+
+// solveCountsSinceIn counts the user's solves per date in loc from start onwards
+func (r *Repository) solveCountsSinceIn(ctx context.Context, userID string, start time.Time, loc *time.Location) (map[string]int, error) {
+	// Bucket in Go so the result doesn't depend on how the driver serialises timestamps
+	var solvedAt []time.Time
+	err := r.withContext(ctx).Model(&Problem{}).
+		Where("user_id = ? AND solved_at >= ?", userID, start.UTC()).
+		Pluck("solved_at", &solvedAt).Error
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to get solve counts: %w", err)
+	}
+
+	counts := make(map[string]int)
+	for _, t := range solvedAt {
+		counts[t.In(loc).Format("2006-01-02")]++
+	}
+	return counts, nil
+}
+
+// GetReviewEffectiveness returns the fraction of reviewed problems that were first logged as
+// Stuck or Needed Hint and are now Solved. It returns 0 when no such problems have been reviewed.
+func (r *Repository) GetReviewEffectiveness(ctx context.Context, userID string) (float64, error) {
+	var result struct {
+		Reviewed int
+		Improved int
+	}
+	err := r.withContext(ctx).Model(&Problem{}).
+		Select("COUNT(*) AS reviewed, COALESCE(SUM(CASE WHEN status = ? THEN 1 ELSE 0 END), 0) AS improved", StatusSolved).
+		Where("user_id = ? AND review_count > 0", userID).
+		Where("initial_status IN ?", []string{StatusStuck, StatusNeededHint}).
+		Scan(&result).Error
+
+	if err != nil {
+		return 0, fmt.Errorf("failed to get review effectiveness: %w", err)
+	}
+	if result.Reviewed == 0 {
+		return 0, nil
+	}
+	return float64(result.Improved) / float64(result.Reviewed), nil
+}
+
+// countsByLabel converts grouped rows into a label to count map
+func countsByLabel(rows []countRow) map[string]int {
+	counts := make(map[string]int, len(rows))
+	for _, row := range rows {
+		counts[row.Label] = row.Count
+	}
+	return counts
+}
+
+// GetStreaks returns the user's current and longest runs of consecutive UTC days with at least
+// one solve. The current streak still counts if the latest solve was yesterday, since today
+// isn't over yet.
+func (r *Repository) GetStreaks(ctx context.Context, userID string) (current, longest int, err error) {
+	return r.GetStreakData(ctx, userID, 0)
+}
+
+// GetUserStreaks returns the user's streaks, allowing the configured number of grace days
+func (r *Repository) GetUserStreaks(ctx context.Context, userID string) (current, longest int, err error) {
+	return r.GetStreakData(ctx, userID, r.config.StreakGraceDays)
+}
+
+// GetStreakData returns the user's current and longest streaks like GetStreaks, except a run
+// survives up to allowedGap skipped days between solves. Streaks count the days with a solve.
+func (r *Repository) GetStreakData(ctx context.Context, userID string, allowedGap int) (current, longest int, err error) {
+	var solvedAt []time.Time
+	err = r.withContext(ctx).Model(&Problem{}).
+		Where("user_id = ?", userID).
+		Order("solved_at ASC").
+		Pluck("solved_at", &solvedAt).Error
+
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to get solve dates: %w", err)
+	}
+
+	current, longest = computeStreaks(activityDays(solvedAt), time.Now(), allowedGap)
+	return current, longest, nil
+}
+
+// activityDays reduces ascending timestamps to the distinct UTC days they fall on
+func activityDays(times []time.Time) []time.Time {
+	var days []time.Time
+	for _, t := range times {
+		day := t.UTC().Truncate(24 * time.Hour)
+		if len(days) == 0 || !day.Equal(days[len(days)-1]) {
+			days = append(days, day)
+		}
+	}
+	return days
+}
+
+// computeStreaks walks ascending, distinct activity days, extending the run while at most
+// allowedGap days are skipped between them. The run is current if it reaches yesterday or
+// today, allowing for the same gap.
+func computeStreaks(days []time.Time, now time.Time, allowedGap int) (current, longest int) {
+	run := 0
+	for idx, day := range days {
+		if idx > 0 && daysBetween(days[idx-1], day)-1 <= allowedGap {
+			run++
+		} else {
+			run = 1
+		}
+		longest = max(longest, run)
+	}
+
+	today := now.UTC().Truncate(24 * time.Hour)
+	if run > 0 && daysBetween(days[len(days)-1], today)-1 <= allowedGap {
+		current = run
+	}
+	return current, longest
+}
+
+// utcDay returns midnight UTC of the day t falls on
+func utcDay(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// daysBetween returns the number of whole days from a to b, both truncated to UTC days
+func daysBetween(a, b time.Time) int {
+	return int(b.Sub(a).Hours() / 24)
+}
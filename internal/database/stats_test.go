@@ -0,0 +1,204 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"testing"
+	"time"
+)
+
+func TestGetCategoryDistribution(t *testing.T) {
+	repo := newTestRepository(t)
+	seedTestData(t, repo, "stats-user")
+	seedTestData(t, repo, "other-user")
+
+	got, err := repo.GetCategoryDistribution(context.Background(), "stats-user")
+	if err != nil {
+		t.Fatalf("GetCategoryDistribution: %v", err)
+	}
+	want := map[string]int{"Array": 1, "Stack": 1, "Dynamic Programming": 2, "Sliding Window": 1, "Graph": 2, "Tree": 1, "Heap": 1, "Two Pointers": 1}
+	if !maps.Equal(got, want) {
+		t.Errorf("GetCategoryDistribution = %v, want %v", got, want)
+	}
+}
+
+func TestGetTagDistribution(t *testing.T) {
+	repo := newTestRepository(t)
+	seedTestData(t, repo, "stats-user")
+	seedTestData(t, repo, "other-user")
+
+	got, err := repo.GetTagDistribution(context.Background(), "stats-user")
+	if err != nil {
+		t.Fatalf("GetTagDistribution: %v", err)
+	}
+	want := map[string]int{
+		"bfs": 4, "hash-table": 3, "dp": 2, "stack": 2, "string": 2,
+		"array": 1, "sliding-window": 1, "dfs": 1, "matrix": 1, "trees": 1, "heap": 1, "linked-list": 1, "two-pointers": 1,
+	}
+	if !maps.Equal(got, want) {
+		t.Errorf("GetTagDistribution = %v, want %v", got, want)
+	}
+}
+
+func TestGetSolveCountByDate(t *testing.T) {
+	ctx := context.Background()
+	repo := newTestRepository(t)
+	seedTestData(t, repo, "stats-user") // solved in 2024, well outside the window
+
+	today := utcDay(time.Now())
+	for idx, solvedAt := range []time.Time{today.Add(time.Hour), today.Add(2 * time.Hour), today.AddDate(0, 0, -6), today.AddDate(0, 0, -7)} {
+		entry := &ProblemEntry{UserID: "stats-user", ProblemName: "Recent " + string(rune('A'+idx)), Difficulty: DifficultyEasy, Category: "Array", Status: StatusSolved, SolvedAt: solvedAt}
+		if err := repo.CreateProblem(ctx, entry); err != nil {
+			t.Fatalf("CreateProblem: %v", err)
+		}
+	}
+
+	got, err := repo.GetSolveCountByDate(ctx, "stats-user", 7)
+	if err != nil {
+		t.Fatalf("GetSolveCountByDate: %v", err)
+	}
+	want := map[string]int{today.Format("2006-01-02"): 2, today.AddDate(0, 0, -6).Format("2006-01-02"): 1}
+	if !maps.Equal(got, want) {
+		t.Errorf("GetSolveCountByDate = %v, want %v", got, want)
+	}
+
+	if _, err := repo.GetSolveCountByDate(ctx, "stats-user", 0); err == nil {
+		t.Error("GetSolveCountByDate accepted 0 days")
+	}
+}
+
+func TestGetReviewEffectiveness(t *testing.T) {
+	ctx := context.Background()
+	repo := newTestRepository(t)
+	problems := seedTestData(t, repo, "stats-user")
+
+	// Valid Parentheses has been reviewed but was solved from the start, so it doesn't count
+	if got, err := repo.GetReviewEffectiveness(ctx, "stats-user"); err != nil || got != 0 {
+		t.Errorf("GetReviewEffectiveness = %v, %v; want 0", got, err)
+	}
+
+	// Review Climbing Stairs (needed a hint) and Coin Change (stuck); only Coin Change improves
+	climbing, coin := problems[2], problems[4]
+	for _, p := range []*ProblemEntry{climbing, coin} {
+		if err := repo.IncrementReviewCount(ctx, p.ID); err != nil {
+			t.Fatalf("IncrementReviewCount: %v", err)
+		}
+	}
+	coin, err := repo.GetProblem(ctx, coin.ID)
+	if err != nil {
+		t.Fatalf("GetProblem: %v", err)
+	}
+	coin.Status = StatusSolved
+	if err := repo.UpdateProblem(ctx, coin); err != nil {
+		t.Fatalf("UpdateProblem: %v", err)
+	}
+
+	got, err := repo.GetReviewEffectiveness(ctx, "stats-user")
+	if err != nil {
+		t.Fatalf("GetReviewEffectiveness: %v", err)
+	}
+	if got != 0.5 {
+		t.Errorf("GetReviewEffectiveness = %v, want 0.5", got)
+	}
+}
+
+func TestComputeStreaks(t *testing.T) {
+	now := time.Date(2024, time.March, 10, 18, 0, 0, 0, time.UTC)
+	// days returns the activity days the given number of days before now
+	days := func(ago ...int) []time.Time {
+		result := make([]time.Time, len(ago))
+		for idx, n := range ago {
+			result[idx] = now.Truncate(24*time.Hour).AddDate(0, 0, -n)
+		}
+		return result
+	}
+
+	tests := []struct {
+		name        string
+		days        []time.Time
+		allowedGap  int
+		wantCurrent int
+		wantLongest int
+	}{
+		{name: "no activity", days: nil, wantCurrent: 0, wantLongest: 0},
+		{name: "consecutive through today", days: days(2, 1, 0), wantCurrent: 3, wantLongest: 3},
+		{name: "through yesterday is still current", days: days(2, 1), wantCurrent: 2, wantLongest: 2},
+		{name: "strict breaks on a one-day gap", days: days(4, 3, 1, 0), wantCurrent: 2, wantLongest: 2},
+		{name: "tolerant bridges a one-day gap", days: days(4, 3, 1, 0), allowedGap: 1, wantCurrent: 4, wantLongest: 4},
+		{name: "tolerant breaks on a longer gap", days: days(5, 4, 1, 0), allowedGap: 1, wantCurrent: 2, wantLongest: 2},
+		{name: "strict ends two days ago", days: days(3, 2), wantCurrent: 0, wantLongest: 2},
+		{name: "tolerant keeps it current", days: days(3, 2), allowedGap: 1, wantCurrent: 2, wantLongest: 2},
+		{name: "longest run in the past", days: days(20, 19, 18, 17, 2, 1), wantCurrent: 2, wantLongest: 4},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			current, longest := computeStreaks(tt.days, now, tt.allowedGap)
+			if current != tt.wantCurrent || longest != tt.wantLongest {
+				t.Errorf("computeStreaks = %d current, %d longest, want %d and %d", current, longest, tt.wantCurrent, tt.wantLongest)
+			}
+		})
+	}
+}
+
+func TestGetStreakData(t *testing.T) {
+	ctx := context.Background()
+	repo := newTestRepository(t)
+	today := time.Now().UTC()
+
+	// Solves today, yesterday (twice) and three days ago, missing the day before yesterday
+	for idx, ago := range []int{0, 1, 1, 3} {
+		entry := &ProblemEntry{UserID: "streak-user", ProblemName: fmt.Sprintf("Problem %d", idx), Difficulty: DifficultyEasy, Category: "Array", Status: StatusSolved, SolvedAt: today.AddDate(0, 0, -ago)}
+		if err := repo.CreateProblem(ctx, entry); err != nil {
+			t.Fatalf("CreateProblem: %v", err)
+		}
+	}
+
+	current, longest, err := repo.GetStreaks(ctx, "streak-user")
+	if err != nil {
+		t.Fatalf("GetStreaks: %v", err)
+	}
+	if current != 2 || longest != 2 {
+		t.Errorf("strict streaks = %d current, %d longest, want 2 and 2", current, longest)
+	}
+
+	current, longest, err = repo.GetStreakData(ctx, "streak-user", 1)
+	if err != nil {
+		t.Fatalf("GetStreakData: %v", err)
+	}
+	if current != 3 || longest != 3 {
+		t.Errorf("tolerant streaks = %d current, %d longest, want 3 and 3", current, longest)
+	}
+}
+
+func TestGetSolveHeatmap(t *testing.T) {
+	ctx := context.Background()
+	repo := newTestRepository(t)
+	eastern := time.FixedZone("UTC-5", -5*60*60)
+
+	// 02:00 UTC two days ago is still the evening before in UTC-5
+	twoDaysAgo := utcDay(time.Now()).AddDate(0, 0, -2).Add(2 * time.Hour)
+	for idx, solvedAt := range []time.Time{twoDaysAgo, twoDaysAgo.Add(time.Hour), time.Now().AddDate(-2, 0, 0)} {
+		entry := &ProblemEntry{UserID: "heatmap-user", ProblemName: fmt.Sprintf("Problem %d", idx), Difficulty: DifficultyEasy, Category: "Array", Status: StatusSolved, SolvedAt: solvedAt}
+		if err := repo.CreateProblem(ctx, entry); err != nil {
+			t.Fatalf("CreateProblem: %v", err)
+		}
+	}
+	seedTestData(t, repo, "other-user")
+
+	for _, loc := range []*time.Location{time.UTC, eastern} {
+		counts, err := repo.GetSolveHeatmap(ctx, "heatmap-user", 53, loc)
+		if err != nil {
+			t.Fatalf("GetSolveHeatmap: %v", err)
+		}
+		// The solve from two years ago is outside the 53 weeks
+		want := map[string]int{twoDaysAgo.In(loc).Format("2006-01-02"): 2}
+		if !maps.Equal(counts, want) {
+			t.Errorf("counts in %s = %v, want %v", loc, counts, want)
+		}
+	}
+
+	if _, err := repo.GetSolveHeatmap(ctx, "heatmap-user", 0, time.UTC); err == nil {
+		t.Error("GetSolveHeatmap accepted zero weeks")
+	}
+}
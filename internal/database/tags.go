@@ -0,0 +1,135 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// DeleteTag removes one of a user's tags from all of their problems, optionally re-tagging
+// those problems with reassignTo. It returns the number of problems that were updated.
+func (r *Repository) DeleteTag(ctx context.Context, userID, name, reassignTo string) (int, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	reassignTo = strings.ToLower(strings.TrimSpace(reassignTo))
+	if name == "" {
+		return 0, errors.New("tag name is required")
+	}
+	if name == reassignTo {
+		return 0, errors.New("cannot reassign a tag to itself")
+	}
+
+	var updated int
+	err := r.WithRetry(ctx, defaultTxRetries, func(tx *gorm.DB) error {
+		var tag Tag
+		if err := tx.Where("user_id = ? AND name = ?", userID, name).First(&tag).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return fmt.Errorf("tag not found: %s", name)
+			}
+			return fmt.Errorf("failed to find tag: %w", err)
+		}
+
+		var problemIDs []uint
+		if err := tx.Table("problem_tags").
+			Where("tag_id = ?", tag.ID).
+			Pluck("problem_id", &problemIDs).Error; err != nil {
+			return fmt.Errorf("failed to find tagged problems: %w", err)
+		}
+
+		if reassignTo != "" {
+			targets, err := findOrCreateTags(tx, userID, []Tag{{UserID: userID, Name: reassignTo}})
+			if err != nil {
+				return err
+			}
+			target := targets[0]
+
+			links := make([]map[string]interface{}, 0, len(problemIDs))
+			for _, problemID := range problemIDs {
+				links = append(links, map[string]interface{}{"problem_id": problemID, "tag_id": target.ID})
+			}
+			if err := tx.Table("problem_tags").Clauses(clause.OnConflict{DoNothing: true}).Create(links).Error; err != nil {
+				return fmt.Errorf("failed to reassign tag: %w", err)
+			}
+		}
+
+		// The tag belongs to this user alone, so it can be removed outright
+		if err := tx.Exec("DELETE FROM problem_tags WHERE tag_id = ?", tag.ID).Error; err != nil {
+			return fmt.Errorf("failed to remove tag: %w", err)
+		}
+		if err := tx.Unscoped().Delete(&tag).Error; err != nil {
+			return fmt.Errorf("failed to delete tag: %w", err)
+		}
+
+		updated = len(problemIDs)
+		return nil
+	})
+
+	return updated, err
+}
+
+// findOrCreateTags returns the user's rows for the given tags, creating any that don't exist yet
+func findOrCreateTags(tx *gorm.DB, userID string, tags []Tag) ([]Tag, error) {
+	result := make([]Tag, 0, len(tags))
+	for _, tag := range tags {
+		existing := Tag{UserID: userID, Name: tag.Name}
+		if err := tx.Where("user_id = ? AND name = ?", userID, tag.Name).FirstOrCreate(&existing).Error; err != nil {
+			return nil, fmt.Errorf("failed to find or create tag %q: %w", tag.Name, err)
+		}
+		result = append(result, existing)
+	}
+	return result, nil
+}
+
+// ListProblemsByTag retrieves a user's unarchived problems tagged with any of the given tags, or
+// with all of them when matchAll is set, most recently solved first. Each problem appears once.
+func (r *Repository) ListProblemsByTag(ctx context.Context, userID string, tagNames []string, matchAll bool) ([]*ProblemEntry, error) {
+	tagNames = normalizeTags(tagNames)
+	if len(tagNames) == 0 {
+		return nil, errors.New("at least one tag is required")
+	}
+
+	var problems []Problem
+	err := r.withContext(ctx).Model(&Problem{}).Preload("Tags").
+		Scopes(TaggedWith(userID, tagNames, matchAll)).
+		Where("problems.user_id = ? AND problems.archived = ?", userID, false).
+		Order("problems.solved_at DESC").
+		Find(&problems).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to list problems by tag: %w", err)
+	}
+	return toEntries(problems), nil
+}
+
+// TaggedWith limits a problem query to those carrying any of the user's given tags, or all of
+// them when matchAll is set. No tags leaves the query unchanged.
+func TaggedWith(userID string, tagNames []string, matchAll bool) ProblemScope {
+	tagNames = normalizeTags(tagNames)
+	return func(db *gorm.DB) *gorm.DB {
+		if len(tagNames) == 0 {
+			return db
+		}
+		subquery := taggedProblemIDs(db.Session(&gorm.Session{NewDB: true}), userID, tagNames, matchAll)
+		return db.Where("problems.id IN (?)", subquery)
+	}
+}
+
+// taggedProblemIDs builds a subquery selecting the IDs of problems carrying any of the normalized
+// tags, or all of them when matchAll is set. An empty userID matches every user's tags. Selecting
+// IDs rather than joining keeps a problem with several matching tags from being returned twice.
+func taggedProblemIDs(db *gorm.DB, userID string, tagNames []string, matchAll bool) *gorm.DB {
+	query := db.Table("problem_tags").
+		Select("problem_tags.problem_id").
+		Joins("JOIN tags ON tags.id = problem_tags.tag_id").
+		Where("tags.name IN ?", tagNames)
+	if userID != "" {
+		query = query.Where("tags.user_id = ?", userID)
+	}
+	if matchAll {
+		query = query.Group("problem_tags.problem_id").
+			Having("COUNT(DISTINCT tags.name) = ?", len(tagNames))
+	}
+	return query
+}
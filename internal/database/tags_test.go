@@ -0,0 +1,255 @@
+package database
+
+import (
+	"context"
+	"slices"
+	"testing"
+)
+
+func TestTaggedWith(t *testing.T) {
+	repo := newTestRepository(t)
+	seedTestData(t, repo, "tag-user")
+	seedTestData(t, repo, "other-user")
+
+	tests := []struct {
+		name     string
+		tags     []string
+		matchAll bool
+		want     []string
+	}{
+		{name: "any", tags: []string{"dp"}, want: []string{"Coin Change", "Climbing Stairs"}},
+		// Word Ladder carries both tags but is listed once
+		{name: "any of several", tags: []string{"bfs", "hash-table"}, want: []string{"Word Ladder", "Binary Tree Level Order Traversal", "Number of Islands", "Coin Change", "Longest Substring Without Repeating Characters", "Two Sum"}},
+		{name: "all of several", tags: []string{"bfs", "hash-table"}, matchAll: true, want: []string{"Word Ladder"}},
+		{name: "case and spacing", tags: []string{" BFS ", "Hash-Table"}, matchAll: true, want: []string{"Word Ladder"}},
+		{name: "all with a repeated tag", tags: []string{"dp", "dp"}, matchAll: true, want: []string{"Coin Change", "Climbing Stairs"}},
+		{name: "unknown tag", tags: []string{"segment-tree"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			problems, err := repo.ListProblems(context.Background(), "tag-user", "", "", "", nil, true, 0, 0, TaggedWith("tag-user", tt.tags, tt.matchAll))
+			if err != nil {
+				t.Fatalf("ListProblems: %v", err)
+			}
+			if len(problems) != len(tt.want) {
+				t.Fatalf("got %d problems, want %v", len(problems), tt.want)
+			}
+			for idx, p := range problems {
+				if p.UserID != "tag-user" || p.ProblemName != tt.want[idx] {
+					t.Errorf("problem %d = %q of %s, want %q", idx, p.ProblemName, p.UserID, tt.want[idx])
+				}
+			}
+		})
+	}
+}
+
+func TestListProblemsByTag(t *testing.T) {
+	ctx := context.Background()
+	repo := newTestRepository(t)
+	seedTestData(t, repo, "tag-user")
+	seedTestData(t, repo, "other-user")
+
+	tests := []struct {
+		name     string
+		tags     []string
+		matchAll bool
+		want     []string
+	}{
+		// Word Ladder carries both tags but is listed once
+		{name: "match any", tags: []string{"bfs", "hash-table"}, want: []string{"Word Ladder", "Binary Tree Level Order Traversal", "Number of Islands", "Coin Change", "Longest Substring Without Repeating Characters", "Two Sum"}},
+		{name: "match all", tags: []string{"bfs", "hash-table"}, matchAll: true, want: []string{"Word Ladder"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			problems, err := repo.ListProblemsByTag(ctx, "tag-user", tt.tags, tt.matchAll)
+			if err != nil {
+				t.Fatalf("ListProblemsByTag: %v", err)
+			}
+			if got := problemNames(problems); !slices.Equal(got, tt.want) {
+				t.Errorf("ListProblemsByTag = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	if _, err := repo.ListProblemsByTag(ctx, "tag-user", []string{" "}, false); err == nil {
+		t.Error("ListProblemsByTag accepted no tags")
+	}
+}
+
+func TestNormalizeTags(t *testing.T) {
+	tests := []struct {
+		name string
+		tags []string
+		want []string
+	}{
+		{name: "casing", tags: []string{"DP", "Graph"}, want: []string{"dp", "graph"}},
+		{name: "duplicates collapse", tags: []string{"DP", "dp", " dp "}, want: []string{"dp"}},
+		{name: "first occurrence keeps its place", tags: []string{"bfs", "DP", "BFS"}, want: []string{"bfs", "dp"}},
+		{name: "blank tags dropped", tags: []string{"", "  ", "heap"}, want: []string{"heap"}},
+		{name: "none", tags: nil, want: []string{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := normalizeTags(tt.tags); !slices.Equal(got, tt.want) {
+				t.Errorf("normalizeTags(%q) = %q, want %q", tt.tags, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestProblemTagsNormalized(t *testing.T) {
+	ctx := context.Background()
+	repo := newTestRepository(t)
+
+	entry := &ProblemEntry{UserID: "tag-user", ProblemName: "Coin Change", Difficulty: DifficultyMedium, Category: "DP", Status: StatusSolved, SolvedAt: seedBaseTime, Tags: []string{"DP", " dp ", "Greedy"}}
+	if err := repo.CreateProblem(ctx, entry); err != nil {
+		t.Fatalf("CreateProblem: %v", err)
+	}
+	got, err := repo.GetProblem(ctx, entry.ID)
+	if err != nil {
+		t.Fatalf("GetProblem: %v", err)
+	}
+	if tags := sortedTags(got.Tags); !slices.Equal(tags, []string{"dp", "greedy"}) {
+		t.Errorf("tags after create = %q, want [dp greedy]", tags)
+	}
+
+	got.Tags = []string{"BFS", "bfs", "Dp"}
+	if err := repo.UpdateProblem(ctx, got); err != nil {
+		t.Fatalf("UpdateProblem: %v", err)
+	}
+	if got, err = repo.GetProblem(ctx, entry.ID); err != nil {
+		t.Fatalf("GetProblem: %v", err)
+	}
+	if tags := sortedTags(got.Tags); !slices.Equal(tags, []string{"bfs", "dp"}) {
+		t.Errorf("tags after update = %q, want [bfs dp]", tags)
+	}
+
+	// Every spelling maps to the same tag row
+	var count int64
+	if err := repo.db.Model(&Tag{}).Where("user_id = ? AND LOWER(name) = ?", "tag-user", "dp").Count(&count).Error; err != nil {
+		t.Fatalf("count tags: %v", err)
+	}
+	if count != 1 {
+		t.Errorf("%d rows for the dp tag, want 1", count)
+	}
+}
+
+func TestDeleteTag(t *testing.T) {
+	ctx := context.Background()
+	repo := newTestRepository(t)
+	seedTestData(t, repo, "tag-user")
+	seedTestData(t, repo, "other-user")
+
+	updated, err := repo.DeleteTag(ctx, "tag-user", "DP", "")
+	if err != nil {
+		t.Fatalf("DeleteTag: %v", err)
+	}
+	if updated != 2 {
+		t.Errorf("DeleteTag updated %d problems, want 2", updated)
+	}
+
+	// The tag row is gone for this user only
+	var count int64
+	repo.db.Model(&Tag{}).Unscoped().Where("user_id = ? AND name = ?", "tag-user", "dp").Count(&count)
+	if count != 0 {
+		t.Error("the deleted tag's row survived")
+	}
+	tagged, err := repo.ListProblems(ctx, "other-user", "", "", "", nil, true, 0, 0, TaggedWith("other-user", []string{"dp"}, false))
+	if err != nil {
+		t.Fatalf("ListProblems: %v", err)
+	}
+	if len(tagged) != 2 {
+		t.Errorf("other-user has %d problems tagged dp, want 2", len(tagged))
+	}
+
+	if _, err := repo.DeleteTag(ctx, "tag-user", "dp", ""); err == nil {
+		t.Error("deleting a missing tag succeeded")
+	}
+	if _, err := repo.DeleteTag(ctx, "tag-user", "bfs", " BFS "); err == nil {
+		t.Error("reassigning a tag to itself succeeded")
+	}
+}
+
+func TestDeleteTagReassign(t *testing.T) {
+	ctx := context.Background()
+	repo := newTestRepository(t)
+	seedTestData(t, repo, "tag-user")
+
+	// Coin Change already carries bfs, so it keeps a single link after the move
+	updated, err := repo.DeleteTag(ctx, "tag-user", "dp", "bfs")
+	if err != nil {
+		t.Fatalf("DeleteTag: %v", err)
+	}
+	if updated != 2 {
+		t.Errorf("DeleteTag updated %d problems, want 2", updated)
+	}
+
+	problems, err := repo.ListProblems(ctx, "tag-user", "", "", "", nil, true, 0, 0, TaggedWith("tag-user", []string{"bfs"}, false))
+	if err != nil {
+		t.Fatalf("ListProblems: %v", err)
+	}
+	for _, want := range []string{"Climbing Stairs", "Coin Change"} {
+		if !slices.Contains(problemNames(problems), want) {
+			t.Errorf("%s isn't tagged bfs after the reassignment", want)
+		}
+	}
+	for _, p := range problems {
+		if slices.Contains(p.Tags, "dp") {
+			t.Errorf("%s is still tagged dp", p.ProblemName)
+		}
+	}
+
+	// Reassigning to a new tag creates it
+	if _, err := repo.DeleteTag(ctx, "tag-user", "heap", "priority-queue"); err != nil {
+		t.Fatalf("DeleteTag: %v", err)
+	}
+	problems, err = repo.ListProblems(ctx, "tag-user", "", "", "", nil, true, 0, 0, TaggedWith("tag-user", []string{"priority-queue"}, false))
+	if err != nil {
+		t.Fatalf("ListProblems: %v", err)
+	}
+	if names := problemNames(problems); !slices.Equal(names, []string{"Merge k Sorted Lists"}) {
+		t.Errorf("problems tagged priority-queue = %v, want [Merge k Sorted Lists]", names)
+	}
+}
+
+func TestTagsScopedToUser(t *testing.T) {
+	ctx := context.Background()
+	repo := newTestRepository(t)
+	mine := seedTestData(t, repo, "tag-user")
+	seedTestData(t, repo, "other-user")
+
+	// The same name is a separate tag for each user
+	var owners []string
+	if err := repo.db.Model(&Tag{}).Where("name = ?", "dp").Order("user_id").Pluck("user_id", &owners).Error; err != nil {
+		t.Fatalf("failed to load tags: %v", err)
+	}
+	if !slices.Equal(owners, []string{"other-user", "tag-user"}) {
+		t.Errorf("dp tag owned by %v, want a row per user", owners)
+	}
+
+	// Dropping a tag from all of one user's problems leaves the other user's untouched
+	for _, p := range mine {
+		if !slices.Contains(p.Tags, "dp") {
+			continue
+		}
+		p.Tags = slices.DeleteFunc(slices.Clone(p.Tags), func(tag string) bool { return tag == "dp" })
+		if err := repo.UpdateProblem(ctx, p); err != nil {
+			t.Fatalf("UpdateProblem: %v", err)
+		}
+	}
+
+	theirs, err := repo.GetTagDistribution(ctx, "other-user")
+	if err != nil {
+		t.Fatalf("GetTagDistribution: %v", err)
+	}
+	if theirs["dp"] != 2 {
+		t.Errorf("other-user's dp count = %d, want 2", theirs["dp"])
+	}
+	ours, err := repo.GetTagDistribution(ctx, "tag-user")
+	if err != nil {
+		t.Fatalf("GetTagDistribution: %v", err)
+	}
+	if _, ok := ours["dp"]; ok {
+		t.Error("tag-user still has the dp tag")
+	}
+}
@@ -0,0 +1,47 @@
+package database
+
+import (
+	"context"
+	"math/rand"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog"
+	"gorm.io/gorm"
+)
+
+// defaultTxRetries is the number of retries used by write paths when SQLite reports contention
+const defaultTxRetries = 3
+
+// txRetryBaseDelay is the initial backoff between transaction attempts
+const txRetryBaseDelay = 50 * time.Millisecond
+
+// WithRetry runs fn inside a transaction, retrying up to maxRetries times with jittered
+// backoff when SQLite reports that the database is locked or busy
+func (r *Repository) WithRetry(ctx context.Context, maxRetries int, fn func(*gorm.DB) error) error {
+	var err error
+	for attempt := 0; ; attempt++ {
+		err = r.withContext(ctx).Transaction(fn)
+		if err == nil || !isBusyError(err) || attempt >= maxRetries {
+			return err
+		}
+
+		// Exponential backoff with up to 100% jitter
+		delay := txRetryBaseDelay << attempt
+		delay += time.Duration(rand.Int63n(int64(delay)))
+
+		zerolog.Ctx(ctx).Warn().Err(err).Int("attempt", attempt+1).Dur("delay", delay).Msg("Database busy, retrying transaction")
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(delay):
+		}
+	}
+}
+
+// isBusyError reports whether err indicates SQLite write contention
+func isBusyError(err error) bool {
+	msg := err.Error()
+	return strings.Contains(msg, "database is locked") || strings.Contains(msg, "SQLITE_BUSY")
+}
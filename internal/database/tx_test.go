@@ -0,0 +1,128 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/yugonline/grind_review_bot/config"
+	"gorm.io/gorm"
+)
+
+// newContendedRepository opens a migrated database with two connections that fail at once,
+// rather than wait, when the other holds the write lock
+func newContendedRepository(t *testing.T) *Repository {
+	t.Helper()
+	ctx := context.Background()
+
+	repo, err := New(ctx, config.DatabaseConfig{
+		Driver:            "sqlite3",
+		DSN:               filepath.Join(t.TempDir(), "tx.db") + "?_busy_timeout=0",
+		MaxOpenConns:      2,
+		MaxIdleConns:      2,
+		MaxTagsPerProblem: 10,
+	})
+	if err != nil {
+		t.Fatalf("failed to open test database: %v", err)
+	}
+	if err := Migrate(ctx, repo); err != nil {
+		t.Fatalf("failed to migrate test database: %v", err)
+	}
+	t.Cleanup(func() {
+		if sqlDB, err := repo.db.DB(); err == nil {
+			sqlDB.Close()
+		}
+	})
+	return repo
+}
+
+func TestWithRetryConcurrentInserts(t *testing.T) {
+	ctx := context.Background()
+	repo := newContendedRepository(t)
+
+	locked := make(chan struct{})
+	var wg sync.WaitGroup
+	errs := make([]error, 2)
+
+	// The first writer holds the write lock for a while after inserting
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		errs[0] = repo.WithRetry(ctx, defaultTxRetries, func(tx *gorm.DB) error {
+			if err := insertProblem(tx, &ProblemEntry{UserID: "tx-user", ProblemName: "First", Difficulty: DifficultyEasy, Category: "Array", Status: StatusSolved, SolvedAt: seedBaseTime}); err != nil {
+				return err
+			}
+			close(locked)
+			time.Sleep(80 * time.Millisecond)
+			return nil
+		})
+	}()
+
+	// The second finds the database locked and succeeds on a retry
+	<-locked
+	attempts := 0
+	errs[1] = repo.WithRetry(ctx, defaultTxRetries, func(tx *gorm.DB) error {
+		attempts++
+		return insertProblem(tx, &ProblemEntry{UserID: "tx-user", ProblemName: "Second", Difficulty: DifficultyEasy, Category: "Array", Status: StatusSolved, SolvedAt: seedBaseTime})
+	})
+	wg.Wait()
+
+	for idx, err := range errs {
+		if err != nil {
+			t.Errorf("writer %d: %v", idx+1, err)
+		}
+	}
+	if attempts < 2 || attempts > defaultTxRetries+1 {
+		t.Errorf("second writer took %d attempts, want a retry within %d", attempts, defaultTxRetries)
+	}
+
+	problems, err := repo.ListProblems(ctx, "tx-user", "", "", "", nil, true, 0, 0)
+	if err != nil {
+		t.Fatalf("ListProblems: %v", err)
+	}
+	if len(problems) != 2 {
+		t.Errorf("stored %d problems, want 2", len(problems))
+	}
+}
+
+func TestWithRetryOnlyRetriesBusyErrors(t *testing.T) {
+	repo := newTestRepository(t)
+	errInvalid := errors.New("constraint failed")
+
+	attempts := 0
+	err := repo.WithRetry(context.Background(), defaultTxRetries, func(*gorm.DB) error {
+		attempts++
+		return errInvalid
+	})
+	if !errors.Is(err, errInvalid) || attempts != 1 {
+		t.Errorf("WithRetry = %v after %d attempts, want the error after 1", err, attempts)
+	}
+
+	attempts = 0
+	err = repo.WithRetry(context.Background(), 2, func(*gorm.DB) error {
+		attempts++
+		return fmt.Errorf("database is locked")
+	})
+	if err == nil || attempts != 3 {
+		t.Errorf("WithRetry = %v after %d attempts, want a busy error after 3", err, attempts)
+	}
+}
+
+func TestWithRetryStopsOnCancel(t *testing.T) {
+	repo := newTestRepository(t)
+	ctx, cancel := context.WithCancel(context.Background())
+
+	attempts := 0
+	err := repo.WithRetry(ctx, 10, func(*gorm.DB) error {
+		attempts++
+		cancel()
+		return errors.New("SQLITE_BUSY")
+	})
+	if !errors.Is(err, context.Canceled) || attempts != 1 {
+		t.Errorf("WithRetry = %v after %d attempts, want context.Canceled after 1", err, attempts)
+	}
+}
@@ -0,0 +1,114 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// UserUsage summarises one user's command usage across all commands
+type UserUsage struct {
+	UserID      string
+	Commands    int64 // Successful and failed invocations together
+	Errors      int64
+	FirstUsedAt time.Time
+	LastUsedAt  time.Time
+}
+
+// RecordCommandUsage counts one invocation of command by the user, as a success or an error
+func (r *Repository) RecordCommandUsage(ctx context.Context, userID, command string, success bool) error {
+	now := time.Now().UTC()
+	usage := &CommandUsage{UserID: userID, Command: command, FirstUsedAt: now, LastUsedAt: now}
+	counter := "error_count"
+	if success {
+		usage.SuccessCount = 1
+		counter = "success_count"
+	} else {
+		usage.ErrorCount = 1
+	}
+
+	err := r.withContext(ctx).Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "user_id"}, {Name: "command"}},
+		DoUpdates: clause.Assignments(map[string]interface{}{
+			counter:        gorm.Expr(counter + " + 1"),
+			"last_used_at": now,
+		}),
+	}).Create(usage).Error
+
+	if err != nil {
+		return fmt.Errorf("failed to record command usage: %w", err)
+	}
+	return nil
+}
+
+// GetTopCommandUsers returns the users who have run the most commands, busiest first
+func (r *Repository) GetTopCommandUsers(ctx context.Context, limit int) ([]UserUsage, error) {
+	var usages []CommandUsage
+	if err := r.withContext(ctx).Find(&usages).Error; err != nil {
+		return nil, fmt.Errorf("failed to get top command users: %w", err)
+	}
+
+	users := usageByUser(usages)
+	sort.Slice(users, func(i, j int) bool {
+		if users[i].Commands != users[j].Commands {
+			return users[i].Commands > users[j].Commands
+		}
+		return users[i].UserID < users[j].UserID
+	})
+	if limit > 0 && len(users) > limit {
+		users = users[:limit]
+	}
+	return users, nil
+}
+
+// GetCommandsPerUserPerDay returns, for each user who ran a command since the given time, the
+// average number of commands they have run per day since their first one
+func (r *Repository) GetCommandsPerUserPerDay(ctx context.Context, since time.Time) ([]float64, error) {
+	var usages []CommandUsage
+	err := r.withContext(ctx).
+		Where("user_id IN (?)", r.withContext(ctx).Model(&CommandUsage{}).Select("user_id").Where("last_used_at >= ?", since.UTC())).
+		Find(&usages).Error
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to get command usage: %w", err)
+	}
+
+	now := time.Now()
+	users := usageByUser(usages)
+	result := make([]float64, len(users))
+	for i, user := range users {
+		days := int(now.Sub(user.FirstUsedAt).Hours()/24) + 1
+		result[i] = float64(user.Commands) / float64(max(days, 1))
+	}
+	return result, nil
+}
+
+// usageByUser totals per-command usage rows into one summary per user. Aggregating in Go keeps
+// the timestamps typed, which SQL MAX() and MIN() don't on SQLite.
+func usageByUser(usages []CommandUsage) []UserUsage {
+	var users []UserUsage
+	index := make(map[string]int)
+	for _, usage := range usages {
+		i, ok := index[usage.UserID]
+		if !ok {
+			i = len(users)
+			index[usage.UserID] = i
+			users = append(users, UserUsage{UserID: usage.UserID, FirstUsedAt: usage.FirstUsedAt, LastUsedAt: usage.LastUsedAt})
+		}
+
+		user := &users[i]
+		user.Commands += usage.SuccessCount + usage.ErrorCount
+		user.Errors += usage.ErrorCount
+		if usage.FirstUsedAt.Before(user.FirstUsedAt) {
+			user.FirstUsedAt = usage.FirstUsedAt
+		}
+		if usage.LastUsedAt.After(user.LastUsedAt) {
+			user.LastUsedAt = usage.LastUsedAt
+		}
+	}
+	return users
+}
@@ -0,0 +1,64 @@
+package database
+
+import "fmt"
+
+// Problem fields a ValidationError can refer to, named after the command options that set them
+const (
+	FieldUserID              = "user_id"
+	FieldProblemName         = "problem_name"
+	FieldDifficulty          = "difficulty"
+	FieldPerceivedDifficulty = "perceived_difficulty"
+	FieldStatus              = "status"
+	FieldCategory            = "category"
+	FieldTags                = "tags"
+)
+
+// Reasons a field can fail validation
+const (
+	ReasonRequired = "required" // The field is empty
+	ReasonInvalid  = "invalid"  // The value isn't one of the allowed ones
+	ReasonTooMany  = "too_many" // The field holds more items than allowed
+)
+
+// ValidationError is returned by ValidateProblemEntry and names the field that failed and why.
+// Value is the rejected value, if any; for ReasonTooMany, Count and Limit say how far over it is.
+type ValidationError struct {
+	Field  string
+	Reason string
+	Value  string
+	Count  int
+	Limit  int
+}
+
+// Error implements error. The messages are kept stable for logs.
+func (e *ValidationError) Error() string {
+	switch {
+	case e.Reason == ReasonRequired && e.Field == FieldUserID:
+		return "user ID is required"
+	case e.Reason == ReasonRequired:
+		return fieldLabel(e.Field) + " is required"
+	case e.Reason == ReasonTooMany:
+		return fmt.Sprintf("%v: %d given, the limit is %d", ErrTooManyTags, e.Count, e.Limit)
+	default:
+		return fmt.Sprintf("invalid %s: %s", fieldLabel(e.Field), e.Value)
+	}
+}
+
+// Unwrap lets errors.Is keep matching ErrTooManyTags
+func (e *ValidationError) Unwrap() error {
+	if e.Field == FieldTags && e.Reason == ReasonTooMany {
+		return ErrTooManyTags
+	}
+	return nil
+}
+
+// fieldLabel turns a field name into the words used in error messages
+func fieldLabel(field string) string {
+	label := []byte(field)
+	for i, c := range label {
+		if c == '_' {
+			label[i] = ' '
+		}
+	}
+	return string(label)
+}
@@ -0,0 +1,106 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestValidateProblemEntry(t *testing.T) {
+	valid := func() *ProblemEntry {
+		return &ProblemEntry{UserID: "user-1", ProblemName: "Two Sum", Difficulty: DifficultyEasy, Status: StatusSolved, Category: "Array", Tags: []string{"array"}}
+	}
+
+	tests := []struct {
+		name    string
+		modify  func(p *ProblemEntry)
+		want    ValidationError
+		message string
+	}{
+		{name: "missing user", modify: func(p *ProblemEntry) { p.UserID = "" }, want: ValidationError{Field: FieldUserID, Reason: ReasonRequired}, message: "user ID is required"},
+		{name: "missing name", modify: func(p *ProblemEntry) { p.ProblemName = "" }, want: ValidationError{Field: FieldProblemName, Reason: ReasonRequired}, message: "problem name is required"},
+		{name: "bad difficulty", modify: func(p *ProblemEntry) { p.Difficulty = "Extreme" }, want: ValidationError{Field: FieldDifficulty, Reason: ReasonInvalid, Value: "Extreme"}, message: "invalid difficulty: Extreme"},
+		{name: "bad perceived difficulty", modify: func(p *ProblemEntry) { p.PerceivedDifficulty = "Trivial" }, want: ValidationError{Field: FieldPerceivedDifficulty, Reason: ReasonInvalid, Value: "Trivial"}, message: "invalid perceived difficulty: Trivial"},
+		{name: "bad status", modify: func(p *ProblemEntry) { p.Status = "Skipped" }, want: ValidationError{Field: FieldStatus, Reason: ReasonInvalid, Value: "Skipped"}, message: "invalid status: Skipped"},
+		{name: "missing category", modify: func(p *ProblemEntry) { p.Category = "" }, want: ValidationError{Field: FieldCategory, Reason: ReasonRequired}, message: "category is required"},
+		{name: "too many tags", modify: func(p *ProblemEntry) { p.Tags = []string{"a", "b", "c", "d"} }, want: ValidationError{Field: FieldTags, Reason: ReasonTooMany, Count: 4, Limit: 3}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entry := valid()
+			tt.modify(entry)
+
+			err := ValidateProblemEntry(entry, 3)
+			var invalid *ValidationError
+			if !errors.As(err, &invalid) {
+				t.Fatalf("error = %v, want a *ValidationError", err)
+			}
+			if *invalid != tt.want {
+				t.Errorf("error = %+v, want %+v", *invalid, tt.want)
+			}
+			if tt.message != "" && err.Error() != tt.message {
+				t.Errorf("message = %q, want %q", err.Error(), tt.message)
+			}
+		})
+	}
+}
+
+func TestValidateProblemEntryTags(t *testing.T) {
+	entry := &ProblemEntry{UserID: "user-1", ProblemName: "Two Sum", Difficulty: DifficultyEasy, Status: StatusSolved, Category: "Array"}
+
+	// Duplicates and blanks don't count against the limit
+	entry.Tags = []string{"dp", "DP", " ", "bfs"}
+	if err := ValidateProblemEntry(entry, 2); err != nil {
+		t.Errorf("ValidateProblemEntry: %v", err)
+	}
+
+	entry.Tags = []string{"a", "b", "c"}
+	if err := ValidateProblemEntry(entry, 2); !errors.Is(err, ErrTooManyTags) {
+		t.Errorf("error = %v, want ErrTooManyTags", err)
+	}
+	if err := ValidateProblemEntry(entry, 0); err != nil {
+		t.Errorf("a limit of 0 rejected tags: %v", err)
+	}
+}
+
+// tagNames returns n distinct tag names
+func tagNames(n int) []string {
+	tags := make([]string, n)
+	for idx := range tags {
+		tags[idx] = fmt.Sprintf("tag-%d", idx)
+	}
+	return tags
+}
+
+func TestTagLimitOnCreateAndUpdate(t *testing.T) {
+	ctx := context.Background()
+	repo := newTestRepository(t) // allows 10 tags per problem
+
+	atLimit := &ProblemEntry{UserID: "tag-user", ProblemName: "Two Sum", Difficulty: DifficultyEasy, Status: StatusSolved, Category: "Array", SolvedAt: seedBaseTime, Tags: tagNames(10)}
+	if err := repo.CreateProblem(ctx, atLimit); err != nil {
+		t.Fatalf("CreateProblem with 10 tags: %v", err)
+	}
+
+	overLimit := &ProblemEntry{UserID: "tag-user", ProblemName: "3Sum", Difficulty: DifficultyMedium, Status: StatusSolved, Category: "Array", SolvedAt: seedBaseTime, Tags: tagNames(11)}
+	err := repo.CreateProblem(ctx, overLimit)
+	if !errors.Is(err, ErrTooManyTags) {
+		t.Fatalf("CreateProblem with 11 tags = %v, want ErrTooManyTags", err)
+	}
+	if msg := err.Error(); !strings.Contains(msg, "11 given, the limit is 10") {
+		t.Errorf("message = %q, want it to name the count and the limit", msg)
+	}
+
+	atLimit.Tags = tagNames(11)
+	if err := repo.UpdateProblem(ctx, atLimit); !errors.Is(err, ErrTooManyTags) {
+		t.Errorf("UpdateProblem with 11 tags = %v, want ErrTooManyTags", err)
+	}
+	got, err := repo.GetProblem(ctx, atLimit.ID)
+	if err != nil {
+		t.Fatalf("GetProblem: %v", err)
+	}
+	if len(got.Tags) != 10 {
+		t.Errorf("problem has %d tags after a rejected update, want 10", len(got.Tags))
+	}
+}
@@ -0,0 +1,133 @@
+package metrics
+
+import (
+	"context"
+	"math"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/rs/zerolog/log"
+)
+
+// activeUsersWindow is the period a user must have solved a problem within to count as active
+const activeUsersWindow = 7 * 24 * time.Hour
+
+// commandUsageWindow is the period a user must have run a command within to be sampled for the usage distribution
+const commandUsageWindow = 24 * time.Hour
+
+// commandDistributionQuantiles are the quantiles of the usage distribution exported on each refresh
+var commandDistributionQuantiles = []float64{0.5, 0.9, 0.99}
+
+var (
+	totalProblems = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "bot_total_problems",
+		Help: "Total number of problems logged across all users.",
+	})
+	activeUsers = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "bot_active_users_7d",
+		Help: "Number of distinct users who solved a problem in the last 7 days.",
+	})
+	userCommandDistribution = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "bot_user_command_distribution",
+		Help: "Quantiles of the average commands per day of the users active in the last day, as of the last refresh.",
+	}, []string{"quantile"})
+)
+
+// StatsSource provides the aggregate counts exported as gauges
+type StatsSource interface {
+	CountAllProblems(ctx context.Context) (int64, error)
+	CountActiveUsers(ctx context.Context, since time.Time) (int64, error)
+	GetCommandsPerUserPerDay(ctx context.Context, since time.Time) ([]float64, error)
+}
+
+// Collector periodically refreshes aggregate gauges from a StatsSource
+type Collector struct {
+	source   StatsSource
+	interval time.Duration
+	cancel   context.CancelFunc
+	wg       sync.WaitGroup
+}
+
+// NewCollector creates a new gauge collector
+func NewCollector(source StatsSource, interval time.Duration) *Collector {
+	if interval <= 0 {
+		interval = time.Minute
+	}
+	return &Collector{
+		source:   source,
+		interval: interval,
+	}
+}
+
+// Start refreshes the gauges immediately and then on every interval until Stop is called
+func (c *Collector) Start(ctx context.Context) {
+	ctx, c.cancel = context.WithCancel(ctx)
+
+	c.wg.Add(1)
+	go func() {
+		defer c.wg.Done()
+
+		ticker := time.NewTicker(c.interval)
+		defer ticker.Stop()
+
+		c.refresh(ctx)
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				c.refresh(ctx)
+			}
+		}
+	}()
+
+	log.Info().Dur("interval", c.interval).Msg("Metrics collector started")
+}
+
+// Stop halts the refresh loop and waits for it to exit
+func (c *Collector) Stop() {
+	if c.cancel == nil {
+		return
+	}
+	c.cancel()
+	c.wg.Wait()
+	log.Info().Msg("Metrics collector stopped")
+}
+
+// refresh queries the source and updates the gauges
+func (c *Collector) refresh(ctx context.Context) {
+	if count, err := c.source.CountAllProblems(ctx); err != nil {
+		log.Error().Err(err).Msg("Failed to refresh total problems gauge")
+	} else {
+		totalProblems.Set(float64(count))
+	}
+
+	if count, err := c.source.CountActiveUsers(ctx, time.Now().Add(-activeUsersWindow)); err != nil {
+		log.Error().Err(err).Msg("Failed to refresh active users gauge")
+	} else {
+		activeUsers.Set(float64(count))
+	}
+
+	if rates, err := c.source.GetCommandsPerUserPerDay(ctx, time.Now().Add(-commandUsageWindow)); err != nil {
+		log.Error().Err(err).Msg("Failed to sample command usage distribution")
+	} else {
+		// Set from the current users only, so each refresh replaces rather than adds to the distribution
+		sort.Float64s(rates)
+		for _, q := range commandDistributionQuantiles {
+			userCommandDistribution.WithLabelValues(strconv.FormatFloat(q, 'f', -1, 64)).Set(quantile(rates, q))
+		}
+	}
+}
+
+// quantile returns the nearest-rank q quantile of sorted values, or 0 when there are none
+func quantile(sorted []float64, q float64) float64 {
+	if len(sorted) == 0 {
+		return 0
+	}
+	rank := int(math.Ceil(q * float64(len(sorted))))
+	return sorted[min(max(rank-1, 0), len(sorted)-1)]
+}
@@ -0,0 +1,35 @@
+package metrics
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var commandsHandled = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "bot_commands_total",
+	Help: "Number of commands handled, by outcome (ok, error or timeout).",
+}, []string{"command", "outcome"})
+
+var commandDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+	Name:    "bot_command_duration_seconds",
+	Help:    "Time taken to handle a command.",
+	Buckets: prometheus.DefBuckets,
+}, []string{"command"})
+
+var commandTimeouts = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "bot_command_timeouts_total",
+	Help: "Number of commands that exceeded the configured processing timeout.",
+}, []string{"command"})
+
+// RecordCommandTimeout counts a command that ran past its processing timeout
+func RecordCommandTimeout(command string) {
+	commandTimeouts.WithLabelValues(command).Inc()
+}
+
+// RecordCommand counts a handled command and observes how long it took
+func RecordCommand(command, outcome string, duration time.Duration) {
+	commandsHandled.WithLabelValues(command, outcome).Inc()
+	commandDuration.WithLabelValues(command).Observe(duration.Seconds())
+}
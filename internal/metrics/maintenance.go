@@ -0,0 +1,16 @@
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var orphanedTagsCleaned = promauto.NewCounter(prometheus.CounterOpts{
+	Name: "bot_orphaned_tags_cleaned_total",
+	Help: "Number of tags removed because no problem used them anymore.",
+})
+
+// RecordOrphanedTagsCleaned counts tags removed by the orphaned tag cleanup
+func RecordOrphanedTagsCleaned(count int64) {
+	orphanedTagsCleaned.Add(float64(count))
+}
@@ -3,6 +3,7 @@ package metrics
 import (
 	"context"
 	"fmt"
+	"net"
 	"net/http"
 	"time"
 
@@ -31,12 +32,20 @@ func New(cfg config.MetricsConfig) *Server {
 	}
 }
 
-// Start starts the metrics server
+// Start binds the metrics address and serves in the background. Binding happens before
+// Start returns, so an address that is already in use is reported to the caller.
 func (s *Server) Start() error {
-	log.Info().Str("address", s.config.Address).Msg("Starting metrics server")
-	if err := s.httpServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
-		return fmt.Errorf("metrics server failed: %w", err)
+	listener, err := net.Listen("tcp", s.config.Address)
+	if err != nil {
+		return fmt.Errorf("failed to listen on metrics address: %w", err)
 	}
+
+	log.Info().Str("address", listener.Addr().String()).Msg("Starting metrics server")
+	go func() {
+		if err := s.httpServer.Serve(listener); err != nil && err != http.ErrServerClosed {
+			log.Error().Err(err).Msg("Metrics server failed")
+		}
+	}()
 	return nil
 }
 
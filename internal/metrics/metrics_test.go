@@ -0,0 +1,168 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/yugonline/grind_review_bot/config"
+)
+
+// freeAddress returns a loopback address with a port nothing is listening on
+func freeAddress(t *testing.T) string {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to find a free port: %v", err)
+	}
+	defer listener.Close()
+	return listener.Addr().String()
+}
+
+// startServer starts a metrics server on a free port and returns its /metrics URL
+func startServer(t *testing.T) (*Server, string) {
+	t.Helper()
+	address := freeAddress(t)
+	server := New(config.MetricsConfig{Enabled: true, Address: address})
+	if err := server.Start(); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	return server, "http://" + address + "/metrics"
+}
+
+// scrape fetches url, retrying briefly while the server comes up, and returns the body
+func scrape(t *testing.T, url string) string {
+	t.Helper()
+	var lastErr error
+	for range 20 {
+		resp, err := http.Get(url)
+		if err != nil {
+			lastErr = err
+			time.Sleep(10 * time.Millisecond)
+			continue
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("GET %s = %d, want 200", url, resp.StatusCode)
+		}
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			t.Fatalf("failed to read metrics: %v", err)
+		}
+		return string(body)
+	}
+	t.Fatalf("metrics server never answered: %v", lastErr)
+	return ""
+}
+
+func TestServerStartStop(t *testing.T) {
+	server, url := startServer(t)
+
+	if body := scrape(t, url); !strings.Contains(body, "go_goroutines") {
+		t.Error("metrics output is missing the Go runtime metrics")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	if err := server.Stop(ctx); err != nil {
+		t.Fatalf("Stop: %v", err)
+	}
+
+	if resp, err := http.Get(url); err == nil {
+		resp.Body.Close()
+		t.Error("metrics server still answers after Stop")
+	}
+}
+
+func TestServerStartAddressInUse(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Listen: %v", err)
+	}
+	defer listener.Close()
+
+	server := New(config.MetricsConfig{Enabled: true, Address: listener.Addr().String()})
+	if err := server.Start(); err == nil {
+		server.Stop(context.Background())
+		t.Error("Start succeeded on an address already in use")
+	}
+}
+
+func TestServerExportsCounters(t *testing.T) {
+	server, url := startServer(t)
+	defer server.Stop(context.Background())
+
+	custom := promauto.NewCounter(prometheus.CounterOpts{
+		Name: "bot_test_events_total",
+		Help: "Events counted by the metrics test.",
+	})
+	custom.Add(3)
+	RecordCommand("metrics-test", "ok", 20*time.Millisecond)
+	RecordCommandTimeout("metrics-test")
+
+	body := scrape(t, url)
+	for _, want := range []string{
+		"bot_test_events_total 3",
+		`bot_commands_total{command="metrics-test",outcome="ok"} 1`,
+		`bot_command_timeouts_total{command="metrics-test"} 1`,
+		`bot_command_duration_seconds_count{command="metrics-test"} 1`,
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("metrics output is missing %q", want)
+		}
+	}
+}
+
+// fakeStatsSource returns fixed aggregates, or err from every method when it is set
+type fakeStatsSource struct {
+	problems, users int64
+	rates           []float64
+	err             error
+}
+
+func (f *fakeStatsSource) CountAllProblems(context.Context) (int64, error) {
+	return f.problems, f.err
+}
+
+func (f *fakeStatsSource) CountActiveUsers(context.Context, time.Time) (int64, error) {
+	return f.users, f.err
+}
+
+func (f *fakeStatsSource) GetCommandsPerUserPerDay(context.Context, time.Time) ([]float64, error) {
+	return f.rates, f.err
+}
+
+func TestCollectorRefresh(t *testing.T) {
+	server, url := startServer(t)
+	defer server.Stop(context.Background())
+
+	source := &fakeStatsSource{problems: 42, users: 7, rates: []float64{1, 2, 3, 4, 10}}
+	collector := NewCollector(source, time.Hour)
+	collector.refresh(context.Background())
+
+	body := scrape(t, url)
+	for _, want := range []string{
+		"bot_total_problems 42",
+		"bot_active_users_7d 7",
+		`bot_user_command_distribution{quantile="0.5"} 3`,
+		`bot_user_command_distribution{quantile="0.99"} 10`,
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("metrics output is missing %q", want)
+		}
+	}
+
+	// A failing source leaves the last values in place
+	source.err = errors.New("database is down")
+	collector.refresh(context.Background())
+	if body := scrape(t, url); !strings.Contains(body, "bot_total_problems 42") {
+		t.Error("a failed refresh cleared the total problems gauge")
+	}
+}
@@ -0,0 +1,33 @@
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// Reasons a reminder delivery failed
+const (
+	ReminderFailureRateLimit = "rate-limit"
+	ReminderFailureForbidden = "forbidden"
+	ReminderFailureOther     = "other"
+)
+
+var remindersSent = promauto.NewCounter(prometheus.CounterOpts{
+	Name: "bot_reminders_sent_total",
+	Help: "Number of review reminders delivered.",
+})
+
+var remindersFailed = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "bot_reminders_failed_total",
+	Help: "Number of review reminders that could not be delivered after all retries, by reason (rate-limit, forbidden or other).",
+}, []string{"reason"})
+
+// RecordReminderSent counts a delivered review reminder
+func RecordReminderSent() {
+	remindersSent.Inc()
+}
+
+// RecordReminderFailed counts a review reminder that failed after all retries
+func RecordReminderFailed(reason string) {
+	remindersFailed.WithLabelValues(reason).Inc()
+}
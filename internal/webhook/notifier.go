@@ -0,0 +1,136 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"github.com/yugonline/grind_review_bot/config"
+	"github.com/yugonline/grind_review_bot/internal/database"
+)
+
+// SignatureHeader carries the hex HMAC-SHA256 of the request body, prefixed with "sha256="
+const SignatureHeader = "X-Grind-Signature-256"
+
+// EventHeader names the event that triggered the delivery
+const EventHeader = "X-Grind-Event"
+
+// EventProblemCreated is sent after a problem is logged
+const EventProblemCreated = "problem.created"
+
+// Notifier delivers problem events to the configured webhook URLs in the background
+type Notifier struct {
+	urls       []string
+	secret     []byte
+	client     *http.Client
+	attempts   int
+	retryDelay time.Duration
+	wg         sync.WaitGroup
+}
+
+// New creates a webhook notifier; with no URLs configured it delivers nothing
+func New(cfg config.WebhooksConfig) *Notifier {
+	return &Notifier{
+		urls:       cfg.URLs,
+		secret:     []byte(cfg.Secret),
+		client:     &http.Client{Timeout: cfg.Timeout},
+		attempts:   cfg.RetryAttempts,
+		retryDelay: cfg.RetryDelay,
+	}
+}
+
+// ProblemCreated posts the problem to every webhook without blocking the caller
+func (n *Notifier) ProblemCreated(problem *database.ProblemEntry) {
+	if n == nil || len(n.urls) == 0 {
+		return
+	}
+
+	body, err := json.Marshal(problem)
+	if err != nil {
+		log.Error().Err(err).Uint("problem_id", problem.ID).Msg("Failed to encode webhook payload")
+		return
+	}
+
+	for _, url := range n.urls {
+		n.wg.Add(1)
+		go func(url string) {
+			defer n.wg.Done()
+			if err := n.deliver(url, EventProblemCreated, body); err != nil {
+				log.Error().Err(err).Str("url", url).Uint("problem_id", problem.ID).Msg("Webhook delivery failed")
+			}
+		}(url)
+	}
+}
+
+// Wait blocks until in-flight deliveries finish or ctx is done
+func (n *Notifier) Wait(ctx context.Context) {
+	done := make(chan struct{})
+	go func() {
+		n.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-ctx.Done():
+		log.Warn().Msg("Gave up waiting for webhook deliveries")
+	}
+}
+
+// deliver posts body to url, retrying failed attempts with exponential backoff
+func (n *Notifier) deliver(url, event string, body []byte) error {
+	attempts := max(n.attempts, 1)
+	delay := n.retryDelay
+
+	var lastErr error
+	for attempt := 1; attempt <= attempts; attempt++ {
+		if lastErr = n.post(url, event, body); lastErr == nil {
+			return nil
+		}
+		if attempt < attempts {
+			log.Warn().Err(lastErr).Str("url", url).Int("attempt", attempt).Msg("Webhook delivery failed, retrying")
+			time.Sleep(delay)
+			delay *= 2
+		}
+	}
+	return fmt.Errorf("failed after %d attempts: %w", attempts, lastErr)
+}
+
+// post sends a single signed request
+func (n *Notifier) post(url, event string, body []byte) error {
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(EventHeader, event)
+	if len(n.secret) > 0 {
+		req.Header.Set(SignatureHeader, "sha256="+Sign(n.secret, body))
+	}
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// Sign returns the hex-encoded HMAC-SHA256 of body keyed with secret, for receivers to verify payloads
+func Sign(secret, body []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,136 @@
+package webhook
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/yugonline/grind_review_bot/config"
+	"github.com/yugonline/grind_review_bot/internal/database"
+)
+
+// delivery is a request received by a test webhook receiver
+type delivery struct {
+	header http.Header
+	body   []byte
+}
+
+// newReceiver starts a webhook receiver that records deliveries, answering the first
+// failures requests with 500
+func newReceiver(t *testing.T, failures int32) (*httptest.Server, func() []delivery) {
+	t.Helper()
+	var mu sync.Mutex
+	var received []delivery
+	var calls atomic.Int32
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) <= failures {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		body, _ := io.ReadAll(r.Body)
+		mu.Lock()
+		received = append(received, delivery{header: r.Header.Clone(), body: body})
+		mu.Unlock()
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	t.Cleanup(server.Close)
+
+	return server, func() []delivery {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]delivery(nil), received...)
+	}
+}
+
+// waitForDeliveries waits for the notifier's in-flight deliveries to finish
+func waitForDeliveries(t *testing.T, n *Notifier) {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	n.Wait(ctx)
+	if ctx.Err() != nil {
+		t.Fatal("deliveries didn't finish")
+	}
+}
+
+func TestProblemCreatedPayloadAndSignature(t *testing.T) {
+	server, received := newReceiver(t, 0)
+	n := New(config.WebhooksConfig{URLs: []string{server.URL}, Secret: "shh", Timeout: time.Second, RetryAttempts: 1})
+	problem := &database.ProblemEntry{ID: 7, UserID: "user-1", ProblemName: "Two Sum", Difficulty: database.DifficultyEasy, Tags: []string{"array"}}
+
+	n.ProblemCreated(problem)
+	waitForDeliveries(t, n)
+
+	deliveries := received()
+	if len(deliveries) != 1 {
+		t.Fatalf("received %d deliveries, want 1", len(deliveries))
+	}
+	got := deliveries[0]
+
+	var payload database.ProblemEntry
+	if err := json.Unmarshal(got.body, &payload); err != nil {
+		t.Fatalf("payload isn't a ProblemEntry: %v", err)
+	}
+	if payload.ID != 7 || payload.ProblemName != "Two Sum" || payload.UserID != "user-1" {
+		t.Errorf("payload = %+v, want the created problem", payload)
+	}
+	if event := got.header.Get(EventHeader); event != EventProblemCreated {
+		t.Errorf("%s = %q, want %q", EventHeader, event, EventProblemCreated)
+	}
+
+	// Receivers verify the signature independently of Sign
+	mac := hmac.New(sha256.New, []byte("shh"))
+	mac.Write(got.body)
+	if want := "sha256=" + hex.EncodeToString(mac.Sum(nil)); got.header.Get(SignatureHeader) != want {
+		t.Errorf("%s = %q, want %q", SignatureHeader, got.header.Get(SignatureHeader), want)
+	}
+}
+
+func TestProblemCreatedRetries(t *testing.T) {
+	server, received := newReceiver(t, 2)
+	n := New(config.WebhooksConfig{URLs: []string{server.URL}, Timeout: time.Second, RetryAttempts: 3, RetryDelay: time.Millisecond})
+
+	n.ProblemCreated(&database.ProblemEntry{ID: 1})
+	waitForDeliveries(t, n)
+
+	deliveries := received()
+	if len(deliveries) != 1 {
+		t.Fatalf("received %d deliveries, want 1 after two failures", len(deliveries))
+	}
+	if sig := deliveries[0].header.Get(SignatureHeader); sig != "" {
+		t.Errorf("%s = %q without a secret, want none", SignatureHeader, sig)
+	}
+}
+
+func TestProblemCreatedDoesNotBlock(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	}))
+	defer server.Close()
+	defer close(release)
+	n := New(config.WebhooksConfig{URLs: []string{server.URL}, Timeout: 5 * time.Second, RetryAttempts: 1})
+
+	start := time.Now()
+	n.ProblemCreated(&database.ProblemEntry{ID: 1})
+	if elapsed := time.Since(start); elapsed > 100*time.Millisecond {
+		t.Errorf("ProblemCreated blocked for %s", elapsed)
+	}
+}
+
+func TestNotifierWithoutURLs(t *testing.T) {
+	// Neither a nil notifier nor one without URLs has anything to do
+	var n *Notifier
+	n.ProblemCreated(&database.ProblemEntry{ID: 1})
+	New(config.WebhooksConfig{}).ProblemCreated(&database.ProblemEntry{ID: 1})
+}
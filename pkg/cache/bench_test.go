@@ -0,0 +1,169 @@
+package cache
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/yugonline/grind_review_bot/internal/database"
+)
+
+// benchKeys is the number of distinct keys the benchmarks cycle through, about one per active user
+const benchKeys = 1024
+
+// benchKey returns a key shaped like the bot's per-user keys, which end in a Discord snowflake
+func benchKey(idx int) string {
+	return fmt.Sprintf("stats:%018d", 123456789012345678+idx)
+}
+
+// benchValue returns a problem like the ones the bot caches
+func benchValue(idx int) *database.ProblemEntry {
+	return &database.ProblemEntry{
+		ID:          uint(idx),
+		UserID:      "123456789012345678",
+		ProblemName: "Longest Substring Without Repeating Characters",
+		Difficulty:  database.DifficultyMedium,
+		Category:    "Sliding Window",
+		Status:      database.StatusSolved,
+		SolvedAt:    time.Date(2024, time.January, 1, 12, 0, 0, 0, time.UTC),
+		Tags:        []string{"sliding-window", "hash-table", "string"},
+	}
+}
+
+func benchKeysAndValues() ([]string, []*database.ProblemEntry) {
+	keys := make([]string, benchKeys)
+	values := make([]*database.ProblemEntry, benchKeys)
+	for idx := range keys {
+		keys[idx] = benchKey(idx)
+		values[idx] = benchValue(idx)
+	}
+	return keys, values
+}
+
+func BenchmarkCacheSet(b *testing.B) {
+	c := New(5*time.Minute, 0)
+	keys, values := benchKeysAndValues()
+
+	b.ResetTimer()
+	for idx := range b.N {
+		c.Set(keys[idx%benchKeys], values[idx%benchKeys])
+	}
+}
+
+func BenchmarkCacheGet(b *testing.B) {
+	c := New(5*time.Minute, 0)
+	keys, values := benchKeysAndValues()
+	for idx := range keys {
+		c.Set(keys[idx], values[idx])
+	}
+
+	b.ResetTimer()
+	for idx := range b.N {
+		if _, ok := c.Get(keys[idx%benchKeys]); !ok {
+			b.Fatal("cached item missing")
+		}
+	}
+}
+
+// BenchmarkCacheSetConcurrent mixes one write to every nine reads across goroutines, as handlers do
+func BenchmarkCacheSetConcurrent(b *testing.B) {
+	c := New(5*time.Minute, 0)
+	keys, values := benchKeysAndValues()
+	for idx := range keys {
+		c.Set(keys[idx], values[idx])
+	}
+
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		idx := 0
+		for pb.Next() {
+			key := idx % benchKeys
+			if idx%10 == 0 {
+				c.Set(keys[key], values[key])
+			} else {
+				c.Get(keys[key])
+			}
+			idx++
+		}
+	})
+}
+
+// BenchmarkCacheExpiredCleanup measures sweeping a cache whose items have all expired
+func BenchmarkCacheExpiredCleanup(b *testing.B) {
+	c := New(5*time.Minute, 0)
+	keys, values := benchKeysAndValues()
+
+	for range b.N {
+		b.StopTimer()
+		for idx := range keys {
+			c.SetWithExpiration(keys[idx], values[idx], time.Nanosecond)
+		}
+		time.Sleep(time.Microsecond)
+		b.StartTimer()
+
+		if n := len(c.Keys()); n != 0 {
+			b.Fatalf("%d expired items survived the sweep", n)
+		}
+	}
+}
+
+// mutexCache is the map and RWMutex alternative to Cache's sync.Map, for comparison only
+type mutexCache struct {
+	mu    sync.RWMutex
+	items map[string]Item
+}
+
+func (c *mutexCache) Set(key string, value interface{}) {
+	c.mu.Lock()
+	c.items[key] = Item{value: value, expiration: time.Now().Add(5 * time.Minute).UnixNano()}
+	c.mu.Unlock()
+}
+
+func (c *mutexCache) Get(key string) (interface{}, bool) {
+	c.mu.RLock()
+	item, ok := c.items[key]
+	c.mu.RUnlock()
+	if !ok || time.Now().UnixNano() > item.expiration {
+		return nil, false
+	}
+	return item.value, true
+}
+
+// benchCache is the part of a cache the comparison benchmark exercises
+type benchCache interface {
+	Set(key string, value interface{})
+	Get(key string) (interface{}, bool)
+}
+
+// BenchmarkCacheVsMapMutex compares sync.Map with a RWMutex-guarded map under the concurrent mix
+func BenchmarkCacheVsMapMutex(b *testing.B) {
+	keys, values := benchKeysAndValues()
+	caches := []struct {
+		name  string
+		cache benchCache
+	}{
+		{name: "sync.Map", cache: New(5*time.Minute, 0)},
+		{name: "RWMutex", cache: &mutexCache{items: make(map[string]Item)}},
+	}
+
+	for _, tt := range caches {
+		for idx := range keys {
+			tt.cache.Set(keys[idx], values[idx])
+		}
+		b.Run(tt.name, func(b *testing.B) {
+			b.RunParallel(func(pb *testing.PB) {
+				idx := 0
+				for pb.Next() {
+					key := idx % benchKeys
+					if idx%10 == 0 {
+						tt.cache.Set(keys[key], values[key])
+					} else {
+						tt.cache.Get(keys[key])
+					}
+					idx++
+				}
+			})
+		})
+	}
+}
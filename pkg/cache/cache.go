@@ -1,10 +1,14 @@
 package cache
 
 import (
+	"errors"
 	"sync"
 	"time"
 )
 
+// errLoaderPanicked is returned to GetOrSet callers waiting on a loader that panicked
+var errLoaderPanicked = errors.New("cache loader panicked")
+
 // Item represents a cached item
 type Item struct {
 	value      interface{}
@@ -16,18 +20,48 @@ type Cache struct {
 	items             sync.Map
 	defaultExpiration time.Duration
 	cleanupInterval   time.Duration
+
+	done      chan struct{} // Closed by Close to stop the cleanup goroutine
+	closeOnce sync.Once
+	stopped   chan struct{} // Closed once the cleanup goroutine has exited
+
+	loadMu  sync.Mutex
+	loading map[string]*load // GetOrSet loads in flight, by key
 }
 
-// New creates a new cache instance
+// load is a GetOrSet loader call that concurrent callers for the same key wait on
+type load struct {
+	done  chan struct{} // Closed once value and err are set
+	value interface{}
+	err   error
+}
+
+// New creates a new cache instance. Expired items are swept every cleanupInterval until Close
+// is called; a cleanupInterval of zero or less disables the sweep, leaving Get to drop expired
+// items as it finds them.
 func New(defaultExpiration, cleanupInterval time.Duration) *Cache {
 	cache := &Cache{
 		defaultExpiration: defaultExpiration,
 		cleanupInterval:   cleanupInterval,
+		done:              make(chan struct{}),
+		stopped:           make(chan struct{}),
+		loading:           make(map[string]*load),
+	}
+	if cleanupInterval > 0 {
+		go cache.cleanupExpired()
+	} else {
+		close(cache.stopped)
 	}
-	go cache.cleanupExpired()
 	return cache
 }
 
+// Close stops the cleanup goroutine and waits for it to exit. The cache stays usable, but
+// expired items are no longer swept. Calling Close more than once is safe.
+func (c *Cache) Close() {
+	c.closeOnce.Do(func() { close(c.done) })
+	<-c.stopped
+}
+
 // Set adds an item to the cache with a default expiration time
 func (c *Cache) Set(key string, value interface{}) {
 	c.SetWithExpiration(key, value, c.defaultExpiration)
@@ -61,11 +95,86 @@ func (c *Cache) Delete(key string) {
 	c.items.Delete(key)
 }
 
+// GetOrSet returns the cached item for key, or calls loader and caches its result for ttl.
+// Concurrent callers missing the same key share a single loader call and all receive its
+// result. Errors from loader are returned to every waiting caller and nothing is cached.
+func (c *Cache) GetOrSet(key string, ttl time.Duration, loader func() (interface{}, error)) (interface{}, error) {
+	if value, found := c.Get(key); found {
+		return value, nil
+	}
+
+	c.loadMu.Lock()
+	// Another caller may have stored the item while this one waited for the lock
+	if value, found := c.Get(key); found {
+		c.loadMu.Unlock()
+		return value, nil
+	}
+	if inFlight, ok := c.loading[key]; ok {
+		c.loadMu.Unlock()
+		<-inFlight.done
+		return inFlight.value, inFlight.err
+	}
+	call := &load{done: make(chan struct{})}
+	c.loading[key] = call
+	c.loadMu.Unlock()
+
+	// Release waiters even if loader panics; they see errLoaderPanicked in that case
+	defer func() {
+		c.loadMu.Lock()
+		delete(c.loading, key)
+		c.loadMu.Unlock()
+		close(call.done)
+	}()
+
+	call.err = errLoaderPanicked
+	call.value, call.err = loader()
+	if call.err == nil {
+		c.SetWithExpiration(key, call.value, ttl)
+	}
+	return call.value, call.err
+}
+
+// Has reports whether key holds an item that hasn't expired, dropping it if it has like Get
+func (c *Cache) Has(key string) bool {
+	_, found := c.Get(key)
+	return found
+}
+
+// Len returns the number of items that haven't expired
+func (c *Cache) Len() int {
+	return len(c.Keys())
+}
+
+// Keys returns the keys of the items that haven't expired, in no particular order. Expired
+// items found along the way are dropped like Get does.
+func (c *Cache) Keys() []string {
+	now := time.Now().UnixNano()
+	var keys []string
+	c.items.Range(func(key, value interface{}) bool {
+		item := value.(Item)
+		if item.expiration > 0 && now > item.expiration {
+			c.items.Delete(key)
+			return true
+		}
+		keys = append(keys, key.(string))
+		return true
+	})
+	return keys
+}
+
 // cleanupExpired periodically removes expired items from the cache
 func (c *Cache) cleanupExpired() {
+	defer close(c.stopped)
+
 	ticker := time.NewTicker(c.cleanupInterval)
 	defer ticker.Stop()
-	for range ticker.C {
+	for {
+		select {
+		case <-c.done:
+			return
+		case <-ticker.C:
+		}
+
 		c.items.Range(func(key, value interface{}) bool {
 			item := value.(Item)
 			if item.expiration > 0 && time.Now().UnixNano() > item.expiration {
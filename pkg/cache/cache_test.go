@@ -0,0 +1,214 @@
+package cache
+
+import (
+	"errors"
+	"runtime"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// storedItems counts the items held, expired or not, without dropping any
+func storedItems(c *Cache) int {
+	n := 0
+	c.items.Range(func(_, _ interface{}) bool {
+		n++
+		return true
+	})
+	return n
+}
+
+func TestCacheClose(t *testing.T) {
+	before := runtime.NumGoroutine()
+	c := New(time.Minute, time.Millisecond)
+	c.SetWithExpiration("short", 1, time.Millisecond)
+
+	// The sweep drops the expired item without anyone reading it
+	deadline := time.Now().Add(time.Second)
+	for storedItems(c) != 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("the cleanup goroutine never swept the expired item")
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	c.Close()
+	select {
+	case <-c.stopped:
+	default:
+		t.Fatal("Close returned before the cleanup goroutine exited")
+	}
+	for deadline := time.Now().Add(time.Second); runtime.NumGoroutine() > before; {
+		if time.Now().After(deadline) {
+			t.Fatalf("%d goroutines after Close, want %d", runtime.NumGoroutine(), before)
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	// Closing again is harmless, and the cache still works without the sweep
+	c.Close()
+	c.Set("key", "value")
+	if value, ok := c.Get("key"); !ok || value != "value" {
+		t.Errorf("Get after Close = %v, %v, want value", value, ok)
+	}
+	c.SetWithExpiration("expired", 2, time.Millisecond)
+	time.Sleep(5 * time.Millisecond)
+	if storedItems(c) != 2 {
+		t.Errorf("%d items stored, want the expired one kept once the sweep stopped", storedItems(c))
+	}
+}
+
+func TestCacheWithoutCleanup(t *testing.T) {
+	c := New(time.Minute, 0)
+	// With no sweep there is nothing to wait for
+	done := make(chan struct{})
+	go func() {
+		c.Close()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Close blocked on a cache without a cleanup goroutine")
+	}
+}
+
+func TestCacheIntrospection(t *testing.T) {
+	// No sweep, so only the reads themselves can drop expired items
+	c := New(time.Hour, 0)
+	c.SetWithExpiration("short", 1, time.Millisecond)
+	c.Set("long", 2)
+	c.SetWithExpiration("forever", 3, 0)
+
+	if !c.Has("short") || c.Len() != 3 {
+		t.Fatalf("Has(short) = %v, Len = %d before expiry, want true and 3", c.Has("short"), c.Len())
+	}
+	time.Sleep(5 * time.Millisecond)
+
+	if c.Has("short") {
+		t.Error("Has reported an expired item")
+	}
+	if !c.Has("long") || !c.Has("forever") || c.Has("missing") {
+		t.Errorf("Has(long, forever, missing) = %v, %v, %v, want true, true, false", c.Has("long"), c.Has("forever"), c.Has("missing"))
+	}
+
+	// Keys and Len agree with Get, and drop the expired item as they go
+	c.SetWithExpiration("also-short", 4, time.Millisecond)
+	time.Sleep(5 * time.Millisecond)
+	keys := c.Keys()
+	slices.Sort(keys)
+	if want := []string{"forever", "long"}; !slices.Equal(keys, want) {
+		t.Errorf("Keys = %v, want %v", keys, want)
+	}
+	if c.Len() != 2 {
+		t.Errorf("Len = %d, want 2", c.Len())
+	}
+	if storedItems(c) != 2 {
+		t.Errorf("%d items stored, want the expired ones dropped", storedItems(c))
+	}
+
+	c.Delete("long")
+	if c.Has("long") || c.Len() != 1 {
+		t.Errorf("Has(long) = %v, Len = %d after Delete, want false and 1", c.Has("long"), c.Len())
+	}
+}
+
+func TestTypedCacheIntrospection(t *testing.T) {
+	c := NewTyped[int](time.Hour, 0)
+	c.SetWithExpiration("short", 1, time.Millisecond)
+	c.Set("long", 2)
+	time.Sleep(5 * time.Millisecond)
+
+	if c.Has("short") || !c.Has("long") {
+		t.Errorf("Has(short, long) = %v, %v, want false, true", c.Has("short"), c.Has("long"))
+	}
+	if keys := c.Keys(); !slices.Equal(keys, []string{"long"}) || c.Len() != 1 {
+		t.Errorf("Keys = %v, Len = %d, want [long] and 1", keys, c.Len())
+	}
+}
+
+func TestCacheGetOrSet(t *testing.T) {
+	c := New(time.Hour, 0)
+	var calls atomic.Int32
+	loader := func() (interface{}, error) {
+		calls.Add(1)
+		time.Sleep(10 * time.Millisecond)
+		return 42, nil
+	}
+
+	var wg sync.WaitGroup
+	for range 10 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if value, err := c.GetOrSet("answer", time.Minute, loader); err != nil || value != 42 {
+				t.Errorf("GetOrSet = %v, %v, want 42", value, err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if calls.Load() != 1 {
+		t.Errorf("loader called %d times, want 1", calls.Load())
+	}
+	if value, ok := c.Get("answer"); !ok || value != 42 {
+		t.Errorf("Get = %v, %v, want the loaded value cached", value, ok)
+	}
+}
+
+func TestCacheGetOrSetError(t *testing.T) {
+	c := New(time.Hour, 0)
+	errLoad := errors.New("database unavailable")
+
+	if _, err := c.GetOrSet("key", time.Minute, func() (interface{}, error) { return nil, errLoad }); !errors.Is(err, errLoad) {
+		t.Fatalf("GetOrSet = %v, want the loader's error", err)
+	}
+	if c.Has("key") {
+		t.Error("a failed load was cached")
+	}
+
+	// The next caller tries again
+	if value, err := c.GetOrSet("key", time.Minute, func() (interface{}, error) { return "loaded", nil }); err != nil || value != "loaded" {
+		t.Errorf("GetOrSet after a failure = %v, %v, want loaded", value, err)
+	}
+}
+
+func TestCacheGetOrSetPanic(t *testing.T) {
+	c := New(time.Hour, 0)
+	started, release := make(chan struct{}), make(chan struct{})
+
+	go func() {
+		defer func() { recover() }()
+		c.GetOrSet("key", time.Minute, func() (interface{}, error) {
+			close(started)
+			<-release
+			panic("loader failed")
+		})
+	}()
+	<-started
+
+	waiter := make(chan error)
+	go func() {
+		_, err := c.GetOrSet("key", time.Minute, func() (interface{}, error) {
+			return nil, errors.New("loaded again instead of waiting")
+		})
+		waiter <- err
+	}()
+	// Give the waiter time to find the load in flight
+	time.Sleep(10 * time.Millisecond)
+	close(release)
+
+	select {
+	case err := <-waiter:
+		if !errors.Is(err, errLoaderPanicked) {
+			t.Errorf("waiter got %v, want %v", err, errLoaderPanicked)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("the waiter was never released after the loader panicked")
+	}
+	if c.Has("key") {
+		t.Error("a panicked load was cached")
+	}
+}
@@ -0,0 +1,188 @@
+package cache
+
+import (
+	"container/list"
+	"sync"
+	"time"
+)
+
+// LRUCache is a size-bounded cache. Once it holds maxSize items, setting a new key evicts the
+// least recently used one. Items can also expire like in Cache, but expired items are only
+// dropped when they're found or evicted, so there's no cleanup goroutine.
+type LRUCache[K comparable, V any] struct {
+	mu                sync.Mutex
+	maxSize           int
+	defaultExpiration time.Duration
+	items             map[K]*list.Element // Elements hold *lruEntry[K, V]
+	order             *list.List          // Most recently used at the front
+
+	loading map[K]*lruLoad[V] // GetOrSet loads in flight, by key
+}
+
+// lruEntry is one item in an LRUCache
+type lruEntry[K comparable, V any] struct {
+	key        K
+	value      V
+	expiration int64
+}
+
+// lruLoad is a GetOrSet loader call that concurrent callers for the same key wait on
+type lruLoad[V any] struct {
+	done  chan struct{} // Closed once value and err are set
+	value V
+	err   error
+}
+
+// NewLRU creates an LRU cache holding at most maxSize items (at least 1), each expiring after
+// defaultExpiration unless that is zero or less
+func NewLRU[K comparable, V any](maxSize int, defaultExpiration time.Duration) *LRUCache[K, V] {
+	return &LRUCache[K, V]{
+		maxSize:           max(maxSize, 1),
+		defaultExpiration: defaultExpiration,
+		items:             make(map[K]*list.Element),
+		order:             list.New(),
+		loading:           make(map[K]*lruLoad[V]),
+	}
+}
+
+// Set adds an item to the cache with the default expiration time
+func (c *LRUCache[K, V]) Set(key K, value V) {
+	c.SetWithExpiration(key, value, c.defaultExpiration)
+}
+
+// SetWithExpiration adds an item to the cache with a specified expiration time, marking it most
+// recently used and evicting the least recently used item if the cache is full
+func (c *LRUCache[K, V]) SetWithExpiration(key K, value V, expiration time.Duration) {
+	var expiry int64
+	if expiration > 0 {
+		expiry = time.Now().Add(expiration).UnixNano()
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if elem, ok := c.items[key]; ok {
+		entry := elem.Value.(*lruEntry[K, V])
+		entry.value, entry.expiration = value, expiry
+		c.order.MoveToFront(elem)
+		return
+	}
+	c.items[key] = c.order.PushFront(&lruEntry[K, V]{key: key, value: value, expiration: expiry})
+	for c.order.Len() > c.maxSize {
+		c.removeElement(c.order.Back())
+	}
+}
+
+// Get retrieves an item from the cache and marks it most recently used
+func (c *LRUCache[K, V]) Get(key K) (V, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.get(key)
+}
+
+// get is Get for callers holding mu
+func (c *LRUCache[K, V]) get(key K) (V, bool) {
+	var zero V
+	elem, ok := c.items[key]
+	if !ok {
+		return zero, false
+	}
+	entry := elem.Value.(*lruEntry[K, V])
+	if entry.expired(time.Now().UnixNano()) {
+		c.removeElement(elem)
+		return zero, false
+	}
+	c.order.MoveToFront(elem)
+	return entry.value, true
+}
+
+// Delete removes an item from the cache
+func (c *LRUCache[K, V]) Delete(key K) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if elem, ok := c.items[key]; ok {
+		c.removeElement(elem)
+	}
+}
+
+// GetOrSet returns the cached item for key, or calls loader and caches its result for ttl.
+// Concurrent callers missing the same key share a single loader call and all receive its
+// result. Errors from loader are returned to every waiting caller and nothing is cached.
+func (c *LRUCache[K, V]) GetOrSet(key K, ttl time.Duration, loader func() (V, error)) (V, error) {
+	c.mu.Lock()
+	if value, found := c.get(key); found {
+		c.mu.Unlock()
+		return value, nil
+	}
+	if inFlight, ok := c.loading[key]; ok {
+		c.mu.Unlock()
+		<-inFlight.done
+		return inFlight.value, inFlight.err
+	}
+	call := &lruLoad[V]{done: make(chan struct{})}
+	c.loading[key] = call
+	c.mu.Unlock()
+
+	// Release waiters even if loader panics; they see errLoaderPanicked in that case
+	defer func() {
+		c.mu.Lock()
+		delete(c.loading, key)
+		c.mu.Unlock()
+		close(call.done)
+	}()
+
+	call.err = errLoaderPanicked
+	call.value, call.err = loader()
+	if call.err == nil {
+		c.SetWithExpiration(key, call.value, ttl)
+	}
+	return call.value, call.err
+}
+
+// Has reports whether key holds an item that hasn't expired, without marking it used
+func (c *LRUCache[K, V]) Has(key K) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	elem, ok := c.items[key]
+	return ok && !elem.Value.(*lruEntry[K, V]).expired(time.Now().UnixNano())
+}
+
+// Len returns the number of items that haven't expired
+func (c *LRUCache[K, V]) Len() int {
+	return len(c.Keys())
+}
+
+// Keys returns the keys of the items that haven't expired, most recently used first. Expired
+// items found along the way are dropped.
+func (c *LRUCache[K, V]) Keys() []K {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := time.Now().UnixNano()
+	keys := make([]K, 0, c.order.Len())
+	for elem := c.order.Front(); elem != nil; {
+		next := elem.Next()
+		entry := elem.Value.(*lruEntry[K, V])
+		if entry.expired(now) {
+			c.removeElement(elem)
+		} else {
+			keys = append(keys, entry.key)
+		}
+		elem = next
+	}
+	return keys
+}
+
+// Close does nothing; it lets an LRUCache stand in for a Cache, which has a goroutine to stop
+func (c *LRUCache[K, V]) Close() {}
+
+// removeElement drops an item; callers must hold mu
+func (c *LRUCache[K, V]) removeElement(elem *list.Element) {
+	c.order.Remove(elem)
+	delete(c.items, elem.Value.(*lruEntry[K, V]).key)
+}
+
+// expired reports whether the entry's expiration, if it has one, is before now
+func (e *lruEntry[K, V]) expired(now int64) bool {
+	return e.expiration > 0 && now > e.expiration
+}
@@ -0,0 +1,132 @@
+package cache
+
+import (
+	"fmt"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestLRUCacheCapacity(t *testing.T) {
+	c := NewLRU[string, int](3, 0)
+	for idx := range 5 {
+		c.Set(fmt.Sprintf("key-%d", idx), idx)
+	}
+
+	if got, want := c.Keys(), []string{"key-4", "key-3", "key-2"}; !slices.Equal(got, want) {
+		t.Errorf("Keys = %v, want %v", got, want)
+	}
+	if _, ok := c.Get("key-0"); ok {
+		t.Error("the oldest item survived past capacity")
+	}
+
+	// Updating a key doesn't take a second slot
+	c.Set("key-2", 20)
+	if c.Len() != 3 {
+		t.Errorf("Len = %d after an update, want 3", c.Len())
+	}
+	if value, _ := c.Get("key-2"); value != 20 {
+		t.Errorf("key-2 = %d, want 20", value)
+	}
+}
+
+func TestLRUCacheEvictsLeastRecentlyUsed(t *testing.T) {
+	c := NewLRU[string, int](2, 0)
+	c.Set("a", 1)
+	c.Set("b", 2)
+
+	// Reading a makes b the least recently used
+	if _, ok := c.Get("a"); !ok {
+		t.Fatal("a missing")
+	}
+	c.Set("c", 3)
+	if _, ok := c.Get("b"); ok {
+		t.Error("b survived although a was used more recently")
+	}
+	if _, ok := c.Get("a"); !ok {
+		t.Error("a was evicted although it was used more recently")
+	}
+
+	// Has doesn't count as a use
+	c.Has("c")
+	c.Set("d", 4)
+	if c.Has("c") {
+		t.Error("Has kept c from being evicted")
+	}
+
+	c.Delete("a")
+	if got := c.Keys(); !slices.Equal(got, []string{"d"}) {
+		t.Errorf("Keys after Delete = %v, want [d]", got)
+	}
+}
+
+func TestLRUCacheExpiration(t *testing.T) {
+	c := NewLRU[string, int](10, time.Hour)
+	c.SetWithExpiration("short", 1, time.Millisecond)
+	c.Set("long", 2)
+	c.SetWithExpiration("forever", 3, 0)
+	time.Sleep(5 * time.Millisecond)
+
+	if _, ok := c.Get("short"); ok {
+		t.Error("expired item returned")
+	}
+	if got := c.Keys(); !slices.Equal(got, []string{"forever", "long"}) {
+		t.Errorf("Keys = %v, want [forever long]", got)
+	}
+}
+
+func TestLRUCacheGetOrSet(t *testing.T) {
+	c := NewLRU[string, int](10, 0)
+	var calls atomic.Int32
+	loader := func() (int, error) {
+		calls.Add(1)
+		time.Sleep(10 * time.Millisecond)
+		return 42, nil
+	}
+
+	var wg sync.WaitGroup
+	for range 10 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if value, err := c.GetOrSet("answer", time.Minute, loader); err != nil || value != 42 {
+				t.Errorf("GetOrSet = %d, %v, want 42", value, err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if calls.Load() != 1 {
+		t.Errorf("loader called %d times, want 1", calls.Load())
+	}
+}
+
+func TestLRUCacheConcurrentAccess(t *testing.T) {
+	c := NewLRU[int, int](16, time.Minute)
+
+	var wg sync.WaitGroup
+	for worker := range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for idx := range 1000 {
+				key := (worker*1000 + idx) % 64
+				switch idx % 4 {
+				case 0:
+					c.Set(key, idx)
+				case 1:
+					c.Delete(key)
+				default:
+					c.Get(key)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	if n := c.Len(); n > 16 {
+		t.Errorf("Len = %d, over the capacity of 16", n)
+	}
+}
@@ -0,0 +1,71 @@
+package cache
+
+import "time"
+
+// TypedCache wraps Cache so values are stored and retrieved as T without type assertions at call sites
+type TypedCache[T any] struct {
+	cache *Cache
+}
+
+// NewTyped creates a new typed cache instance
+func NewTyped[T any](defaultExpiration, cleanupInterval time.Duration) *TypedCache[T] {
+	return &TypedCache[T]{cache: New(defaultExpiration, cleanupInterval)}
+}
+
+// Set adds an item to the cache with the default expiration time
+func (c *TypedCache[T]) Set(key string, value T) {
+	c.cache.Set(key, value)
+}
+
+// SetWithExpiration adds an item to the cache with a specified expiration time
+func (c *TypedCache[T]) SetWithExpiration(key string, value T, expiration time.Duration) {
+	c.cache.SetWithExpiration(key, value, expiration)
+}
+
+// Get retrieves an item from the cache
+func (c *TypedCache[T]) Get(key string) (T, bool) {
+	value, found := c.cache.Get(key)
+	if !found {
+		var zero T
+		return zero, false
+	}
+	return value.(T), true
+}
+
+// Delete removes an item from the cache
+func (c *TypedCache[T]) Delete(key string) {
+	c.cache.Delete(key)
+}
+
+// GetOrSet returns the cached item for key, or calls loader once across concurrent callers and
+// caches its result for ttl
+func (c *TypedCache[T]) GetOrSet(key string, ttl time.Duration, loader func() (T, error)) (T, error) {
+	value, err := c.cache.GetOrSet(key, ttl, func() (interface{}, error) {
+		return loader()
+	})
+	if err != nil {
+		var zero T
+		return zero, err
+	}
+	return value.(T), nil
+}
+
+// Has reports whether key holds an item that hasn't expired
+func (c *TypedCache[T]) Has(key string) bool {
+	return c.cache.Has(key)
+}
+
+// Len returns the number of items that haven't expired
+func (c *TypedCache[T]) Len() int {
+	return c.cache.Len()
+}
+
+// Keys returns the keys of the items that haven't expired, in no particular order
+func (c *TypedCache[T]) Keys() []string {
+	return c.cache.Keys()
+}
+
+// Close stops the underlying cache's cleanup goroutine
+func (c *TypedCache[T]) Close() {
+	c.cache.Close()
+}
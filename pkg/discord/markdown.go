@@ -0,0 +1,19 @@
+package discord
+
+import "strings"
+
+// markdownEscaper backslash-escapes the characters Discord treats as formatting
+var markdownEscaper = strings.NewReplacer(
+	`\`, `\\`,
+	"*", `\*`,
+	"_", `\_`,
+	"~", `\~`,
+	"`", "\\`",
+	"|", `\|`,
+	">", `\>`,
+)
+
+// EscapeMarkdown escapes Discord formatting in user text so it is shown literally
+func EscapeMarkdown(text string) string {
+	return markdownEscaper.Replace(text)
+}
@@ -0,0 +1,103 @@
+package discord
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// MaxMessageLength is Discord's limit on the content of a single message
+const MaxMessageLength = 2000
+
+// codeFence opens and closes a Discord code block
+const codeFence = "```"
+
+// truncatedSuffix is appended to content cut short by EnsureMessageFits
+const truncatedSuffix = "…(truncated)"
+
+// SplitMessage splits content into chunks no longer than maxLen, breaking at the last
+// newline before the limit where possible. If content is a code block (starts with ```),
+// every chunk is wrapped in its own fences so formatting survives the split.
+func SplitMessage(content string, maxLen int) []string {
+	if len(content) <= maxLen {
+		return []string{content}
+	}
+
+	if !strings.HasPrefix(content, codeFence) {
+		return splitLines(content, maxLen)
+	}
+
+	// Separate the opening fence line (which may carry a language) from the body
+	opener := codeFence + "\n"
+	body := strings.TrimPrefix(content, codeFence)
+	if idx := strings.Index(body, "\n"); idx >= 0 {
+		opener = codeFence + body[:idx+1]
+		body = body[idx+1:]
+	}
+	body = strings.TrimSuffix(strings.TrimSuffix(body, codeFence), "\n")
+	closer := "\n" + codeFence
+
+	budget := maxLen - len(opener) - len(closer)
+	if budget <= 0 {
+		return splitLines(content, maxLen)
+	}
+
+	parts := splitLines(body, budget)
+	for i, part := range parts {
+		parts[i] = opener + part + closer
+	}
+	return parts
+}
+
+// splitLines splits content into chunks of at most maxLen bytes, preferring newline boundaries
+func splitLines(content string, maxLen int) []string {
+	var parts []string
+	for len(content) > maxLen {
+		cut := strings.LastIndex(content[:maxLen], "\n")
+		if cut > 0 {
+			parts = append(parts, content[:cut])
+			content = content[cut+1:]
+			continue
+		}
+
+		// No newline to break on; cut at the limit without splitting a rune
+		cut = runeBoundary(content, maxLen)
+		parts = append(parts, content[:cut])
+		content = content[cut:]
+	}
+	if content != "" {
+		parts = append(parts, content)
+	}
+	return parts
+}
+
+// EnsureMessageFits returns content unchanged and true if it fits in a single message.
+// Otherwise it returns a truncated version marked with "…(truncated)" and false,
+// closing any code block left open by the cut.
+func EnsureMessageFits(content string) (string, bool) {
+	if len(content) <= MaxMessageLength {
+		return content, true
+	}
+
+	// Leave room for the suffix and a possible closing fence
+	limit := MaxMessageLength - len(truncatedSuffix) - len("\n"+codeFence+"\n")
+	truncated := content[:runeBoundary(content, limit)]
+	if idx := strings.LastIndex(truncated, "\n"); idx > 0 {
+		truncated = truncated[:idx]
+	}
+
+	if strings.Count(truncated, codeFence)%2 == 1 {
+		truncated += "\n" + codeFence
+	}
+	return truncated + "\n" + truncatedSuffix, false
+}
+
+// runeBoundary returns the largest index <= n that does not split a UTF-8 sequence
+func runeBoundary(s string, n int) int {
+	if n >= len(s) {
+		return len(s)
+	}
+	for n > 0 && !utf8.RuneStart(s[n]) {
+		n--
+	}
+	return n
+}
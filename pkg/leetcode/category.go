@@ -0,0 +1,88 @@
+package leetcode
+
+import "strings"
+
+// CanonicalCategories lists the standard LeetCode topic names problems are filed under
+var CanonicalCategories = []string{
+	"Array",
+	"String",
+	"Hash Table",
+	"Dynamic Programming",
+	"Math",
+	"Sorting",
+	"Greedy",
+	"Depth-First Search",
+	"Breadth-First Search",
+	"Binary Search",
+	"Tree",
+	"Binary Tree",
+	"Binary Search Tree",
+	"Matrix",
+	"Two Pointers",
+	"Bit Manipulation",
+	"Stack",
+	"Monotonic Stack",
+	"Queue",
+	"Heap",
+	"Prefix Sum",
+	"Graph",
+	"Topological Sort",
+	"Union Find",
+	"Simulation",
+	"Design",
+	"Backtracking",
+	"Sliding Window",
+	"Linked List",
+	"Trie",
+	"Recursion",
+	"Divide and Conquer",
+	"Intervals",
+	"Segment Tree",
+	"Database",
+}
+
+// NormalizeCategory finds the canonical category closest to input by case-insensitive
+// Levenshtein distance. The confidence is 1 for an exact match and falls towards 0 as the
+// edit distance approaches the length of the longer string.
+func NormalizeCategory(input string) (string, float64) {
+	normalized := strings.ToLower(strings.TrimSpace(input))
+	if normalized == "" {
+		return "", 0
+	}
+
+	best, bestConfidence := "", 0.0
+	for _, category := range CanonicalCategories {
+		candidate := strings.ToLower(category)
+		longest := max(len([]rune(normalized)), len([]rune(candidate)))
+		confidence := 1 - float64(levenshtein(normalized, candidate))/float64(longest)
+		if confidence > bestConfidence {
+			best, bestConfidence = category, confidence
+		}
+	}
+	return best, bestConfidence
+}
+
+// levenshtein returns the minimum number of single-rune insertions, deletions and
+// substitutions needed to turn a into b
+func levenshtein(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(rb)]
+}
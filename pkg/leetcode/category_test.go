@@ -0,0 +1,52 @@
+package leetcode
+
+import "testing"
+
+func TestNormalizeCategory(t *testing.T) {
+	tests := []struct {
+		name          string
+		input         string
+		want          string
+		minConfidence float64
+		maxConfidence float64
+	}{
+		{name: "exact", input: "Dynamic Programming", want: "Dynamic Programming", minConfidence: 1, maxConfidence: 1},
+		{name: "exact ignoring case and spaces", input: "  binary search TREE ", want: "Binary Search Tree", minConfidence: 1, maxConfidence: 1},
+		{name: "one typo", input: "Dynamic Programing", want: "Dynamic Programming", minConfidence: 0.9, maxConfidence: 0.99},
+		{name: "plural", input: "graphs", want: "Graph", minConfidence: 0.8, maxConfidence: 0.9},
+		{name: "close", input: "trees", want: "Tree", minConfidence: 0.8, maxConfidence: 0.8},
+		{name: "looser", input: "hashmap", want: "Hash Table", minConfidence: 0.5, maxConfidence: 0.8},
+		{name: "distant", input: "Astrophysics", maxConfidence: 0.5},
+		{name: "empty", input: "   ", want: "", maxConfidence: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, confidence := NormalizeCategory(tt.input)
+			if tt.want != "" && got != tt.want {
+				t.Errorf("NormalizeCategory(%q) = %q, want %q", tt.input, got, tt.want)
+			}
+			if confidence < tt.minConfidence || confidence > tt.maxConfidence {
+				t.Errorf("confidence for %q = %v, want between %v and %v", tt.input, confidence, tt.minConfidence, tt.maxConfidence)
+			}
+		})
+	}
+}
+
+func TestLevenshtein(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{a: "", b: "", want: 0},
+		{a: "", b: "heap", want: 4},
+		{a: "tree", b: "tree", want: 0},
+		{a: "tree", b: "trie", want: 1},
+		{a: "kitten", b: "sitting", want: 3},
+		{a: "héap", b: "heap", want: 1},
+	}
+	for _, tt := range tests {
+		if got := levenshtein(tt.a, tt.b); got != tt.want {
+			t.Errorf("levenshtein(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
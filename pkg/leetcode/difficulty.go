@@ -0,0 +1,35 @@
+package leetcode
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Canonical difficulty levels, matching the values stored by the database package
+const (
+	DifficultyEasy   = "Easy"
+	DifficultyMedium = "Medium"
+	DifficultyHard   = "Hard"
+)
+
+// difficultyAliases maps lowercase spellings and abbreviations to canonical difficulties
+var difficultyAliases = map[string]string{
+	"easy":   DifficultyEasy,
+	"e":      DifficultyEasy,
+	"ez":     DifficultyEasy,
+	"medium": DifficultyMedium,
+	"m":      DifficultyMedium,
+	"med":    DifficultyMedium,
+	"mid":    DifficultyMedium,
+	"hard":   DifficultyHard,
+	"h":      DifficultyHard,
+}
+
+// NormalizeDifficulty maps case-insensitive difficulty names and common abbreviations
+// such as "e", "med" or "H" to the canonical Easy, Medium or Hard
+func NormalizeDifficulty(s string) (string, error) {
+	if difficulty, ok := difficultyAliases[strings.ToLower(strings.TrimSpace(s))]; ok {
+		return difficulty, nil
+	}
+	return "", fmt.Errorf("unknown difficulty %q, expected one of %s, %s or %s", s, DifficultyEasy, DifficultyMedium, DifficultyHard)
+}
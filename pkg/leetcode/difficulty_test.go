@@ -0,0 +1,57 @@
+package leetcode
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestNormalizeDifficulty(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{input: "Easy", want: DifficultyEasy},
+		{input: "easy", want: DifficultyEasy},
+		{input: "EASY", want: DifficultyEasy},
+		{input: "e", want: DifficultyEasy},
+		{input: "E", want: DifficultyEasy},
+		{input: "ez", want: DifficultyEasy},
+		{input: "  easy ", want: DifficultyEasy},
+		{input: "Medium", want: DifficultyMedium},
+		{input: "MEDIUM", want: DifficultyMedium},
+		{input: "m", want: DifficultyMedium},
+		{input: "med", want: DifficultyMedium},
+		{input: "Med", want: DifficultyMedium},
+		{input: "mid", want: DifficultyMedium},
+		{input: "Hard", want: DifficultyHard},
+		{input: "hard", want: DifficultyHard},
+		{input: "HARD", want: DifficultyHard},
+		{input: "h", want: DifficultyHard},
+		{input: "\thard\n", want: DifficultyHard},
+	}
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := NormalizeDifficulty(tt.input)
+			if err != nil {
+				t.Fatalf("NormalizeDifficulty(%q): %v", tt.input, err)
+			}
+			if got != tt.want {
+				t.Errorf("NormalizeDifficulty(%q) = %q, want %q", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNormalizeDifficultyInvalid(t *testing.T) {
+	for _, input := range []string{"", "   ", "extreme", "easyish", "md", "1"} {
+		_, err := NormalizeDifficulty(input)
+		if err == nil {
+			t.Errorf("NormalizeDifficulty(%q) succeeded, want an error", input)
+			continue
+		}
+		// The message lists the valid values
+		if msg := err.Error(); !strings.Contains(msg, "Easy, Medium or Hard") {
+			t.Errorf("NormalizeDifficulty(%q) = %q, want the valid values listed", input, msg)
+		}
+	}
+}
@@ -0,0 +1,53 @@
+package retry
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"time"
+)
+
+// WithBackoff calls fn until it succeeds, retrying up to maxRetries times with exponential
+// backoff and jitter starting from initialDelay. It gives up early when ctx is cancelled.
+func WithBackoff(ctx context.Context, maxRetries int, initialDelay time.Duration, fn func() error) error {
+	return WithCappedBackoff(ctx, maxRetries, initialDelay, 0, fn)
+}
+
+// WithCappedBackoff is WithBackoff with the delay between attempts limited to maxDelay.
+// A maxDelay of zero leaves the delay uncapped.
+func WithCappedBackoff(ctx context.Context, maxRetries int, initialDelay, maxDelay time.Duration, fn func() error) error {
+	delay := initialDelay
+	for attempt := 0; ; attempt++ {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		err := fn()
+		if err == nil {
+			return nil
+		}
+		if attempt >= maxRetries {
+			return fmt.Errorf("failed after %d attempts: %w", attempt+1, err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(jitter(delay)):
+		}
+
+		delay *= 2
+		if maxDelay > 0 && delay > maxDelay {
+			delay = maxDelay
+		}
+	}
+}
+
+// jitter spreads delay over [delay/2, delay) so reconnecting clients don't retry in lockstep
+func jitter(delay time.Duration) time.Duration {
+	half := delay / 2
+	if half <= 0 {
+		return delay
+	}
+	return half + time.Duration(rand.Int63n(int64(half)))
+}
@@ -0,0 +1,91 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+var errUnavailable = errors.New("gateway unavailable")
+
+func TestWithBackoffStopsOnCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	// Cancelling while waiting between attempts ends the retries at once
+	attempts := 0
+	start := time.Now()
+	err := WithBackoff(ctx, 5, time.Hour, func() error {
+		attempts++
+		time.AfterFunc(10*time.Millisecond, cancel)
+		return errUnavailable
+	})
+	if !errors.Is(err, context.Canceled) || attempts != 1 {
+		t.Errorf("WithBackoff = %v after %d attempts, want context.Canceled after 1", err, attempts)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("WithBackoff returned after %s, want it to stop waiting on cancel", elapsed)
+	}
+
+	// An already cancelled context never calls fn
+	attempts = 0
+	err = WithBackoff(ctx, 5, time.Millisecond, func() error {
+		attempts++
+		return nil
+	})
+	if !errors.Is(err, context.Canceled) || attempts != 0 {
+		t.Errorf("WithBackoff = %v after %d attempts, want context.Canceled after 0", err, attempts)
+	}
+}
+
+func TestWithBackoffRetries(t *testing.T) {
+	attempts := 0
+	err := WithBackoff(context.Background(), 5, time.Millisecond, func() error {
+		attempts++
+		if attempts < 3 {
+			return errUnavailable
+		}
+		return nil
+	})
+	if err != nil || attempts != 3 {
+		t.Errorf("WithBackoff = %v after %d attempts, want success after 3", err, attempts)
+	}
+
+	attempts = 0
+	err = WithBackoff(context.Background(), 2, time.Millisecond, func() error {
+		attempts++
+		return errUnavailable
+	})
+	if !errors.Is(err, errUnavailable) || attempts != 3 {
+		t.Errorf("WithBackoff = %v after %d attempts, want the last error after 3", err, attempts)
+	}
+}
+
+func TestWithCappedBackoffLimitsDelay(t *testing.T) {
+	var calls []time.Time
+	err := WithCappedBackoff(context.Background(), 6, 10*time.Millisecond, 20*time.Millisecond, func() error {
+		calls = append(calls, time.Now())
+		return errUnavailable
+	})
+	if err == nil {
+		t.Fatal("WithCappedBackoff succeeded, want the last error")
+	}
+
+	// Uncapped, the last wait would be up to 320ms; capped, each stays under 20ms plus scheduling slack
+	for idx := 1; idx < len(calls); idx++ {
+		if gap := calls[idx].Sub(calls[idx-1]); gap > 200*time.Millisecond {
+			t.Errorf("attempt %d waited %s, want at most the 20ms cap", idx+1, gap)
+		}
+	}
+}
+
+func TestJitter(t *testing.T) {
+	for range 100 {
+		if got := jitter(100 * time.Millisecond); got < 50*time.Millisecond || got >= 100*time.Millisecond {
+			t.Fatalf("jitter(100ms) = %s, want within [50ms, 100ms)", got)
+		}
+	}
+	if got := jitter(time.Nanosecond); got != time.Nanosecond {
+		t.Errorf("jitter(1ns) = %s, want 1ns", got)
+	}
+}